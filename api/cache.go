@@ -1,15 +1,14 @@
 package api
 
 import (
-	"context"
 	"errors"
-	"fmt"
 	"net/http"
 
+	"github.com/imrishuroy/read-cache-api/auth"
 	db "github.com/imrishuroy/read-cache-api/db/sqlc"
+	"github.com/imrishuroy/read-cache-api/unfurl"
 	"github.com/jackc/pgx/v5/pgtype"
 
-	"firebase.google.com/go/v4/auth"
 	"github.com/gin-gonic/gin"
 )
 
@@ -19,9 +18,42 @@ func (server *Server) ping(ctx *gin.Context) {
 }
 
 type createCacheRequest struct {
-	Title    string `json:"title" binding:"required"`
-	Content  string `json:"content" binding:"required"`
-	IsPublic bool   `json:"is_public" binding:"required"`
+	Title   string `json:"title" binding:"required"`
+	Content string `json:"content" binding:"required"`
+	// IsPublic is a pointer so that required accepts an explicit false
+	IsPublic *bool   `json:"is_public" binding:"required"`
+	TagIDs   []int32 `json:"tag_ids"`
+}
+
+// createCacheQuery picks what happens when the link is already saved:
+// reject answers 409 with the existing cache id, merge adds the requested
+// tags to the existing cache and returns it
+type createCacheQuery struct {
+	OnDuplicate string `form:"on_duplicate" binding:"omitempty,oneof=reject merge"`
+}
+
+type cacheWithTagsResponse struct {
+	db.Cache
+	Tags []db.Tag `json:"tags"`
+}
+
+var errDuplicateCache = errors.New("link is already saved")
+
+func duplicateCacheResponse(cacheID int64) gin.H {
+	return gin.H{"error": errDuplicateCache.Error(), "cache_id": cacheID}
+}
+
+// normalizedLink returns the duplicate detection key of content, empty for notes
+func normalizedLink(content string) (linkURL string, normalizedURL string) {
+	linkURL, ok := unfurl.LinkURL(content)
+	if !ok {
+		return "", ""
+	}
+	normalizedURL, err := unfurl.NormalizeURL(linkURL)
+	if err != nil {
+		return linkURL, ""
+	}
+	return linkURL, normalizedURL
 }
 
 func (server *Server) createCache(ctx *gin.Context) {
@@ -30,29 +62,122 @@ func (server *Server) createCache(ctx *gin.Context) {
 		ctx.JSON(http.StatusBadRequest, errorResponse(err))
 		return
 	}
-	authPayload := ctx.MustGet(authorizationPayloadKey).(*auth.Token)
+	var query createCacheQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*auth.Principal)
+
+	// add this cache and its tags to DB
+	arg := newCreateCacheParams(authPayload.UID, req)
+
+	result, err := server.store.CreateCacheWithTagsTx(ctx, arg)
+	if err != nil {
+		server.handleCreateCacheError(ctx, err, arg, query.OnDuplicate)
+		return
+	}
 
-	// add this cache to DB
-	arg := db.CreateCacheParams{
-		Owner:    authPayload.UID,
-		Title:    req.Title,
-		Content:  req.Content,
-		IsPublic: pgtype.Bool{Bool: req.IsPublic, Valid: true},
+	rsp := cacheWithTagsResponse{
+		Cache: result.Cache,
+		Tags:  result.Tags,
 	}
+	ctx.JSON(http.StatusOK, rsp)
+}
 
-	cache, err := server.store.CreateCache(ctx, arg)
+// newCreateCacheParams turns a create request into the parameters of the
+// create cache transaction
+func newCreateCacheParams(owner string, req createCacheRequest) db.CreateCacheWithTagsTxParams {
+	// links get a preview queued, the unfurl worker fills it in the background
+	linkURL, normalizedURL := normalizedLink(req.Content)
+
+	return db.CreateCacheWithTagsTxParams{
+		CreateCacheParams: db.CreateCacheParams{
+			Owner:         owner,
+			Title:         req.Title,
+			Content:       req.Content,
+			IsPublic:      pgtype.Bool{Bool: *req.IsPublic, Valid: true},
+			NormalizedUrl: normalizedURL,
+		},
+		TagIDs:  uniqueTagIDs(req.TagIDs),
+		LinkURL: linkURL,
+	}
+}
+
+// handleCreateCacheError answers a failed create cache transaction, a
+// collision with an already saved link is handled as onDuplicate asks
+func (server *Server) handleCreateCacheError(ctx *gin.Context, err error, arg db.CreateCacheWithTagsTxParams, onDuplicate string) {
+	errorCode := db.ErrorCode(err)
+	if errorCode == db.UniqueViolation && arg.NormalizedUrl != "" &&
+		server.handleDuplicateCache(ctx, arg.Owner, arg.NormalizedUrl, arg.TagIDs, onDuplicate) {
+		return
+	}
+
+	if errorCode == db.ForeignKeyViolation || errorCode == db.UniqueViolation {
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+}
+
+// handleDuplicateCache answers a create that collided with an already saved
+// link. It returns false when the owner has no cache with that link, so the
+// unique violation came from somewhere else.
+func (server *Server) handleDuplicateCache(ctx *gin.Context, owner string, normalizedURL string, tagIDs []int32, onDuplicate string) bool {
+	existing, err := server.store.GetCacheByNormalizedURL(ctx, db.GetCacheByNormalizedURLParams{
+		Owner:         owner,
+		NormalizedUrl: normalizedURL,
+	})
 	if err != nil {
-		errorCode := db.ErrorCode(err)
-		if errorCode == db.ForeignKeyViolation || errorCode == db.UniqueViolation {
-			ctx.JSON(http.StatusForbidden, errorResponse(err))
-			return
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return false
 		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return true
+	}
+
+	if onDuplicate != "merge" {
+		ctx.JSON(http.StatusConflict, duplicateCacheResponse(existing.ID))
+		return true
+	}
 
+	current, err := server.store.ListCacheTags(ctx, existing.ID)
+	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
-		return
+		return true
+	}
+
+	tagged := make(map[int32]bool, len(current))
+	for _, tag := range current {
+		tagged[tag.TagID] = true
+	}
+	var newTagIDs []int32
+	for _, tagID := range uniqueTagIDs(tagIDs) {
+		if !tagged[tagID] {
+			newTagIDs = append(newTagIDs, tagID)
+		}
+	}
+
+	result, err := server.store.SetCacheTagsTx(ctx, db.SetCacheTagsTxParams{
+		CacheID: existing.ID,
+		TagIDs:  newTagIDs,
+	})
+	if err != nil {
+		if db.ErrorCode(err) == db.ForeignKeyViolation {
+			ctx.JSON(http.StatusForbidden, errorResponse(err))
+			return true
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return true
 	}
 
-	ctx.JSON(http.StatusOK, cache)
+	rsp := cacheWithTagsResponse{
+		Cache: existing,
+		Tags:  result.Tags,
+	}
+	ctx.JSON(http.StatusOK, rsp)
+	return true
 }
 
 type getCacheRequest struct {
@@ -66,31 +191,60 @@ func (server *Server) getCache(ctx *gin.Context) {
 		return
 	}
 
-	cache, err := server.store.GetCache(ctx, req.ID)
-	if err != nil {
-		if errors.Is(err, db.ErrRecordNotFound) {
-			//errorMessage := "Cache not found with the given ID " + strconv.FormatInt(req.ID, 10)
-			//ctx.JSON(http.StatusNotFound, gin.H{"error": errorMessage})
+	cache, ok := server.authorizeCache(ctx, req.ID, viewCache)
+	if !ok {
+		return
+	}
 
-			ctx.JSON(http.StatusNotFound, errorResponse(err))
-			return
-		}
+	rsp, err := server.newCacheResponses(ctx, []db.Cache{cache})
+	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
-	authPayload := ctx.MustGet(authorizationPayloadKey).(*auth.Token)
-	if cache.Owner != authPayload.UID {
-		err := errors.New("cache does't belong to the authenticated user")
-		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
-		return
+
+	ctx.JSON(http.StatusOK, rsp[0])
+}
+
+// tagFilterRequest selects caches by their tags. With mode=any a cache needs
+// at least one of tag_ids, with mode=all it needs every one of them. Caches
+// carrying any of exclude_tag_ids are always left out.
+type tagFilterRequest struct {
+	TagIDs        []int32 `form:"tag_ids"`
+	Mode          string  `form:"mode" binding:"omitempty,oneof=any all"`
+	ExcludeTagIDs []int32 `form:"exclude_tag_ids"`
+}
+
+func (req tagFilterRequest) isEmpty() bool {
+	return len(req.TagIDs) == 0 && len(req.ExcludeTagIDs) == 0
+}
+
+func (req tagFilterRequest) matchAll() bool {
+	return req.Mode == "all"
+}
+
+// uniqueTagIDs drops repeated ids, match-all compares against the number of
+// requested tags and a cache carries each tag once
+func uniqueTagIDs(ids []int32) []int32 {
+	if len(ids) == 0 {
+		return nil
 	}
 
-	ctx.JSON(http.StatusOK, cache)
+	seen := make(map[int32]bool, len(ids))
+	unique := make([]int32, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
 }
 
 type listCacheRequest struct {
-	PageID   int32 `form:"page_id" binding:"required,min=1"`
-	PageSize int32 `form:"page_size" binding:"required,min=5,max=10"`
+	pageRequest
+	tagFilterRequest
+	Status       string `form:"status" binding:"omitempty,oneof=unread reading read archived all"`
+	CollectionID int64  `form:"collection_id" binding:"omitempty,min=1"`
 }
 
 func (server *Server) listCaches(ctx *gin.Context) {
@@ -100,22 +254,74 @@ func (server *Server) listCaches(ctx *gin.Context) {
 		return
 	}
 
-	authPayload := ctx.MustGet(authorizationPayloadKey).(*auth.Token)
+	p, err := server.resolvePage(req.pageRequest)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if req.CollectionID > 0 {
+		server.listCollectionCaches(ctx, req, p)
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*auth.Principal)
+	status, includeArchived := readingStatusFilter(req.Status)
+
+	if p.legacy {
+		arg := db.ListCachesParams{
+			Owner:           authPayload.UID,
+			Limit:           p.limit,
+			Offset:          p.offset,
+			TagIds:          uniqueTagIDs(req.TagIDs),
+			MatchAll:        req.matchAll(),
+			ExcludeTagIds:   uniqueTagIDs(req.ExcludeTagIDs),
+			Status:          status,
+			IncludeArchived: includeArchived,
+		}
+
+		caches, err := server.store.ListCaches(ctx, arg)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+
+		rsp, err := server.newCacheResponses(ctx, caches)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
 
-	arg := db.ListCachesParams{
-		Owner:  authPayload.UID,
-		Limit:  req.PageSize,
-		Offset: (req.PageID - 1) * req.PageSize,
+		setDeprecatedPaginationHeaders(ctx)
+		ctx.JSON(http.StatusOK, rsp)
+		return
 	}
 
-	caches, err := server.store.ListCaches(ctx, arg)
+	arg := db.ListCachesByCursorParams{
+		Owner:           authPayload.UID,
+		TagIds:          uniqueTagIDs(req.TagIDs),
+		MatchAll:        req.matchAll(),
+		ExcludeTagIds:   uniqueTagIDs(req.ExcludeTagIDs),
+		Status:          status,
+		IncludeArchived: includeArchived,
+		CursorCreatedAt: p.cursorCreatedAt(),
+		CursorID:        p.cursorID(),
+		PageLimit:       p.fetchLimit(),
+	}
 
+	caches, err := server.store.ListCachesByCursor(ctx, arg)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
-	ctx.JSON(http.StatusOK, caches)
+	rsp, err := server.cachePageResponse(ctx, caches, p)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, rsp)
 }
 
 type updateCacheRequest struct {
@@ -132,39 +338,44 @@ func (server *Server) updateCache(ctx *gin.Context) {
 		return
 	}
 
-	authPayload := ctx.MustGet(authorizationPayloadKey).(*auth.Token)
-
-	dbCache, err := server.store.GetCache(context.Background(), req.ID)
-	if err != nil {
-
-		if err == db.ErrRecordNotFound {
-			ctx.JSON(http.StatusBadRequest, gin.H{"error": "no data found for this cache id"})
-			return
-		}
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+	if _, ok := server.authorizeCache(ctx, req.ID, editCache); !ok {
 		return
 	}
 
-	if dbCache.Owner != authPayload.UID {
-		err := errors.New("cache does't belong to the authenticated user")
-		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
-		return
-	}
+	_, normalizedURL := normalizedLink(req.Content)
 
 	arg := db.UpdateCacheParams{
-		ID:       req.ID,
-		Title:    req.Title,
-		Content:  req.Content,
-		IsPublic: pgtype.Bool{Bool: req.IsPublic, Valid: true},
+		ID:            req.ID,
+		Title:         req.Title,
+		Content:       req.Content,
+		IsPublic:      pgtype.Bool{Bool: req.IsPublic, Valid: true},
+		NormalizedUrl: normalizedURL,
 	}
 
-	cache, err := server.store.UpdateCache(ctx, arg)
+	cache, err := server.store.UpdateCacheTx(ctx, arg)
 	if err != nil {
+		if db.ErrorCode(err) == db.UniqueViolation {
+			authPayload := ctx.MustGet(authorizationPayloadKey).(*auth.Principal)
+			existing, lookupErr := server.store.GetCacheByNormalizedURL(ctx, db.GetCacheByNormalizedURLParams{
+				Owner:         authPayload.UID,
+				NormalizedUrl: normalizedURL,
+			})
+			if lookupErr == nil {
+				ctx.JSON(http.StatusConflict, duplicateCacheResponse(existing.ID))
+				return
+			}
+		}
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
-	ctx.JSON(http.StatusOK, cache)
+	rsp, err := server.newCacheResponses(ctx, []db.Cache{cache})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, rsp[0])
 }
 
 type deleteCacheRequest struct {
@@ -178,36 +389,145 @@ func (server *Server) deleteCache(ctx *gin.Context) {
 		return
 	}
 
-	authPayload := ctx.MustGet(authorizationPayloadKey).(*auth.Token)
-	dbCache, err := server.store.GetCache(context.Background(), req.ID)
+	if _, ok := server.authorizeCache(ctx, req.ID, editCache); !ok {
+		return
+	}
+
+	err := server.store.DeleteCacheTx(ctx, req.ID)
 	if err != nil {
-		if err == db.ErrRecordNotFound {
-			ctx.JSON(http.StatusBadRequest, gin.H{"error": "no data found for this cache id"})
-			return
-		}
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
-	if dbCache.Owner != authPayload.UID {
-		err := errors.New("cache does't belong to the authenticated user")
-		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+	ctx.JSON(http.StatusOK, successResponse())
+}
+
+type searchCachesRequest struct {
+	pageRequest
+	Query string `form:"q" binding:"required,max=200"`
+}
+
+// searchCacheResponse is a cache in the search results, its highlights are
+// HTML with the matches in <mark> tags and everything else escaped
+type searchCacheResponse struct {
+	cacheResponse
+	Rank           float32 `json:"rank"`
+	TitleHighlight string  `json:"title_highlight"`
+	ContentSnippet string  `json:"content_snippet"`
+}
+
+// searchCaches does a ranked full-text search over the caller's own caches and all public caches
+func (server *Server) searchCaches(ctx *gin.Context) {
+	var req searchCachesRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
 		return
 	}
 
-	error := server.store.DeleteCache(ctx, req.ID)
+	p, err := server.resolvePage(req.pageRequest)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(error))
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	query := buildSearchQuery(req.Query)
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*auth.Principal)
+
+	if p.legacy {
+		rows := []db.SearchCachesRow{}
+		if query != "" {
+			rows, err = server.store.SearchCaches(ctx, db.SearchCachesParams{
+				Query:      query,
+				Owner:      authPayload.UID,
+				PageLimit:  p.limit,
+				PageOffset: p.offset,
+			})
+			if err != nil {
+				ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+				return
+			}
+		}
+
+		rsp, err := server.newSearchCacheResponses(ctx, rows)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+
+		setDeprecatedPaginationHeaders(ctx)
+		ctx.JSON(http.StatusOK, rsp)
 		return
 	}
+
+	rows := []db.SearchCachesRow{}
+	if query != "" {
+		arg := db.SearchCachesByCursorParams{
+			Query:     query,
+			Owner:     authPayload.UID,
+			CursorID:  p.cursorID(),
+			PageLimit: p.fetchLimit(),
+		}
+		if p.cursor != nil {
+			arg.CursorRank = pgtype.Float4{Float32: p.cursor.Rank, Valid: true}
+		}
+
+		cursorRows, err := server.store.SearchCachesByCursor(ctx, arg)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+		for _, row := range cursorRows {
+			rows = append(rows, db.SearchCachesRow(row))
+		}
+	}
+
+	rowPage := newPageResponse(rows, p, func(row db.SearchCachesRow) pageCursor {
+		return pageCursor{CreatedAt: row.Cache.CreatedAt, ID: row.Cache.ID, Rank: row.Rank}
+	})
+
+	items, err := server.newSearchCacheResponses(ctx, rowPage.Items)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, pageResponse[searchCacheResponse]{Items: items, NextCursor: rowPage.NextCursor})
+}
+
+// newSearchCacheResponses is newCacheResponses for search results
+func (server *Server) newSearchCacheResponses(ctx *gin.Context, rows []db.SearchCachesRow) ([]searchCacheResponse, error) {
+	caches := make([]db.Cache, len(rows))
+	for i, row := range rows {
+		caches[i] = row.Cache
+	}
+
+	items, err := server.newCacheResponses(ctx, caches)
+	if err != nil {
+		return nil, err
+	}
+
+	rsp := make([]searchCacheResponse, len(items))
+	for i, item := range items {
+		rsp[i] = searchCacheResponse{
+			cacheResponse:  item,
+			Rank:           rows[i].Rank,
+			TitleHighlight: rows[i].TitleHighlight,
+			ContentSnippet: rows[i].ContentSnippet,
+		}
+	}
+	return rsp, nil
 }
 
+// listPublicCachesByTagIDsRequest lists public caches newest first, or by the
+// likes they got this week with sort=most_liked_week
 type listPublicCachesByTagIDsRequest struct {
-	PageID   int32   `form:"page_id" binding:"required,min=1"`
-	PageSize int32   `form:"page_size" binding:"required,min=5,max=10"`
-	TagIDs   []int32 `form:"tag_ids"`
+	pageRequest
+	tagFilterRequest
+	Sort string `form:"sort" binding:"omitempty,oneof=newest most_liked_week"`
 }
 
+var errLegacyPageSort = errors.New("page_id can't be combined with sort=most_liked_week, use cursor")
+
 func (server *Server) listPublicCaches(ctx *gin.Context) {
 	var req listPublicCachesByTagIDsRequest
 	if err := ctx.ShouldBindQuery(&req); err != nil {
@@ -215,35 +535,90 @@ func (server *Server) listPublicCaches(ctx *gin.Context) {
 		return
 	}
 
-	fmt.Println("req.TagIDs", req.TagIDs)
+	p, err := server.resolvePage(req.pageRequest)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
 
-	publicCacheArg := db.ListPublicCachesParams{
+	if req.Sort == "most_liked_week" {
+		if p.legacy {
+			ctx.JSON(http.StatusBadRequest, errorResponse(errLegacyPageSort))
+			return
+		}
+		server.listMostLikedPublicCaches(ctx, req.tagFilterRequest, p)
+		return
+	}
 
-		Limit:  req.PageSize,
-		Offset: (req.PageID - 1) * req.PageSize,
+	if p.legacy {
+		setDeprecatedPaginationHeaders(ctx)
+		server.listPublicCachesByPage(ctx, req.tagFilterRequest, p)
+		return
 	}
 
-	if len(req.TagIDs) == 0 {
-		caches, err := server.store.ListPublicCaches(ctx, publicCacheArg)
-		if err != nil {
-			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
-			return
+	var caches []db.Cache
+	if req.isEmpty() {
+		arg := db.ListPublicCachesByCursorParams{
+			CursorCreatedAt: p.cursorCreatedAt(),
+			CursorID:        p.cursorID(),
+			PageLimit:       p.fetchLimit(),
 		}
-		ctx.JSON(http.StatusOK, caches)
+		caches, err = server.store.ListPublicCachesByCursor(ctx, arg)
+	} else {
+		arg := db.ListPublicCachesByTagsCursorParams{
+			TagIds:          uniqueTagIDs(req.TagIDs),
+			MatchAll:        req.matchAll(),
+			ExcludeTagIds:   uniqueTagIDs(req.ExcludeTagIDs),
+			CursorCreatedAt: p.cursorCreatedAt(),
+			CursorID:        p.cursorID(),
+			PageLimit:       p.fetchLimit(),
+		}
+		caches, err = server.store.ListPublicCachesByTagsCursor(ctx, arg)
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp, err := server.cachePageResponse(ctx, caches, p)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
-	publicCacheByTagsArg := db.ListPublicCachesByTagsParams{
-		TagIds: req.TagIDs,
-		Limit:  req.PageSize,
-		Offset: (req.PageID - 1) * req.PageSize,
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+// listPublicCachesByPage serves the deprecated page_id based pagination
+func (server *Server) listPublicCachesByPage(ctx *gin.Context, filter tagFilterRequest, p page) {
+	publicCacheArg := db.ListPublicCachesParams{
+		Limit:  p.limit,
+		Offset: p.offset,
 	}
 
-	caches, err := server.store.ListPublicCachesByTags(ctx, publicCacheByTagsArg)
+	var caches []db.Cache
+	var err error
+	if filter.isEmpty() {
+		caches, err = server.store.ListPublicCaches(ctx, publicCacheArg)
+	} else {
+		publicCacheByTagsArg := db.ListPublicCachesByTagsParams{
+			TagIds:        uniqueTagIDs(filter.TagIDs),
+			MatchAll:      filter.matchAll(),
+			ExcludeTagIds: uniqueTagIDs(filter.ExcludeTagIDs),
+			Limit:         p.limit,
+			Offset:        p.offset,
+		}
+		caches, err = server.store.ListPublicCachesByTags(ctx, publicCacheByTagsArg)
+	}
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
-	ctx.JSON(http.StatusOK, caches)
 
+	rsp, err := server.newCacheResponses(ctx, caches)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	ctx.JSON(http.StatusOK, rsp)
 }
@@ -0,0 +1,196 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/imrishuroy/read-cache-api/auth"
+	db "github.com/imrishuroy/read-cache-api/db/sqlc"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// mostLikedWindow is the period "most liked this week" counts likes over
+const mostLikedWindow = 7 * 24 * time.Hour
+
+var errCacheNotPublic = errors.New("only public caches can be liked")
+
+// cacheResponse is a cache as seen by the caller
+type cacheResponse struct {
+	db.Cache
+	LikedByMe bool `json:"liked_by_me"`
+}
+
+// rankedCacheResponse is a cache in the most liked ranking
+type rankedCacheResponse struct {
+	cacheResponse
+	RecentLikes int64 `json:"recent_likes"`
+}
+
+// newCacheResponses flags the caches the caller liked with a single query
+func (server *Server) newCacheResponses(ctx *gin.Context, caches []db.Cache) ([]cacheResponse, error) {
+	rsp := make([]cacheResponse, len(caches))
+	if len(caches) == 0 {
+		return rsp, nil
+	}
+
+	ids := make([]int64, len(caches))
+	for i, cache := range caches {
+		ids[i] = cache.ID
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*auth.Principal)
+	likedIDs, err := server.store.ListLikedCacheIDs(ctx, db.ListLikedCacheIDsParams{
+		UserID:   authPayload.UID,
+		CacheIds: ids,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	liked := make(map[int64]bool, len(likedIDs))
+	for _, id := range likedIDs {
+		liked[id] = true
+	}
+	for i, cache := range caches {
+		rsp[i] = cacheResponse{Cache: cache, LikedByMe: liked[cache.ID]}
+	}
+	return rsp, nil
+}
+
+// cachePageResponse is newPageResponse for caches, with liked_by_me set
+func (server *Server) cachePageResponse(ctx *gin.Context, caches []db.Cache, p page) (pageResponse[cacheResponse], error) {
+	cachePage := newPageResponse(caches, p, cacheCursor)
+	items, err := server.newCacheResponses(ctx, cachePage.Items)
+	return pageResponse[cacheResponse]{Items: items, NextCursor: cachePage.NextCursor}, err
+}
+
+type likeCacheRequest struct {
+	CacheID int64 `uri:"cache_id" binding:"required,min=1"`
+}
+
+// unlikeCacheRequest binds :id, the wildcard every DELETE /caches route shares
+type unlikeCacheRequest struct {
+	CacheID int64 `uri:"id" binding:"required,min=1"`
+}
+
+type cacheLikeResponse struct {
+	CacheID   int64 `json:"cache_id"`
+	LikeCount int32 `json:"like_count"`
+	LikedByMe bool  `json:"liked_by_me"`
+}
+
+// likeCache is idempotent, liking a cache twice keeps a single like
+func (server *Server) likeCache(ctx *gin.Context) {
+	var req likeCacheRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	server.setCacheLike(ctx, req.CacheID, true)
+}
+
+// unlikeCache is idempotent, unliking a cache that isn't liked changes nothing
+func (server *Server) unlikeCache(ctx *gin.Context) {
+	var req unlikeCacheRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	server.setCacheLike(ctx, req.CacheID, false)
+}
+
+func (server *Server) setCacheLike(ctx *gin.Context, cacheID int64, like bool) {
+	cache, ok := server.authorizeCache(ctx, cacheID, viewCache)
+	if !ok {
+		return
+	}
+	if like && !cache.IsPublic.Bool {
+		ctx.JSON(http.StatusForbidden, errorResponse(errCacheNotPublic))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*auth.Principal)
+
+	arg := db.LikeCacheTxParams{
+		CacheID: cache.ID,
+		UserID:  authPayload.UID,
+	}
+
+	var result db.LikeCacheTxResult
+	var err error
+	if like {
+		result, err = server.store.LikeCacheTx(ctx, arg)
+	} else {
+		result, err = server.store.UnlikeCacheTx(ctx, arg)
+	}
+	if err != nil {
+		if db.ErrorCode(err) == db.ForeignKeyViolation {
+			ctx.JSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, cacheLikeResponse{
+		CacheID:   cache.ID,
+		LikeCount: result.LikeCount,
+		LikedByMe: like,
+	})
+}
+
+// listMostLikedPublicCaches ranks public caches by the likes they got in the
+// last week, only caches liked in that window are listed. The first page
+// fixes the window in the cursor so later pages rank the same likes.
+func (server *Server) listMostLikedPublicCaches(ctx *gin.Context, filter tagFilterRequest, p page) {
+	likedSince := time.Now().Add(-mostLikedWindow)
+	if p.cursor != nil && p.cursor.Since != nil {
+		likedSince = *p.cursor.Since
+	}
+
+	arg := db.ListMostLikedPublicCachesParams{
+		LikedSince:    likedSince,
+		TagIds:        uniqueTagIDs(filter.TagIDs),
+		MatchAll:      filter.matchAll(),
+		ExcludeTagIds: uniqueTagIDs(filter.ExcludeTagIDs),
+		PageLimit:     p.fetchLimit(),
+	}
+	if p.cursor != nil {
+		arg.CursorLikes = pgtype.Int8{Int64: p.cursor.Likes, Valid: true}
+		arg.CursorID = p.cursorID()
+	}
+
+	rows, err := server.store.ListMostLikedPublicCaches(ctx, arg)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rowPage := newPageResponse(rows, p, func(row db.ListMostLikedPublicCachesRow) pageCursor {
+		return pageCursor{CreatedAt: row.Cache.CreatedAt, ID: row.Cache.ID, Likes: row.RecentLikes, Since: &likedSince}
+	})
+
+	caches := make([]db.Cache, len(rowPage.Items))
+	for i, row := range rowPage.Items {
+		caches[i] = row.Cache
+	}
+
+	items, err := server.newCacheResponses(ctx, caches)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp := pageResponse[rankedCacheResponse]{
+		Items:      make([]rankedCacheResponse, len(items)),
+		NextCursor: rowPage.NextCursor,
+	}
+	for i, item := range items {
+		rsp.Items[i] = rankedCacheResponse{cacheResponse: item, RecentLikes: rowPage.Items[i].RecentLikes}
+	}
+	ctx.JSON(http.StatusOK, rsp)
+}
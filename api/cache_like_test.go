@@ -0,0 +1,269 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/imrishuroy/read-cache-api/db/mock"
+	db "github.com/imrishuroy/read-cache-api/db/sqlc"
+	"github.com/imrishuroy/read-cache-api/util"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLikeCacheAPI(t *testing.T) {
+	owner := util.RandomOwner()
+	liker := util.RandomOwner()
+	publicCache := randomCache(owner, true)
+	privateCache := randomCache(owner, false)
+
+	testCases := []struct {
+		name          string
+		uid           string
+		cacheID       int64
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:    "OK",
+			uid:     liker,
+			cacheID: publicCache.ID,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetCache(gomock.Any(), gomock.Eq(publicCache.ID)).
+					Times(1).
+					Return(publicCache, nil)
+
+				arg := db.LikeCacheTxParams{
+					CacheID: publicCache.ID,
+					UserID:  liker,
+				}
+				store.EXPECT().
+					LikeCacheTx(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(db.LikeCacheTxResult{LikeCount: 4}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp cacheLikeResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.Equal(t, cacheLikeResponse{CacheID: publicCache.ID, LikeCount: 4, LikedByMe: true}, rsp)
+			},
+		},
+		{
+			name:    "OwnPrivateCache",
+			uid:     owner,
+			cacheID: privateCache.ID,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetCache(gomock.Any(), gomock.Eq(privateCache.ID)).
+					Times(1).
+					Return(privateCache, nil)
+				store.EXPECT().
+					LikeCacheTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:    "OtherUserPrivateCache",
+			uid:     liker,
+			cacheID: privateCache.ID,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetCache(gomock.Any(), gomock.Eq(privateCache.ID)).
+					Times(1).
+					Return(privateCache, nil)
+				store.EXPECT().
+					LikeCacheTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:    "InternalError",
+			uid:     liker,
+			cacheID: publicCache.ID,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetCache(gomock.Any(), gomock.Eq(publicCache.ID)).
+					Times(1).
+					Return(publicCache, nil)
+				store.EXPECT().
+					LikeCacheTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.LikeCacheTxResult{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			url := fmt.Sprintf("/api/caches/%d/like", tc.cacheID)
+			recorder := serveTestRequest(t, tc.buildStubs, tc.uid, http.MethodPost, url, nil)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestUnlikeCacheAPI(t *testing.T) {
+	owner := util.RandomOwner()
+	liker := util.RandomOwner()
+	cache := randomCache(owner, true)
+
+	recorder := serveTestRequest(t, func(store *mockdb.MockStore) {
+		store.EXPECT().
+			GetCache(gomock.Any(), gomock.Eq(cache.ID)).
+			Times(1).
+			Return(cache, nil)
+		store.EXPECT().
+			UnlikeCacheTx(gomock.Any(), gomock.Eq(db.LikeCacheTxParams{CacheID: cache.ID, UserID: liker})).
+			Times(1).
+			Return(db.LikeCacheTxResult{LikeCount: 2}, nil)
+	}, liker, http.MethodDelete, fmt.Sprintf("/api/caches/%d/like", cache.ID), nil)
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	var rsp cacheLikeResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+	require.Equal(t, cacheLikeResponse{CacheID: cache.ID, LikeCount: 2}, rsp)
+}
+
+func TestListMostLikedPublicCachesAPI(t *testing.T) {
+	uid := util.RandomOwner()
+
+	rows := make([]db.ListMostLikedPublicCachesRow, 3)
+	for i := range rows {
+		cache := randomCache(util.RandomOwner(), true)
+		cache.CreatedAt = time.Now().Add(-time.Duration(i) * time.Hour)
+		cache.LikeCount = int32(10 - i)
+		rows[i] = db.ListMostLikedPublicCachesRow{Cache: cache, RecentLikes: int64(5 - i)}
+	}
+	since := time.Now().Add(-mostLikedWindow - time.Hour).UTC().Truncate(time.Microsecond)
+	cursor := pageCursor{CreatedAt: time.Now().UTC().Truncate(time.Microsecond), ID: 9, Likes: 3, Since: &since}
+
+	testCases := []struct {
+		name          string
+		query         string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "FirstPage",
+			query: "sort=most_liked_week&page_size=2",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListMostLikedPublicCaches(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.ListMostLikedPublicCachesParams) ([]db.ListMostLikedPublicCachesRow, error) {
+						require.WithinDuration(t, time.Now().Add(-mostLikedWindow), arg.LikedSince, time.Second)
+						require.False(t, arg.CursorLikes.Valid)
+						require.Equal(t, int32(3), arg.PageLimit)
+						return rows, nil
+					})
+				store.EXPECT().
+					ListLikedCacheIDs(gomock.Any(), gomock.Eq(db.ListLikedCacheIDsParams{
+						UserID:   uid,
+						CacheIds: []int64{rows[0].Cache.ID, rows[1].Cache.ID},
+					})).
+					Times(1).
+					Return([]int64{rows[1].Cache.ID}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp pageResponse[rankedCacheResponse]
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.Len(t, rsp.Items, 2)
+				require.Equal(t, int64(5), rsp.Items[0].RecentLikes)
+				require.Equal(t, int32(10), rsp.Items[0].LikeCount)
+				require.False(t, rsp.Items[0].LikedByMe)
+				require.True(t, rsp.Items[1].LikedByMe)
+
+				require.NotNil(t, rsp.NextCursor)
+				next, err := decodeCursor(*rsp.NextCursor)
+				require.NoError(t, err)
+				require.Equal(t, rows[1].Cache.ID, next.ID)
+				require.Equal(t, rows[1].RecentLikes, next.Likes)
+				require.NotNil(t, next.Since)
+				require.WithinDuration(t, time.Now().Add(-mostLikedWindow), *next.Since, time.Second)
+			},
+		},
+		{
+			name:  "NextPage",
+			query: "sort=most_liked_week&cursor=" + encodeCursor(cursor),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListMostLikedPublicCaches(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.ListMostLikedPublicCachesParams) ([]db.ListMostLikedPublicCachesRow, error) {
+						// the window of the first page, not one recomputed now
+						require.True(t, since.Equal(arg.LikedSince))
+						require.Equal(t, pgtype.Int8{Int64: cursor.Likes, Valid: true}, arg.CursorLikes)
+						require.Equal(t, pgtype.Int8{Int64: cursor.ID, Valid: true}, arg.CursorID)
+						return []db.ListMostLikedPublicCachesRow{}, nil
+					})
+				store.EXPECT().
+					ListLikedCacheIDs(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp pageResponse[rankedCacheResponse]
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.Empty(t, rsp.Items)
+				require.Nil(t, rsp.NextCursor)
+			},
+		},
+		{
+			name:  "LegacyPageID",
+			query: "sort=most_liked_week&page_id=1&page_size=5",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListMostLikedPublicCaches(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "InvalidSort",
+			query: "sort=oldest",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListPublicCachesByCursor(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := serveTestRequest(t, tc.buildStubs, uid, http.MethodGet, "/api/caches/public?"+tc.query, nil)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	db "github.com/imrishuroy/read-cache-api/db/sqlc"
+	"github.com/imrishuroy/read-cache-api/unfurl"
+)
+
+var (
+	errCacheSnapshotNotFound = errors.New("cache has no snapshot")
+	errCacheNotALink         = errors.New("cache content is not a link")
+)
+
+// cacheSnapshotResponse leaves out the retry bookkeeping of db.CacheSnapshot.
+// While a re-snapshot is pending the previous html and text are still served.
+type cacheSnapshotResponse struct {
+	CacheID    int64      `json:"cache_id"`
+	URL        string     `json:"url"`
+	Status     string     `json:"status"`
+	Title      string     `json:"title"`
+	HTML       string     `json:"html"`
+	Text       string     `json:"text"`
+	Images     []string   `json:"images"`
+	ArchivedAt *time.Time `json:"archived_at"`
+}
+
+func newCacheSnapshotResponse(snapshot db.CacheSnapshot) cacheSnapshotResponse {
+	rsp := cacheSnapshotResponse{
+		CacheID: snapshot.CacheID,
+		URL:     snapshot.Url,
+		Status:  snapshot.Status,
+		Title:   snapshot.Title,
+		HTML:    snapshot.Html,
+		Text:    snapshot.Text,
+		Images:  snapshot.Images,
+	}
+	if snapshot.ArchivedAt.Valid {
+		rsp.ArchivedAt = &snapshot.ArchivedAt.Time
+	}
+	return rsp
+}
+
+type cacheSnapshotRequest struct {
+	CacheID int64 `uri:"cache_id" binding:"required,min=1"`
+}
+
+func (server *Server) getCacheSnapshot(ctx *gin.Context) {
+	var req cacheSnapshotRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if _, ok := server.authorizeCache(ctx, req.CacheID, viewCache); !ok {
+		return
+	}
+
+	snapshot, err := server.store.GetCacheSnapshot(ctx, req.CacheID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(errCacheSnapshotNotFound))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newCacheSnapshotResponse(snapshot))
+}
+
+// requestCacheSnapshot queues a fresh snapshot, the archive worker picks it up in the background
+func (server *Server) requestCacheSnapshot(ctx *gin.Context) {
+	var req cacheSnapshotRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	cache, ok := server.authorizeCache(ctx, req.CacheID, editCache)
+	if !ok {
+		return
+	}
+
+	linkURL, ok := unfurl.LinkURL(cache.Content)
+	if !ok {
+		ctx.JSON(http.StatusBadRequest, errorResponse(errCacheNotALink))
+		return
+	}
+
+	arg := db.RequestCacheSnapshotParams{
+		CacheID: cache.ID,
+		Url:     linkURL,
+	}
+
+	snapshot, err := server.store.RequestCacheSnapshot(ctx, arg)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, newCacheSnapshotResponse(snapshot))
+}
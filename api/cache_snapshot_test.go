@@ -0,0 +1,210 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/imrishuroy/read-cache-api/db/mock"
+	db "github.com/imrishuroy/read-cache-api/db/sqlc"
+	"github.com/imrishuroy/read-cache-api/util"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetCacheSnapshotAPI(t *testing.T) {
+	owner := util.RandomOwner()
+	publicCache := randomCache(owner, true)
+	snapshot := db.CacheSnapshot{
+		CacheID:    publicCache.ID,
+		Url:        "https://example.com/article",
+		Status:     "done",
+		Title:      util.RandomTitle(),
+		Html:       "<p>" + util.RandomContent() + "</p>",
+		Text:       util.RandomContent(),
+		Images:     []string{"https://example.com/cover.png"},
+		ArchivedAt: pgtype.Timestamptz{Time: time.Now().UTC().Truncate(time.Second), Valid: true},
+	}
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetCache(gomock.Any(), gomock.Eq(publicCache.ID)).
+					Times(1).
+					Return(publicCache, nil)
+				store.EXPECT().
+					GetCacheSnapshot(gomock.Any(), gomock.Eq(publicCache.ID)).
+					Times(1).
+					Return(snapshot, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp cacheSnapshotResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.Equal(t, newCacheSnapshotResponse(snapshot), rsp)
+			},
+		},
+		{
+			name: "NoSnapshot",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetCache(gomock.Any(), gomock.Eq(publicCache.ID)).
+					Times(1).
+					Return(publicCache, nil)
+				store.EXPECT().
+					GetCacheSnapshot(gomock.Any(), gomock.Eq(publicCache.ID)).
+					Times(1).
+					Return(db.CacheSnapshot{}, db.ErrRecordNotFound)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "InternalError",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetCache(gomock.Any(), gomock.Eq(publicCache.ID)).
+					Times(1).
+					Return(publicCache, nil)
+				store.EXPECT().
+					GetCacheSnapshot(gomock.Any(), gomock.Eq(publicCache.ID)).
+					Times(1).
+					Return(db.CacheSnapshot{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			// public caches can be read by anyone
+			url := fmt.Sprintf("/api/caches/%d/snapshot", publicCache.ID)
+			recorder := serveTestRequest(t, tc.buildStubs, util.RandomOwner(), http.MethodGet, url, nil)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestRequestCacheSnapshotAPI(t *testing.T) {
+	owner := util.RandomOwner()
+	linkCache := randomCache(owner, true)
+	linkCache.Content = "https://example.com/article"
+	noteCache := randomCache(owner, true)
+
+	testCases := []struct {
+		name          string
+		cache         db.Cache
+		uid           string
+		buildStubs    func(store *mockdb.MockStore, cache db.Cache)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "Accepted",
+			cache: linkCache,
+			uid:   owner,
+			buildStubs: func(store *mockdb.MockStore, cache db.Cache) {
+				store.EXPECT().
+					GetCache(gomock.Any(), gomock.Eq(cache.ID)).
+					Times(1).
+					Return(cache, nil)
+				arg := db.RequestCacheSnapshotParams{
+					CacheID: cache.ID,
+					Url:     cache.Content,
+				}
+				store.EXPECT().
+					RequestCacheSnapshot(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(db.CacheSnapshot{CacheID: cache.ID, Url: cache.Content, Status: "pending"}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusAccepted, recorder.Code)
+
+				var rsp cacheSnapshotResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.Equal(t, "pending", rsp.Status)
+			},
+		},
+		{
+			name:  "NotALink",
+			cache: noteCache,
+			uid:   owner,
+			buildStubs: func(store *mockdb.MockStore, cache db.Cache) {
+				store.EXPECT().
+					GetCache(gomock.Any(), gomock.Eq(cache.ID)).
+					Times(1).
+					Return(cache, nil)
+				store.EXPECT().
+					RequestCacheSnapshot(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "NotOwner",
+			cache: linkCache,
+			uid:   util.RandomOwner(),
+			buildStubs: func(store *mockdb.MockStore, cache db.Cache) {
+				store.EXPECT().
+					GetCache(gomock.Any(), gomock.Eq(cache.ID)).
+					Times(1).
+					Return(cache, nil)
+				store.EXPECT().
+					RequestCacheSnapshot(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:  "InternalError",
+			cache: linkCache,
+			uid:   owner,
+			buildStubs: func(store *mockdb.MockStore, cache db.Cache) {
+				store.EXPECT().
+					GetCache(gomock.Any(), gomock.Eq(cache.ID)).
+					Times(1).
+					Return(cache, nil)
+				store.EXPECT().
+					RequestCacheSnapshot(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.CacheSnapshot{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			buildStubs := func(store *mockdb.MockStore) {
+				tc.buildStubs(store, tc.cache)
+			}
+			url := fmt.Sprintf("/api/caches/%d/snapshot", tc.cache.ID)
+			recorder := serveTestRequest(t, buildStubs, tc.uid, http.MethodPost, url, nil)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
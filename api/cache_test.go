@@ -0,0 +1,1129 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/imrishuroy/read-cache-api/db/mock"
+	db "github.com/imrishuroy/read-cache-api/db/sqlc"
+	"github.com/imrishuroy/read-cache-api/util"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetCacheAPI(t *testing.T) {
+	owner := util.RandomOwner()
+	privateCache := randomCache(owner, false)
+	publicCache := randomCache(owner, true)
+
+	testCases := []struct {
+		name          string
+		cacheID       int64
+		uid           string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:    "OwnerPrivate",
+			cacheID: privateCache.ID,
+			uid:     owner,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetCache(gomock.Any(), gomock.Eq(privateCache.ID)).
+					Times(1).
+					Return(privateCache, nil)
+				store.EXPECT().
+					ListLikedCacheIDs(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return([]int64{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchCache(t, recorder.Body, privateCache)
+			},
+		},
+		{
+			name:    "OtherUserPublic",
+			cacheID: publicCache.ID,
+			uid:     util.RandomOwner(),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetCache(gomock.Any(), gomock.Eq(publicCache.ID)).
+					Times(1).
+					Return(publicCache, nil)
+				store.EXPECT().
+					ListLikedCacheIDs(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return([]int64{publicCache.ID}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp cacheResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.Equal(t, publicCache, rsp.Cache)
+				require.True(t, rsp.LikedByMe)
+			},
+		},
+		{
+			name:    "OtherUserPrivate",
+			cacheID: privateCache.ID,
+			uid:     util.RandomOwner(),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetCache(gomock.Any(), gomock.Eq(privateCache.ID)).
+					Times(1).
+					Return(privateCache, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:    "NotFound",
+			cacheID: privateCache.ID,
+			uid:     owner,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetCache(gomock.Any(), gomock.Eq(privateCache.ID)).
+					Times(1).
+					Return(db.Cache{}, db.ErrRecordNotFound)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:    "InternalError",
+			cacheID: privateCache.ID,
+			uid:     owner,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetCache(gomock.Any(), gomock.Eq(privateCache.ID)).
+					Times(1).
+					Return(db.Cache{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+		{
+			name:    "InvalidID",
+			cacheID: 0,
+			uid:     owner,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetCache(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := serveTestRequest(t, tc.buildStubs, tc.uid, http.MethodGet, fmt.Sprintf("/api/caches/%d", tc.cacheID), nil)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestUpdateCacheAPI(t *testing.T) {
+	owner := util.RandomOwner()
+	privateCache := randomCache(owner, false)
+	publicCache := randomCache(owner, true)
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		uid           string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{"id": privateCache.ID, "title": "new title", "content": privateCache.Content},
+			uid:  owner,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetCache(gomock.Any(), gomock.Eq(privateCache.ID)).
+					Times(1).
+					Return(privateCache, nil)
+
+				arg := db.UpdateCacheParams{
+					ID:       privateCache.ID,
+					Title:    "new title",
+					Content:  privateCache.Content,
+					IsPublic: pgtype.Bool{Bool: false, Valid: true},
+				}
+				store.EXPECT().
+					UpdateCacheTx(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(privateCache, nil)
+				store.EXPECT().
+					ListLikedCacheIDs(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return([]int64{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "OtherUserPublic",
+			body: gin.H{"id": publicCache.ID, "title": "new title", "content": publicCache.Content},
+			uid:  util.RandomOwner(),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetCache(gomock.Any(), gomock.Eq(publicCache.ID)).
+					Times(1).
+					Return(publicCache, nil)
+				store.EXPECT().
+					UpdateCacheTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "OtherUserPrivate",
+			body: gin.H{"id": privateCache.ID, "title": "new title", "content": privateCache.Content},
+			uid:  util.RandomOwner(),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetCache(gomock.Any(), gomock.Eq(privateCache.ID)).
+					Times(1).
+					Return(privateCache, nil)
+				store.EXPECT().
+					UpdateCacheTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "DuplicateLink",
+			body: gin.H{"id": privateCache.ID, "title": "new title", "content": "https://example.com/saved"},
+			uid:  owner,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetCache(gomock.Any(), gomock.Eq(privateCache.ID)).
+					Times(1).
+					Return(privateCache, nil)
+				store.EXPECT().
+					UpdateCacheTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Cache{}, db.ErrUniqueViolation)
+				store.EXPECT().
+					GetCacheByNormalizedURL(gomock.Any(), gomock.Eq(db.GetCacheByNormalizedURLParams{
+						Owner:         owner,
+						NormalizedUrl: "https://example.com/saved",
+					})).
+					Times(1).
+					Return(publicCache, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+				require.Contains(t, recorder.Body.String(), fmt.Sprintf(`"cache_id":%d`, publicCache.ID))
+			},
+		},
+		{
+			name: "BadRequest",
+			body: gin.H{"id": privateCache.ID},
+			uid:  owner,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetCache(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := serveTestRequest(t, tc.buildStubs, tc.uid, http.MethodPut, "/api/caches", tc.body)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestDeleteCacheAPI(t *testing.T) {
+	owner := util.RandomOwner()
+	privateCache := randomCache(owner, false)
+	publicCache := randomCache(owner, true)
+
+	testCases := []struct {
+		name          string
+		cache         db.Cache
+		uid           string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "OK",
+			cache: privateCache,
+			uid:   owner,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetCache(gomock.Any(), gomock.Eq(privateCache.ID)).
+					Times(1).
+					Return(privateCache, nil)
+				store.EXPECT().
+					DeleteCacheTx(gomock.Any(), gomock.Eq(privateCache.ID)).
+					Times(1).
+					Return(nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:  "OtherUserPublic",
+			cache: publicCache,
+			uid:   util.RandomOwner(),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetCache(gomock.Any(), gomock.Eq(publicCache.ID)).
+					Times(1).
+					Return(publicCache, nil)
+				store.EXPECT().
+					DeleteCacheTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:  "OtherUserPrivate",
+			cache: privateCache,
+			uid:   util.RandomOwner(),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetCache(gomock.Any(), gomock.Eq(privateCache.ID)).
+					Times(1).
+					Return(privateCache, nil)
+				store.EXPECT().
+					DeleteCacheTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := serveTestRequest(t, tc.buildStubs, tc.uid, http.MethodDelete, fmt.Sprintf("/api/caches/%d", tc.cache.ID), nil)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+// serveTestRequest runs a single authenticated request against a server backed by a mock store
+
+func randomCache(owner string, isPublic bool) db.Cache {
+	return db.Cache{
+		ID:       util.RandomInt(1, 1000),
+		Owner:    owner,
+		Title:    util.RandomTitle(),
+		Content:  util.RandomContent(),
+		IsPublic: pgtype.Bool{Bool: isPublic, Valid: true},
+	}
+}
+
+func requireBodyMatchCache(t *testing.T, body *bytes.Buffer, cache db.Cache) {
+	data, err := io.ReadAll(body)
+	require.NoError(t, err)
+
+	var gotCache db.Cache
+	err = json.Unmarshal(data, &gotCache)
+	require.NoError(t, err)
+	require.Equal(t, cache, gotCache)
+}
+
+func TestSearchCachesAPI(t *testing.T) {
+	uid := util.RandomOwner()
+
+	rows := make([]db.SearchCachesByCursorRow, 3)
+	for i := range rows {
+		rows[i] = db.SearchCachesByCursorRow{
+			Cache:          randomCache(uid, false),
+			Rank:           float32(3-i) / 10,
+			TitleHighlight: "<mark>read</mark> &lt;later&gt;",
+		}
+		rows[i].Cache.CreatedAt = time.Now()
+	}
+	cursor := pageCursor{CreatedAt: time.Now().UTC().Truncate(time.Microsecond), ID: 7, Rank: 0.25}
+
+	testCases := []struct {
+		name          string
+		query         string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "FirstPage",
+			query: `q="read+later"+go*&page_size=2`,
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.SearchCachesByCursorParams{
+					Query:     "(read <-> later) & go:*",
+					Owner:     uid,
+					PageLimit: 3,
+				}
+				store.EXPECT().
+					SearchCachesByCursor(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(rows, nil)
+				store.EXPECT().
+					ListLikedCacheIDs(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return([]int64{rows[0].Cache.ID}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp pageResponse[searchCacheResponse]
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.Len(t, rsp.Items, 2)
+				require.True(t, rsp.Items[0].LikedByMe)
+				require.Equal(t, rows[0].TitleHighlight, rsp.Items[0].TitleHighlight)
+				require.NotContains(t, recorder.Body.String(), "search_vector")
+
+				require.NotNil(t, rsp.NextCursor)
+				next, err := decodeCursor(*rsp.NextCursor)
+				require.NoError(t, err)
+				require.Equal(t, rows[1].Cache.ID, next.ID)
+				require.Equal(t, rows[1].Rank, next.Rank)
+			},
+		},
+		{
+			name:  "NextPage",
+			query: "q=go&cursor=" + encodeCursor(cursor),
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.SearchCachesByCursorParams{
+					Query:      "go",
+					Owner:      uid,
+					CursorRank: pgtype.Float4{Float32: cursor.Rank, Valid: true},
+					CursorID:   pgtype.Int8{Int64: cursor.ID, Valid: true},
+					PageLimit:  defaultPageSize + 1,
+				}
+				store.EXPECT().
+					SearchCachesByCursor(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return([]db.SearchCachesByCursorRow{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.JSONEq(t, `{"items": [], "next_cursor": null}`, recorder.Body.String())
+			},
+		},
+		{
+			name:  "LegacyPage",
+			query: "q=go&page_id=2&page_size=5",
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.SearchCachesParams{
+					Query:      "go",
+					Owner:      uid,
+					PageLimit:  5,
+					PageOffset: 5,
+				}
+				store.EXPECT().
+					SearchCaches(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return([]db.SearchCachesRow{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, "true", recorder.Header().Get("Deprecation"))
+				require.JSONEq(t, "[]", recorder.Body.String())
+			},
+		},
+		{
+			name:  "NothingSearchable",
+			query: "q=%21%21",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					SearchCachesByCursor(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.JSONEq(t, `{"items": [], "next_cursor": null}`, recorder.Body.String())
+			},
+		},
+		{
+			name:  "MissingQuery",
+			query: "page_size=5",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					SearchCachesByCursor(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := serveTestRequest(t, tc.buildStubs, uid, http.MethodGet, "/api/caches/search?"+tc.query, nil)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestListCachesAPI(t *testing.T) {
+	owner := util.RandomOwner()
+
+	n := 3
+	caches := make([]db.Cache, n)
+	for i := 0; i < n; i++ {
+		caches[i] = randomCache(owner, false)
+	}
+	cursor := pageCursor{CreatedAt: time.Now().UTC().Truncate(time.Microsecond), ID: 7}
+
+	testCases := []struct {
+		name          string
+		query         string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "FirstPage",
+			query: "page_size=2",
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.ListCachesByCursorParams{
+					Owner:     owner,
+					PageLimit: 3,
+				}
+				store.EXPECT().
+					ListCachesByCursor(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(caches, nil)
+				store.EXPECT().
+					ListLikedCacheIDs(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return([]int64{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp pageResponse[db.Cache]
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.Len(t, rsp.Items, 2)
+				require.NotNil(t, rsp.NextCursor)
+			},
+		},
+		{
+			name:  "NextPage",
+			query: "cursor=" + encodeCursor(cursor),
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.ListCachesByCursorParams{
+					Owner:           owner,
+					CursorCreatedAt: pgtype.Timestamptz{Time: cursor.CreatedAt, Valid: true},
+					CursorID:        pgtype.Int8{Int64: cursor.ID, Valid: true},
+					PageLimit:       defaultPageSize + 1,
+				}
+				store.EXPECT().
+					ListCachesByCursor(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(caches, nil)
+				store.EXPECT().
+					ListLikedCacheIDs(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return([]int64{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp pageResponse[db.Cache]
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.Len(t, rsp.Items, n)
+				require.Nil(t, rsp.NextCursor)
+			},
+		},
+		{
+			name:  "LegacyPageID",
+			query: "page_id=2&page_size=5",
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.ListCachesParams{
+					Owner:  owner,
+					Limit:  5,
+					Offset: 5,
+				}
+				store.EXPECT().
+					ListCaches(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(caches, nil)
+				store.EXPECT().
+					ListLikedCacheIDs(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return([]int64{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, "true", recorder.Header().Get("Deprecation"))
+
+				var rsp []db.Cache
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.Len(t, rsp, n)
+			},
+		},
+		{
+			name:  "LegacyPageSizeTooLarge",
+			query: "page_id=1&page_size=20",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListCaches(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "PageSizeTooLarge",
+			query: "page_size=51",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListCachesByCursor(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "InvalidCursor",
+			query: "cursor=garbage",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListCachesByCursor(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "MatchAllTags",
+			query: "tag_ids=1&tag_ids=2&tag_ids=1&mode=all&exclude_tag_ids=3",
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.ListCachesByCursorParams{
+					Owner:         owner,
+					TagIds:        []int32{1, 2},
+					MatchAll:      true,
+					ExcludeTagIds: []int32{3},
+					PageLimit:     defaultPageSize + 1,
+				}
+				store.EXPECT().
+					ListCachesByCursor(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(caches, nil)
+				store.EXPECT().
+					ListLikedCacheIDs(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return([]int64{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:  "StatusFilter",
+			query: "status=archived",
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.ListCachesByCursorParams{
+					Owner:     owner,
+					Status:    db.NullReadingStatus{ReadingStatus: db.ReadingStatusArchived, Valid: true},
+					PageLimit: defaultPageSize + 1,
+				}
+				store.EXPECT().
+					ListCachesByCursor(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(caches, nil)
+				store.EXPECT().
+					ListLikedCacheIDs(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return([]int64{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:  "StatusAll",
+			query: "status=all",
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.ListCachesByCursorParams{
+					Owner:           owner,
+					IncludeArchived: true,
+					PageLimit:       defaultPageSize + 1,
+				}
+				store.EXPECT().
+					ListCachesByCursor(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(caches, nil)
+				store.EXPECT().
+					ListLikedCacheIDs(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return([]int64{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:  "InvalidStatus",
+			query: "status=done",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListCachesByCursor(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "InvalidMode",
+			query: "tag_ids=1&mode=some",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListCachesByCursor(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := serveTestRequest(t, tc.buildStubs, owner, http.MethodGet, "/api/caches?"+tc.query, nil)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestListPublicCachesAPI(t *testing.T) {
+	uid := util.RandomOwner()
+
+	n := 3
+	caches := make([]db.Cache, n)
+	for i := 0; i < n; i++ {
+		caches[i] = randomCache(util.RandomOwner(), true)
+	}
+
+	testCases := []struct {
+		name          string
+		query         string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "NoFilter",
+			query: "",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListPublicCachesByCursor(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(caches, nil)
+				store.EXPECT().
+					ListPublicCachesByTagsCursor(gomock.Any(), gomock.Any()).
+					Times(0)
+				store.EXPECT().
+					ListLikedCacheIDs(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return([]int64{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:  "MatchAnyTags",
+			query: "tag_ids=4&tag_ids=5",
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.ListPublicCachesByTagsCursorParams{
+					TagIds:    []int32{4, 5},
+					PageLimit: defaultPageSize + 1,
+				}
+				store.EXPECT().
+					ListPublicCachesByTagsCursor(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(caches, nil)
+				store.EXPECT().
+					ListLikedCacheIDs(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return([]int64{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp pageResponse[db.Cache]
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.Len(t, rsp.Items, n)
+			},
+		},
+		{
+			name:  "ExcludeOnly",
+			query: "exclude_tag_ids=9",
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.ListPublicCachesByTagsCursorParams{
+					ExcludeTagIds: []int32{9},
+					PageLimit:     defaultPageSize + 1,
+				}
+				store.EXPECT().
+					ListPublicCachesByTagsCursor(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(caches, nil)
+				store.EXPECT().
+					ListLikedCacheIDs(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return([]int64{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:  "LegacyMatchAll",
+			query: "page_id=1&page_size=5&tag_ids=4&tag_ids=5&mode=all",
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.ListPublicCachesByTagsParams{
+					Limit:    5,
+					Offset:   0,
+					TagIds:   []int32{4, 5},
+					MatchAll: true,
+				}
+				store.EXPECT().
+					ListPublicCachesByTags(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(caches, nil)
+				store.EXPECT().
+					ListLikedCacheIDs(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return([]int64{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:  "InternalError",
+			query: "tag_ids=4",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListPublicCachesByTagsCursor(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return([]db.Cache{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := serveTestRequest(t, tc.buildStubs, uid, http.MethodGet, "/api/caches/public?"+tc.query, nil)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestCreateCacheAPI(t *testing.T) {
+	owner := util.RandomOwner()
+	cache := randomCache(owner, true)
+	existing := randomCache(owner, true)
+	existing.Content = "https://example.com/article"
+	existing.NormalizedUrl = "https://example.com/article"
+	existingTag := db.Tag{TagID: 1, TagName: util.RandomString(6)}
+	newTag := db.Tag{TagID: 2, TagName: util.RandomString(6)}
+
+	testCases := []struct {
+		name          string
+		query         string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "LinkQueuesPreview",
+			body: gin.H{
+				"title":     cache.Title,
+				"content":   " https://example.com/article ",
+				"is_public": true,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.CreateCacheWithTagsTxParams{
+					CreateCacheParams: db.CreateCacheParams{
+						Owner:         owner,
+						Title:         cache.Title,
+						Content:       " https://example.com/article ",
+						IsPublic:      pgtype.Bool{Bool: true, Valid: true},
+						NormalizedUrl: "https://example.com/article",
+					},
+					LinkURL: "https://example.com/article",
+				}
+				store.EXPECT().
+					CreateCacheWithTagsTx(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(db.CreateCacheWithTagsTxResult{Cache: cache}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchCache(t, recorder.Body, cache)
+			},
+		},
+		{
+			name: "Private",
+			body: gin.H{
+				"title":     cache.Title,
+				"content":   "a private note",
+				"is_public": false,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateCacheWithTagsTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreateCacheWithTagsTxParams) (db.CreateCacheWithTagsTxResult, error) {
+						require.Equal(t, pgtype.Bool{Bool: false, Valid: true}, arg.IsPublic)
+						return db.CreateCacheWithTagsTxResult{Cache: cache}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "RepeatedTagIDs",
+			body: gin.H{
+				"title":     cache.Title,
+				"content":   "a note",
+				"is_public": true,
+				"tag_ids":   []int32{existingTag.TagID, newTag.TagID, existingTag.TagID},
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateCacheWithTagsTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreateCacheWithTagsTxParams) (db.CreateCacheWithTagsTxResult, error) {
+						require.Equal(t, []int32{existingTag.TagID, newTag.TagID}, arg.TagIDs)
+						return db.CreateCacheWithTagsTxResult{Cache: cache, Tags: []db.Tag{existingTag, newTag}}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "MissingVisibility",
+			body: gin.H{
+				"title":   cache.Title,
+				"content": "a note",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateCacheWithTagsTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "NoteSkipsPreview",
+			body: gin.H{
+				"title":     cache.Title,
+				"content":   "remember to read the go memory model",
+				"is_public": true,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateCacheWithTagsTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreateCacheWithTagsTxParams) (db.CreateCacheWithTagsTxResult, error) {
+						require.Empty(t, arg.LinkURL)
+						return db.CreateCacheWithTagsTxResult{Cache: cache}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "DuplicateLink",
+			body: gin.H{
+				"title":     cache.Title,
+				"content":   "http://EXAMPLE.com/article/?utm_source=newsletter",
+				"is_public": true,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateCacheWithTagsTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreateCacheWithTagsTxParams) (db.CreateCacheWithTagsTxResult, error) {
+						require.Equal(t, existing.NormalizedUrl, arg.NormalizedUrl)
+						return db.CreateCacheWithTagsTxResult{}, db.ErrUniqueViolation
+					})
+				store.EXPECT().
+					GetCacheByNormalizedURL(gomock.Any(), gomock.Eq(db.GetCacheByNormalizedURLParams{
+						Owner:         owner,
+						NormalizedUrl: existing.NormalizedUrl,
+					})).
+					Times(1).
+					Return(existing, nil)
+				store.EXPECT().
+					SetCacheTagsTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+
+				var rsp struct {
+					CacheID int64 `json:"cache_id"`
+				}
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.Equal(t, existing.ID, rsp.CacheID)
+			},
+		},
+		{
+			name:  "DuplicateLinkMerge",
+			query: "?on_duplicate=merge",
+			body: gin.H{
+				"title":     cache.Title,
+				"content":   "https://example.com/article#intro",
+				"is_public": true,
+				"tag_ids":   []int32{existingTag.TagID, newTag.TagID},
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateCacheWithTagsTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.CreateCacheWithTagsTxResult{}, db.ErrUniqueViolation)
+				store.EXPECT().
+					GetCacheByNormalizedURL(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(existing, nil)
+				store.EXPECT().
+					ListCacheTags(gomock.Any(), gomock.Eq(existing.ID)).
+					Times(1).
+					Return([]db.Tag{existingTag}, nil)
+				arg := db.SetCacheTagsTxParams{
+					CacheID: existing.ID,
+					TagIDs:  []int32{newTag.TagID},
+				}
+				store.EXPECT().
+					SetCacheTagsTx(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(db.SetCacheTagsTxResult{Tags: []db.Tag{existingTag, newTag}}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp cacheWithTagsResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.Equal(t, existing.ID, rsp.ID)
+				require.Equal(t, []db.Tag{existingTag, newTag}, rsp.Tags)
+			},
+		},
+		{
+			name: "UniqueViolationWithoutDuplicate",
+			body: gin.H{
+				"title":     cache.Title,
+				"content":   "https://example.com/other",
+				"is_public": true,
+				"tag_ids":   []int32{1, 1},
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateCacheWithTagsTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.CreateCacheWithTagsTxResult{}, db.ErrUniqueViolation)
+				store.EXPECT().
+					GetCacheByNormalizedURL(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Cache{}, db.ErrRecordNotFound)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:  "InvalidOnDuplicate",
+			query: "?on_duplicate=ignore",
+			body: gin.H{
+				"title":     cache.Title,
+				"content":   cache.Content,
+				"is_public": true,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateCacheWithTagsTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "MissingTitle",
+			body: gin.H{
+				"content":   cache.Content,
+				"is_public": true,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateCacheWithTagsTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := serveTestRequest(t, tc.buildStubs, owner, http.MethodPost, "/api/caches"+tc.query, tc.body)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -0,0 +1,341 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/imrishuroy/read-cache-api/auth"
+	db "github.com/imrishuroy/read-cache-api/db/sqlc"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var (
+	errCollectionNameRequired = errors.New("name is required")
+	errCacheNotInCollection   = errors.New("cache is not in the collection")
+	errLegacyPageCollection   = errors.New("page_id can't be combined with collection_id, use cursor")
+)
+
+// collectionResponse is a collection with the caller's role in it
+type collectionResponse struct {
+	db.Collection
+	Role db.CollectionRole `json:"role,omitempty"`
+}
+
+type collectionRequest struct {
+	Name        string `json:"name" binding:"required,max=100"`
+	Description string `json:"description" binding:"max=1000"`
+	Icon        string `json:"icon" binding:"max=64"`
+	IsPublic    bool   `json:"is_public"`
+}
+
+type collectionURI struct {
+	CollectionID int64 `uri:"collection_id" binding:"required,min=1"`
+}
+
+func (server *Server) createCollection(ctx *gin.Context) {
+	var req collectionRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		ctx.JSON(http.StatusBadRequest, errorResponse(errCollectionNameRequired))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*auth.Principal)
+
+	arg := db.CreateCollectionParams{
+		Owner:       authPayload.UID,
+		Name:        name,
+		Description: strings.TrimSpace(req.Description),
+		Icon:        strings.TrimSpace(req.Icon),
+		IsPublic:    req.IsPublic,
+	}
+
+	collection, err := server.store.CreateCollectionTx(ctx, arg)
+	if err != nil {
+		switch db.ErrorCode(err) {
+		case db.UniqueViolation:
+			ctx.JSON(http.StatusForbidden, gin.H{"error": name + " already exists"})
+			return
+		case db.ForeignKeyViolation:
+			ctx.JSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, collectionResponse{Collection: collection, Role: db.CollectionRoleOwner})
+}
+
+// listCollections lists the collections the caller is a member of by name,
+// with the caller's role and the cache counts
+func (server *Server) listCollections(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*auth.Principal)
+
+	collections, err := server.store.ListCollections(ctx, authPayload.UID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, collections)
+}
+
+func (server *Server) getCollection(ctx *gin.Context) {
+	var req collectionURI
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	collection, role, ok := server.authorizeCollection(ctx, req.CollectionID, viewCollection)
+	if !ok {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, collectionResponse{Collection: collection, Role: role})
+}
+
+func (server *Server) updateCollection(ctx *gin.Context) {
+	var uri collectionURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req collectionRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		ctx.JSON(http.StatusBadRequest, errorResponse(errCollectionNameRequired))
+		return
+	}
+
+	_, role, ok := server.authorizeCollection(ctx, uri.CollectionID, manageCollection)
+	if !ok {
+		return
+	}
+
+	arg := db.UpdateCollectionParams{
+		ID:          uri.CollectionID,
+		Name:        name,
+		Description: strings.TrimSpace(req.Description),
+		Icon:        strings.TrimSpace(req.Icon),
+		IsPublic:    req.IsPublic,
+	}
+
+	collection, err := server.store.UpdateCollection(ctx, arg)
+	if err != nil {
+		if db.ErrorCode(err) == db.UniqueViolation {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": name + " already exists"})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, collectionResponse{Collection: collection, Role: role})
+}
+
+// deleteCollection removes a collection and its memberships, the caches stay
+func (server *Server) deleteCollection(ctx *gin.Context) {
+	var req collectionURI
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if _, _, ok := server.authorizeCollection(ctx, req.CollectionID, manageCollection); !ok {
+		return
+	}
+
+	if err := server.store.DeleteCollection(ctx, req.CollectionID); err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse())
+}
+
+type addCacheToCollectionRequest struct {
+	CacheID int64 `json:"cache_id" binding:"required,min=1"`
+}
+
+// addCacheToCollection appends one of the caller's caches to a collection
+// they can edit, adding a cache that is already there keeps its position
+func (server *Server) addCacheToCollection(ctx *gin.Context) {
+	var uri collectionURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req addCacheToCollectionRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if _, _, ok := server.authorizeCollection(ctx, uri.CollectionID, editCollection); !ok {
+		return
+	}
+	if _, ok := server.authorizeCache(ctx, req.CacheID, editCache); !ok {
+		return
+	}
+
+	_, err := server.store.AddCacheToCollectionTx(ctx, db.AddCacheToCollectionParams{
+		CollectionID: uri.CollectionID,
+		CacheID:      req.CacheID,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			// the collection was deleted after it was authorized
+			ctx.JSON(http.StatusNotFound, errorResponse(errCollectionNotFound))
+			return
+		}
+		if db.ErrorCode(err) == db.ForeignKeyViolation {
+			ctx.JSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse())
+}
+
+type collectionCacheURI struct {
+	CollectionID int64 `uri:"collection_id" binding:"required,min=1"`
+	CacheID      int64 `uri:"cache_id" binding:"required,min=1"`
+}
+
+func (server *Server) removeCacheFromCollection(ctx *gin.Context) {
+	var req collectionCacheURI
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if _, _, ok := server.authorizeCollection(ctx, req.CollectionID, editCollection); !ok {
+		return
+	}
+
+	rows, err := server.store.RemoveCacheFromCollection(ctx, db.RemoveCacheFromCollectionParams{
+		CollectionID: req.CollectionID,
+		CacheID:      req.CacheID,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	if rows == 0 {
+		ctx.JSON(http.StatusNotFound, errorResponse(errCacheNotInCollection))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse())
+}
+
+type reorderCollectionRequest struct {
+	CacheIDs []int64 `json:"cache_ids" binding:"required,dive,min=1"`
+}
+
+// reorderCollection takes every cache of the collection in its new order
+func (server *Server) reorderCollection(ctx *gin.Context) {
+	var uri collectionURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req reorderCollectionRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if _, _, ok := server.authorizeCollection(ctx, uri.CollectionID, editCollection); !ok {
+		return
+	}
+
+	err := server.store.ReorderCollectionTx(ctx, db.ReorderCollectionTxParams{
+		CollectionID: uri.CollectionID,
+		CacheIDs:     req.CacheIDs,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrCollectionOrderMismatch) {
+			ctx.JSON(http.StatusConflict, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse())
+}
+
+// listCollectionCaches serves GET /caches?collection_id= in the collection's
+// manual order. Like everywhere else a cache is listed when it is public or
+// the caller's own, being a member doesn't open up the private caches others added.
+func (server *Server) listCollectionCaches(ctx *gin.Context, req listCacheRequest, p page) {
+	if p.legacy {
+		ctx.JSON(http.StatusBadRequest, errorResponse(errLegacyPageCollection))
+		return
+	}
+
+	if _, _, ok := server.authorizeCollection(ctx, req.CollectionID, viewCollection); !ok {
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*auth.Principal)
+	status, includeArchived := readingStatusFilter(req.Status)
+
+	arg := db.ListCollectionCachesParams{
+		CollectionID:    req.CollectionID,
+		Viewer:          authPayload.UID,
+		TagIds:          uniqueTagIDs(req.TagIDs),
+		MatchAll:        req.matchAll(),
+		ExcludeTagIds:   uniqueTagIDs(req.ExcludeTagIDs),
+		Status:          status,
+		IncludeArchived: includeArchived,
+		PageLimit:       p.fetchLimit(),
+	}
+	if p.cursor != nil {
+		arg.CursorPosition = pgtype.Int4{Int32: p.cursor.Position, Valid: true}
+		arg.CursorID = p.cursorID()
+	}
+
+	rows, err := server.store.ListCollectionCaches(ctx, arg)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rowPage := newPageResponse(rows, p, func(row db.ListCollectionCachesRow) pageCursor {
+		return pageCursor{CreatedAt: row.Cache.CreatedAt, ID: row.Cache.ID, Position: row.Position}
+	})
+
+	caches := make([]db.Cache, len(rowPage.Items))
+	for i, row := range rowPage.Items {
+		caches[i] = row.Cache
+	}
+
+	items, err := server.newCacheResponses(ctx, caches)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, pageResponse[cacheResponse]{Items: items, NextCursor: rowPage.NextCursor})
+}
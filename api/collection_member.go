@@ -0,0 +1,357 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/imrishuroy/read-cache-api/auth"
+	db "github.com/imrishuroy/read-cache-api/db/sqlc"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var (
+	errInviteeRequired    = errors.New("either email or user_id is required")
+	errInviteeNotFound    = errors.New("user not found")
+	errAlreadyMember      = errors.New("user is already a member of the collection")
+	errAlreadyInvited     = errors.New("user already has a pending invitation to the collection")
+	errMemberNotFound     = errors.New("user is not a member of the collection")
+	errInvitationNotFound = errors.New("invitation not found")
+	errMembersOnly        = errors.New("only members can see who else is in a collection")
+)
+
+func (server *Server) listCollectionMembers(ctx *gin.Context) {
+	var req collectionURI
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	_, role, ok := server.authorizeCollection(ctx, req.CollectionID, viewCollection)
+	if !ok {
+		return
+	}
+	if role == "" {
+		ctx.JSON(http.StatusForbidden, errorResponse(errMembersOnly))
+		return
+	}
+
+	members, err := server.store.ListCollectionMembers(ctx, req.CollectionID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, members)
+}
+
+type collectionMemberURI struct {
+	CollectionID int64  `uri:"collection_id" binding:"required,min=1"`
+	UserID       string `uri:"user_id" binding:"required"`
+}
+
+type updateCollectionMemberRequest struct {
+	Role string `json:"role" binding:"required,oneof=viewer editor owner"`
+}
+
+func (server *Server) updateCollectionMember(ctx *gin.Context) {
+	var uri collectionMemberURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req updateCollectionMemberRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if _, _, ok := server.authorizeCollection(ctx, uri.CollectionID, manageCollection); !ok {
+		return
+	}
+
+	member, err := server.store.UpdateCollectionMemberTx(ctx, db.UpdateCollectionMemberRoleParams{
+		CollectionID: uri.CollectionID,
+		UserID:       uri.UserID,
+		Role:         db.CollectionRole(req.Role),
+	})
+	if err != nil {
+		server.collectionMemberError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, member)
+}
+
+// removeCollectionMember lets owners remove anyone and every member leave,
+// as long as the collection keeps an owner
+func (server *Server) removeCollectionMember(ctx *gin.Context) {
+	var uri collectionMemberURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*auth.Principal)
+	action := manageCollection
+	if uri.UserID == authPayload.UID {
+		action = viewCollection
+	}
+
+	if _, _, ok := server.authorizeCollection(ctx, uri.CollectionID, action); !ok {
+		return
+	}
+
+	err := server.store.RemoveCollectionMemberTx(ctx, db.DeleteCollectionMemberParams{
+		CollectionID: uri.CollectionID,
+		UserID:       uri.UserID,
+	})
+	if err != nil {
+		server.collectionMemberError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse())
+}
+
+func (server *Server) collectionMemberError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, db.ErrRecordNotFound):
+		ctx.JSON(http.StatusNotFound, errorResponse(errMemberNotFound))
+	case errors.Is(err, db.ErrLastCollectionOwner):
+		ctx.JSON(http.StatusConflict, errorResponse(err))
+	default:
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+	}
+}
+
+type createCollectionInvitationRequest struct {
+	Email  string `json:"email" binding:"omitempty,email"`
+	UserID string `json:"user_id"`
+	Role   string `json:"role" binding:"required,oneof=viewer editor owner"`
+}
+
+// createCollectionInvitation invites a user by email or UID. Addresses
+// without an account yet are invited too, the invitation waits for the
+// user signing up with that email.
+func (server *Server) createCollectionInvitation(ctx *gin.Context) {
+	var uri collectionURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req createCollectionInvitationRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	email := strings.ToLower(strings.TrimSpace(req.Email))
+	userID := strings.TrimSpace(req.UserID)
+	if (email == "") == (userID == "") {
+		ctx.JSON(http.StatusBadRequest, errorResponse(errInviteeRequired))
+		return
+	}
+
+	if _, _, ok := server.authorizeCollection(ctx, uri.CollectionID, manageCollection); !ok {
+		return
+	}
+
+	var invitee db.User
+	var err error
+	if userID != "" {
+		invitee, err = server.store.GetUser(ctx, userID)
+	} else {
+		invitee, err = server.store.GetUserByEmail(ctx, email)
+	}
+	switch {
+	case err == nil:
+		email = invitee.Email
+	case !errors.Is(err, db.ErrRecordNotFound):
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	case userID != "":
+		ctx.JSON(http.StatusNotFound, errorResponse(errInviteeNotFound))
+		return
+	}
+
+	if invitee.ID != "" {
+		_, err = server.store.GetCollectionMember(ctx, db.GetCollectionMemberParams{
+			CollectionID: uri.CollectionID,
+			UserID:       invitee.ID,
+		})
+		if err == nil {
+			ctx.JSON(http.StatusForbidden, errorResponse(errAlreadyMember))
+			return
+		}
+		if !errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*auth.Principal)
+	arg := db.CreateCollectionInvitationParams{
+		CollectionID: uri.CollectionID,
+		Inviter:      authPayload.UID,
+		InviteeEmail: email,
+		InviteeID:    pgtype.Text{String: invitee.ID, Valid: invitee.ID != ""},
+		Role:         db.CollectionRole(req.Role),
+	}
+
+	invitation, err := server.store.CreateCollectionInvitation(ctx, arg)
+	if err != nil {
+		switch db.ErrorCode(err) {
+		case db.UniqueViolation:
+			ctx.JSON(http.StatusForbidden, errorResponse(errAlreadyInvited))
+			return
+		case db.ForeignKeyViolation:
+			ctx.JSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, invitation)
+}
+
+func (server *Server) listCollectionInvitations(ctx *gin.Context) {
+	var req collectionURI
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if _, _, ok := server.authorizeCollection(ctx, req.CollectionID, manageCollection); !ok {
+		return
+	}
+
+	invitations, err := server.store.ListCollectionInvitations(ctx, req.CollectionID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, invitations)
+}
+
+type collectionInvitationURI struct {
+	CollectionID int64 `uri:"collection_id" binding:"required,min=1"`
+	InvitationID int64 `uri:"invitation_id" binding:"required,min=1"`
+}
+
+func (server *Server) revokeCollectionInvitation(ctx *gin.Context) {
+	var req collectionInvitationURI
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if _, _, ok := server.authorizeCollection(ctx, req.CollectionID, manageCollection); !ok {
+		return
+	}
+
+	invitation, err := server.store.RevokeCollectionInvitation(ctx, db.RevokeCollectionInvitationParams{
+		ID:           req.InvitationID,
+		CollectionID: req.CollectionID,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(errInvitationNotFound))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, invitation)
+}
+
+// invitee returns the caller and the email their invitations are matched on.
+// Only the token's verified email is trusted, not users.email, which is whatever
+// the client registered with. Callers without one, such as personal access
+// tokens or phone sign-ins, are matched on their UID alone.
+func invitee(ctx *gin.Context) (*auth.Principal, string) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*auth.Principal)
+	return authPayload, authPayload.VerifiedEmail()
+}
+
+// listMyInvitations lists the pending invitations addressed to the caller's UID or verified email
+func (server *Server) listMyInvitations(ctx *gin.Context) {
+	authPayload, email := invitee(ctx)
+
+	invitations, err := server.store.ListPendingInvitationsForUser(ctx, db.ListPendingInvitationsForUserParams{
+		UserID: authPayload.UID,
+		Email:  email,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, invitations)
+}
+
+type invitationURI struct {
+	InvitationID int64 `uri:"invitation_id" binding:"required,min=1"`
+}
+
+func (server *Server) acceptInvitation(ctx *gin.Context) {
+	var req invitationURI
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload, email := invitee(ctx)
+
+	member, err := server.store.AcceptCollectionInvitationTx(ctx, db.RespondToCollectionInvitationParams{
+		UserID: authPayload.UID,
+		ID:     req.InvitationID,
+		Email:  email,
+	})
+	if err != nil {
+		// invitations addressed to someone else look the same as missing ones
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(errInvitationNotFound))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, member)
+}
+
+// declineInvitation closes an invitation on behalf of its invitee, owners see
+// it as declined rather than revoked
+func (server *Server) declineInvitation(ctx *gin.Context) {
+	var req invitationURI
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload, email := invitee(ctx)
+
+	invitation, err := server.store.RespondToCollectionInvitation(ctx, db.RespondToCollectionInvitationParams{
+		Status: db.InvitationStatusDeclined,
+		UserID: authPayload.UID,
+		ID:     req.InvitationID,
+		Email:  email,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(errInvitationNotFound))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, invitation)
+}
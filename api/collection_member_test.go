@@ -0,0 +1,643 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/imrishuroy/read-cache-api/auth"
+	mockdb "github.com/imrishuroy/read-cache-api/db/mock"
+	db "github.com/imrishuroy/read-cache-api/db/sqlc"
+	"github.com/imrishuroy/read-cache-api/util"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListCollectionMembersAPI(t *testing.T) {
+	owner := util.RandomOwner()
+	collection := randomCollection(owner, true)
+
+	testCases := []struct {
+		name          string
+		uid           string
+		role          db.CollectionRole
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "Viewer",
+			uid:  util.RandomOwner(),
+			role: db.CollectionRoleViewer,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListCollectionMembers(gomock.Any(), gomock.Eq(collection.ID)).
+					Times(1).
+					Return([]db.ListCollectionMembersRow{{CollectionID: collection.ID, UserID: owner, Role: db.CollectionRoleOwner}}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "PublicButNotMember",
+			uid:  util.RandomOwner(),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListCollectionMembers(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := serveTestRequest(t, func(store *mockdb.MockStore) {
+				stubCollectionRole(store, collection, tc.uid, tc.role)
+				tc.buildStubs(store)
+			}, tc.uid, http.MethodGet, fmt.Sprintf("/api/collections/%d/members", collection.ID), nil)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestUpdateCollectionMemberAPI(t *testing.T) {
+	owner := util.RandomOwner()
+	member := util.RandomOwner()
+	collection := randomCollection(owner, false)
+
+	testCases := []struct {
+		name          string
+		uid           string
+		role          db.CollectionRole
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			uid:  owner,
+			role: db.CollectionRoleOwner,
+			body: gin.H{"role": "editor"},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.UpdateCollectionMemberRoleParams{
+					CollectionID: collection.ID,
+					UserID:       member,
+					Role:         db.CollectionRoleEditor,
+				}
+				store.EXPECT().
+					UpdateCollectionMemberTx(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(db.CollectionMember{CollectionID: collection.ID, UserID: member, Role: db.CollectionRoleEditor}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp db.CollectionMember
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.Equal(t, db.CollectionRoleEditor, rsp.Role)
+			},
+		},
+		{
+			name: "LastOwner",
+			uid:  owner,
+			role: db.CollectionRoleOwner,
+			body: gin.H{"role": "viewer"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpdateCollectionMemberTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.CollectionMember{}, db.ErrLastCollectionOwner)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name: "NotAMember",
+			uid:  owner,
+			role: db.CollectionRoleOwner,
+			body: gin.H{"role": "viewer"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpdateCollectionMemberTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.CollectionMember{}, db.ErrRecordNotFound)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "EditorCantChangeRoles",
+			uid:  util.RandomOwner(),
+			role: db.CollectionRoleEditor,
+			body: gin.H{"role": "owner"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpdateCollectionMemberTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := serveTestRequest(t, func(store *mockdb.MockStore) {
+				stubCollectionRole(store, collection, tc.uid, tc.role)
+				tc.buildStubs(store)
+			}, tc.uid, http.MethodPut, fmt.Sprintf("/api/collections/%d/members/%s", collection.ID, member), tc.body)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestUpdateCollectionMemberInvalidRole(t *testing.T) {
+	recorder := serveTestRequest(t, func(store *mockdb.MockStore) {
+		store.EXPECT().
+			GetCollection(gomock.Any(), gomock.Any()).
+			Times(0)
+	}, util.RandomOwner(), http.MethodPut, "/api/collections/1/members/someone", gin.H{"role": "admin"})
+
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+}
+
+func TestRemoveCollectionMemberAPI(t *testing.T) {
+	owner := util.RandomOwner()
+	viewer := util.RandomOwner()
+	collection := randomCollection(owner, false)
+
+	testCases := []struct {
+		name          string
+		uid           string
+		role          db.CollectionRole
+		target        string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "OwnerRemovesViewer",
+			uid:    owner,
+			role:   db.CollectionRoleOwner,
+			target: viewer,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					RemoveCollectionMemberTx(gomock.Any(), gomock.Eq(db.DeleteCollectionMemberParams{
+						CollectionID: collection.ID,
+						UserID:       viewer,
+					})).
+					Times(1).
+					Return(nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:   "ViewerLeaves",
+			uid:    viewer,
+			role:   db.CollectionRoleViewer,
+			target: viewer,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					RemoveCollectionMemberTx(gomock.Any(), gomock.Eq(db.DeleteCollectionMemberParams{
+						CollectionID: collection.ID,
+						UserID:       viewer,
+					})).
+					Times(1).
+					Return(nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:   "LastOwnerLeaves",
+			uid:    owner,
+			role:   db.CollectionRoleOwner,
+			target: owner,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					RemoveCollectionMemberTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.ErrLastCollectionOwner)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name:   "ViewerRemovesOwner",
+			uid:    viewer,
+			role:   db.CollectionRoleViewer,
+			target: owner,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					RemoveCollectionMemberTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := serveTestRequest(t, func(store *mockdb.MockStore) {
+				stubCollectionRole(store, collection, tc.uid, tc.role)
+				tc.buildStubs(store)
+			}, tc.uid, http.MethodDelete, fmt.Sprintf("/api/collections/%d/members/%s", collection.ID, tc.target), nil)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestCreateCollectionInvitationAPI(t *testing.T) {
+	owner := util.RandomOwner()
+	collection := randomCollection(owner, false)
+	invitee := db.User{ID: util.RandomOwner(), Email: "reader@example.com"}
+
+	testCases := []struct {
+		name          string
+		role          db.CollectionRole
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "ByUserID",
+			role: db.CollectionRoleOwner,
+			body: gin.H{"user_id": invitee.ID, "role": "editor"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(invitee.ID)).
+					Times(1).
+					Return(invitee, nil)
+				store.EXPECT().
+					GetCollectionMember(gomock.Any(), gomock.Eq(db.GetCollectionMemberParams{CollectionID: collection.ID, UserID: invitee.ID})).
+					Times(1).
+					Return(db.CollectionMember{}, db.ErrRecordNotFound)
+
+				arg := db.CreateCollectionInvitationParams{
+					CollectionID: collection.ID,
+					Inviter:      owner,
+					InviteeEmail: invitee.Email,
+					InviteeID:    pgtype.Text{String: invitee.ID, Valid: true},
+					Role:         db.CollectionRoleEditor,
+				}
+				store.EXPECT().
+					CreateCollectionInvitation(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(db.CollectionInvitation{ID: 1, CollectionID: collection.ID, Status: db.InvitationStatusPending}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "EmailWithoutAccount",
+			role: db.CollectionRoleOwner,
+			body: gin.H{"email": "New@Example.com", "role": "viewer"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserByEmail(gomock.Any(), gomock.Eq("new@example.com")).
+					Times(1).
+					Return(db.User{}, db.ErrRecordNotFound)
+
+				arg := db.CreateCollectionInvitationParams{
+					CollectionID: collection.ID,
+					Inviter:      owner,
+					InviteeEmail: "new@example.com",
+					Role:         db.CollectionRoleViewer,
+				}
+				store.EXPECT().
+					CreateCollectionInvitation(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(db.CollectionInvitation{ID: 2}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "AlreadyMember",
+			role: db.CollectionRoleOwner,
+			body: gin.H{"email": invitee.Email, "role": "viewer"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserByEmail(gomock.Any(), gomock.Eq(invitee.Email)).
+					Times(1).
+					Return(invitee, nil)
+				store.EXPECT().
+					GetCollectionMember(gomock.Any(), gomock.Eq(db.GetCollectionMemberParams{CollectionID: collection.ID, UserID: invitee.ID})).
+					Times(1).
+					Return(db.CollectionMember{Role: db.CollectionRoleViewer}, nil)
+				store.EXPECT().
+					CreateCollectionInvitation(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "AlreadyInvited",
+			role: db.CollectionRoleOwner,
+			body: gin.H{"email": "new@example.com", "role": "viewer"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserByEmail(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.User{}, db.ErrRecordNotFound)
+				store.EXPECT().
+					CreateCollectionInvitation(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.CollectionInvitation{}, db.ErrUniqueViolation)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "UnknownUserID",
+			role: db.CollectionRoleOwner,
+			body: gin.H{"user_id": "nobody", "role": "viewer"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq("nobody")).
+					Times(1).
+					Return(db.User{}, db.ErrRecordNotFound)
+				store.EXPECT().
+					CreateCollectionInvitation(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "EditorCantInvite",
+			role: db.CollectionRoleEditor,
+			body: gin.H{"email": "new@example.com", "role": "viewer"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateCollectionInvitation(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := serveTestRequest(t, func(store *mockdb.MockStore) {
+				stubCollectionRole(store, collection, owner, tc.role)
+				tc.buildStubs(store)
+			}, owner, http.MethodPost, fmt.Sprintf("/api/collections/%d/invitations", collection.ID), tc.body)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestCreateCollectionInvitationNeedsOneInvitee(t *testing.T) {
+	for _, body := range []gin.H{
+		{"role": "viewer"},
+		{"email": "a@example.com", "user_id": "someone", "role": "viewer"},
+	} {
+		recorder := serveTestRequest(t, func(store *mockdb.MockStore) {
+			store.EXPECT().
+				GetCollection(gomock.Any(), gomock.Any()).
+				Times(0)
+		}, util.RandomOwner(), http.MethodPost, "/api/collections/1/invitations", body)
+
+		require.Equal(t, http.StatusBadRequest, recorder.Code)
+	}
+}
+
+func TestRevokeCollectionInvitationAPI(t *testing.T) {
+	owner := util.RandomOwner()
+	collection := randomCollection(owner, false)
+
+	testCases := []struct {
+		name          string
+		err           error
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "NotPending",
+			err:  db.ErrRecordNotFound,
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := serveTestRequest(t, func(store *mockdb.MockStore) {
+				stubCollectionRole(store, collection, owner, db.CollectionRoleOwner)
+				store.EXPECT().
+					RevokeCollectionInvitation(gomock.Any(), gomock.Eq(db.RevokeCollectionInvitationParams{
+						ID:           5,
+						CollectionID: collection.ID,
+					})).
+					Times(1).
+					Return(db.CollectionInvitation{ID: 5, Status: db.InvitationStatusRevoked}, tc.err)
+			}, owner, http.MethodDelete, fmt.Sprintf("/api/collections/%d/invitations/5", collection.ID), nil)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestListMyInvitationsAPI(t *testing.T) {
+	uid := util.RandomOwner()
+
+	recorder := serveTestRequest(t, func(store *mockdb.MockStore) {
+		store.EXPECT().
+			ListPendingInvitationsForUser(gomock.Any(), gomock.Eq(db.ListPendingInvitationsForUserParams{
+				UserID: uid,
+				Email:  uid + "@gmail.com",
+			})).
+			Times(1).
+			Return([]db.ListPendingInvitationsForUserRow{{ID: 1, CollectionName: "Team reads"}}, nil)
+	}, uid, http.MethodGet, "/api/invitations", nil)
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var rsp []db.ListPendingInvitationsForUserRow
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+	require.Len(t, rsp, 1)
+	require.Equal(t, "Team reads", rsp[0].CollectionName)
+}
+
+func TestAcceptInvitationAPI(t *testing.T) {
+	uid := util.RandomOwner()
+
+	testCases := []struct {
+		name          string
+		err           error
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp db.CollectionMember
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.Equal(t, db.CollectionRoleEditor, rsp.Role)
+			},
+		},
+		{
+			name: "NotForCaller",
+			err:  db.ErrRecordNotFound,
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := serveTestRequest(t, func(store *mockdb.MockStore) {
+				store.EXPECT().
+					AcceptCollectionInvitationTx(gomock.Any(), gomock.Eq(db.RespondToCollectionInvitationParams{
+						UserID: uid,
+						ID:     3,
+						Email:  uid + "@gmail.com",
+					})).
+					Times(1).
+					Return(db.CollectionMember{CollectionID: 9, UserID: uid, Role: db.CollectionRoleEditor}, tc.err)
+			}, uid, http.MethodPost, "/api/invitations/3/accept", nil)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestDeclineInvitationAPI(t *testing.T) {
+	uid := util.RandomOwner()
+
+	recorder := serveTestRequest(t, func(store *mockdb.MockStore) {
+		store.EXPECT().
+			RespondToCollectionInvitation(gomock.Any(), gomock.Eq(db.RespondToCollectionInvitationParams{
+				Status: db.InvitationStatusDeclined,
+				UserID: uid,
+				ID:     3,
+				Email:  uid + "@gmail.com",
+			})).
+			Times(1).
+			Return(db.CollectionInvitation{ID: 3, Status: db.InvitationStatusDeclined}, nil)
+	}, uid, http.MethodPost, "/api/invitations/3/decline", nil)
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+}
+
+func TestInvitationsWithoutVerifiedEmail(t *testing.T) {
+	uid := util.RandomOwner()
+	plainToken, hash, err := auth.NewPersonalAccessToken()
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name       string
+		setupAuth  func(t *testing.T, request *http.Request, server *Server)
+		buildStubs func(store *mockdb.MockStore)
+	}{
+		{
+			// a token without an email is only matched on its UID
+			name: "NoEmail",
+			setupAuth: func(t *testing.T, request *http.Request, server *Server) {
+				token, err := server.verifier.(*auth.JWTVerifier).CreateToken(uid, "", time.Minute)
+				require.NoError(t, err)
+				request.Header.Set(authorizationHeaderKey, authorizationTypeBearer+" "+token)
+			},
+			buildStubs: func(store *mockdb.MockStore) {},
+		},
+		{
+			name: "PersonalAccessToken",
+			setupAuth: func(t *testing.T, request *http.Request, server *Server) {
+				request.Header.Set(authorizationHeaderKey, authorizationTypeBearer+" "+plainToken)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetPersonalAccessTokenByHash(gomock.Any(), gomock.Eq(hash)).
+					Times(2).
+					Return(db.PersonalAccessToken{ID: 1, UserID: uid, Scope: auth.ScopeWrite}, nil)
+				store.EXPECT().
+					UpdatePersonalAccessTokenLastUsed(gomock.Any(), gomock.Any()).
+					Times(2).
+					Return(nil)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+			store.EXPECT().
+				ListPendingInvitationsForUser(gomock.Any(), gomock.Eq(db.ListPendingInvitationsForUserParams{
+					UserID: uid,
+				})).
+				Times(1).
+				Return([]db.ListPendingInvitationsForUserRow{{ID: 3, CollectionName: "Team reads"}}, nil)
+			store.EXPECT().
+				AcceptCollectionInvitationTx(gomock.Any(), gomock.Eq(db.RespondToCollectionInvitationParams{
+					UserID: uid,
+					ID:     3,
+				})).
+				Times(1).
+				Return(db.CollectionMember{CollectionID: 9, UserID: uid, Role: db.CollectionRoleViewer}, nil)
+
+			server := newTestServer(t, store)
+
+			for _, route := range []struct{ method, url string }{
+				{http.MethodGet, "/api/invitations"},
+				{http.MethodPost, "/api/invitations/3/accept"},
+			} {
+				recorder := httptest.NewRecorder()
+				request, err := http.NewRequest(route.method, route.url, nil)
+				require.NoError(t, err)
+				tc.setupAuth(t, request, server)
+
+				server.router.ServeHTTP(recorder, request)
+				require.Equal(t, http.StatusOK, recorder.Code)
+			}
+		})
+	}
+}
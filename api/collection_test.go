@@ -0,0 +1,676 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/imrishuroy/read-cache-api/db/mock"
+	db "github.com/imrishuroy/read-cache-api/db/sqlc"
+	"github.com/imrishuroy/read-cache-api/util"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func randomCollection(owner string, isPublic bool) db.Collection {
+	return db.Collection{
+		ID:       util.RandomInt(1, 1000),
+		Owner:    owner,
+		Name:     util.RandomString(8),
+		IsPublic: isPublic,
+	}
+}
+
+// stubCollectionRole expects the collection lookup of authorizeCollection,
+// uid holds role in the collection or is an outsider if role is empty
+func stubCollectionRole(store *mockdb.MockStore, collection db.Collection, uid string, role db.CollectionRole) {
+	store.EXPECT().
+		GetCollection(gomock.Any(), gomock.Eq(collection.ID)).
+		Times(1).
+		Return(collection, nil)
+
+	member := db.CollectionMember{CollectionID: collection.ID, UserID: uid, Role: role}
+	var err error
+	if role == "" {
+		member, err = db.CollectionMember{}, db.ErrRecordNotFound
+	}
+	store.EXPECT().
+		GetCollectionMember(gomock.Any(), gomock.Eq(db.GetCollectionMemberParams{CollectionID: collection.ID, UserID: uid})).
+		Times(1).
+		Return(member, err)
+}
+
+func TestCreateCollectionAPI(t *testing.T) {
+	owner := util.RandomOwner()
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{"name": " Recipes ", "description": "to cook", "icon": "🍳"},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.CreateCollectionParams{
+					Owner:       owner,
+					Name:        "Recipes",
+					Description: "to cook",
+					Icon:        "🍳",
+				}
+				store.EXPECT().
+					CreateCollectionTx(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(db.Collection{ID: 1, Owner: owner, Name: arg.Name}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var collection db.Collection
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &collection))
+				require.Equal(t, "Recipes", collection.Name)
+			},
+		},
+		{
+			name: "BlankName",
+			body: gin.H{"name": "   "},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateCollectionTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "DuplicateName",
+			body: gin.H{"name": "Recipes"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateCollectionTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Collection{}, db.ErrUniqueViolation)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "InternalError",
+			body: gin.H{"name": "Recipes"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateCollectionTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Collection{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := serveTestRequest(t, tc.buildStubs, owner, http.MethodPost, "/api/collections", tc.body)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestGetCollectionAPI(t *testing.T) {
+	owner := util.RandomOwner()
+	private := randomCollection(owner, false)
+	public := randomCollection(owner, true)
+
+	testCases := []struct {
+		name          string
+		uid           string
+		role          db.CollectionRole
+		collection    db.Collection
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:       "Owner",
+			uid:        owner,
+			role:       db.CollectionRoleOwner,
+			collection: private,
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:       "OtherUserPublic",
+			uid:        util.RandomOwner(),
+			collection: public,
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:       "ViewerOfPrivate",
+			uid:        util.RandomOwner(),
+			role:       db.CollectionRoleViewer,
+			collection: private,
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp collectionResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.Equal(t, db.CollectionRoleViewer, rsp.Role)
+			},
+		},
+		{
+			name:       "OtherUserPrivate",
+			uid:        util.RandomOwner(),
+			collection: private,
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := serveTestRequest(t, func(store *mockdb.MockStore) {
+				stubCollectionRole(store, tc.collection, tc.uid, tc.role)
+			}, tc.uid, http.MethodGet, fmt.Sprintf("/api/collections/%d", tc.collection.ID), nil)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestUpdateCollectionAPI(t *testing.T) {
+	owner := util.RandomOwner()
+	collection := randomCollection(owner, false)
+
+	testCases := []struct {
+		name          string
+		uid           string
+		role          db.CollectionRole
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			uid:  owner,
+			role: db.CollectionRoleOwner,
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.UpdateCollectionParams{
+					ID:       collection.ID,
+					Name:     "Later",
+					IsPublic: true,
+				}
+				store.EXPECT().
+					UpdateCollection(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(db.Collection{ID: collection.ID, Owner: owner, Name: "Later", IsPublic: true}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "Editor",
+			uid:  util.RandomOwner(),
+			role: db.CollectionRoleEditor,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpdateCollection(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "NotMember",
+			uid:  util.RandomOwner(),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpdateCollection(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := serveTestRequest(t, func(store *mockdb.MockStore) {
+				stubCollectionRole(store, collection, tc.uid, tc.role)
+				tc.buildStubs(store)
+			}, tc.uid, http.MethodPut, fmt.Sprintf("/api/collections/%d", collection.ID), gin.H{"name": "Later", "is_public": true})
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestDeleteCollectionAPI(t *testing.T) {
+	owner := util.RandomOwner()
+	collection := randomCollection(owner, true)
+
+	testCases := []struct {
+		name          string
+		uid           string
+		role          db.CollectionRole
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			uid:  owner,
+			role: db.CollectionRoleOwner,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					DeleteCollection(gomock.Any(), gomock.Eq(collection.ID)).
+					Times(1).
+					Return(nil)
+				// the caches in the collection are left alone
+				store.EXPECT().
+					DeleteCacheTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "PublicButNotMember",
+			uid:  util.RandomOwner(),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					DeleteCollection(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := serveTestRequest(t, func(store *mockdb.MockStore) {
+				stubCollectionRole(store, collection, tc.uid, tc.role)
+				tc.buildStubs(store)
+			}, tc.uid, http.MethodDelete, fmt.Sprintf("/api/collections/%d", collection.ID), nil)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestAddCacheToCollectionAPI(t *testing.T) {
+	owner := util.RandomOwner()
+	editor := util.RandomOwner()
+	collection := randomCollection(owner, false)
+	cache := randomCache(owner, false)
+	editorCache := randomCache(editor, false)
+
+	testCases := []struct {
+		name          string
+		uid           string
+		role          db.CollectionRole
+		cache         db.Cache
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "OK",
+			uid:   owner,
+			role:  db.CollectionRoleOwner,
+			cache: cache,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetCache(gomock.Any(), gomock.Eq(cache.ID)).
+					Times(1).
+					Return(cache, nil)
+				store.EXPECT().
+					AddCacheToCollectionTx(gomock.Any(), gomock.Eq(db.AddCacheToCollectionParams{
+						CollectionID: collection.ID,
+						CacheID:      cache.ID,
+					})).
+					Times(1).
+					Return(int64(1), nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:  "CollectionDeleted",
+			uid:   owner,
+			role:  db.CollectionRoleOwner,
+			cache: cache,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetCache(gomock.Any(), gomock.Eq(cache.ID)).
+					Times(1).
+					Return(cache, nil)
+				store.EXPECT().
+					AddCacheToCollectionTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(int64(0), db.ErrRecordNotFound)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:  "EditorAddsOwnCache",
+			uid:   editor,
+			role:  db.CollectionRoleEditor,
+			cache: editorCache,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetCache(gomock.Any(), gomock.Eq(editorCache.ID)).
+					Times(1).
+					Return(editorCache, nil)
+				store.EXPECT().
+					AddCacheToCollectionTx(gomock.Any(), gomock.Eq(db.AddCacheToCollectionParams{
+						CollectionID: collection.ID,
+						CacheID:      editorCache.ID,
+					})).
+					Times(1).
+					Return(int64(1), nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:  "EditorAddsOwnersCache",
+			uid:   editor,
+			role:  db.CollectionRoleEditor,
+			cache: cache,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetCache(gomock.Any(), gomock.Eq(cache.ID)).
+					Times(1).
+					Return(cache, nil)
+				store.EXPECT().
+					AddCacheToCollectionTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:  "Viewer",
+			uid:   editor,
+			role:  db.CollectionRoleViewer,
+			cache: editorCache,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetCache(gomock.Any(), gomock.Any()).
+					Times(0)
+				store.EXPECT().
+					AddCacheToCollectionTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := serveTestRequest(t, func(store *mockdb.MockStore) {
+				stubCollectionRole(store, collection, tc.uid, tc.role)
+				tc.buildStubs(store)
+			}, tc.uid, http.MethodPost, fmt.Sprintf("/api/collections/%d/caches", collection.ID), gin.H{"cache_id": tc.cache.ID})
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestRemoveCacheFromCollectionAPI(t *testing.T) {
+	owner := util.RandomOwner()
+	collection := randomCollection(owner, false)
+
+	testCases := []struct {
+		name          string
+		rows          int64
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			rows: 1,
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "NotInCollection",
+			rows: 0,
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := serveTestRequest(t, func(store *mockdb.MockStore) {
+				stubCollectionRole(store, collection, owner, db.CollectionRoleOwner)
+				store.EXPECT().
+					RemoveCacheFromCollection(gomock.Any(), gomock.Eq(db.RemoveCacheFromCollectionParams{
+						CollectionID: collection.ID,
+						CacheID:      7,
+					})).
+					Times(1).
+					Return(tc.rows, nil)
+			}, owner, http.MethodDelete, fmt.Sprintf("/api/collections/%d/caches/7", collection.ID), nil)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestReorderCollectionAPI(t *testing.T) {
+	owner := util.RandomOwner()
+	collection := randomCollection(owner, false)
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{"cache_ids": []int64{3, 1, 2}},
+			buildStubs: func(store *mockdb.MockStore) {
+				stubCollectionRole(store, collection, owner, db.CollectionRoleOwner)
+				store.EXPECT().
+					ReorderCollectionTx(gomock.Any(), gomock.Eq(db.ReorderCollectionTxParams{
+						CollectionID: collection.ID,
+						CacheIDs:     []int64{3, 1, 2},
+					})).
+					Times(1).
+					Return(nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "StaleOrder",
+			body: gin.H{"cache_ids": []int64{3, 1}},
+			buildStubs: func(store *mockdb.MockStore) {
+				stubCollectionRole(store, collection, owner, db.CollectionRoleOwner)
+				store.EXPECT().
+					ReorderCollectionTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.ErrCollectionOrderMismatch)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name: "MissingCacheIDs",
+			body: gin.H{},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ReorderCollectionTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := serveTestRequest(t, tc.buildStubs, owner, http.MethodPut, fmt.Sprintf("/api/collections/%d/order", collection.ID), tc.body)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestListCachesByCollectionAPI(t *testing.T) {
+	owner := util.RandomOwner()
+	visitor := util.RandomOwner()
+	collection := randomCollection(owner, true)
+
+	n := 3
+	rows := make([]db.ListCollectionCachesRow, n)
+	for i := range rows {
+		cache := randomCache(owner, true)
+		cache.CreatedAt = time.Now().Add(-time.Duration(i) * time.Hour)
+		rows[i] = db.ListCollectionCachesRow{Cache: cache, Position: int32(i + 1)}
+	}
+
+	testCases := []struct {
+		name          string
+		uid           string
+		query         string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "ManualOrder",
+			uid:   owner,
+			query: fmt.Sprintf("collection_id=%d&page_size=2", collection.ID),
+			buildStubs: func(store *mockdb.MockStore) {
+				stubCollectionRole(store, collection, owner, db.CollectionRoleOwner)
+
+				arg := db.ListCollectionCachesParams{
+					CollectionID: collection.ID,
+					Viewer:       owner,
+					PageLimit:    3,
+				}
+				store.EXPECT().
+					ListCollectionCaches(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(rows, nil)
+				store.EXPECT().
+					ListLikedCacheIDs(gomock.Any(), gomock.Any()).
+					Times(1)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp pageResponse[cacheResponse]
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.Len(t, rsp.Items, 2)
+				require.Equal(t, rows[0].Cache.ID, rsp.Items[0].ID)
+				require.Equal(t, rows[0].Cache.Title, rsp.Items[0].Title)
+				require.Equal(t, rows[1].Cache.ID, rsp.Items[1].ID)
+				require.NotNil(t, rsp.NextCursor)
+
+				cursor, err := decodeCursor(*rsp.NextCursor)
+				require.NoError(t, err)
+				require.Equal(t, rows[1].Position, cursor.Position)
+			},
+		},
+		{
+			name:  "NextPageAsVisitor",
+			uid:   visitor,
+			query: fmt.Sprintf("collection_id=%d&cursor=%s", collection.ID, encodeCursor(pageCursor{CreatedAt: rows[1].Cache.CreatedAt, ID: rows[1].Cache.ID, Position: 2})),
+			buildStubs: func(store *mockdb.MockStore) {
+				stubCollectionRole(store, collection, visitor, "")
+				store.EXPECT().
+					ListCollectionCaches(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ any, arg db.ListCollectionCachesParams) ([]db.ListCollectionCachesRow, error) {
+						require.Equal(t, visitor, arg.Viewer)
+						require.Equal(t, pgtype.Int4{Int32: 2, Valid: true}, arg.CursorPosition)
+						require.Equal(t, pgtype.Int8{Int64: rows[1].Cache.ID, Valid: true}, arg.CursorID)
+						return rows[2:], nil
+					})
+				store.EXPECT().
+					ListLikedCacheIDs(gomock.Any(), gomock.Any()).
+					Times(1)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:  "PrivateCollectionOfAnotherUser",
+			uid:   visitor,
+			query: fmt.Sprintf("collection_id=%d", collection.ID),
+			buildStubs: func(store *mockdb.MockStore) {
+				private := collection
+				private.IsPublic = false
+				stubCollectionRole(store, private, visitor, "")
+				store.EXPECT().
+					ListCollectionCaches(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:  "LegacyPageID",
+			uid:   owner,
+			query: fmt.Sprintf("collection_id=%d&page_id=1&page_size=5", collection.ID),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetCollection(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := serveTestRequest(t, tc.buildStubs, tc.uid, http.MethodGet, "/api/caches?"+tc.query, nil)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -0,0 +1,406 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/imrishuroy/read-cache-api/auth"
+	db "github.com/imrishuroy/read-cache-api/db/sqlc"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var (
+	errCommentNotFound      = errors.New("comment not found")
+	errCommentsNotAllowed   = errors.New("comments are only allowed on public caches")
+	errCommentNotEditable   = errors.New("comment doesn't belong to the authenticated user")
+	errCommentHidden        = errors.New("comment was hidden by the cache owner")
+	errCommentNotModerator  = errors.New("only the cache owner can hide comments")
+	errNestedReply          = errors.New("replies can only be one level deep")
+	errReplyParentGone      = errors.New("parent comment was deleted or hidden")
+	errCommentsCursorOnly   = errors.New("page_id is not supported on comments, use cursor")
+	errCommentParentMissing = errors.New("parent comment not found on this cache")
+)
+
+// commentResponse hides what a viewer may not see: deleted comments keep
+// their place in the thread without author or body, hidden comments lose
+// their body for everyone but the author and the cache owner
+type commentResponse struct {
+	ID         int64      `json:"id"`
+	CacheID    int64      `json:"cache_id"`
+	ParentID   *int64     `json:"parent_id"`
+	Author     string     `json:"author"`
+	Body       string     `json:"body"`
+	CreatedAt  time.Time  `json:"created_at"`
+	EditedAt   *time.Time `json:"edited_at"`
+	Deleted    bool       `json:"deleted"`
+	Hidden     bool       `json:"hidden"`
+	ReplyCount *int64     `json:"reply_count,omitempty"`
+}
+
+func newCommentResponse(principal *auth.Principal, cache db.Cache, comment db.Comment) commentResponse {
+	rsp := commentResponse{
+		ID:        comment.ID,
+		CacheID:   comment.CacheID,
+		Author:    comment.Author,
+		Body:      comment.Body,
+		CreatedAt: comment.CreatedAt,
+		Deleted:   comment.DeletedAt.Valid,
+		Hidden:    comment.HiddenAt.Valid,
+	}
+	if comment.ParentID.Valid {
+		rsp.ParentID = &comment.ParentID.Int64
+	}
+	if comment.EditedAt.Valid {
+		rsp.EditedAt = &comment.EditedAt.Time
+	}
+
+	if rsp.Deleted {
+		rsp.Author, rsp.Body = "", ""
+	} else if rsp.Hidden && !canReadHiddenComment(principal, cache, comment) {
+		rsp.Body = ""
+	}
+	return rsp
+}
+
+// commentCursor pages comments oldest first in (created_at, id) order
+func commentCursor(rsp commentResponse) pageCursor {
+	return pageCursor{CreatedAt: rsp.CreatedAt, ID: rsp.ID}
+}
+
+// authorizeComment loads a comment and the cache it belongs to, checking the
+// caller may view that cache. On failure it writes the error response.
+func (server *Server) authorizeComment(ctx *gin.Context, commentID int64) (db.Comment, db.Cache, bool) {
+	comment, err := server.store.GetComment(ctx, commentID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(errCommentNotFound))
+			return db.Comment{}, db.Cache{}, false
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return db.Comment{}, db.Cache{}, false
+	}
+
+	cache, ok := server.authorizeCache(ctx, comment.CacheID, viewCache)
+	if !ok {
+		return db.Comment{}, db.Cache{}, false
+	}
+	return comment, cache, true
+}
+
+type cacheCommentsURI struct {
+	CacheID int64 `uri:"cache_id" binding:"required,min=1"`
+}
+
+type commentURI struct {
+	ID int64 `uri:"comment_id" binding:"required,min=1"`
+}
+
+type createCommentRequest struct {
+	Body string `json:"body" binding:"required,max=5000"`
+	// ParentID makes the comment a reply to a top level comment
+	ParentID int64 `json:"parent_id" binding:"omitempty,min=1"`
+}
+
+func (server *Server) createComment(ctx *gin.Context) {
+	var uri cacheCommentsURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req createCommentRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	cache, ok := server.authorizeCache(ctx, uri.CacheID, viewCache)
+	if !ok {
+		return
+	}
+	if !cache.IsPublic.Bool {
+		ctx.JSON(http.StatusForbidden, errorResponse(errCommentsNotAllowed))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*auth.Principal)
+
+	arg := db.CreateCommentParams{
+		CacheID: cache.ID,
+		Author:  authPayload.UID,
+		Body:    req.Body,
+	}
+
+	if req.ParentID != 0 {
+		parent, err := server.store.GetComment(ctx, req.ParentID)
+		if err != nil && !errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+		switch {
+		case err != nil || parent.CacheID != cache.ID:
+			ctx.JSON(http.StatusBadRequest, errorResponse(errCommentParentMissing))
+			return
+		case parent.ParentID.Valid:
+			ctx.JSON(http.StatusBadRequest, errorResponse(errNestedReply))
+			return
+		case parent.DeletedAt.Valid || parent.HiddenAt.Valid:
+			ctx.JSON(http.StatusBadRequest, errorResponse(errReplyParentGone))
+			return
+		}
+		arg.ParentID = pgtype.Int8{Int64: parent.ID, Valid: true}
+	}
+
+	comment, err := server.store.CreateComment(ctx, arg)
+	if err != nil {
+		if db.ErrorCode(err) == db.ForeignKeyViolation {
+			ctx.JSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newCommentResponse(authPayload, cache, comment))
+}
+
+// listComments pages through the top level comments of a cache, oldest first.
+// Each carries its reply_count, replies are paged with listCommentReplies.
+func (server *Server) listComments(ctx *gin.Context) {
+	var uri cacheCommentsURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	p, ok := server.resolveCommentPage(ctx)
+	if !ok {
+		return
+	}
+
+	cache, ok := server.authorizeCache(ctx, uri.CacheID, viewCache)
+	if !ok {
+		return
+	}
+
+	arg := db.ListCommentsParams{
+		CacheID:         cache.ID,
+		CursorCreatedAt: p.cursorCreatedAt(),
+		CursorID:        p.cursorID(),
+		PageLimit:       p.fetchLimit(),
+	}
+
+	rows, err := server.store.ListComments(ctx, arg)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*auth.Principal)
+	comments := make([]commentResponse, len(rows))
+	for i, row := range rows {
+		comments[i] = newCommentResponse(authPayload, cache, db.Comment{
+			ID:        row.ID,
+			CacheID:   row.CacheID,
+			Author:    row.Author,
+			ParentID:  row.ParentID,
+			Body:      row.Body,
+			CreatedAt: row.CreatedAt,
+			EditedAt:  row.EditedAt,
+			DeletedAt: row.DeletedAt,
+			HiddenAt:  row.HiddenAt,
+		})
+		comments[i].ReplyCount = &rows[i].ReplyCount
+	}
+
+	ctx.JSON(http.StatusOK, newPageResponse(comments, p, commentCursor))
+}
+
+// listCommentReplies pages through the replies of a top level comment, oldest first
+func (server *Server) listCommentReplies(ctx *gin.Context) {
+	var uri commentURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	p, ok := server.resolveCommentPage(ctx)
+	if !ok {
+		return
+	}
+
+	parent, cache, ok := server.authorizeComment(ctx, uri.ID)
+	if !ok {
+		return
+	}
+
+	arg := db.ListCommentRepliesParams{
+		ParentID:        pgtype.Int8{Int64: parent.ID, Valid: true},
+		CursorCreatedAt: p.cursorCreatedAt(),
+		CursorID:        p.cursorID(),
+		PageLimit:       p.fetchLimit(),
+	}
+
+	replies, err := server.store.ListCommentReplies(ctx, arg)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*auth.Principal)
+	comments := make([]commentResponse, len(replies))
+	for i, reply := range replies {
+		comments[i] = newCommentResponse(authPayload, cache, reply)
+	}
+
+	ctx.JSON(http.StatusOK, newPageResponse(comments, p, commentCursor))
+}
+
+// resolveCommentPage only accepts cursor pagination, comments never had page_id
+func (server *Server) resolveCommentPage(ctx *gin.Context) (page, bool) {
+	var req pageRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return page{}, false
+	}
+	if req.PageID > 0 {
+		ctx.JSON(http.StatusBadRequest, errorResponse(errCommentsCursorOnly))
+		return page{}, false
+	}
+
+	p, err := server.resolvePage(req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return page{}, false
+	}
+	return p, true
+}
+
+type updateCommentRequest struct {
+	Body string `json:"body" binding:"required,max=5000"`
+}
+
+func (server *Server) updateComment(ctx *gin.Context) {
+	var uri commentURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req updateCommentRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	comment, cache, ok := server.authorizeComment(ctx, uri.ID)
+	if !ok {
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*auth.Principal)
+	if !canEditComment(authPayload, comment) {
+		ctx.JSON(http.StatusForbidden, errorResponse(errCommentNotEditable))
+		return
+	}
+	if comment.HiddenAt.Valid {
+		ctx.JSON(http.StatusForbidden, errorResponse(errCommentHidden))
+		return
+	}
+
+	arg := db.UpdateCommentBodyParams{
+		ID:   comment.ID,
+		Body: req.Body,
+	}
+
+	comment, err := server.store.UpdateCommentBody(ctx, arg)
+	if err != nil {
+		// deleted comments can't be edited
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(errCommentNotFound))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newCommentResponse(authPayload, cache, comment))
+}
+
+// deleteComment soft deletes a comment, its replies stay in place
+func (server *Server) deleteComment(ctx *gin.Context) {
+	var uri commentURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	comment, _, ok := server.authorizeComment(ctx, uri.ID)
+	if !ok {
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*auth.Principal)
+	if !canEditComment(authPayload, comment) {
+		ctx.JSON(http.StatusForbidden, errorResponse(errCommentNotEditable))
+		return
+	}
+
+	_, err := server.store.SoftDeleteComment(ctx, comment.ID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(errCommentNotFound))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse())
+}
+
+type setCommentVisibilityRequest struct {
+	Hidden *bool `json:"hidden" binding:"required"`
+}
+
+// setCommentVisibility lets the cache owner hide a comment from everyone but
+// its author, or show it again
+func (server *Server) setCommentVisibility(ctx *gin.Context) {
+	var uri commentURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req setCommentVisibilityRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	comment, cache, ok := server.authorizeComment(ctx, uri.ID)
+	if !ok {
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*auth.Principal)
+	if !canModerateComments(authPayload, cache) {
+		ctx.JSON(http.StatusForbidden, errorResponse(errCommentNotModerator))
+		return
+	}
+
+	arg := db.SetCommentHiddenParams{
+		ID:     comment.ID,
+		Hidden: *req.Hidden,
+	}
+
+	comment, err := server.store.SetCommentHidden(ctx, arg)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newCommentResponse(authPayload, cache, comment))
+}
@@ -0,0 +1,656 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/imrishuroy/read-cache-api/db/mock"
+	db "github.com/imrishuroy/read-cache-api/db/sqlc"
+	"github.com/imrishuroy/read-cache-api/util"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func randomComment(cache db.Cache, author string) db.Comment {
+	return db.Comment{
+		ID:        util.RandomInt(1, 1000),
+		CacheID:   cache.ID,
+		Author:    author,
+		Body:      util.RandomContent(),
+		CreatedAt: time.Now().UTC().Truncate(time.Microsecond),
+	}
+}
+
+func TestCreateCommentAPI(t *testing.T) {
+	owner := util.RandomOwner()
+	commenter := util.RandomOwner()
+	cache := randomCache(owner, true)
+	privateCache := randomCache(owner, false)
+	parent := randomComment(cache, owner)
+	reply := randomComment(cache, owner)
+	reply.ParentID = pgtype.Int8{Int64: parent.ID, Valid: true}
+
+	testCases := []struct {
+		name          string
+		cacheID       int64
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:    "OK",
+			cacheID: cache.ID,
+			body:    gin.H{"body": "great read"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetCache(gomock.Any(), gomock.Eq(cache.ID)).
+					Times(1).
+					Return(cache, nil)
+
+				arg := db.CreateCommentParams{
+					CacheID: cache.ID,
+					Author:  commenter,
+					Body:    "great read",
+				}
+				store.EXPECT().
+					CreateComment(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(db.Comment{ID: 1, CacheID: cache.ID, Author: commenter, Body: "great read"}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp commentResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.Equal(t, "great read", rsp.Body)
+				require.Equal(t, commenter, rsp.Author)
+				require.Nil(t, rsp.ParentID)
+			},
+		},
+		{
+			name:    "Reply",
+			cacheID: cache.ID,
+			body:    gin.H{"body": "agreed", "parent_id": parent.ID},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetCache(gomock.Any(), gomock.Eq(cache.ID)).
+					Times(1).
+					Return(cache, nil)
+				store.EXPECT().
+					GetComment(gomock.Any(), gomock.Eq(parent.ID)).
+					Times(1).
+					Return(parent, nil)
+
+				arg := db.CreateCommentParams{
+					CacheID:  cache.ID,
+					Author:   commenter,
+					ParentID: pgtype.Int8{Int64: parent.ID, Valid: true},
+					Body:     "agreed",
+				}
+				store.EXPECT().
+					CreateComment(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(db.Comment{ID: 2, CacheID: cache.ID, Author: commenter, ParentID: arg.ParentID, Body: "agreed"}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp commentResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.NotNil(t, rsp.ParentID)
+				require.Equal(t, parent.ID, *rsp.ParentID)
+			},
+		},
+		{
+			name:    "NestedReply",
+			cacheID: cache.ID,
+			body:    gin.H{"body": "deeper", "parent_id": reply.ID},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetCache(gomock.Any(), gomock.Eq(cache.ID)).
+					Times(1).
+					Return(cache, nil)
+				store.EXPECT().
+					GetComment(gomock.Any(), gomock.Eq(reply.ID)).
+					Times(1).
+					Return(reply, nil)
+				store.EXPECT().
+					CreateComment(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:    "ParentOnAnotherCache",
+			cacheID: cache.ID,
+			body:    gin.H{"body": "hi", "parent_id": parent.ID},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetCache(gomock.Any(), gomock.Eq(cache.ID)).
+					Times(1).
+					Return(cache, nil)
+
+				elsewhere := parent
+				elsewhere.CacheID = cache.ID + 1
+				store.EXPECT().
+					GetComment(gomock.Any(), gomock.Eq(parent.ID)).
+					Times(1).
+					Return(elsewhere, nil)
+				store.EXPECT().
+					CreateComment(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:    "DeletedParent",
+			cacheID: cache.ID,
+			body:    gin.H{"body": "hi", "parent_id": parent.ID},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetCache(gomock.Any(), gomock.Eq(cache.ID)).
+					Times(1).
+					Return(cache, nil)
+
+				deleted := parent
+				deleted.DeletedAt = pgtype.Timestamptz{Time: time.Now(), Valid: true}
+				store.EXPECT().
+					GetComment(gomock.Any(), gomock.Eq(parent.ID)).
+					Times(1).
+					Return(deleted, nil)
+				store.EXPECT().
+					CreateComment(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:    "PrivateCache",
+			cacheID: privateCache.ID,
+			body:    gin.H{"body": "hi"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetCache(gomock.Any(), gomock.Eq(privateCache.ID)).
+					Times(1).
+					Return(privateCache, nil)
+				store.EXPECT().
+					CreateComment(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:    "EmptyBody",
+			cacheID: cache.ID,
+			body:    gin.H{"body": ""},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetCache(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:    "InternalError",
+			cacheID: cache.ID,
+			body:    gin.H{"body": "hi"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetCache(gomock.Any(), gomock.Eq(cache.ID)).
+					Times(1).
+					Return(cache, nil)
+				store.EXPECT().
+					CreateComment(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Comment{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			url := fmt.Sprintf("/api/caches/%d/comments", tc.cacheID)
+			recorder := serveTestRequest(t, tc.buildStubs, commenter, http.MethodPost, url, tc.body)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestListCommentsAPI(t *testing.T) {
+	owner := util.RandomOwner()
+	author := util.RandomOwner()
+	cache := randomCache(owner, true)
+
+	visible := randomComment(cache, author)
+	hidden := randomComment(cache, author)
+	hidden.HiddenAt = pgtype.Timestamptz{Time: time.Now(), Valid: true}
+	deleted := randomComment(cache, author)
+	deleted.Body = ""
+	deleted.DeletedAt = pgtype.Timestamptz{Time: time.Now(), Valid: true}
+
+	rows := []db.ListCommentsRow{}
+	for _, c := range []db.Comment{visible, hidden, deleted} {
+		rows = append(rows, db.ListCommentsRow{
+			ID:         c.ID,
+			CacheID:    c.CacheID,
+			Author:     c.Author,
+			Body:       c.Body,
+			CreatedAt:  c.CreatedAt,
+			DeletedAt:  c.DeletedAt,
+			HiddenAt:   c.HiddenAt,
+			ReplyCount: 2,
+		})
+	}
+
+	testCases := []struct {
+		name          string
+		uid           string
+		query         string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "Visitor",
+			uid:   util.RandomOwner(),
+			query: "page_size=2",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetCache(gomock.Any(), gomock.Eq(cache.ID)).
+					Times(1).
+					Return(cache, nil)
+
+				arg := db.ListCommentsParams{
+					CacheID:   cache.ID,
+					PageLimit: 3,
+				}
+				store.EXPECT().
+					ListComments(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(rows, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp pageResponse[commentResponse]
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.Len(t, rsp.Items, 2)
+				require.Equal(t, visible.Body, rsp.Items[0].Body)
+				require.Equal(t, int64(2), *rsp.Items[0].ReplyCount)
+				require.True(t, rsp.Items[1].Hidden)
+				require.Empty(t, rsp.Items[1].Body)
+				require.NotNil(t, rsp.NextCursor)
+			},
+		},
+		{
+			name:  "CacheOwnerSeesHiddenAndDeletedPlaceholders",
+			uid:   owner,
+			query: "",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetCache(gomock.Any(), gomock.Eq(cache.ID)).
+					Times(1).
+					Return(cache, nil)
+				store.EXPECT().
+					ListComments(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(rows, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp pageResponse[commentResponse]
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.Len(t, rsp.Items, 3)
+				require.Equal(t, hidden.Body, rsp.Items[1].Body)
+				require.True(t, rsp.Items[2].Deleted)
+				require.Empty(t, rsp.Items[2].Author)
+				require.Nil(t, rsp.NextCursor)
+			},
+		},
+		{
+			name:  "PageIDNotSupported",
+			uid:   owner,
+			query: "page_id=1&page_size=5",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListComments(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			url := fmt.Sprintf("/api/caches/%d/comments?%s", cache.ID, tc.query)
+			recorder := serveTestRequest(t, tc.buildStubs, tc.uid, http.MethodGet, url, nil)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestListCommentRepliesAPI(t *testing.T) {
+	owner := util.RandomOwner()
+	cache := randomCache(owner, true)
+	parent := randomComment(cache, owner)
+	reply := randomComment(cache, util.RandomOwner())
+	reply.ParentID = pgtype.Int8{Int64: parent.ID, Valid: true}
+
+	recorder := serveTestRequest(t, func(store *mockdb.MockStore) {
+		store.EXPECT().
+			GetComment(gomock.Any(), gomock.Eq(parent.ID)).
+			Times(1).
+			Return(parent, nil)
+		store.EXPECT().
+			GetCache(gomock.Any(), gomock.Eq(cache.ID)).
+			Times(1).
+			Return(cache, nil)
+
+		arg := db.ListCommentRepliesParams{
+			ParentID:  pgtype.Int8{Int64: parent.ID, Valid: true},
+			PageLimit: defaultPageSize + 1,
+		}
+		store.EXPECT().
+			ListCommentReplies(gomock.Any(), gomock.Eq(arg)).
+			Times(1).
+			Return([]db.Comment{reply}, nil)
+	}, util.RandomOwner(), http.MethodGet, fmt.Sprintf("/api/comments/%d/replies", parent.ID), nil)
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	var rsp pageResponse[commentResponse]
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+	require.Len(t, rsp.Items, 1)
+	require.Equal(t, reply.Body, rsp.Items[0].Body)
+}
+
+func TestUpdateCommentAPI(t *testing.T) {
+	owner := util.RandomOwner()
+	author := util.RandomOwner()
+	cache := randomCache(owner, true)
+	comment := randomComment(cache, author)
+
+	testCases := []struct {
+		name          string
+		uid           string
+		comment       db.Comment
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:    "OK",
+			uid:     author,
+			comment: comment,
+			buildStubs: func(store *mockdb.MockStore) {
+				edited := comment
+				edited.Body = "edited"
+				edited.EditedAt = pgtype.Timestamptz{Time: time.Now(), Valid: true}
+				store.EXPECT().
+					UpdateCommentBody(gomock.Any(), gomock.Eq(db.UpdateCommentBodyParams{ID: comment.ID, Body: "edited"})).
+					Times(1).
+					Return(edited, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp commentResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.Equal(t, "edited", rsp.Body)
+				require.NotNil(t, rsp.EditedAt)
+			},
+		},
+		{
+			name:    "NotAuthor",
+			uid:     owner,
+			comment: comment,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpdateCommentBody(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "Hidden",
+			uid:  author,
+			comment: func() db.Comment {
+				hidden := comment
+				hidden.HiddenAt = pgtype.Timestamptz{Time: time.Now(), Valid: true}
+				return hidden
+			}(),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpdateCommentBody(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:    "Deleted",
+			uid:     author,
+			comment: comment,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpdateCommentBody(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Comment{}, db.ErrRecordNotFound)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := serveTestRequest(t, func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetComment(gomock.Any(), gomock.Eq(tc.comment.ID)).
+					Times(1).
+					Return(tc.comment, nil)
+				store.EXPECT().
+					GetCache(gomock.Any(), gomock.Eq(cache.ID)).
+					Times(1).
+					Return(cache, nil)
+				tc.buildStubs(store)
+			}, tc.uid, http.MethodPut, fmt.Sprintf("/api/comments/%d", tc.comment.ID), gin.H{"body": "edited"})
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestDeleteCommentAPI(t *testing.T) {
+	owner := util.RandomOwner()
+	author := util.RandomOwner()
+	cache := randomCache(owner, true)
+	comment := randomComment(cache, author)
+
+	testCases := []struct {
+		name          string
+		uid           string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			uid:  author,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					SoftDeleteComment(gomock.Any(), gomock.Eq(comment.ID)).
+					Times(1).
+					Return(comment, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "CacheOwnerCantDelete",
+			uid:  owner,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					SoftDeleteComment(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "AlreadyDeleted",
+			uid:  author,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					SoftDeleteComment(gomock.Any(), gomock.Eq(comment.ID)).
+					Times(1).
+					Return(db.Comment{}, db.ErrRecordNotFound)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := serveTestRequest(t, func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetComment(gomock.Any(), gomock.Eq(comment.ID)).
+					Times(1).
+					Return(comment, nil)
+				store.EXPECT().
+					GetCache(gomock.Any(), gomock.Eq(cache.ID)).
+					Times(1).
+					Return(cache, nil)
+				tc.buildStubs(store)
+			}, tc.uid, http.MethodDelete, fmt.Sprintf("/api/comments/%d", comment.ID), nil)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestSetCommentVisibilityAPI(t *testing.T) {
+	owner := util.RandomOwner()
+	author := util.RandomOwner()
+	cache := randomCache(owner, true)
+	comment := randomComment(cache, author)
+
+	testCases := []struct {
+		name          string
+		uid           string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OwnerHides",
+			uid:  owner,
+			body: gin.H{"hidden": true},
+			buildStubs: func(store *mockdb.MockStore) {
+				hidden := comment
+				hidden.HiddenAt = pgtype.Timestamptz{Time: time.Now(), Valid: true}
+				store.EXPECT().
+					SetCommentHidden(gomock.Any(), gomock.Eq(db.SetCommentHiddenParams{ID: comment.ID, Hidden: true})).
+					Times(1).
+					Return(hidden, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp commentResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.True(t, rsp.Hidden)
+				require.Equal(t, comment.Body, rsp.Body)
+			},
+		},
+		{
+			name: "OwnerShowsAgain",
+			uid:  owner,
+			body: gin.H{"hidden": false},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					SetCommentHidden(gomock.Any(), gomock.Eq(db.SetCommentHiddenParams{ID: comment.ID, Hidden: false})).
+					Times(1).
+					Return(comment, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "NotCacheOwner",
+			uid:  author,
+			body: gin.H{"hidden": true},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					SetCommentHidden(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := serveTestRequest(t, func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetComment(gomock.Any(), gomock.Eq(comment.ID)).
+					Times(1).
+					Return(comment, nil)
+				store.EXPECT().
+					GetCache(gomock.Any(), gomock.Eq(cache.ID)).
+					Times(1).
+					Return(cache, nil)
+				tc.buildStubs(store)
+			}, tc.uid, http.MethodPut, fmt.Sprintf("/api/comments/%d/visibility", comment.ID), tc.body)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestSetCommentVisibilityMissingHidden(t *testing.T) {
+	recorder := serveTestRequest(t, func(store *mockdb.MockStore) {
+		store.EXPECT().
+			GetComment(gomock.Any(), gomock.Any()).
+			Times(0)
+	}, util.RandomOwner(), http.MethodPut, "/api/comments/1/visibility", gin.H{})
+
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+}
@@ -0,0 +1,147 @@
+package api
+
+import (
+	"bytes"
+	"errors"
+	"html/template"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/imrishuroy/read-cache-api/auth"
+	db "github.com/imrishuroy/read-cache-api/db/sqlc"
+	"github.com/imrishuroy/read-cache-api/digest"
+)
+
+// confirmUnsubscribePage is shown to people following the link in a digest.
+// It only asks, mail scanners and link prefetchers open every link in a mail.
+var confirmUnsubscribePage = template.Must(template.New("unsubscribe").Parse(`<!DOCTYPE html>
+<html><body style="font-family: sans-serif;">
+<form method="post" action="/digest/unsubscribe?token={{.}}">
+<p>Stop getting ReadCache digests?</p>
+<button type="submit">Unsubscribe</button>
+</form>
+</body></html>
+`))
+
+// unsubscribedPage is shown once the form on confirmUnsubscribePage is sent
+const unsubscribedPage = `<!DOCTYPE html>
+<html><body style="font-family: sans-serif;">
+<p>You won't get ReadCache digests anymore. You can turn them back on in the app.</p>
+</body></html>
+`
+
+type digestSettingsResponse struct {
+	Frequency  string     `json:"frequency"`
+	LastSentAt *time.Time `json:"last_sent_at"`
+	NextSendAt *time.Time `json:"next_send_at"`
+}
+
+func newDigestSettingsResponse(settings db.DigestSetting) digestSettingsResponse {
+	rsp := digestSettingsResponse{Frequency: settings.Frequency}
+	if settings.Frequency != digest.FrequencyOff {
+		rsp.LastSentAt = &settings.LastSentAt
+	}
+	if settings.NextSendAt.Valid {
+		rsp.NextSendAt = &settings.NextSendAt.Time
+	}
+	return rsp
+}
+
+func (server *Server) getDigestSettings(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*auth.Principal)
+
+	settings, err := server.store.GetDigestSettings(ctx, authPayload.UID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			// digests are off until the user picks a frequency
+			ctx.JSON(http.StatusOK, digestSettingsResponse{Frequency: digest.FrequencyOff})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newDigestSettingsResponse(settings))
+}
+
+type updateDigestSettingsRequest struct {
+	Frequency string `json:"frequency" binding:"required,oneof=off daily weekly"`
+}
+
+// updateDigestSettings picks how often the caller gets a digest. The first
+// digest after turning them on covers the caches created from then on.
+func (server *Server) updateDigestSettings(ctx *gin.Context) {
+	var req updateDigestSettingsRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*auth.Principal)
+
+	settings, err := server.store.UpsertDigestSettings(ctx, db.UpsertDigestSettingsParams{
+		UserID:    authPayload.UID,
+		Frequency: req.Frequency,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newDigestSettingsResponse(settings))
+}
+
+type unsubscribeDigestRequest struct {
+	Token string `form:"token" binding:"required"`
+}
+
+// confirmUnsubscribeDigest is the link in a digest, it needs no login and
+// only shows a form that posts the token back to unsubscribeDigest
+func (server *Server) confirmUnsubscribeDigest(ctx *gin.Context) {
+	var req unsubscribeDigestRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if _, err := digest.VerifyUnsubscribeToken([]byte(server.config.DigestSecret), req.Token); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var page bytes.Buffer
+	if err := confirmUnsubscribePage.Execute(&page, req.Token); err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	ctx.Data(http.StatusOK, "text/html; charset=utf-8", page.Bytes())
+}
+
+// unsubscribeDigest turns digests off for the user a signed token was made
+// for. It needs no login and is posted by the form of confirmUnsubscribeDigest
+// or by mail clients doing a one-click unsubscribe (RFC 8058).
+func (server *Server) unsubscribeDigest(ctx *gin.Context) {
+	var req unsubscribeDigestRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	userID, err := digest.VerifyUnsubscribeToken([]byte(server.config.DigestSecret), req.Token)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if err := server.store.UnsubscribeDigest(ctx, userID); err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if ctx.PostForm("List-Unsubscribe") == "One-Click" {
+		ctx.JSON(http.StatusOK, successResponse())
+		return
+	}
+	ctx.Data(http.StatusOK, "text/html; charset=utf-8", []byte(unsubscribedPage))
+}
@@ -0,0 +1,305 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/imrishuroy/read-cache-api/db/mock"
+	db "github.com/imrishuroy/read-cache-api/db/sqlc"
+	"github.com/imrishuroy/read-cache-api/digest"
+	"github.com/imrishuroy/read-cache-api/util"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetDigestSettingsAPI(t *testing.T) {
+	user := util.RandomOwner()
+	nextSendAt := time.Now().UTC().Add(time.Hour).Truncate(time.Second)
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetDigestSettings(gomock.Any(), user).
+					Times(1).
+					Return(db.DigestSetting{
+						UserID:     user,
+						Frequency:  digest.FrequencyWeekly,
+						LastSentAt: nextSendAt.Add(-7 * 24 * time.Hour),
+						NextSendAt: pgtype.Timestamptz{Time: nextSendAt, Valid: true},
+					}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp digestSettingsResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.Equal(t, digest.FrequencyWeekly, rsp.Frequency)
+				require.True(t, nextSendAt.Equal(*rsp.NextSendAt))
+				require.NotNil(t, rsp.LastSentAt)
+			},
+		},
+		{
+			name: "NeverSet",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetDigestSettings(gomock.Any(), user).
+					Times(1).
+					Return(db.DigestSetting{}, db.ErrRecordNotFound)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.JSONEq(t, `{"frequency":"off","last_sent_at":null,"next_send_at":null}`, recorder.Body.String())
+			},
+		},
+		{
+			name: "InternalError",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetDigestSettings(gomock.Any(), user).
+					Times(1).
+					Return(db.DigestSetting{}, sql.ErrConnDone)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := serveTestRequest(t, tc.buildStubs, user, http.MethodGet, "/api/digest", nil)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestUpdateDigestSettingsAPI(t *testing.T) {
+	user := util.RandomOwner()
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "Daily",
+			body: gin.H{"frequency": "daily"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpsertDigestSettings(gomock.Any(), db.UpsertDigestSettingsParams{
+						UserID:    user,
+						Frequency: digest.FrequencyDaily,
+					}).
+					Times(1).
+					Return(db.DigestSetting{
+						UserID:     user,
+						Frequency:  digest.FrequencyDaily,
+						LastSentAt: time.Now(),
+						NextSendAt: pgtype.Timestamptz{Time: time.Now().Add(24 * time.Hour), Valid: true},
+					}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp digestSettingsResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.Equal(t, digest.FrequencyDaily, rsp.Frequency)
+				require.NotNil(t, rsp.NextSendAt)
+			},
+		},
+		{
+			name: "Off",
+			body: gin.H{"frequency": "off"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpsertDigestSettings(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.DigestSetting{UserID: user, Frequency: digest.FrequencyOff, LastSentAt: time.Now()}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.JSONEq(t, `{"frequency":"off","last_sent_at":null,"next_send_at":null}`, recorder.Body.String())
+			},
+		},
+		{
+			name: "InvalidFrequency",
+			body: gin.H{"frequency": "hourly"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpsertDigestSettings(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "InternalError",
+			body: gin.H{"frequency": "weekly"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpsertDigestSettings(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.DigestSetting{}, sql.ErrConnDone)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := serveTestRequest(t, tc.buildStubs, user, http.MethodPut, "/api/digest", tc.body)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestUnsubscribeDigestAPI(t *testing.T) {
+	user := util.RandomOwner()
+	secret := util.RandomString(32)
+	token := digest.SignUnsubscribeToken([]byte(secret), user)
+
+	testCases := []struct {
+		name          string
+		method        string
+		token         string
+		body          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			// following the link only asks, scanners opening it change nothing
+			name:   "Link",
+			method: http.MethodGet,
+			token:  token,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UnsubscribeDigest(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, "text/html; charset=utf-8", recorder.Header().Get("Content-Type"))
+				require.Contains(t, recorder.Body.String(), `<form method="post" action="/digest/unsubscribe?token=`+token+`">`)
+			},
+		},
+		{
+			name:   "ConfirmForm",
+			method: http.MethodPost,
+			token:  token,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UnsubscribeDigest(gomock.Any(), user).
+					Times(1).
+					Return(nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, "text/html; charset=utf-8", recorder.Header().Get("Content-Type"))
+				require.Contains(t, recorder.Body.String(), "You won't get ReadCache digests anymore")
+			},
+		},
+		{
+			name:   "OneClick",
+			method: http.MethodPost,
+			token:  token,
+			body:   "List-Unsubscribe=One-Click",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UnsubscribeDigest(gomock.Any(), user).
+					Times(1).
+					Return(nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.JSONEq(t, `{"result":"success"}`, recorder.Body.String())
+			},
+		},
+		{
+			name:   "ForgedToken",
+			method: http.MethodGet,
+			token:  digest.SignUnsubscribeToken([]byte("another secret"), user),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UnsubscribeDigest(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:   "MissingToken",
+			method: http.MethodGet,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UnsubscribeDigest(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:   "InternalError",
+			method: http.MethodPost,
+			token:  token,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UnsubscribeDigest(gomock.Any(), user).
+					Times(1).
+					Return(sql.ErrConnDone)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			server.config.DigestSecret = secret
+			recorder := httptest.NewRecorder()
+
+			// no authorization, the token is the credential
+			request, err := http.NewRequest(tc.method, "/digest/unsubscribe?token="+url.QueryEscape(tc.token), strings.NewReader(tc.body))
+			require.NoError(t, err)
+			if tc.body != "" {
+				request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			}
+
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/imrishuroy/read-cache-api/auth"
+	db "github.com/imrishuroy/read-cache-api/db/sqlc"
+	"github.com/imrishuroy/read-cache-api/exporter"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/rs/zerolog/log"
+)
+
+// exportBatchSize is how many caches are read from the database at a time
+const exportBatchSize = 500
+
+type exportRequest struct {
+	Format string `form:"format" binding:"required,oneof=json csv html markdown"`
+}
+
+// exportCaches streams every cache of the caller with its tags. The library
+// is read in keyset pages and each page is written out before the next one is
+// fetched, so memory use doesn't grow with the size of the library. Once the
+// first byte is sent the status can't change anymore: a later database error
+// cuts the download short and is only logged.
+func (server *Server) exportCaches(ctx *gin.Context) {
+	var req exportRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	format := exporter.Format(req.Format)
+
+	w, err := exporter.NewWriter(format, ctx.Writer)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*auth.Principal)
+
+	arg := db.ListCachesForExportParams{
+		Owner:     authPayload.UID,
+		PageLimit: exportBatchSize,
+	}
+	caches, err := server.store.ListCachesForExport(ctx, arg)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	filename := fmt.Sprintf("readcache-%s.%s", time.Now().UTC().Format("2006-01-02"), exporter.Extension(format))
+	ctx.Header("Content-Type", exporter.ContentType(format))
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	ctx.Status(http.StatusOK)
+
+	for {
+		for _, cache := range caches {
+			if err := w.Write(cache); err != nil {
+				log.Error().Err(err).Str("user", arg.Owner).Msg("cannot write export")
+				return
+			}
+		}
+		ctx.Writer.Flush()
+
+		if len(caches) < exportBatchSize {
+			break
+		}
+
+		last := caches[len(caches)-1]
+		arg.CursorCreatedAt = pgtype.Timestamptz{Time: last.CreatedAt, Valid: true}
+		arg.CursorID = pgtype.Int8{Int64: last.ID, Valid: true}
+
+		caches, err = server.store.ListCachesForExport(ctx, arg)
+		if err != nil {
+			log.Error().Err(err).Str("user", arg.Owner).Msg("cannot read export")
+			return
+		}
+	}
+
+	if err := w.Close(); err != nil {
+		log.Error().Err(err).Str("user", arg.Owner).Msg("cannot finish export")
+	}
+}
@@ -0,0 +1,150 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/imrishuroy/read-cache-api/db/mock"
+	db "github.com/imrishuroy/read-cache-api/db/sqlc"
+	"github.com/imrishuroy/read-cache-api/util"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportCachesAPI(t *testing.T) {
+	owner := util.RandomOwner()
+	createdAt := time.Now().UTC().Truncate(time.Second)
+
+	// one full batch followed by a partial one
+	caches := make([]db.ListCachesForExportRow, exportBatchSize+1)
+	for i := range caches {
+		caches[i] = db.ListCachesForExportRow{
+			ID:        int64(i + 1),
+			Owner:     owner,
+			Title:     util.RandomTitle(),
+			Content:   fmt.Sprintf("https://example.com/%d", i),
+			CreatedAt: createdAt.Add(time.Duration(i) * time.Second),
+			Status:    db.ReadingStatusUnread,
+			Tags:      []string{"go"},
+		}
+	}
+	firstBatch, secondBatch := caches[:exportBatchSize], caches[exportBatchSize:]
+	last := firstBatch[len(firstBatch)-1]
+
+	testCases := []struct {
+		name          string
+		query         string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "JSON",
+			query: "?format=json",
+			buildStubs: func(store *mockdb.MockStore) {
+				gomock.InOrder(
+					store.EXPECT().
+						ListCachesForExport(gomock.Any(), db.ListCachesForExportParams{
+							Owner:     owner,
+							PageLimit: exportBatchSize,
+						}).
+						Times(1).
+						Return(firstBatch, nil),
+					store.EXPECT().
+						ListCachesForExport(gomock.Any(), db.ListCachesForExportParams{
+							Owner:           owner,
+							CursorCreatedAt: pgtype.Timestamptz{Time: last.CreatedAt, Valid: true},
+							CursorID:        pgtype.Int8{Int64: last.ID, Valid: true},
+							PageLimit:       exportBatchSize,
+						}).
+						Times(1).
+						Return(secondBatch, nil),
+				)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+				require.Contains(t, recorder.Header().Get("Content-Disposition"), ".json")
+
+				var rsp struct {
+					Caches []struct {
+						ID   int64    `json:"id"`
+						URL  string   `json:"url"`
+						Tags []string `json:"tags"`
+					} `json:"caches"`
+				}
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.Len(t, rsp.Caches, len(caches))
+				require.Equal(t, caches[0].Content, rsp.Caches[0].URL)
+				require.Equal(t, []string{"go"}, rsp.Caches[0].Tags)
+				require.Equal(t, caches[len(caches)-1].ID, rsp.Caches[len(caches)-1].ID)
+			},
+		},
+		{
+			name:  "Markdown",
+			query: "?format=markdown",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListCachesForExport(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(secondBatch, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, "application/zip", recorder.Header().Get("Content-Type"))
+				require.Contains(t, recorder.Header().Get("Content-Disposition"), ".zip")
+			},
+		},
+		{
+			name:  "MissingFormat",
+			query: "",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListCachesForExport(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "InvalidFormat",
+			query: "?format=pdf",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListCachesForExport(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "InternalError",
+			query: "?format=csv",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListCachesForExport(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, sql.ErrConnDone)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := serveTestRequest(t, tc.buildStubs, owner, http.MethodGet, "/api/export"+tc.query, nil)
+			tc.checkResponse(recorder)
+		})
+	}
+}
@@ -0,0 +1,244 @@
+package api
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	db "github.com/imrishuroy/read-cache-api/db/sqlc"
+	"github.com/imrishuroy/read-cache-api/feed"
+	"github.com/imrishuroy/read-cache-api/unfurl"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// feedLimit is how many of the newest caches a feed carries
+const feedLimit = 50
+
+// feedMaxAge is how long feed readers and proxies may reuse a feed without revalidating
+const feedMaxAge = 5 * time.Minute
+
+type feedFormatRequest struct {
+	Format string `uri:"format" binding:"required,oneof=rss atom json"`
+}
+
+type tagFeedRequest struct {
+	TagName string `uri:"tag_name" binding:"required"`
+	Format  string `uri:"format" binding:"required,oneof=rss atom json"`
+}
+
+type userFeedRequest struct {
+	UserID string `uri:"user_id" binding:"required"`
+	Format string `uri:"format" binding:"required,oneof=rss atom json"`
+}
+
+// publicFeed serves the newest public caches of everyone
+func (server *Server) publicFeed(ctx *gin.Context) {
+	var req feedFormatRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	server.serveFeed(ctx, feed.Format(req.Format), db.ListFeedCachesParams{}, feed.Feed{
+		Title:       "ReadCache public caches",
+		Description: "The newest public caches on ReadCache",
+	})
+}
+
+// tagFeed serves the newest public caches with a tag
+func (server *Server) tagFeed(ctx *gin.Context) {
+	var req tagFeedRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	tag, err := server.store.GetTagByName(ctx, strings.ToLower(req.TagName))
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	arg := db.ListFeedCachesParams{TagID: pgtype.Int4{Int32: tag.TagID, Valid: true}}
+	server.serveFeed(ctx, feed.Format(req.Format), arg, feed.Feed{
+		Title:       fmt.Sprintf("#%s on ReadCache", tag.TagName),
+		Description: fmt.Sprintf("Public caches tagged %s", tag.TagName),
+	})
+}
+
+// userFeed serves the newest public caches of a user
+func (server *Server) userFeed(ctx *gin.Context) {
+	var req userFeedRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	user, err := server.store.GetUser(ctx, req.UserID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	arg := db.ListFeedCachesParams{Owner: pgtype.Text{String: user.ID, Valid: true}}
+	server.serveFeed(ctx, feed.Format(req.Format), arg, feed.Feed{
+		Title:       fmt.Sprintf("%s on ReadCache", user.Name),
+		Description: fmt.Sprintf("Public caches saved by %s", user.Name),
+	})
+}
+
+// serveFeed lists the caches matching arg and renders them into f. The ETag
+// is the item count and a hash of the rendered document, so an edited,
+// added or removed item changes it. Last-Modified is the newest updated_at,
+// so a reader polling an unchanged feed gets a 304 without a body.
+func (server *Server) serveFeed(ctx *gin.Context, format feed.Format, arg db.ListFeedCachesParams, f feed.Feed) {
+	arg.PageLimit = feedLimit
+	caches, err := server.store.ListFeedCaches(ctx, arg)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	baseURL := server.publicURL(ctx)
+	// the /api routes need a login, the feed is the public page it describes
+	f.FeedURL = baseURL + ctx.Request.URL.Path
+	f.Link = f.FeedURL
+	// an empty feed has nothing to date it by, the epoch keeps the document,
+	// and with it the ETag, stable between requests
+	f.Updated = time.Unix(0, 0)
+	for _, cache := range caches {
+		f.Items = append(f.Items, newFeedItem(baseURL, cache))
+		if cache.UpdatedAt.After(f.Updated) {
+			f.Updated = cache.UpdatedAt
+		}
+	}
+
+	body, err := feed.Render(format, f)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	sum := sha256.Sum256(body)
+	etag := fmt.Sprintf(`"%d-%s"`, len(caches), hex.EncodeToString(sum[:16]))
+	lastModified := f.Updated.UTC().Truncate(time.Second)
+
+	ctx.Header("ETag", etag)
+	ctx.Header("Last-Modified", lastModified.Format(http.TimeFormat))
+	ctx.Header("Cache-Control", fmt.Sprintf("public, max-age=%d", int(feedMaxAge.Seconds())))
+
+	if notModified(ctx.Request, etag, lastModified) {
+		ctx.Status(http.StatusNotModified)
+		return
+	}
+
+	ctx.Data(http.StatusOK, feed.ContentType(format), body)
+}
+
+// notModified reports whether the client's cached copy is still current.
+// If-None-Match wins over If-Modified-Since when both are sent (RFC 9110).
+func notModified(r *http.Request, etag string, lastModified time.Time) bool {
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		for _, candidate := range strings.Split(inm, ",") {
+			candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+			if candidate == "*" || candidate == etag {
+				return true
+			}
+		}
+		return false
+	}
+
+	if ims := r.Header.Get("If-Modified-Since"); ims != "" {
+		since, err := http.ParseTime(ims)
+		return err == nil && !lastModified.After(since)
+	}
+	return false
+}
+
+// publicURL is the base URL links in feeds are built from, the configured
+// PUBLIC_URL or else the host the request came in on
+func (server *Server) publicURL(ctx *gin.Context) string {
+	if server.config.PublicURL != "" {
+		return strings.TrimSuffix(server.config.PublicURL, "/")
+	}
+	scheme := "http"
+	if ctx.Request.TLS != nil {
+		scheme = "https"
+	}
+	return scheme + "://" + ctx.Request.Host
+}
+
+// feedCacheURL is the public address of a cache in a feed
+func feedCacheURL(baseURL string, cacheID int64) string {
+	return fmt.Sprintf("%s/feeds/caches/%d", baseURL, cacheID)
+}
+
+func newFeedItem(baseURL string, cache db.ListFeedCachesRow) feed.Item {
+	item := feed.Item{
+		ID:        feedCacheURL(baseURL, cache.ID),
+		Title:     cache.Title,
+		Summary:   cache.Description,
+		Image:     cache.ImageUrl,
+		Author:    cache.OwnerName,
+		Published: cache.CreatedAt,
+		Updated:   cache.UpdatedAt,
+		Tags:      cache.Tags,
+	}
+	if link, ok := unfurl.LinkURL(cache.Content); ok {
+		item.Link = link
+	} else {
+		// a note has no link of its own, readers open its public page
+		item.Link = item.ID
+		item.Content = cache.Content
+	}
+	return item
+}
+
+type feedCacheRequest struct {
+	CacheID int64 `uri:"cache_id" binding:"required,min=1"`
+}
+
+// feedCache serves a public cache without a login, it is the page feed items
+// link to. A private cache is answered like a missing one.
+func (server *Server) feedCache(ctx *gin.Context) {
+	var req feedCacheRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	cache, err := server.store.GetCache(ctx, req.CacheID)
+	if err == nil && !cache.IsPublic.Bool {
+		err = db.ErrRecordNotFound
+	}
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	tags, err := server.store.ListCacheTags(ctx, cache.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.Header("Cache-Control", fmt.Sprintf("public, max-age=%d", int(feedMaxAge.Seconds())))
+	ctx.JSON(http.StatusOK, cacheWithTagsResponse{Cache: cache, Tags: tags})
+}
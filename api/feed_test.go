@@ -0,0 +1,479 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/imrishuroy/read-cache-api/db/mock"
+	db "github.com/imrishuroy/read-cache-api/db/sqlc"
+	"github.com/imrishuroy/read-cache-api/util"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func serveFeedRequest(
+	t *testing.T,
+	buildStubs func(store *mockdb.MockStore),
+	url string,
+	header http.Header,
+) *httptest.ResponseRecorder {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	buildStubs(store)
+
+	server := newTestServer(t, store)
+	server.config.PublicURL = "https://readcache.example/"
+	recorder := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodGet, url, nil)
+	require.NoError(t, err)
+	for key, values := range header {
+		request.Header[key] = values
+	}
+
+	// no authorization, feed readers fetch anonymously
+	server.router.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func randomFeedCaches() []db.ListFeedCachesRow {
+	createdAt := time.Now().UTC().Truncate(time.Second)
+	return []db.ListFeedCachesRow{
+		{
+			ID:          2,
+			Owner:       util.RandomOwner(),
+			Title:       util.RandomTitle(),
+			Content:     "https://go.dev/blog/channels",
+			CreatedAt:   createdAt,
+			UpdatedAt:   createdAt,
+			OwnerName:   "Ann",
+			Description: "Share memory by communicating",
+			Tags:        []string{"go"},
+		},
+		{
+			ID:        1,
+			Owner:     util.RandomOwner(),
+			Title:     util.RandomTitle(),
+			Content:   "a note",
+			CreatedAt: createdAt.Add(-time.Hour),
+			// edited after the newest cache was saved
+			UpdatedAt: createdAt.Add(time.Minute),
+			OwnerName: "Bob",
+		},
+	}
+}
+
+func TestPublicFeedAPI(t *testing.T) {
+	caches := randomFeedCaches()
+	lastModified := caches[1].UpdatedAt.Format(http.TimeFormat)
+
+	expectList := func(store *mockdb.MockStore) {
+		store.EXPECT().
+			ListFeedCaches(gomock.Any(), db.ListFeedCachesParams{PageLimit: feedLimit}).
+			Times(1).
+			Return(caches, nil)
+	}
+
+	// the ETag is a hash of the document, fetch it once to revalidate against it
+	first := serveFeedRequest(t, expectList, "/feeds/public/json", nil)
+	require.Equal(t, http.StatusOK, first.Code)
+	etag := first.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+
+	testCases := []struct {
+		name          string
+		format        string
+		header        http.Header
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:       "JSON",
+			format:     "json",
+			buildStubs: expectList,
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, "application/feed+json; charset=utf-8", recorder.Header().Get("Content-Type"))
+				require.Equal(t, lastModified, recorder.Header().Get("Last-Modified"))
+				require.Equal(t, "public, max-age=300", recorder.Header().Get("Cache-Control"))
+
+				var rsp struct {
+					HomePageURL string `json:"home_page_url"`
+					FeedURL     string `json:"feed_url"`
+					Items       []struct {
+						ID           string `json:"id"`
+						URL          string `json:"url"`
+						ContentText  string `json:"content_text"`
+						DateModified string `json:"date_modified"`
+					} `json:"items"`
+				}
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.Equal(t, "https://readcache.example/feeds/public/json", rsp.FeedURL)
+				require.Equal(t, rsp.FeedURL, rsp.HomePageURL)
+				require.Len(t, rsp.Items, 2)
+				require.Equal(t, "https://readcache.example/feeds/caches/2", rsp.Items[0].ID)
+				require.Equal(t, caches[0].Content, rsp.Items[0].URL)
+				require.Empty(t, rsp.Items[0].DateModified)
+				// a note links to its public page
+				require.Equal(t, "https://readcache.example/feeds/caches/1", rsp.Items[1].URL)
+				require.Equal(t, "a note", rsp.Items[1].ContentText)
+				require.Equal(t, caches[1].UpdatedAt.Format(time.RFC3339), rsp.Items[1].DateModified)
+			},
+		},
+		{
+			name:       "RSS",
+			format:     "rss",
+			buildStubs: expectList,
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, "application/rss+xml; charset=utf-8", recorder.Header().Get("Content-Type"))
+				require.Contains(t, recorder.Body.String(), `<rss version="2.0"`)
+				require.NotEqual(t, etag, recorder.Header().Get("ETag"))
+			},
+		},
+		{
+			name:       "Atom",
+			format:     "atom",
+			buildStubs: expectList,
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, "application/atom+xml; charset=utf-8", recorder.Header().Get("Content-Type"))
+				require.Contains(t, recorder.Body.String(), `<feed xmlns="http://www.w3.org/2005/Atom">`)
+			},
+		},
+		{
+			name:       "IfNoneMatch",
+			format:     "json",
+			header:     http.Header{"If-None-Match": {`"stale", ` + etag}},
+			buildStubs: expectList,
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotModified, recorder.Code)
+				require.Equal(t, etag, recorder.Header().Get("ETag"))
+				require.Empty(t, recorder.Body.Bytes())
+			},
+		},
+		{
+			name:   "IfNoneMatchWinsOverIfModifiedSince",
+			format: "json",
+			header: http.Header{
+				"If-None-Match":     {`"stale"`},
+				"If-Modified-Since": {lastModified},
+			},
+			buildStubs: expectList,
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:       "IfModifiedSince",
+			format:     "atom",
+			header:     http.Header{"If-Modified-Since": {lastModified}},
+			buildStubs: expectList,
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotModified, recorder.Code)
+			},
+		},
+		{
+			name:   "Edited",
+			format: "json",
+			header: http.Header{"If-None-Match": {etag}},
+			buildStubs: func(store *mockdb.MockStore) {
+				edited := randomFeedCaches()
+				copy(edited, caches)
+				edited[0].Title = "edited"
+				edited[0].UpdatedAt = caches[1].UpdatedAt.Add(time.Minute)
+				store.EXPECT().
+					ListFeedCaches(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(edited, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.NotEqual(t, etag, recorder.Header().Get("ETag"))
+				require.Equal(t, caches[1].UpdatedAt.Add(time.Minute).Format(http.TimeFormat), recorder.Header().Get("Last-Modified"))
+			},
+		},
+		{
+			name:   "Removed",
+			format: "json",
+			header: http.Header{"If-None-Match": {etag}},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListFeedCaches(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(caches[:1], nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Regexp(t, `^"1-`, recorder.Header().Get("ETag"))
+			},
+		},
+		{
+			name:   "ModifiedSince",
+			format: "atom",
+			header: http.Header{
+				"If-Modified-Since": {caches[1].UpdatedAt.Add(-time.Second).Format(http.TimeFormat)},
+			},
+			buildStubs: expectList,
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:   "InvalidFormat",
+			format: "opml",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListFeedCaches(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:   "InternalError",
+			format: "rss",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListFeedCaches(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, sql.ErrConnDone)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := serveFeedRequest(t, tc.buildStubs, "/feeds/public/"+tc.format, tc.header)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestTagFeedAPI(t *testing.T) {
+	tag := db.Tag{TagID: 7, TagName: "go"}
+
+	testCases := []struct {
+		name          string
+		url           string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			url:  "/feeds/tags/Go/atom",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetTagByName(gomock.Any(), tag.TagName).
+					Times(1).
+					Return(tag, nil)
+				store.EXPECT().
+					ListFeedCaches(gomock.Any(), db.ListFeedCachesParams{
+						TagID:     pgtype.Int4{Int32: tag.TagID, Valid: true},
+						PageLimit: feedLimit,
+					}).
+					Times(1).
+					Return(randomFeedCaches(), nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Contains(t, recorder.Body.String(), "<title>#go on ReadCache</title>")
+			},
+		},
+		{
+			name: "Empty",
+			url:  "/feeds/tags/go/rss",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetTagByName(gomock.Any(), tag.TagName).
+					Times(1).
+					Return(tag, nil)
+				store.EXPECT().
+					ListFeedCaches(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return([]db.ListFeedCachesRow{}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, time.Unix(0, 0).UTC().Format(http.TimeFormat), recorder.Header().Get("Last-Modified"))
+			},
+		},
+		{
+			name: "NotFound",
+			url:  "/feeds/tags/unknown/rss",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetTagByName(gomock.Any(), "unknown").
+					Times(1).
+					Return(db.Tag{}, db.ErrRecordNotFound)
+				store.EXPECT().
+					ListFeedCaches(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := serveFeedRequest(t, tc.buildStubs, tc.url, nil)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestUserFeedAPI(t *testing.T) {
+	user := db.User{ID: util.RandomOwner(), Name: "Ann"}
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), user.ID).
+					Times(1).
+					Return(user, nil)
+				store.EXPECT().
+					ListFeedCaches(gomock.Any(), db.ListFeedCachesParams{
+						Owner:     pgtype.Text{String: user.ID, Valid: true},
+						PageLimit: feedLimit,
+					}).
+					Times(1).
+					Return(randomFeedCaches(), nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Contains(t, recorder.Body.String(), `"title": "Ann on ReadCache"`)
+			},
+		},
+		{
+			name: "NotFound",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), user.ID).
+					Times(1).
+					Return(db.User{}, db.ErrRecordNotFound)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "InternalError",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), user.ID).
+					Times(1).
+					Return(db.User{}, sql.ErrConnDone)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := serveFeedRequest(t, tc.buildStubs, "/feeds/users/"+user.ID+"/json", nil)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestFeedCacheAPI(t *testing.T) {
+	cache := randomCache(util.RandomOwner(), true)
+	private := randomCache(util.RandomOwner(), false)
+	tags := []db.Tag{{TagID: 1, TagName: "go"}}
+
+	testCases := []struct {
+		name          string
+		cacheID       int64
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:    "OK",
+			cacheID: cache.ID,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetCache(gomock.Any(), cache.ID).
+					Times(1).
+					Return(cache, nil)
+				store.EXPECT().
+					ListCacheTags(gomock.Any(), cache.ID).
+					Times(1).
+					Return(tags, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp cacheWithTagsResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.Equal(t, cache.ID, rsp.ID)
+				require.Equal(t, tags, rsp.Tags)
+			},
+		},
+		{
+			name:    "Private",
+			cacheID: private.ID,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetCache(gomock.Any(), private.ID).
+					Times(1).
+					Return(private, nil)
+				store.EXPECT().
+					ListCacheTags(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:    "NotFound",
+			cacheID: cache.ID,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetCache(gomock.Any(), cache.ID).
+					Times(1).
+					Return(db.Cache{}, db.ErrRecordNotFound)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := serveFeedRequest(t, tc.buildStubs, fmt.Sprintf("/feeds/caches/%d", tc.cacheID), nil)
+			tc.checkResponse(recorder)
+		})
+	}
+}
@@ -0,0 +1,342 @@
+package api
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/imrishuroy/read-cache-api/auth"
+	db "github.com/imrishuroy/read-cache-api/db/sqlc"
+	"github.com/imrishuroy/read-cache-api/importer"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	maxImportFileSize = 20 << 20
+	maxImportItems    = 20000
+	// listImportsLimit is how many of the latest imports GET /import returns
+	listImportsLimit = 20
+)
+
+var (
+	errImportTooLarge     = errors.New("import file must be at most 20MB")
+	errImportEmpty        = errors.New("import file has no bookmarks")
+	errImportTooMany      = errors.New("import file must have at most 20000 bookmarks")
+	errImportNotFound     = errors.New("import not found")
+	errImportsCursorOnly  = errors.New("page_id is not supported on import items, use cursor")
+	errImportFileRequired = errors.New("file is required")
+)
+
+// importJobResponse leaves out the retry bookkeeping of db.ImportJob
+type importJobResponse struct {
+	ID         int64      `json:"id"`
+	Format     string     `json:"format"`
+	Filename   string     `json:"filename"`
+	Status     string     `json:"status"`
+	Total      int32      `json:"total"`
+	Processed  int32      `json:"processed"`
+	Created    int32      `json:"created"`
+	Skipped    int32      `json:"skipped"`
+	Failed     int32      `json:"failed"`
+	Error      string     `json:"error,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	FinishedAt *time.Time `json:"finished_at"`
+}
+
+func newImportJobResponse(job db.ImportJob) importJobResponse {
+	rsp := importJobResponse{
+		ID:        job.ID,
+		Format:    job.Format,
+		Filename:  job.Filename,
+		Status:    job.Status,
+		Total:     job.Total,
+		Processed: job.Processed,
+		Created:   job.CreatedCount,
+		Skipped:   job.SkippedCount,
+		Failed:    job.FailedCount,
+		Error:     job.LastError,
+		CreatedAt: job.CreatedAt,
+	}
+	if job.FinishedAt.Valid {
+		rsp.FinishedAt = &job.FinishedAt.Time
+	}
+	return rsp
+}
+
+type importItemResponse struct {
+	Position int32    `json:"position"`
+	URL      string   `json:"url"`
+	Title    string   `json:"title"`
+	Tags     []string `json:"tags"`
+	Status   string   `json:"status"`
+	CacheID  *int64   `json:"cache_id"`
+	Error    string   `json:"error,omitempty"`
+
+	createdAt time.Time
+}
+
+func newImportItemResponse(item db.ImportItem) importItemResponse {
+	rsp := importItemResponse{
+		Position:  item.Position,
+		URL:       item.Url,
+		Title:     item.Title,
+		Tags:      item.Tags,
+		Status:    item.Status,
+		Error:     item.Error,
+		createdAt: item.CreatedAt,
+	}
+	if item.CacheID.Valid {
+		rsp.CacheID = &item.CacheID.Int64
+	}
+	return rsp
+}
+
+func importItemCursor(item importItemResponse) pageCursor {
+	return pageCursor{CreatedAt: item.createdAt, ID: int64(item.Position)}
+}
+
+type createImportRequest struct {
+	// Format skips detection when set
+	Format string `form:"format" binding:"omitempty,oneof=netscape pocket_html pocket_csv instapaper json"`
+}
+
+// createImport reads an uploaded export and queues its bookmarks as an import
+// job. Parsing happens up front so a broken file is rejected right away, the
+// caches themselves are created in the background by importer.Worker.
+func (server *Server) createImport(ctx *gin.Context) {
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxImportFileSize+(1<<20))
+
+	var req createImportRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		ctx.JSON(importUploadStatus(err), errorResponse(err))
+		return
+	}
+
+	fileHeader, err := ctx.FormFile("file")
+	if err != nil {
+		status := importUploadStatus(err)
+		if status == http.StatusBadRequest {
+			err = errImportFileRequired
+		}
+		ctx.JSON(status, errorResponse(err))
+		return
+	}
+	if fileHeader.Size > maxImportFileSize {
+		ctx.JSON(http.StatusRequestEntityTooLarge, errorResponse(errImportTooLarge))
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	format := importer.Format(req.Format)
+	if format == "" {
+		format, err = importer.Detect(data)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+	}
+
+	items, err := importer.Parse(format, data)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	switch {
+	case len(items) == 0:
+		ctx.JSON(http.StatusBadRequest, errorResponse(errImportEmpty))
+		return
+	case len(items) > maxImportItems:
+		ctx.JSON(http.StatusBadRequest, errorResponse(errImportTooMany))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*auth.Principal)
+
+	arg := db.CreateImportJobTxParams{
+		UserID:   authPayload.UID,
+		Format:   string(format),
+		Filename: fileHeader.Filename,
+		Items:    make([]db.NewImportItem, len(items)),
+	}
+	for i, item := range items {
+		arg.Items[i] = db.NewImportItem{
+			Position:      int32(i + 1),
+			URL:           item.URL,
+			Title:         item.Title,
+			Content:       item.Content,
+			Tags:          item.Tags,
+			Archived:      item.Archived,
+			IsPublic:      item.IsPublic,
+			ReadingStatus: item.Status,
+			Progress:      item.Progress,
+		}
+		if !item.AddedAt.IsZero() {
+			arg.Items[i].AddedAt = &items[i].AddedAt
+		}
+		if !item.ReadAt.IsZero() {
+			arg.Items[i].ReadAt = &items[i].ReadAt
+		}
+		if !item.ArchivedAt.IsZero() {
+			arg.Items[i].ArchivedAt = &items[i].ArchivedAt
+		}
+	}
+
+	job, err := server.store.CreateImportJobTx(ctx, arg)
+	if err != nil {
+		if db.ErrorCode(err) == db.ForeignKeyViolation {
+			ctx.JSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, newImportJobResponse(job))
+}
+
+// importUploadStatus tells an upload over the size limit apart from a malformed one
+func importUploadStatus(err error) int {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return http.StatusRequestEntityTooLarge
+	}
+	return http.StatusBadRequest
+}
+
+// listImports lists the caller's latest imports, newest first
+func (server *Server) listImports(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*auth.Principal)
+
+	jobs, err := server.store.ListImportJobs(ctx, db.ListImportJobsParams{
+		UserID: authPayload.UID,
+		Limit:  listImportsLimit,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp := make([]importJobResponse, len(jobs))
+	for i, job := range jobs {
+		rsp[i] = newImportJobResponse(job)
+	}
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+type importURI struct {
+	ImportID int64 `uri:"import_id" binding:"required,min=1"`
+}
+
+// getImport reports the progress of an import, clients poll it until the
+// status is done or failed
+func (server *Server) getImport(ctx *gin.Context) {
+	var uri importURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	job, ok := server.authorizeImport(ctx, uri.ImportID)
+	if !ok {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newImportJobResponse(job))
+}
+
+type listImportItemsRequest struct {
+	Status string `form:"status" binding:"omitempty,oneof=pending created skipped failed"`
+}
+
+// listImportItems pages through the per item report of an import in file order
+func (server *Server) listImportItems(ctx *gin.Context) {
+	var uri importURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req listImportItemsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var pageReq pageRequest
+	if err := ctx.ShouldBindQuery(&pageReq); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if pageReq.PageID > 0 {
+		ctx.JSON(http.StatusBadRequest, errorResponse(errImportsCursorOnly))
+		return
+	}
+	p, err := server.resolvePage(pageReq)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	job, ok := server.authorizeImport(ctx, uri.ImportID)
+	if !ok {
+		return
+	}
+
+	arg := db.ListImportItemsParams{
+		JobID:           job.ID,
+		Status:          pgtype.Text{String: req.Status, Valid: req.Status != ""},
+		CursorCreatedAt: p.cursorCreatedAt(),
+		PageLimit:       p.fetchLimit(),
+	}
+	if p.cursor != nil {
+		arg.CursorID = pgtype.Int4{Int32: int32(p.cursor.ID), Valid: true}
+	}
+
+	rows, err := server.store.ListImportItems(ctx, arg)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	items := make([]importItemResponse, len(rows))
+	for i, row := range rows {
+		items[i] = newImportItemResponse(row)
+	}
+
+	ctx.JSON(http.StatusOK, newPageResponse(items, p, importItemCursor))
+}
+
+// authorizeImport loads an import of the caller, other users' imports are
+// reported as missing
+func (server *Server) authorizeImport(ctx *gin.Context, id int64) (db.ImportJob, bool) {
+	job, err := server.store.GetImportJob(ctx, id)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(errImportNotFound))
+			return job, false
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return job, false
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*auth.Principal)
+	if job.UserID != authPayload.UID {
+		ctx.JSON(http.StatusNotFound, errorResponse(errImportNotFound))
+		return job, false
+	}
+	return job, true
+}
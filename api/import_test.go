@@ -0,0 +1,391 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/imrishuroy/read-cache-api/db/mock"
+	db "github.com/imrishuroy/read-cache-api/db/sqlc"
+	"github.com/imrishuroy/read-cache-api/util"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+const testPocketCSV = "title,url,time_added,tags,status\n" +
+	"First,https://example.com/first,1650000000,go|reading,unread\n" +
+	"Second,https://example.com/second,,,archive\n"
+
+func TestCreateImportAPI(t *testing.T) {
+	owner := util.RandomOwner()
+	job := randomImportJob(owner)
+
+	testCases := []struct {
+		name          string
+		format        string
+		filename      string
+		content       string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "OK",
+			filename: "pocket.csv",
+			content:  testPocketCSV,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateImportJobTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreateImportJobTxParams) (db.ImportJob, error) {
+						require.Equal(t, owner, arg.UserID)
+						require.Equal(t, "pocket_csv", arg.Format)
+						require.Equal(t, "pocket.csv", arg.Filename)
+						require.Len(t, arg.Items, 2)
+
+						require.Equal(t, int32(1), arg.Items[0].Position)
+						require.Equal(t, "https://example.com/first", arg.Items[0].URL)
+						require.Equal(t, []string{"go", "reading"}, arg.Items[0].Tags)
+						require.NotNil(t, arg.Items[0].AddedAt)
+						require.Equal(t, int64(1650000000), arg.Items[0].AddedAt.Unix())
+
+						require.Equal(t, int32(2), arg.Items[1].Position)
+						require.Nil(t, arg.Items[1].AddedAt)
+						require.True(t, arg.Items[1].Archived)
+						return job, nil
+					})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusAccepted, recorder.Code)
+
+				var rsp importJobResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.Equal(t, job.ID, rsp.ID)
+				require.Equal(t, job.Status, rsp.Status)
+				require.Equal(t, job.Total, rsp.Total)
+			},
+		},
+		{
+			name:     "ExplicitFormat",
+			format:   "netscape",
+			filename: "bookmarks.html",
+			content:  `<DL><p><DT><H3>Go</H3><DL><p><DT><A HREF="https://go.dev">Go</A></DL><p></DL><p>`,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateImportJobTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreateImportJobTxParams) (db.ImportJob, error) {
+						require.Equal(t, "netscape", arg.Format)
+						require.Equal(t, []db.NewImportItem{
+							{Position: 1, URL: "https://go.dev", Title: "Go", Tags: []string{"go"}},
+						}, arg.Items)
+						return job, nil
+					})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusAccepted, recorder.Code)
+			},
+		},
+		{
+			name: "NoFile",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateImportJobTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "InvalidFormat",
+			format:   "delicious",
+			filename: "pocket.csv",
+			content:  testPocketCSV,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateImportJobTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "UnknownFormat",
+			filename: "notes.csv",
+			content:  "id,name\n1,foo\n",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateImportJobTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "NoBookmarks",
+			filename: "bookmarks.html",
+			content:  "<!DOCTYPE NETSCAPE-Bookmark-file-1>\n<DL><p>\n</DL><p>\n",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateImportJobTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "InternalError",
+			filename: "pocket.csv",
+			content:  testPocketCSV,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateImportJobTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.ImportJob{}, sql.ErrConnDone)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			var body bytes.Buffer
+			writer := multipart.NewWriter(&body)
+			if tc.format != "" {
+				require.NoError(t, writer.WriteField("format", tc.format))
+			}
+			if tc.filename != "" {
+				part, err := writer.CreateFormFile("file", tc.filename)
+				require.NoError(t, err)
+				_, err = part.Write([]byte(tc.content))
+				require.NoError(t, err)
+			}
+			require.NoError(t, writer.Close())
+
+			request, err := http.NewRequest(http.MethodPost, "/api/import", &body)
+			require.NoError(t, err)
+			request.Header.Set("Content-Type", writer.FormDataContentType())
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+			addAuthorization(t, request, server.verifier, owner)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestGetImportAPI(t *testing.T) {
+	owner := util.RandomOwner()
+	job := randomImportJob(owner)
+
+	testCases := []struct {
+		name          string
+		uid           string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			uid:  owner,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetImportJob(gomock.Any(), gomock.Eq(job.ID)).
+					Times(1).
+					Return(job, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp importJobResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.Equal(t, job.ID, rsp.ID)
+				require.Equal(t, job.Processed, rsp.Processed)
+				require.Equal(t, job.CreatedCount, rsp.Created)
+				require.Equal(t, job.SkippedCount, rsp.Skipped)
+				require.Equal(t, job.FailedCount, rsp.Failed)
+			},
+		},
+		{
+			name: "OtherUser",
+			uid:  util.RandomOwner(),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetImportJob(gomock.Any(), gomock.Eq(job.ID)).
+					Times(1).
+					Return(job, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "NotFound",
+			uid:  owner,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetImportJob(gomock.Any(), gomock.Eq(job.ID)).
+					Times(1).
+					Return(db.ImportJob{}, db.ErrRecordNotFound)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			url := fmt.Sprintf("/api/import/%d", job.ID)
+			recorder := serveTestRequest(t, tc.buildStubs, tc.uid, http.MethodGet, url, nil)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestListImportItemsAPI(t *testing.T) {
+	owner := util.RandomOwner()
+	job := randomImportJob(owner)
+	createdAt := time.Now().UTC().Truncate(time.Second)
+
+	items := make([]db.ImportItem, 3)
+	for i := range items {
+		items[i] = db.ImportItem{
+			JobID:     job.ID,
+			Position:  int32(i + 1),
+			Url:       fmt.Sprintf("https://example.com/%d", i),
+			Status:    db.ImportItemCreated,
+			CacheID:   pgtype.Int8{Int64: int64(i + 100), Valid: true},
+			CreatedAt: createdAt,
+		}
+	}
+	cursor := encodeCursor(pageCursor{CreatedAt: createdAt, ID: 2})
+
+	testCases := []struct {
+		name          string
+		query         string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "OK",
+			query: "?page_size=2",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetImportJob(gomock.Any(), gomock.Eq(job.ID)).
+					Times(1).
+					Return(job, nil)
+				store.EXPECT().
+					ListImportItems(gomock.Any(), db.ListImportItemsParams{JobID: job.ID, PageLimit: 3}).
+					Times(1).
+					Return(items, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp pageResponse[importItemResponse]
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.Len(t, rsp.Items, 2)
+				require.Equal(t, int32(1), rsp.Items[0].Position)
+				require.Equal(t, int64(100), *rsp.Items[0].CacheID)
+				require.NotNil(t, rsp.NextCursor)
+				require.Equal(t, cursor, *rsp.NextCursor)
+			},
+		},
+		{
+			name:  "StatusAndCursor",
+			query: "?status=failed&cursor=" + cursor,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetImportJob(gomock.Any(), gomock.Eq(job.ID)).
+					Times(1).
+					Return(job, nil)
+				store.EXPECT().
+					ListImportItems(gomock.Any(), db.ListImportItemsParams{
+						JobID:           job.ID,
+						Status:          pgtype.Text{String: db.ImportItemFailed, Valid: true},
+						CursorCreatedAt: pgtype.Timestamptz{Time: createdAt, Valid: true},
+						CursorID:        pgtype.Int4{Int32: 2, Valid: true},
+						PageLimit:       defaultPageSize + 1,
+					}).
+					Times(1).
+					Return([]db.ImportItem{}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:  "InvalidStatus",
+			query: "?status=done",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetImportJob(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "LegacyPage",
+			query: "?page_id=1&page_size=5",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetImportJob(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			url := fmt.Sprintf("/api/import/%d/items%s", job.ID, tc.query)
+			recorder := serveTestRequest(t, tc.buildStubs, owner, http.MethodGet, url, nil)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func randomImportJob(owner string) db.ImportJob {
+	total := int32(util.RandomInt(10, 100))
+	return db.ImportJob{
+		ID:           util.RandomInt(1, 1000),
+		UserID:       owner,
+		Format:       "pocket_csv",
+		Status:       "running",
+		Total:        total,
+		Processed:    total / 2,
+		CreatedCount: total / 4,
+		SkippedCount: total / 8,
+		FailedCount:  total/2 - total/4 - total/8,
+	}
+}
@@ -0,0 +1,324 @@
+package api
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/imrishuroy/read-cache-api/auth"
+	db "github.com/imrishuroy/read-cache-api/db/sqlc"
+	"github.com/imrishuroy/read-cache-api/importer"
+	"github.com/imrishuroy/read-cache-api/unfurl"
+	"github.com/imrishuroy/read-cache-api/webhook"
+)
+
+const (
+	// maxInboundBodyBytes caps the payload of an inbound hook request
+	maxInboundBodyBytes = 64 << 10
+	// idempotencyKeyHeader carries the client id, it wins over the id field of the payload
+	idempotencyKeyHeader = "Idempotency-Key"
+	maxClientIDLength    = 255
+)
+
+var (
+	errInboundHookURL  = errors.New("url must be an http or https link")
+	errInboundClientID = errors.New("id must be at most 255 characters")
+)
+
+// inboundHookResponse never exposes the token hash or the signing secret
+type inboundHookResponse struct {
+	ID               int64      `json:"id"`
+	Name             string     `json:"name"`
+	RequireSignature bool       `json:"require_signature"`
+	IsPublic         bool       `json:"is_public"`
+	LastUsedAt       *time.Time `json:"last_used_at"`
+	CreatedAt        time.Time  `json:"created_at"`
+}
+
+func newInboundHookResponse(hook db.InboundHook) inboundHookResponse {
+	rsp := inboundHookResponse{
+		ID:               hook.ID,
+		Name:             hook.Name,
+		RequireSignature: hook.RequireSignature,
+		IsPublic:         hook.IsPublic,
+		CreatedAt:        hook.CreatedAt,
+	}
+	if hook.LastUsedAt.Valid {
+		rsp.LastUsedAt = &hook.LastUsedAt.Time
+	}
+	return rsp
+}
+
+type createInboundHookRequest struct {
+	Name string `json:"name" binding:"required,max=100"`
+	// RequireSignature rejects requests without a valid signature, otherwise
+	// the secret in the URL is enough
+	RequireSignature bool `json:"require_signature"`
+	// IsPublic is the visibility of the caches saved through the hook
+	IsPublic bool `json:"is_public"`
+}
+
+type createInboundHookResponse struct {
+	URL    string `json:"url"`
+	Secret string `json:"secret"`
+	inboundHookResponse
+}
+
+func (server *Server) createInboundHook(ctx *gin.Context) {
+	var req createInboundHookRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*auth.Principal)
+	if authPayload.IsPersonalAccessToken() {
+		err := errors.New("personal access tokens cannot create inbound hooks")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	token, hash, err := webhook.NewInboundToken()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	secret, err := webhook.NewSecret()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	arg := db.CreateInboundHookParams{
+		UserID:           authPayload.UID,
+		Name:             req.Name,
+		TokenHash:        hash,
+		Secret:           secret,
+		RequireSignature: req.RequireSignature,
+		IsPublic:         req.IsPublic,
+	}
+
+	hook, err := server.store.CreateInboundHook(ctx, arg)
+	if err != nil {
+		if db.ErrorCode(err) == db.ForeignKeyViolation {
+			ctx.JSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	// the URL and the secret are only ever returned here
+	rsp := createInboundHookResponse{
+		URL:                 server.publicURL(ctx) + "/hooks/" + token,
+		Secret:              hook.Secret,
+		inboundHookResponse: newInboundHookResponse(hook),
+	}
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+func (server *Server) listInboundHooks(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*auth.Principal)
+
+	hooks, err := server.store.ListInboundHooks(ctx, authPayload.UID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp := make([]inboundHookResponse, 0, len(hooks))
+	for _, hook := range hooks {
+		rsp = append(rsp, newInboundHookResponse(hook))
+	}
+
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+type inboundHookURI struct {
+	ID int64 `uri:"hook_id" binding:"required,min=1"`
+}
+
+func (server *Server) deleteInboundHook(ctx *gin.Context) {
+	var uri inboundHookURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*auth.Principal)
+
+	arg := db.DeleteInboundHookParams{
+		ID:     uri.ID,
+		UserID: authPayload.UID,
+	}
+
+	_, err := server.store.DeleteInboundHook(ctx, arg)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse())
+}
+
+type inboundTokenURI struct {
+	Token string `uri:"token" binding:"required"`
+}
+
+// inboundCacheRequest is the payload sent to an inbound hook, as JSON or as a
+// form. Tags are names, several can be given comma separated.
+type inboundCacheRequest struct {
+	ID    string   `json:"id" form:"id"`
+	URL   string   `json:"url" form:"url" binding:"required,max=2048"`
+	Title string   `json:"title" form:"title"`
+	Tags  []string `json:"tags" form:"tags"`
+}
+
+// saveInboundCache saves a link for the owner of the hook in the URL. Callers
+// have no login, the secret token in the URL identifies the hook and hooks
+// can additionally require a signed request. A repeated client id answers
+// with the cache the first request created.
+func (server *Server) saveInboundCache(ctx *gin.Context) {
+	var uri inboundTokenURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	hook, err := server.store.GetInboundHookByTokenHash(ctx, webhook.HashInboundToken(uri.Token))
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxInboundBodyBytes))
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			ctx.JSON(http.StatusRequestEntityTooLarge, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	// an unexpected signature is still checked, a wrong one means a misconfigured sender
+	if hook.RequireSignature || ctx.GetHeader(webhook.SignatureHeader) != "" {
+		if err := webhook.VerifyRequest(hook.Secret, ctx.Request.Header, body, time.Now()); err != nil {
+			ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+			return
+		}
+	}
+
+	ctx.Request.Body = io.NopCloser(bytes.NewReader(body))
+	var req inboundCacheRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	var query createCacheQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	clientID := strings.TrimSpace(req.ID)
+	if key := strings.TrimSpace(ctx.GetHeader(idempotencyKeyHeader)); key != "" {
+		clientID = key
+	}
+	if len(clientID) > maxClientIDLength {
+		ctx.JSON(http.StatusBadRequest, errorResponse(errInboundClientID))
+		return
+	}
+
+	linkURL, ok := unfurl.LinkURL(req.URL)
+	if !ok {
+		ctx.JSON(http.StatusBadRequest, errorResponse(errInboundHookURL))
+		return
+	}
+
+	var names []string
+	for _, tags := range req.Tags {
+		names = append(names, strings.Split(tags, ",")...)
+	}
+
+	title := strings.TrimSpace(req.Title)
+	if title == "" {
+		title = linkURL
+	}
+
+	cacheReq := createCacheRequest{
+		Title:    title,
+		Content:  linkURL,
+		IsPublic: &hook.IsPublic,
+	}
+	if err := binding.Validator.ValidateStruct(&cacheReq); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	arg := db.SaveInboundCacheTxParams{
+		CreateCacheWithTagsTxParams: newCreateCacheParams(hook.UserID, cacheReq),
+		TagNames:                    importer.NormalizeTags(names),
+		HookID:                      hook.ID,
+		ClientID:                    clientID,
+	}
+
+	result, err := server.store.SaveInboundCacheTx(ctx, arg)
+	if err != nil && clientID != "" && db.ErrorCode(err) == db.UniqueViolation {
+		// a concurrent request with the same client id may have won, trying
+		// again replays its cache
+		result, err = server.store.SaveInboundCacheTx(ctx, arg)
+	}
+	if err != nil {
+		if db.ErrorCode(err) == db.UniqueViolation && query.OnDuplicate == "merge" && len(arg.TagNames) > 0 {
+			// the tags were rolled back with the cache, merging adds them to
+			// the saved one so only now are they created
+			tagIDs, tagErr := server.upsertTags(ctx, arg.TagNames)
+			if tagErr != nil {
+				ctx.JSON(http.StatusInternalServerError, errorResponse(tagErr))
+				return
+			}
+			arg.TagIDs = tagIDs
+		}
+		server.handleCreateCacheError(ctx, err, arg.CreateCacheWithTagsTxParams, query.OnDuplicate)
+		return
+	}
+
+	if result.Replayed {
+		ctx.Header("Idempotent-Replayed", "true")
+	}
+
+	rsp := cacheWithTagsResponse{
+		Cache: result.Cache,
+		Tags:  result.Tags,
+	}
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+// upsertTags creates the named tags that don't exist yet and returns their ids
+func (server *Server) upsertTags(ctx *gin.Context, names []string) ([]int32, error) {
+	tagIDs := make([]int32, 0, len(names))
+	for _, name := range names {
+		tag, err := server.store.UpsertTag(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		tagIDs = append(tagIDs, tag.TagID)
+	}
+	return tagIDs, nil
+}
@@ -0,0 +1,395 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/imrishuroy/read-cache-api/db/mock"
+	db "github.com/imrishuroy/read-cache-api/db/sqlc"
+	"github.com/imrishuroy/read-cache-api/util"
+	"github.com/imrishuroy/read-cache-api/webhook"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func serveInboundRequest(
+	t *testing.T,
+	buildStubs func(store *mockdb.MockStore),
+	token string,
+	body string,
+	header http.Header,
+) *httptest.ResponseRecorder {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	buildStubs(store)
+
+	server := newTestServer(t, store)
+	recorder := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodPost, "/hooks/"+token, strings.NewReader(body))
+	require.NoError(t, err)
+	for key, values := range header {
+		request.Header[key] = values
+	}
+
+	// no authorization, the token in the URL identifies the hook
+	server.router.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func TestCreateInboundHookAPI(t *testing.T) {
+	owner := util.RandomOwner()
+
+	buildStubs := func(store *mockdb.MockStore) {
+		store.EXPECT().
+			CreateInboundHook(gomock.Any(), gomock.Any()).
+			Times(1).
+			DoAndReturn(func(_ context.Context, arg db.CreateInboundHookParams) (db.InboundHook, error) {
+				require.Equal(t, owner, arg.UserID)
+				require.Equal(t, "shortcuts", arg.Name)
+				require.True(t, arg.RequireSignature)
+				require.Len(t, arg.TokenHash, 64)
+				return db.InboundHook{
+					ID:               1,
+					UserID:           arg.UserID,
+					Name:             arg.Name,
+					TokenHash:        arg.TokenHash,
+					Secret:           arg.Secret,
+					RequireSignature: arg.RequireSignature,
+				}, nil
+			})
+	}
+
+	body := gin.H{"name": "shortcuts", "require_signature": true}
+	recorder := serveTestRequest(t, buildStubs, owner, http.MethodPost, "/api/inbound-hooks", body)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var rsp createInboundHookResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+	require.True(t, strings.HasPrefix(rsp.URL, "http://"))
+	require.Contains(t, rsp.URL, "/hooks/"+webhook.InboundTokenPrefix)
+	require.True(t, strings.HasPrefix(rsp.Secret, "whsec_"))
+	require.NotContains(t, recorder.Body.String(), "token_hash")
+}
+
+func TestDeleteInboundHookAPI(t *testing.T) {
+	owner := util.RandomOwner()
+
+	buildStubs := func(store *mockdb.MockStore) {
+		store.EXPECT().
+			DeleteInboundHook(gomock.Any(), gomock.Eq(db.DeleteInboundHookParams{ID: 3, UserID: owner})).
+			Times(1).
+			Return(db.InboundHook{}, db.ErrRecordNotFound)
+	}
+
+	recorder := serveTestRequest(t, buildStubs, owner, http.MethodDelete, "/api/inbound-hooks/3", nil)
+	require.Equal(t, http.StatusNotFound, recorder.Code)
+}
+
+func TestSaveInboundCacheAPI(t *testing.T) {
+	owner := util.RandomOwner()
+	token, hash, err := webhook.NewInboundToken()
+	require.NoError(t, err)
+
+	hook := db.InboundHook{
+		ID:        7,
+		UserID:    owner,
+		Name:      "shortcuts",
+		TokenHash: hash,
+		Secret:    "whsec_test",
+	}
+	signedHook := hook
+	signedHook.RequireSignature = true
+
+	link := "https://example.com/article"
+	cache := db.Cache{
+		ID:            11,
+		Owner:         owner,
+		Title:         "An article",
+		Content:       link,
+		IsPublic:      pgtype.Bool{Bool: false, Valid: true},
+		NormalizedUrl: "https://example.com/article",
+	}
+
+	jsonHeader := http.Header{"Content-Type": []string{"application/json"}}
+	signed := func(body string, sentAt time.Time) http.Header {
+		header := jsonHeader.Clone()
+		header.Set(webhook.TimestampHeader, strconv.FormatInt(sentAt.Unix(), 10))
+		header.Set(webhook.SignatureHeader, webhook.Sign(hook.Secret, sentAt.Unix(), []byte(body)))
+		return header
+	}
+
+	expectHook := func(store *mockdb.MockStore, hook db.InboundHook) {
+		store.EXPECT().
+			GetInboundHookByTokenHash(gomock.Any(), gomock.Eq(hash)).
+			Times(1).
+			Return(hook, nil)
+	}
+
+	testCases := []struct {
+		name          string
+		token         string
+		body          string
+		header        http.Header
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "JSON",
+			body:   `{"url":"https://example.com/article","title":"An article","tags":["Go","reading, Later"],"id":"abc"}`,
+			header: jsonHeader,
+			buildStubs: func(store *mockdb.MockStore) {
+				expectHook(store, hook)
+				// tags are created by the transaction, never for a replayed request
+				store.EXPECT().UpsertTag(gomock.Any(), gomock.Any()).Times(0)
+
+				arg := db.SaveInboundCacheTxParams{
+					CreateCacheWithTagsTxParams: db.CreateCacheWithTagsTxParams{
+						CreateCacheParams: db.CreateCacheParams{
+							Owner:         owner,
+							Title:         "An article",
+							Content:       link,
+							IsPublic:      pgtype.Bool{Bool: false, Valid: true},
+							NormalizedUrl: cache.NormalizedUrl,
+						},
+						LinkURL: link,
+					},
+					TagNames: []string{"go", "reading", "later"},
+					HookID:   hook.ID,
+					ClientID: "abc",
+				}
+				store.EXPECT().
+					SaveInboundCacheTx(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(db.SaveInboundCacheTxResult{
+						CreateCacheWithTagsTxResult: db.CreateCacheWithTagsTxResult{Cache: cache},
+					}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Empty(t, recorder.Header().Get("Idempotent-Replayed"))
+
+				var rsp cacheWithTagsResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.Equal(t, cache.ID, rsp.ID)
+			},
+		},
+		{
+			name: "FormWithIdempotencyKey",
+			body: url.Values{"url": {link}, "id": {"from-body"}}.Encode(),
+			header: http.Header{
+				"Content-Type":    []string{"application/x-www-form-urlencoded"},
+				"Idempotency-Key": []string{"from-header"},
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				expectHook(store, hook)
+				store.EXPECT().
+					SaveInboundCacheTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.SaveInboundCacheTxParams) (db.SaveInboundCacheTxResult, error) {
+						require.Equal(t, "from-header", arg.ClientID)
+						require.Equal(t, link, arg.Title)
+						require.Empty(t, arg.TagNames)
+						return db.SaveInboundCacheTxResult{
+							CreateCacheWithTagsTxResult: db.CreateCacheWithTagsTxResult{Cache: cache},
+							Replayed:                    true,
+						}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, "true", recorder.Header().Get("Idempotent-Replayed"))
+			},
+		},
+		{
+			name:   "ConcurrentRetryReplays",
+			body:   `{"url":"https://example.com/article","id":"abc"}`,
+			header: jsonHeader,
+			buildStubs: func(store *mockdb.MockStore) {
+				expectHook(store, hook)
+				gomock.InOrder(
+					store.EXPECT().
+						SaveInboundCacheTx(gomock.Any(), gomock.Any()).
+						Return(db.SaveInboundCacheTxResult{}, db.ErrUniqueViolation),
+					store.EXPECT().
+						SaveInboundCacheTx(gomock.Any(), gomock.Any()).
+						Return(db.SaveInboundCacheTxResult{
+							CreateCacheWithTagsTxResult: db.CreateCacheWithTagsTxResult{Cache: cache},
+							Replayed:                    true,
+						}, nil),
+				)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, "true", recorder.Header().Get("Idempotent-Replayed"))
+			},
+		},
+		{
+			name:   "DuplicateLink",
+			body:   `{"url":"https://example.com/article"}`,
+			header: jsonHeader,
+			buildStubs: func(store *mockdb.MockStore) {
+				expectHook(store, hook)
+				store.EXPECT().
+					SaveInboundCacheTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.SaveInboundCacheTxResult{}, db.ErrUniqueViolation)
+				store.EXPECT().
+					GetCacheByNormalizedURL(gomock.Any(), gomock.Eq(db.GetCacheByNormalizedURLParams{
+						Owner:         owner,
+						NormalizedUrl: cache.NormalizedUrl,
+					})).
+					Times(1).
+					Return(cache, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+				require.JSONEq(t, `{"error":"link is already saved","cache_id":11}`, recorder.Body.String())
+			},
+		},
+		{
+			name:   "DuplicateLinkMerge",
+			token:  token + "?on_duplicate=merge",
+			body:   `{"url":"https://example.com/article","tags":["go"]}`,
+			header: jsonHeader,
+			buildStubs: func(store *mockdb.MockStore) {
+				expectHook(store, hook)
+				store.EXPECT().
+					SaveInboundCacheTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.SaveInboundCacheTxResult{}, db.ErrUniqueViolation)
+				// the tags rolled back with the cache, merging creates them
+				store.EXPECT().
+					UpsertTag(gomock.Any(), gomock.Eq("go")).
+					Times(1).
+					Return(db.Tag{TagID: 1, TagName: "go"}, nil)
+				store.EXPECT().
+					GetCacheByNormalizedURL(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(cache, nil)
+				store.EXPECT().
+					ListCacheTags(gomock.Any(), gomock.Eq(cache.ID)).
+					Times(1).
+					Return([]db.Tag{}, nil)
+				store.EXPECT().
+					SetCacheTagsTx(gomock.Any(), gomock.Eq(db.SetCacheTagsTxParams{CacheID: cache.ID, TagIDs: []int32{1}})).
+					Times(1).
+					Return(db.SetCacheTagsTxResult{Tags: []db.Tag{{TagID: 1, TagName: "go"}}}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:   "Signed",
+			body:   `{"url":"https://example.com/article"}`,
+			header: signed(`{"url":"https://example.com/article"}`, time.Now()),
+			buildStubs: func(store *mockdb.MockStore) {
+				expectHook(store, signedHook)
+				store.EXPECT().
+					SaveInboundCacheTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.SaveInboundCacheTxResult{
+						CreateCacheWithTagsTxResult: db.CreateCacheWithTagsTxResult{Cache: cache},
+					}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:   "SignatureRequired",
+			body:   `{"url":"https://example.com/article"}`,
+			header: jsonHeader,
+			buildStubs: func(store *mockdb.MockStore) {
+				expectHook(store, signedHook)
+				store.EXPECT().
+					SaveInboundCacheTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:   "StaleSignature",
+			body:   `{"url":"https://example.com/article"}`,
+			header: signed(`{"url":"https://example.com/article"}`, time.Now().Add(-time.Hour)),
+			buildStubs: func(store *mockdb.MockStore) {
+				expectHook(store, hook)
+				store.EXPECT().
+					SaveInboundCacheTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:   "NotALink",
+			body:   `{"url":"remember the milk"}`,
+			header: jsonHeader,
+			buildStubs: func(store *mockdb.MockStore) {
+				expectHook(store, hook)
+				store.EXPECT().
+					SaveInboundCacheTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:   "MissingURL",
+			body:   `{"title":"no link"}`,
+			header: jsonHeader,
+			buildStubs: func(store *mockdb.MockStore) {
+				expectHook(store, hook)
+				store.EXPECT().
+					SaveInboundCacheTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:   "UnknownToken",
+			token:  webhook.InboundTokenPrefix + "unknown",
+			body:   `{"url":"https://example.com/article"}`,
+			header: jsonHeader,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetInboundHookByTokenHash(gomock.Any(), gomock.Eq(webhook.HashInboundToken(webhook.InboundTokenPrefix+"unknown"))).
+					Times(1).
+					Return(db.InboundHook{}, db.ErrRecordNotFound)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.token == "" {
+				tc.token = token
+			}
+			recorder := serveInboundRequest(t, tc.buildStubs, tc.token, tc.body, tc.header)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	db "github.com/imrishuroy/read-cache-api/db/sqlc"
+)
+
+var errLinkPreviewNotFound = errors.New("cache has no link preview")
+
+// linkPreviewResponse leaves out the retry bookkeeping of db.LinkPreview
+type linkPreviewResponse struct {
+	CacheID      int64      `json:"cache_id"`
+	URL          string     `json:"url"`
+	Status       string     `json:"status"`
+	Title        string     `json:"title"`
+	Description  string     `json:"description"`
+	SiteName     string     `json:"site_name"`
+	ImageURL     string     `json:"image_url"`
+	CanonicalURL string     `json:"canonical_url"`
+	FetchedAt    *time.Time `json:"fetched_at"`
+}
+
+func newLinkPreviewResponse(preview db.LinkPreview) linkPreviewResponse {
+	rsp := linkPreviewResponse{
+		CacheID:      preview.CacheID,
+		URL:          preview.Url,
+		Status:       preview.Status,
+		Title:        preview.Title,
+		Description:  preview.Description,
+		SiteName:     preview.SiteName,
+		ImageURL:     preview.ImageUrl,
+		CanonicalURL: preview.CanonicalUrl,
+	}
+	if preview.FetchedAt.Valid {
+		rsp.FetchedAt = &preview.FetchedAt.Time
+	}
+	return rsp
+}
+
+type getLinkPreviewRequest struct {
+	CacheID int64 `uri:"cache_id" binding:"required,min=1"`
+}
+
+func (server *Server) getLinkPreview(ctx *gin.Context) {
+	var req getLinkPreviewRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if _, ok := server.authorizeCache(ctx, req.CacheID, viewCache); !ok {
+		return
+	}
+
+	preview, err := server.store.GetLinkPreview(ctx, req.CacheID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(errLinkPreviewNotFound))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newLinkPreviewResponse(preview))
+}
@@ -0,0 +1,125 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/imrishuroy/read-cache-api/db/mock"
+	db "github.com/imrishuroy/read-cache-api/db/sqlc"
+	"github.com/imrishuroy/read-cache-api/util"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetLinkPreviewAPI(t *testing.T) {
+	owner := util.RandomOwner()
+	privateCache := randomCache(owner, false)
+	preview := db.LinkPreview{
+		CacheID:      privateCache.ID,
+		Url:          "https://example.com/article",
+		Status:       "done",
+		Title:        util.RandomTitle(),
+		Description:  util.RandomContent(),
+		SiteName:     "Example",
+		ImageUrl:     "https://example.com/cover.png",
+		CanonicalUrl: "https://example.com/article",
+		Attempts:     1,
+		FetchedAt:    pgtype.Timestamptz{Time: time.Now().UTC().Truncate(time.Second), Valid: true},
+	}
+
+	testCases := []struct {
+		name          string
+		uid           string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			uid:  owner,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetCache(gomock.Any(), gomock.Eq(privateCache.ID)).
+					Times(1).
+					Return(privateCache, nil)
+				store.EXPECT().
+					GetLinkPreview(gomock.Any(), gomock.Eq(privateCache.ID)).
+					Times(1).
+					Return(preview, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp linkPreviewResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.Equal(t, newLinkPreviewResponse(preview), rsp)
+				require.NotContains(t, recorder.Body.String(), "attempts")
+			},
+		},
+		{
+			name: "OtherUserPrivate",
+			uid:  util.RandomOwner(),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetCache(gomock.Any(), gomock.Eq(privateCache.ID)).
+					Times(1).
+					Return(privateCache, nil)
+				store.EXPECT().
+					GetLinkPreview(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "NoPreview",
+			uid:  owner,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetCache(gomock.Any(), gomock.Eq(privateCache.ID)).
+					Times(1).
+					Return(privateCache, nil)
+				store.EXPECT().
+					GetLinkPreview(gomock.Any(), gomock.Eq(privateCache.ID)).
+					Times(1).
+					Return(db.LinkPreview{}, db.ErrRecordNotFound)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "InternalError",
+			uid:  owner,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetCache(gomock.Any(), gomock.Eq(privateCache.ID)).
+					Times(1).
+					Return(privateCache, nil)
+				store.EXPECT().
+					GetLinkPreview(gomock.Any(), gomock.Eq(privateCache.ID)).
+					Times(1).
+					Return(db.LinkPreview{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			url := fmt.Sprintf("/api/caches/%d/preview", privateCache.ID)
+			recorder := serveTestRequest(t, tc.buildStubs, tc.uid, http.MethodGet, url, nil)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/imrishuroy/read-cache-api/auth"
+	mockdb "github.com/imrishuroy/read-cache-api/db/mock"
+	db "github.com/imrishuroy/read-cache-api/db/sqlc"
+	"github.com/imrishuroy/read-cache-api/util"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestServer(t *testing.T, store db.Store) *Server {
+	config := util.Config{
+		AuthProvider: "jwt",
+		JWTAlgorithm: auth.AlgorithmHS256,
+		JWTSecretKey: util.RandomString(32),
+		MaxPageSize:  50,
+	}
+
+	server, err := NewServer(config, store)
+	require.NoError(t, err)
+
+	return server
+}
+
+func addAuthorization(
+	t *testing.T,
+	request *http.Request,
+	verifier auth.TokenVerifier,
+	uid string,
+) {
+	jwtVerifier, ok := verifier.(*auth.JWTVerifier)
+	require.True(t, ok)
+
+	token, err := jwtVerifier.CreateToken(uid, fmt.Sprintf("%s@gmail.com", uid), time.Minute)
+	require.NoError(t, err)
+
+	authorizationHeader := fmt.Sprintf("%s %s", authorizationTypeBearer, token)
+	request.Header.Set(authorizationHeaderKey, authorizationHeader)
+}
+
+func serveTestRequest(
+	t *testing.T,
+	buildStubs func(store *mockdb.MockStore),
+	uid string,
+	method string,
+	url string,
+	body gin.H,
+) *httptest.ResponseRecorder {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	buildStubs(store)
+
+	server := newTestServer(t, store)
+	recorder := httptest.NewRecorder()
+
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		require.NoError(t, err)
+		reader = bytes.NewReader(data)
+	}
+
+	request, err := http.NewRequest(method, url, reader)
+	require.NoError(t, err)
+
+	addAuthorization(t, request, server.verifier, uid)
+	server.router.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func TestMain(m *testing.M) {
+	gin.SetMode(gin.TestMode)
+
+	os.Exit(m.Run())
+}
@@ -1,23 +1,28 @@
 package api
 
 import (
-	"context"
 	"errors"
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
-	"firebase.google.com/go/v4/auth"
 	"github.com/gin-gonic/gin"
+	"github.com/imrishuroy/read-cache-api/auth"
+	db "github.com/imrishuroy/read-cache-api/db/sqlc"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/rs/zerolog/log"
 )
 
 const (
 	authorizationHeaderKey  = "authorization"
 	authorizationTypeBearer = "bearer"
 	authorizationPayloadKey = "authorization_payload"
+	authorizationRoleKey    = "authorization_role"
 )
 
-func authMiddleware(auth *auth.Client) gin.HandlerFunc {
+// authMiddleware accepts ID tokens checked by the verifier and personal access tokens stored in the db
+func authMiddleware(verifier auth.TokenVerifier, store db.Store) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		authrorizationHeader := ctx.GetHeader(authorizationHeaderKey)
 		if len(authrorizationHeader) == 0 {
@@ -39,16 +44,104 @@ func authMiddleware(auth *auth.Client) gin.HandlerFunc {
 			return
 		}
 		accessToken := fields[1]
-		auth, err := auth.VerifyIDToken(context.Background(), accessToken)
+
+		if auth.IsPersonalAccessToken(accessToken) {
+			principal, err := verifyPersonalAccessToken(ctx, store, accessToken)
+			if err != nil {
+				ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err))
+				return
+			}
+
+			if principal.Scope() == auth.ScopeRead && !isReadOnlyMethod(ctx.Request.Method) {
+				err := errors.New("personal access token has read-only scope")
+				ctx.AbortWithStatusJSON(http.StatusForbidden, errorResponse(err))
+				return
+			}
+
+			ctx.Set(authorizationPayloadKey, principal)
+			ctx.Next()
+			return
+		}
+
+		principal, err := verifier.VerifyToken(ctx.Request.Context(), accessToken)
 		if err != nil {
 			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err))
 			return
 		}
 
-		fmt.Printf("firebase auth %v ", auth)
-
-		ctx.Set(authorizationPayloadKey, auth)
+		ctx.Set(authorizationPayloadKey, principal)
 		ctx.Next()
 
 	}
 }
+
+func verifyPersonalAccessToken(ctx *gin.Context, store db.Store, accessToken string) (*auth.Principal, error) {
+	token, err := store.GetPersonalAccessTokenByHash(ctx, auth.HashPersonalAccessToken(accessToken))
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil, auth.ErrInvalidToken
+		}
+		return nil, err
+	}
+
+	if token.RevokedAt.Valid {
+		return nil, errors.New("personal access token has been revoked")
+	}
+
+	if token.ExpiresAt.Valid && time.Now().After(token.ExpiresAt.Time) {
+		return nil, auth.ErrExpiredToken
+	}
+
+	arg := db.UpdatePersonalAccessTokenLastUsedParams{
+		ID:         token.ID,
+		LastUsedIp: pgtype.Text{String: ctx.ClientIP(), Valid: true},
+	}
+	if err := store.UpdatePersonalAccessTokenLastUsed(ctx, arg); err != nil {
+		// failing to record usage should not lock the user out
+		log.Error().Err(err).Int64("token_id", token.ID).Msg("cannot record personal access token usage")
+	}
+
+	return &auth.Principal{
+		UID: token.UserID,
+		Claims: map[string]interface{}{
+			auth.ClaimPersonalAccessTokenID: token.ID,
+			auth.ClaimScope:                 token.Scope,
+		},
+	}, nil
+}
+
+// requireRole aborts with 403 unless the authenticated user holds at least the required role.
+// The role comes from the token's custom claims when present, otherwise from the users table.
+func requireRole(store db.Store, required string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		authPayload := ctx.MustGet(authorizationPayloadKey).(*auth.Principal)
+
+		role := authPayload.Role()
+		if role == "" {
+			user, err := store.GetUser(ctx, authPayload.UID)
+			if err != nil {
+				if errors.Is(err, db.ErrRecordNotFound) {
+					err := fmt.Errorf("missing required role %s", required)
+					ctx.AbortWithStatusJSON(http.StatusForbidden, errorResponse(err))
+					return
+				}
+				ctx.AbortWithStatusJSON(http.StatusInternalServerError, errorResponse(err))
+				return
+			}
+			role = user.Role
+		}
+
+		if !auth.HasRole(role, required) {
+			err := fmt.Errorf("missing required role %s", required)
+			ctx.AbortWithStatusJSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+
+		ctx.Set(authorizationRoleKey, role)
+		ctx.Next()
+	}
+}
+
+func isReadOnlyMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
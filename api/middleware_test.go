@@ -0,0 +1,192 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/imrishuroy/read-cache-api/auth"
+	mockdb "github.com/imrishuroy/read-cache-api/db/mock"
+	db "github.com/imrishuroy/read-cache-api/db/sqlc"
+	"github.com/imrishuroy/read-cache-api/util"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthMiddlewarePersonalAccessToken(t *testing.T) {
+	owner := util.RandomOwner()
+	plainToken, hash, err := auth.NewPersonalAccessToken()
+	require.NoError(t, err)
+
+	token := randomPersonalAccessToken(owner)
+	token.TokenHash = hash
+
+	testCases := []struct {
+		name          string
+		method        string
+		setupAuth     func(request *http.Request)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "OK",
+			method: http.MethodGet,
+			setupAuth: func(request *http.Request) {
+				request.Header.Set(authorizationHeaderKey, fmt.Sprintf("%s %s", authorizationTypeBearer, plainToken))
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetPersonalAccessTokenByHash(gomock.Any(), gomock.Eq(hash)).
+					Times(1).
+					Return(token, nil)
+				store.EXPECT().
+					UpdatePersonalAccessTokenLastUsed(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.UpdatePersonalAccessTokenLastUsedParams) error {
+						require.Equal(t, token.ID, arg.ID)
+						return nil
+					})
+				// the request runs as the owner of the token
+				store.EXPECT().
+					ListPersonalAccessTokens(gomock.Any(), gomock.Eq(owner)).
+					Times(1).
+					Return([]db.PersonalAccessToken{token}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:   "Revoked",
+			method: http.MethodGet,
+			setupAuth: func(request *http.Request) {
+				request.Header.Set(authorizationHeaderKey, fmt.Sprintf("%s %s", authorizationTypeBearer, plainToken))
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				revoked := token
+				revoked.RevokedAt = pgtype.Timestamptz{Time: time.Now().Add(-time.Hour), Valid: true}
+				store.EXPECT().
+					GetPersonalAccessTokenByHash(gomock.Any(), gomock.Eq(hash)).
+					Times(1).
+					Return(revoked, nil)
+				store.EXPECT().
+					UpdatePersonalAccessTokenLastUsed(gomock.Any(), gomock.Any()).
+					Times(0)
+				store.EXPECT().
+					ListPersonalAccessTokens(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:   "Expired",
+			method: http.MethodGet,
+			setupAuth: func(request *http.Request) {
+				request.Header.Set(authorizationHeaderKey, fmt.Sprintf("%s %s", authorizationTypeBearer, plainToken))
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				expired := token
+				expired.ExpiresAt = pgtype.Timestamptz{Time: time.Now().Add(-time.Minute), Valid: true}
+				store.EXPECT().
+					GetPersonalAccessTokenByHash(gomock.Any(), gomock.Eq(hash)).
+					Times(1).
+					Return(expired, nil)
+				store.EXPECT().
+					UpdatePersonalAccessTokenLastUsed(gomock.Any(), gomock.Any()).
+					Times(0)
+				store.EXPECT().
+					ListPersonalAccessTokens(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:   "Unknown",
+			method: http.MethodGet,
+			setupAuth: func(request *http.Request) {
+				request.Header.Set(authorizationHeaderKey, fmt.Sprintf("%s %s", authorizationTypeBearer, plainToken))
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetPersonalAccessTokenByHash(gomock.Any(), gomock.Eq(hash)).
+					Times(1).
+					Return(db.PersonalAccessToken{}, db.ErrRecordNotFound)
+				store.EXPECT().
+					ListPersonalAccessTokens(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:   "ReadOnlyScope",
+			method: http.MethodPost,
+			setupAuth: func(request *http.Request) {
+				request.Header.Set(authorizationHeaderKey, fmt.Sprintf("%s %s", authorizationTypeBearer, plainToken))
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				readOnly := token
+				readOnly.Scope = auth.ScopeRead
+				store.EXPECT().
+					GetPersonalAccessTokenByHash(gomock.Any(), gomock.Eq(hash)).
+					Times(1).
+					Return(readOnly, nil)
+				store.EXPECT().
+					UpdatePersonalAccessTokenLastUsed(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil)
+				store.EXPECT().
+					CreatePersonalAccessToken(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:   "NoAuthorization",
+			method: http.MethodGet,
+			setupAuth: func(request *http.Request) {
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetPersonalAccessTokenByHash(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(tc.method, "/api/tokens", nil)
+			require.NoError(t, err)
+
+			tc.setupAuth(request)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package api
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	db "github.com/imrishuroy/read-cache-api/db/sqlc"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	defaultPageSize = 10
+	// legacy page_id requests keep their original page_size bounds
+	legacyMinPageSize = 5
+	legacyMaxPageSize = 10
+)
+
+var errInvalidCursor = errors.New("invalid cursor")
+
+// pageRequest holds the pagination query parameters shared by list endpoints.
+// page_id selects the deprecated offset pagination; otherwise cursor pagination is used.
+type pageRequest struct {
+	PageID   int32  `form:"page_id" binding:"omitempty,min=1"`
+	PageSize int32  `form:"page_size" binding:"omitempty,min=1"`
+	Cursor   string `form:"cursor"`
+}
+
+// page is a validated pageRequest
+type page struct {
+	legacy bool
+	limit  int32
+	offset int32
+	cursor *pageCursor
+}
+
+// pageCursor is the position of the last item of a page in (created_at, id) order
+type pageCursor struct {
+	CreatedAt time.Time `json:"created_at"`
+	ID        int64     `json:"id"`
+	// Likes is only set by rankings ordered by (likes, id)
+	Likes int64 `json:"likes,omitempty"`
+	// Rank is only set by search results in (rank, id) order
+	Rank float32 `json:"rank,omitempty"`
+	// Since is the start of a ranking's time window, fixed by its first page
+	Since *time.Time `json:"since,omitempty"`
+	// Position is only set by collections in their manual (position, id) order
+	Position int32 `json:"position,omitempty"`
+}
+
+type pageResponse[T any] struct {
+	Items      []T     `json:"items"`
+	NextCursor *string `json:"next_cursor"`
+}
+
+func (server *Server) resolvePage(req pageRequest) (page, error) {
+	if req.PageID > 0 {
+		if req.PageSize < legacyMinPageSize || req.PageSize > legacyMaxPageSize {
+			return page{}, fmt.Errorf("page_size must be between %d and %d", legacyMinPageSize, legacyMaxPageSize)
+		}
+		return page{
+			legacy: true,
+			limit:  req.PageSize,
+			offset: (req.PageID - 1) * req.PageSize,
+		}, nil
+	}
+
+	maxPageSize := server.config.MaxPageSize
+	if maxPageSize <= 0 {
+		maxPageSize = defaultPageSize
+	}
+
+	limit := req.PageSize
+	if limit == 0 {
+		limit = min(defaultPageSize, maxPageSize)
+	}
+	if limit > maxPageSize {
+		return page{}, fmt.Errorf("page_size must be at most %d", maxPageSize)
+	}
+
+	p := page{limit: limit}
+	if req.Cursor != "" {
+		cursor, err := decodeCursor(req.Cursor)
+		if err != nil {
+			return page{}, err
+		}
+		p.cursor = &cursor
+	}
+
+	return p, nil
+}
+
+// fetchLimit asks for one extra row so we know whether there is a next page
+func (p page) fetchLimit() int32 {
+	return p.limit + 1
+}
+
+func (p page) cursorCreatedAt() pgtype.Timestamptz {
+	if p.cursor == nil {
+		return pgtype.Timestamptz{}
+	}
+	return pgtype.Timestamptz{Time: p.cursor.CreatedAt, Valid: true}
+}
+
+func (p page) cursorID() pgtype.Int8 {
+	if p.cursor == nil {
+		return pgtype.Int8{}
+	}
+	return pgtype.Int8{Int64: p.cursor.ID, Valid: true}
+}
+
+func encodeCursor(cursor pageCursor) string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(s string) (pageCursor, error) {
+	var cursor pageCursor
+
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return cursor, errInvalidCursor
+	}
+
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.CreatedAt.IsZero() || cursor.ID <= 0 {
+		return cursor, errInvalidCursor
+	}
+
+	return cursor, nil
+}
+
+// newPageResponse trims the extra row fetched by fetchLimit and turns it into next_cursor
+func newPageResponse[T any](items []T, p page, cursorOf func(T) pageCursor) pageResponse[T] {
+	rsp := pageResponse[T]{Items: items}
+	if int32(len(items)) > p.limit {
+		rsp.Items = items[:p.limit]
+		next := encodeCursor(cursorOf(rsp.Items[len(rsp.Items)-1]))
+		rsp.NextCursor = &next
+	}
+	return rsp
+}
+
+func cacheCursor(cache db.Cache) pageCursor {
+	return pageCursor{CreatedAt: cache.CreatedAt, ID: cache.ID}
+}
+
+// setDeprecatedPaginationHeaders tells clients still sending page_id to move to cursors
+func setDeprecatedPaginationHeaders(ctx *gin.Context) {
+	ctx.Header("Deprecation", "true")
+	ctx.Header("Warning", `299 - "page_id is deprecated, use cursor and next_cursor instead"`)
+}
@@ -0,0 +1,46 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	cursor := pageCursor{
+		CreatedAt: time.Now().UTC().Truncate(time.Microsecond),
+		ID:        42,
+	}
+
+	decoded, err := decodeCursor(encodeCursor(cursor))
+	require.NoError(t, err)
+	require.True(t, cursor.CreatedAt.Equal(decoded.CreatedAt))
+	require.Equal(t, cursor.ID, decoded.ID)
+}
+
+func TestDecodeInvalidCursor(t *testing.T) {
+	for _, s := range []string{"not base64!", "e30", encodeCursor(pageCursor{ID: 1})} {
+		_, err := decodeCursor(s)
+		require.ErrorIs(t, err, errInvalidCursor, s)
+	}
+}
+
+func TestNewPageResponse(t *testing.T) {
+	p := page{limit: 2}
+	cursorOf := func(id int64) pageCursor {
+		return pageCursor{CreatedAt: time.Unix(id, 0), ID: id}
+	}
+
+	rsp := newPageResponse([]int64{3, 2, 1}, p, cursorOf)
+	require.Equal(t, []int64{3, 2}, rsp.Items)
+	require.NotNil(t, rsp.NextCursor)
+
+	next, err := decodeCursor(*rsp.NextCursor)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), next.ID)
+
+	rsp = newPageResponse([]int64{1}, p, cursorOf)
+	require.Equal(t, []int64{1}, rsp.Items)
+	require.Nil(t, rsp.NextCursor)
+}
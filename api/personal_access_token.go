@@ -0,0 +1,191 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/imrishuroy/read-cache-api/auth"
+	db "github.com/imrishuroy/read-cache-api/db/sqlc"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// personalAccessTokenResponse never exposes the token hash
+type personalAccessTokenResponse struct {
+	ID         int64      `json:"id"`
+	Name       string     `json:"name"`
+	Scope      string     `json:"scope"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	LastUsedIP *string    `json:"last_used_ip"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+func newPersonalAccessTokenResponse(token db.PersonalAccessToken) personalAccessTokenResponse {
+	rsp := personalAccessTokenResponse{
+		ID:        token.ID,
+		Name:      token.Name,
+		Scope:     token.Scope,
+		CreatedAt: token.CreatedAt,
+	}
+	if token.LastUsedAt.Valid {
+		rsp.LastUsedAt = &token.LastUsedAt.Time
+	}
+	if token.LastUsedIp.Valid {
+		rsp.LastUsedIP = &token.LastUsedIp.String
+	}
+	if token.ExpiresAt.Valid {
+		rsp.ExpiresAt = &token.ExpiresAt.Time
+	}
+	return rsp
+}
+
+type createPersonalAccessTokenRequest struct {
+	Name          string `json:"name" binding:"required,max=100"`
+	Scope         string `json:"scope" binding:"required,oneof=read write"`
+	ExpiresInDays int32  `json:"expires_in_days" binding:"min=0,max=3650"`
+}
+
+type createPersonalAccessTokenResponse struct {
+	Token string `json:"token"`
+	personalAccessTokenResponse
+}
+
+func (server *Server) createPersonalAccessToken(ctx *gin.Context) {
+	var req createPersonalAccessTokenRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*auth.Principal)
+	if authPayload.IsPersonalAccessToken() {
+		err := errors.New("personal access tokens cannot create other tokens")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	token, hash, err := auth.NewPersonalAccessToken()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	arg := db.CreatePersonalAccessTokenParams{
+		UserID:    authPayload.UID,
+		Name:      req.Name,
+		TokenHash: hash,
+		Scope:     req.Scope,
+	}
+	if req.ExpiresInDays > 0 {
+		arg.ExpiresAt = pgtype.Timestamptz{
+			Time:  time.Now().AddDate(0, 0, int(req.ExpiresInDays)),
+			Valid: true,
+		}
+	}
+
+	dbToken, err := server.store.CreatePersonalAccessToken(ctx, arg)
+	if err != nil {
+		errorCode := db.ErrorCode(err)
+		if errorCode == db.ForeignKeyViolation {
+			ctx.JSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	// the plain token is only ever returned here
+	rsp := createPersonalAccessTokenResponse{
+		Token:                       token,
+		personalAccessTokenResponse: newPersonalAccessTokenResponse(dbToken),
+	}
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+func (server *Server) listPersonalAccessTokens(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*auth.Principal)
+
+	tokens, err := server.store.ListPersonalAccessTokens(ctx, authPayload.UID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp := make([]personalAccessTokenResponse, 0, len(tokens))
+	for _, token := range tokens {
+		rsp = append(rsp, newPersonalAccessTokenResponse(token))
+	}
+
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+type personalAccessTokenURI struct {
+	ID int64 `uri:"token_id" binding:"required,min=1"`
+}
+
+type updatePersonalAccessTokenRequest struct {
+	Name string `json:"name" binding:"required,max=100"`
+}
+
+func (server *Server) updatePersonalAccessToken(ctx *gin.Context) {
+	var uri personalAccessTokenURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req updatePersonalAccessTokenRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*auth.Principal)
+
+	arg := db.UpdatePersonalAccessTokenNameParams{
+		ID:     uri.ID,
+		UserID: authPayload.UID,
+		Name:   req.Name,
+	}
+
+	token, err := server.store.UpdatePersonalAccessTokenName(ctx, arg)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newPersonalAccessTokenResponse(token))
+}
+
+func (server *Server) revokePersonalAccessToken(ctx *gin.Context) {
+	var uri personalAccessTokenURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*auth.Principal)
+
+	arg := db.RevokePersonalAccessTokenParams{
+		ID:     uri.ID,
+		UserID: authPayload.UID,
+	}
+
+	_, err := server.store.RevokePersonalAccessToken(ctx, arg)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse())
+}
@@ -0,0 +1,280 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/imrishuroy/read-cache-api/auth"
+	mockdb "github.com/imrishuroy/read-cache-api/db/mock"
+	db "github.com/imrishuroy/read-cache-api/db/sqlc"
+	"github.com/imrishuroy/read-cache-api/util"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func randomPersonalAccessToken(owner string) db.PersonalAccessToken {
+	return db.PersonalAccessToken{
+		ID:        util.RandomInt(1, 1000),
+		UserID:    owner,
+		Name:      util.RandomString(8),
+		TokenHash: auth.HashPersonalAccessToken(auth.PersonalAccessTokenPrefix + util.RandomString(43)),
+		Scope:     auth.ScopeWrite,
+		CreatedAt: time.Now(),
+	}
+}
+
+func TestCreatePersonalAccessTokenAPI(t *testing.T) {
+	owner := util.RandomOwner()
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{"name": "cli", "scope": auth.ScopeRead, "expires_in_days": 30},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreatePersonalAccessToken(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreatePersonalAccessTokenParams) (db.PersonalAccessToken, error) {
+						require.Equal(t, owner, arg.UserID)
+						require.Equal(t, "cli", arg.Name)
+						require.Equal(t, auth.ScopeRead, arg.Scope)
+						require.Len(t, arg.TokenHash, 64)
+						require.True(t, arg.ExpiresAt.Valid)
+						require.WithinDuration(t, time.Now().AddDate(0, 0, 30), arg.ExpiresAt.Time, time.Minute)
+						return db.PersonalAccessToken{
+							ID:        1,
+							UserID:    arg.UserID,
+							Name:      arg.Name,
+							TokenHash: arg.TokenHash,
+							Scope:     arg.Scope,
+							ExpiresAt: arg.ExpiresAt,
+							CreatedAt: time.Now(),
+						}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp createPersonalAccessTokenResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.Equal(t, int64(1), rsp.ID)
+				require.True(t, strings.HasPrefix(rsp.Token, auth.PersonalAccessTokenPrefix))
+				require.NotNil(t, rsp.ExpiresAt)
+				require.NotContains(t, recorder.Body.String(), "token_hash")
+			},
+		},
+		{
+			name: "NoExpiry",
+			body: gin.H{"name": "cli", "scope": auth.ScopeWrite},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreatePersonalAccessToken(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreatePersonalAccessTokenParams) (db.PersonalAccessToken, error) {
+						require.False(t, arg.ExpiresAt.Valid)
+						return db.PersonalAccessToken{ID: 1, UserID: arg.UserID, Name: arg.Name, Scope: arg.Scope}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp createPersonalAccessTokenResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.Nil(t, rsp.ExpiresAt)
+			},
+		},
+		{
+			name: "InvalidScope",
+			body: gin.H{"name": "cli", "scope": "admin"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreatePersonalAccessToken(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "MissingName",
+			body: gin.H{"scope": auth.ScopeRead},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreatePersonalAccessToken(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "UnknownUser",
+			body: gin.H{"name": "cli", "scope": auth.ScopeRead},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreatePersonalAccessToken(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.PersonalAccessToken{}, &pgconn.PgError{Code: db.ForeignKeyViolation})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := serveTestRequest(t, tc.buildStubs, owner, http.MethodPost, "/api/tokens", tc.body)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestListPersonalAccessTokensAPI(t *testing.T) {
+	owner := util.RandomOwner()
+	tokens := []db.PersonalAccessToken{randomPersonalAccessToken(owner), randomPersonalAccessToken(owner)}
+	tokens[1].LastUsedIp = pgtype.Text{String: "203.0.113.7", Valid: true}
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListPersonalAccessTokens(gomock.Any(), gomock.Eq(owner)).
+					Times(1).
+					Return(tokens, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.NotContains(t, recorder.Body.String(), "token_hash")
+
+				var rsp []personalAccessTokenResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.Len(t, rsp, 2)
+				require.Equal(t, tokens[0].ID, rsp[0].ID)
+				require.Nil(t, rsp[0].LastUsedIP)
+				require.Equal(t, "203.0.113.7", *rsp[1].LastUsedIP)
+			},
+		},
+		{
+			name: "Empty",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListPersonalAccessTokens(gomock.Any(), gomock.Eq(owner)).
+					Times(1).
+					Return([]db.PersonalAccessToken{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.JSONEq(t, "[]", recorder.Body.String())
+			},
+		},
+		{
+			name: "InternalError",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListPersonalAccessTokens(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := serveTestRequest(t, tc.buildStubs, owner, http.MethodGet, "/api/tokens", nil)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestRevokePersonalAccessTokenAPI(t *testing.T) {
+	owner := util.RandomOwner()
+	token := randomPersonalAccessToken(owner)
+
+	testCases := []struct {
+		name          string
+		tokenID       int64
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:    "OK",
+			tokenID: token.ID,
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.RevokePersonalAccessTokenParams{ID: token.ID, UserID: owner}
+				revoked := token
+				revoked.RevokedAt = pgtype.Timestamptz{Time: time.Now(), Valid: true}
+				store.EXPECT().
+					RevokePersonalAccessToken(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(revoked, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			// tokens of other users, and ones already revoked, are not found
+			name:    "NotFound",
+			tokenID: token.ID,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					RevokePersonalAccessToken(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.PersonalAccessToken{}, db.ErrRecordNotFound)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:    "InvalidID",
+			tokenID: 0,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					RevokePersonalAccessToken(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			url := fmt.Sprintf("/api/tokens/%d", tc.tokenID)
+			recorder := serveTestRequest(t, tc.buildStubs, owner, http.MethodDelete, url, nil)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
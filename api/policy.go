@@ -0,0 +1,149 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/imrishuroy/read-cache-api/auth"
+	db "github.com/imrishuroy/read-cache-api/db/sqlc"
+)
+
+type cacheAction int
+
+const (
+	viewCache cacheAction = iota
+	editCache
+)
+
+type collectionAction int
+
+const (
+	viewCollection collectionAction = iota
+	editCollection
+	manageCollection
+)
+
+var (
+	errCacheNotFound         = errors.New("cache not found")
+	errCacheNotEditable      = errors.New("cache doesn't belong to the authenticated user")
+	errCollectionNotFound    = errors.New("collection not found")
+	errCollectionNotEditable = errors.New("your role in the collection doesn't allow this")
+)
+
+// canViewCache reports whether the principal may read a cache and its sub-resources
+func canViewCache(principal *auth.Principal, cache db.Cache) bool {
+	return cache.Owner == principal.UID || cache.IsPublic.Bool
+}
+
+// canEditCache reports whether the principal may change a cache and its sub-resources
+func canEditCache(principal *auth.Principal, cache db.Cache) bool {
+	return cache.Owner == principal.UID
+}
+
+// canEditComment reports whether the principal may change or delete a comment
+func canEditComment(principal *auth.Principal, comment db.Comment) bool {
+	return comment.Author == principal.UID
+}
+
+// canModerateComments reports whether the principal may hide comments on a cache
+func canModerateComments(principal *auth.Principal, cache db.Cache) bool {
+	return canEditCache(principal, cache)
+}
+
+// canReadHiddenComment reports whether the principal still sees the body of a
+// hidden comment: its author and the cache owner who hid it do
+func canReadHiddenComment(principal *auth.Principal, cache db.Cache, comment db.Comment) bool {
+	return canEditComment(principal, comment) || canModerateComments(principal, cache)
+}
+
+// collectionRoleRank orders the roles of a collection, outsiders rank 0
+var collectionRoleRank = map[db.CollectionRole]int{
+	db.CollectionRoleViewer: 1,
+	db.CollectionRoleEditor: 2,
+	db.CollectionRoleOwner:  3,
+}
+
+// canViewCollection reports whether a caller holding role (empty for
+// outsiders) may see a collection and list its caches
+func canViewCollection(role db.CollectionRole, collection db.Collection) bool {
+	return collection.IsPublic || collectionRoleRank[role] >= collectionRoleRank[db.CollectionRoleViewer]
+}
+
+// canEditCollection reports whether role may add, remove and reorder the caches of a collection
+func canEditCollection(role db.CollectionRole) bool {
+	return collectionRoleRank[role] >= collectionRoleRank[db.CollectionRoleEditor]
+}
+
+// canManageCollection reports whether role may change a collection's details,
+// members and invitations, or delete it
+func canManageCollection(role db.CollectionRole) bool {
+	return collectionRoleRank[role] >= collectionRoleRank[db.CollectionRoleOwner]
+}
+
+// authorizeCache loads a cache and checks the policy for action.
+// Caches the caller cannot view are reported as not found so private caches don't leak.
+// On failure it writes the error response and returns false.
+func (server *Server) authorizeCache(ctx *gin.Context, cacheID int64, action cacheAction) (db.Cache, bool) {
+	cache, err := server.store.GetCache(ctx, cacheID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(errCacheNotFound))
+			return db.Cache{}, false
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return db.Cache{}, false
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*auth.Principal)
+	if !canViewCache(authPayload, cache) {
+		ctx.JSON(http.StatusNotFound, errorResponse(errCacheNotFound))
+		return db.Cache{}, false
+	}
+
+	if action == editCache && !canEditCache(authPayload, cache) {
+		ctx.JSON(http.StatusForbidden, errorResponse(errCacheNotEditable))
+		return db.Cache{}, false
+	}
+
+	return cache, true
+}
+
+// authorizeCollection is authorizeCache for collections, access comes from
+// the caller's role in collection_members. It also returns that role, empty
+// when the caller is not a member.
+func (server *Server) authorizeCollection(ctx *gin.Context, collectionID int64, action collectionAction) (db.Collection, db.CollectionRole, bool) {
+	collection, err := server.store.GetCollection(ctx, collectionID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(errCollectionNotFound))
+			return db.Collection{}, "", false
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return db.Collection{}, "", false
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*auth.Principal)
+	member, err := server.store.GetCollectionMember(ctx, db.GetCollectionMemberParams{
+		CollectionID: collectionID,
+		UserID:       authPayload.UID,
+	})
+	if err != nil && !errors.Is(err, db.ErrRecordNotFound) {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return db.Collection{}, "", false
+	}
+	role := member.Role
+
+	if !canViewCollection(role, collection) {
+		ctx.JSON(http.StatusNotFound, errorResponse(errCollectionNotFound))
+		return db.Collection{}, "", false
+	}
+
+	if (action == editCollection && !canEditCollection(role)) ||
+		(action == manageCollection && !canManageCollection(role)) {
+		ctx.JSON(http.StatusForbidden, errorResponse(errCollectionNotEditable))
+		return db.Collection{}, "", false
+	}
+
+	return collection, role, true
+}
@@ -0,0 +1,117 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	db "github.com/imrishuroy/read-cache-api/db/sqlc"
+)
+
+// statusAll lists caches in every reading status, archived ones included
+const statusAll = "all"
+
+// readingStatusFilter maps the status query parameter of list endpoints to the
+// query arguments. Without a status the inbox is listed: everything but archived.
+func readingStatusFilter(status string) (filter db.NullReadingStatus, includeArchived bool) {
+	switch status {
+	case "":
+		return db.NullReadingStatus{}, false
+	case statusAll:
+		return db.NullReadingStatus{}, true
+	default:
+		return db.NullReadingStatus{ReadingStatus: db.ReadingStatus(status), Valid: true}, false
+	}
+}
+
+type cacheReadingURI struct {
+	CacheID int64 `uri:"cache_id" binding:"required,min=1"`
+}
+
+type updateCacheStatusRequest struct {
+	Status string `json:"status" binding:"required,oneof=unread reading read archived"`
+}
+
+// updateCacheStatus moves a cache to another reading status. Marking it read
+// stamps read_at and completes the progress, marking it unread resets both,
+// and archived_at is only set while the cache is archived.
+func (server *Server) updateCacheStatus(ctx *gin.Context) {
+	var uri cacheReadingURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req updateCacheStatusRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if _, ok := server.authorizeCache(ctx, uri.CacheID, editCache); !ok {
+		return
+	}
+
+	arg := db.UpdateCacheStatusParams{
+		ID:     uri.CacheID,
+		Status: db.ReadingStatus(req.Status),
+	}
+
+	cache, err := server.store.UpdateCacheStatus(ctx, arg)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp, err := server.newCacheResponses(ctx, []db.Cache{cache})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, rsp[0])
+}
+
+type updateCacheProgressRequest struct {
+	// a pointer so that a progress of 0 passes the required check
+	Progress *int32 `json:"progress" binding:"required,min=0,max=100"`
+}
+
+// updateCacheProgress records how far a cache has been read. Starting an
+// unread cache moves it to reading and reaching 100 marks it read, archived
+// caches keep their status.
+func (server *Server) updateCacheProgress(ctx *gin.Context) {
+	var uri cacheReadingURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req updateCacheProgressRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if _, ok := server.authorizeCache(ctx, uri.CacheID, editCache); !ok {
+		return
+	}
+
+	arg := db.UpdateCacheProgressParams{
+		ID:       uri.CacheID,
+		Progress: *req.Progress,
+	}
+
+	cache, err := server.store.UpdateCacheProgress(ctx, arg)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp, err := server.newCacheResponses(ctx, []db.Cache{cache})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, rsp[0])
+}
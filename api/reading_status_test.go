@@ -0,0 +1,226 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/imrishuroy/read-cache-api/db/mock"
+	db "github.com/imrishuroy/read-cache-api/db/sqlc"
+	"github.com/imrishuroy/read-cache-api/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateCacheStatusAPI(t *testing.T) {
+	owner := util.RandomOwner()
+	cache := randomCache(owner, true)
+
+	testCases := []struct {
+		name          string
+		uid           string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			uid:  owner,
+			body: gin.H{"status": "archived"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetCache(gomock.Any(), gomock.Eq(cache.ID)).
+					Times(1).
+					Return(cache, nil)
+
+				archived := cache
+				archived.Status = db.ReadingStatusArchived
+				arg := db.UpdateCacheStatusParams{
+					ID:     cache.ID,
+					Status: db.ReadingStatusArchived,
+				}
+				store.EXPECT().
+					UpdateCacheStatus(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(archived, nil)
+				store.EXPECT().
+					ListLikedCacheIDs(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return([]int64{cache.ID}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp cacheResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.Equal(t, db.ReadingStatusArchived, rsp.Status)
+				require.True(t, rsp.LikedByMe)
+			},
+		},
+		{
+			name: "InvalidStatus",
+			uid:  owner,
+			body: gin.H{"status": "done"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetCache(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "NotOwner",
+			uid:  util.RandomOwner(),
+			body: gin.H{"status": "read"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetCache(gomock.Any(), gomock.Eq(cache.ID)).
+					Times(1).
+					Return(cache, nil)
+				store.EXPECT().
+					UpdateCacheStatus(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "InternalError",
+			uid:  owner,
+			body: gin.H{"status": "read"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetCache(gomock.Any(), gomock.Eq(cache.ID)).
+					Times(1).
+					Return(cache, nil)
+				store.EXPECT().
+					UpdateCacheStatus(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Cache{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			url := fmt.Sprintf("/api/caches/%d/status", cache.ID)
+			recorder := serveTestRequest(t, tc.buildStubs, tc.uid, http.MethodPut, url, tc.body)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestUpdateCacheProgressAPI(t *testing.T) {
+	owner := util.RandomOwner()
+	cache := randomCache(owner, false)
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{"progress": 40},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetCache(gomock.Any(), gomock.Eq(cache.ID)).
+					Times(1).
+					Return(cache, nil)
+
+				reading := cache
+				reading.Status = db.ReadingStatusReading
+				reading.Progress = 40
+				arg := db.UpdateCacheProgressParams{
+					ID:       cache.ID,
+					Progress: 40,
+				}
+				store.EXPECT().
+					UpdateCacheProgress(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(reading, nil)
+				store.EXPECT().
+					ListLikedCacheIDs(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return([]int64{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp cacheResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.Equal(t, db.ReadingStatusReading, rsp.Status)
+				require.Equal(t, int32(40), rsp.Progress)
+				require.False(t, rsp.LikedByMe)
+			},
+		},
+		{
+			name: "ZeroProgress",
+			body: gin.H{"progress": 0},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetCache(gomock.Any(), gomock.Eq(cache.ID)).
+					Times(1).
+					Return(cache, nil)
+				store.EXPECT().
+					UpdateCacheProgress(gomock.Any(), gomock.Eq(db.UpdateCacheProgressParams{ID: cache.ID})).
+					Times(1).
+					Return(cache, nil)
+				store.EXPECT().
+					ListLikedCacheIDs(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return([]int64{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "OutOfRange",
+			body: gin.H{"progress": 101},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpdateCacheProgress(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "MissingProgress",
+			body: gin.H{},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpdateCacheProgress(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			url := fmt.Sprintf("/api/caches/%d/progress", cache.ID)
+			recorder := serveTestRequest(t, tc.buildStubs, owner, http.MethodPut, url, tc.body)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
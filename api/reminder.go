@@ -0,0 +1,201 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/imrishuroy/read-cache-api/auth"
+	db "github.com/imrishuroy/read-cache-api/db/sqlc"
+	"github.com/imrishuroy/read-cache-api/reminder"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var errReminderInPast = errors.New("reminder has no occurrence in the future")
+
+// reminderResponse leaves out the delivery bookkeeping of db.Reminder
+type reminderResponse struct {
+	ID          int64      `json:"id"`
+	CacheID     int64      `json:"cache_id"`
+	Note        string     `json:"note"`
+	RemindAt    time.Time  `json:"remind_at"`
+	RRule       string     `json:"rrule"`
+	TimeZone    string     `json:"time_zone"`
+	NextFireAt  *time.Time `json:"next_fire_at"`
+	FireCount   int32      `json:"fire_count"`
+	LastFiredAt *time.Time `json:"last_fired_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+func newReminderResponse(r db.Reminder) reminderResponse {
+	rsp := reminderResponse{
+		ID:        r.ID,
+		CacheID:   r.CacheID,
+		Note:      r.Note,
+		RemindAt:  r.RemindAt,
+		RRule:     r.Rrule,
+		TimeZone:  r.TimeZone,
+		FireCount: r.FireCount,
+		CreatedAt: r.CreatedAt,
+	}
+	if r.NextFireAt.Valid {
+		rsp.NextFireAt = &r.NextFireAt.Time
+	}
+	if r.LastFiredAt.Valid {
+		rsp.LastFiredAt = &r.LastFiredAt.Time
+	}
+	return rsp
+}
+
+func newReminderResponses(reminders []db.Reminder) []reminderResponse {
+	rsp := make([]reminderResponse, 0, len(reminders))
+	for _, r := range reminders {
+		rsp = append(rsp, newReminderResponse(r))
+	}
+	return rsp
+}
+
+type cacheRemindersURI struct {
+	CacheID int64 `uri:"cache_id" binding:"required,min=1"`
+}
+
+type createReminderRequest struct {
+	RemindAt time.Time `json:"remind_at" binding:"required"`
+	// RRule makes the reminder recurring, e.g. FREQ=WEEKLY;BYDAY=SA
+	RRule    string `json:"rrule" binding:"max=255"`
+	TimeZone string `json:"time_zone" binding:"max=64"`
+	Note     string `json:"note" binding:"max=500"`
+}
+
+// createReminder attaches a reminder to any cache the caller can read, the
+// reminder scheduler delivers it in the background
+func (server *Server) createReminder(ctx *gin.Context) {
+	var uri cacheRemindersURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req createReminderRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if req.TimeZone == "" {
+		req.TimeZone = "UTC"
+	}
+	schedule, err := reminder.NewSchedule(req.RemindAt, req.RRule, req.TimeZone)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	// a recurring reminder may start in the past, it fires from its next occurrence on
+	nextFireAt, ok := schedule.Next(time.Now())
+	if !ok {
+		ctx.JSON(http.StatusBadRequest, errorResponse(errReminderInPast))
+		return
+	}
+
+	if _, ok := server.authorizeCache(ctx, uri.CacheID, viewCache); !ok {
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*auth.Principal)
+
+	arg := db.CreateReminderParams{
+		CacheID:    uri.CacheID,
+		UserID:     authPayload.UID,
+		Note:       req.Note,
+		RemindAt:   req.RemindAt,
+		Rrule:      req.RRule,
+		TimeZone:   req.TimeZone,
+		NextFireAt: pgtype.Timestamptz{Time: nextFireAt, Valid: true},
+	}
+
+	r, err := server.store.CreateReminder(ctx, arg)
+	if err != nil {
+		if db.ErrorCode(err) == db.ForeignKeyViolation {
+			ctx.JSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newReminderResponse(r))
+}
+
+// listCacheReminders lists the caller's reminders on a cache, including finished ones
+func (server *Server) listCacheReminders(ctx *gin.Context) {
+	var uri cacheRemindersURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if _, ok := server.authorizeCache(ctx, uri.CacheID, viewCache); !ok {
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*auth.Principal)
+
+	arg := db.ListCacheRemindersParams{
+		CacheID: uri.CacheID,
+		UserID:  authPayload.UID,
+	}
+
+	reminders, err := server.store.ListCacheReminders(ctx, arg)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newReminderResponses(reminders))
+}
+
+// listReminders lists the caller's upcoming reminders, soonest first
+func (server *Server) listReminders(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*auth.Principal)
+
+	reminders, err := server.store.ListReminders(ctx, authPayload.UID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newReminderResponses(reminders))
+}
+
+type reminderURI struct {
+	ID int64 `uri:"reminder_id" binding:"required,min=1"`
+}
+
+func (server *Server) deleteReminder(ctx *gin.Context) {
+	var uri reminderURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*auth.Principal)
+
+	arg := db.DeleteReminderParams{
+		ID:     uri.ID,
+		UserID: authPayload.UID,
+	}
+
+	_, err := server.store.DeleteReminder(ctx, arg)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse())
+}
@@ -0,0 +1,261 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/imrishuroy/read-cache-api/db/mock"
+	db "github.com/imrishuroy/read-cache-api/db/sqlc"
+	"github.com/imrishuroy/read-cache-api/util"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateReminderAPI(t *testing.T) {
+	owner := util.RandomOwner()
+	cache := randomCache(owner, false)
+	remindAt := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+
+	testCases := []struct {
+		name          string
+		uid           string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OneOff",
+			uid:  owner,
+			body: gin.H{"remind_at": remindAt, "note": "after lunch"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetCache(gomock.Any(), gomock.Eq(cache.ID)).
+					Times(1).
+					Return(cache, nil)
+
+				arg := db.CreateReminderParams{
+					CacheID:    cache.ID,
+					UserID:     owner,
+					Note:       "after lunch",
+					RemindAt:   remindAt,
+					TimeZone:   "UTC",
+					NextFireAt: pgtype.Timestamptz{Time: remindAt, Valid: true},
+				}
+				store.EXPECT().
+					CreateReminder(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, got db.CreateReminderParams) (db.Reminder, error) {
+						require.True(t, arg.RemindAt.Equal(got.RemindAt))
+						require.True(t, arg.NextFireAt.Time.Equal(got.NextFireAt.Time))
+						got.RemindAt, got.NextFireAt = arg.RemindAt, arg.NextFireAt
+						require.Equal(t, arg, got)
+						return db.Reminder{
+							ID:         1,
+							CacheID:    got.CacheID,
+							UserID:     got.UserID,
+							Note:       got.Note,
+							RemindAt:   got.RemindAt,
+							TimeZone:   got.TimeZone,
+							NextFireAt: got.NextFireAt,
+						}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp reminderResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.Equal(t, cache.ID, rsp.CacheID)
+				require.NotNil(t, rsp.NextFireAt)
+				require.True(t, remindAt.Equal(*rsp.NextFireAt))
+			},
+		},
+		{
+			name: "RecurringStartingInThePast",
+			uid:  owner,
+			body: gin.H{
+				"remind_at": remindAt.AddDate(0, 0, -10),
+				"rrule":     "FREQ=DAILY",
+				"time_zone": "Europe/Berlin",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetCache(gomock.Any(), gomock.Eq(cache.ID)).
+					Times(1).
+					Return(cache, nil)
+				store.EXPECT().
+					CreateReminder(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, got db.CreateReminderParams) (db.Reminder, error) {
+						require.Equal(t, "FREQ=DAILY", got.Rrule)
+						require.Equal(t, "Europe/Berlin", got.TimeZone)
+						require.True(t, got.NextFireAt.Time.After(time.Now()))
+						require.True(t, got.NextFireAt.Time.Before(time.Now().Add(25*time.Hour)))
+						return db.Reminder{ID: 2, CacheID: got.CacheID, NextFireAt: got.NextFireAt}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "OneOffInThePast",
+			uid:  owner,
+			body: gin.H{"remind_at": remindAt.Add(-2 * time.Hour)},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateReminder(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidRule",
+			uid:  owner,
+			body: gin.H{"remind_at": remindAt, "rrule": "FREQ=SECONDLY"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateReminder(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidTimeZone",
+			uid:  owner,
+			body: gin.H{"remind_at": remindAt, "time_zone": "Nowhere/Land"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateReminder(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "PrivateCacheOfAnotherUser",
+			uid:  util.RandomOwner(),
+			body: gin.H{"remind_at": remindAt},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetCache(gomock.Any(), gomock.Eq(cache.ID)).
+					Times(1).
+					Return(cache, nil)
+				store.EXPECT().
+					CreateReminder(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "InternalError",
+			uid:  owner,
+			body: gin.H{"remind_at": remindAt},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetCache(gomock.Any(), gomock.Eq(cache.ID)).
+					Times(1).
+					Return(cache, nil)
+				store.EXPECT().
+					CreateReminder(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Reminder{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			url := fmt.Sprintf("/api/caches/%d/reminders", cache.ID)
+			recorder := serveTestRequest(t, tc.buildStubs, tc.uid, http.MethodPost, url, tc.body)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestListRemindersAPI(t *testing.T) {
+	owner := util.RandomOwner()
+	reminders := []db.Reminder{
+		{ID: 1, CacheID: 3, UserID: owner, NextFireAt: pgtype.Timestamptz{Time: time.Now(), Valid: true}},
+		{ID: 2, CacheID: 4, UserID: owner},
+	}
+
+	recorder := serveTestRequest(t, func(store *mockdb.MockStore) {
+		store.EXPECT().
+			ListReminders(gomock.Any(), gomock.Eq(owner)).
+			Times(1).
+			Return(reminders, nil)
+	}, owner, http.MethodGet, "/api/reminders", nil)
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	var rsp []reminderResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+	require.Len(t, rsp, 2)
+	require.NotNil(t, rsp[0].NextFireAt)
+	require.Nil(t, rsp[1].NextFireAt)
+}
+
+func TestDeleteReminderAPI(t *testing.T) {
+	owner := util.RandomOwner()
+
+	testCases := []struct {
+		name          string
+		err           error
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "NotFound",
+			err:  db.ErrRecordNotFound,
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "InternalError",
+			err:  sql.ErrConnDone,
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := serveTestRequest(t, func(store *mockdb.MockStore) {
+				store.EXPECT().
+					DeleteReminder(gomock.Any(), gomock.Eq(db.DeleteReminderParams{ID: 9, UserID: owner})).
+					Times(1).
+					Return(db.Reminder{}, tc.err)
+			}, owner, http.MethodDelete, "/api/reminders/9", nil)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -1,12 +1,29 @@
 package api
 
-import "github.com/gin-gonic/gin"
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/imrishuroy/read-cache-api/auth"
+)
 
 func (server *Server) setupRouter() {
 	router := gin.Default()
 	router.GET("/", server.ping).Use(CORSMiddleware())
 
-	authRoutes := router.Group("/api").Use(authMiddleware(server.auth))
+	// feeds are public, feed readers don't send credentials
+	feedRoutes := router.Group("/feeds")
+	feedRoutes.GET("/public/:format", server.publicFeed)
+	feedRoutes.GET("/tags/:tag_name/:format", server.tagFeed)
+	feedRoutes.GET("/users/:user_id/:format", server.userFeed)
+	feedRoutes.GET("/caches/:cache_id", server.feedCache)
+
+	// digest unsubscribe links carry a signed token instead of a login
+	router.GET("/digest/unsubscribe", server.confirmUnsubscribeDigest)
+	router.POST("/digest/unsubscribe", server.unsubscribeDigest)
+
+	// inbound hooks are called by automation tools, the URL carries a secret token
+	router.POST("/hooks/:token", server.saveInboundCache)
+
+	authRoutes := router.Group("/api").Use(authMiddleware(server.verifier, server.store))
 
 	// users
 	authRoutes.GET("/users/:id", server.getUser)
@@ -14,6 +31,7 @@ func (server *Server) setupRouter() {
 
 	// caches
 	authRoutes.POST("/caches", server.createCache)
+	authRoutes.GET("/caches/search", server.searchCaches)
 	// id is URI parameter
 	authRoutes.GET("/caches/:cache_id", server.getCache)
 	// here page_id and page_size is query parameters
@@ -21,19 +39,99 @@ func (server *Server) setupRouter() {
 	authRoutes.PUT("/caches", server.updateCache)
 	authRoutes.DELETE("/caches/:id", server.deleteCache)
 	authRoutes.GET("caches/public", server.listPublicCaches)
+	authRoutes.GET("/caches/:cache_id/preview", server.getLinkPreview)
+	authRoutes.GET("/caches/:cache_id/snapshot", server.getCacheSnapshot)
+	authRoutes.POST("/caches/:cache_id/snapshot", server.requestCacheSnapshot)
+	authRoutes.PUT("/caches/:cache_id/status", server.updateCacheStatus)
+	authRoutes.PUT("/caches/:cache_id/progress", server.updateCacheProgress)
+	authRoutes.POST("/caches/:cache_id/like", server.likeCache)
+	authRoutes.DELETE("/caches/:id/like", server.unlikeCache)
+
+	// comments
+	authRoutes.POST("/caches/:cache_id/comments", server.createComment)
+	authRoutes.GET("/caches/:cache_id/comments", server.listComments)
+	authRoutes.GET("/comments/:comment_id/replies", server.listCommentReplies)
+	authRoutes.PUT("/comments/:comment_id", server.updateComment)
+	authRoutes.DELETE("/comments/:comment_id", server.deleteComment)
+	authRoutes.PUT("/comments/:comment_id/visibility", server.setCommentVisibility)
+
+	// collections
+	authRoutes.POST("/collections", server.createCollection)
+	authRoutes.GET("/collections", server.listCollections)
+	authRoutes.GET("/collections/:collection_id", server.getCollection)
+	authRoutes.PUT("/collections/:collection_id", server.updateCollection)
+	authRoutes.DELETE("/collections/:collection_id", server.deleteCollection)
+	authRoutes.POST("/collections/:collection_id/caches", server.addCacheToCollection)
+	authRoutes.DELETE("/collections/:collection_id/caches/:cache_id", server.removeCacheFromCollection)
+	authRoutes.PUT("/collections/:collection_id/order", server.reorderCollection)
+	authRoutes.GET("/collections/:collection_id/members", server.listCollectionMembers)
+	authRoutes.PUT("/collections/:collection_id/members/:user_id", server.updateCollectionMember)
+	authRoutes.DELETE("/collections/:collection_id/members/:user_id", server.removeCollectionMember)
+	authRoutes.POST("/collections/:collection_id/invitations", server.createCollectionInvitation)
+	authRoutes.GET("/collections/:collection_id/invitations", server.listCollectionInvitations)
+	authRoutes.DELETE("/collections/:collection_id/invitations/:invitation_id", server.revokeCollectionInvitation)
+	authRoutes.GET("/invitations", server.listMyInvitations)
+	authRoutes.POST("/invitations/:invitation_id/accept", server.acceptInvitation)
+	authRoutes.POST("/invitations/:invitation_id/decline", server.declineInvitation)
+
+	// timeline of caches from subscribed tags
+	authRoutes.GET("/feed", server.listTimeline)
+	authRoutes.GET("/feed/unseen", server.getTimelineUnseen)
+	authRoutes.PUT("/feed/seen", server.markTimelineSeen)
+
+	// email digests
+	authRoutes.GET("/digest", server.getDigestSettings)
+	authRoutes.PUT("/digest", server.updateDigestSettings)
+
+	// webhooks
+	authRoutes.POST("/webhooks", server.createWebhook)
+	authRoutes.GET("/webhooks", server.listWebhooks)
+	authRoutes.GET("/webhooks/:webhook_id", server.getWebhook)
+	authRoutes.PUT("/webhooks/:webhook_id", server.updateWebhook)
+	authRoutes.DELETE("/webhooks/:webhook_id", server.deleteWebhook)
+	authRoutes.GET("/webhooks/:webhook_id/deliveries", server.listWebhookDeliveries)
+	authRoutes.POST("/webhooks/:webhook_id/deliveries/:delivery_id/redeliver", server.redeliverWebhook)
+
+	// inbound hooks
+	authRoutes.POST("/inbound-hooks", server.createInboundHook)
+	authRoutes.GET("/inbound-hooks", server.listInboundHooks)
+	authRoutes.DELETE("/inbound-hooks/:hook_id", server.deleteInboundHook)
+
+	// imports and exports
+	authRoutes.POST("/import", server.createImport)
+	authRoutes.GET("/import", server.listImports)
+	authRoutes.GET("/import/:import_id", server.getImport)
+	authRoutes.GET("/import/:import_id/items", server.listImportItems)
+	authRoutes.GET("/export", server.exportCaches)
+
+	// reminders
+	authRoutes.POST("/caches/:cache_id/reminders", server.createReminder)
+	authRoutes.GET("/caches/:cache_id/reminders", server.listCacheReminders)
+	authRoutes.GET("/reminders", server.listReminders)
+	authRoutes.DELETE("/reminders/:reminder_id", server.deleteReminder)
 
 	// tags
 	authRoutes.POST("/tags", server.createTag)
 	authRoutes.GET("/tags", server.listTags)
-	// TODO: search tags api
+	authRoutes.GET("/tags/search", server.searchTags)
 	authRoutes.POST("/caches/:cache_id/add-tag", server.addTagToCache)
 	authRoutes.GET("/caches/:cache_id/tags", server.listCacheTags)
+	authRoutes.PUT("/caches/:cache_id/tags", server.setCacheTags)
 	authRoutes.POST("/tags/:tag_id/subscribe", server.subscribeTag)
 	authRoutes.DELETE("/tags/:tag_id/unsubscribe", server.unsubscribeTag)
 	authRoutes.GET("/users/tags/subscriptions", server.listUserSubscriptions)
-	authRoutes.DELETE("/tags/:tag_id", server.deleteTag)
+	authRoutes.DELETE("/tags/:tag_id", requireRole(server.store, auth.RoleAdmin), server.deleteTag)
 	authRoutes.DELETE("/caches/:id/tags", server.deleteCacheTags)
 
+	// personal access tokens
+	authRoutes.POST("/tokens", server.createPersonalAccessToken)
+	authRoutes.GET("/tokens", server.listPersonalAccessTokens)
+	authRoutes.PUT("/tokens/:token_id", server.updatePersonalAccessToken)
+	authRoutes.DELETE("/tokens/:token_id", server.revokePersonalAccessToken)
+
+	// admin
+	authRoutes.PUT("/admin/users/:id/role", requireRole(server.store, auth.RoleAdmin), server.updateUserRole)
+
 	server.router = router
 
 }
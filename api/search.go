@@ -0,0 +1,83 @@
+package api
+
+import (
+	"strings"
+	"unicode"
+)
+
+// buildSearchQuery turns a user's search string into a Postgres to_tsquery expression.
+// Plain words are AND-ed, "quoted phrases" must appear in order, a trailing * makes a
+// prefix match and a leading - excludes a word. Everything but letters and digits is
+// dropped so user input can never produce invalid tsquery syntax.
+// It returns an empty string if the input contains nothing searchable.
+func buildSearchQuery(q string) string {
+	var terms []string
+
+	for _, token := range splitSearchTokens(q) {
+		phrase := strings.HasPrefix(token, `"`)
+		token = strings.Trim(token, `"`)
+
+		negate := !phrase && strings.HasPrefix(token, "-")
+		prefix := !phrase && strings.HasSuffix(token, "*")
+
+		words := strings.FieldsFunc(token, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		})
+		if len(words) == 0 {
+			continue
+		}
+
+		for i, word := range words {
+			words[i] = strings.ToLower(word)
+		}
+		if prefix {
+			words[len(words)-1] += ":*"
+		}
+
+		term := strings.Join(words, " <-> ")
+		if len(words) > 1 {
+			term = "(" + term + ")"
+		}
+		if negate {
+			term = "!" + term
+		}
+		terms = append(terms, term)
+	}
+
+	return strings.Join(terms, " & ")
+}
+
+// splitSearchTokens splits on whitespace while keeping "quoted phrases" together
+func splitSearchTokens(q string) []string {
+	var tokens []string
+	var current strings.Builder
+	inQuotes := false
+
+	flush := func() {
+		if current.Len() > 0 {
+			tokens = append(tokens, current.String())
+			current.Reset()
+		}
+	}
+
+	for _, r := range q {
+		switch {
+		case r == '"':
+			if inQuotes {
+				current.WriteRune(r)
+				flush()
+			} else {
+				flush()
+				current.WriteRune(r)
+			}
+			inQuotes = !inQuotes
+		case unicode.IsSpace(r) && !inQuotes:
+			flush()
+		default:
+			current.WriteRune(r)
+		}
+	}
+	flush()
+
+	return tokens
+}
@@ -0,0 +1,35 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildSearchQuery(t *testing.T) {
+	testCases := []struct {
+		name  string
+		q     string
+		query string
+	}{
+		{"Words", "golang generics", "golang & generics"},
+		{"Phrase", `"error handling" go`, "(error <-> handling) & go"},
+		{"Prefix", "gener*", "gener:*"},
+		{"Negate", "go -java", "go & !java"},
+		{"Uppercase", "PostgreSQL", "postgresql"},
+		{"Punctuation", "c++ & rust!", "c & rust"},
+		{"Hyphenated", "e-mail", "(e <-> mail)"},
+		{"UnterminatedQuote", `"read later`, "(read <-> later)"},
+		{"Unicode", "café", "café"},
+		{"OnlySymbols", "!!! & |", ""},
+		{"Empty", "   ", ""},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.query, buildSearchQuery(tc.q))
+		})
+	}
+}
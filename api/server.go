@@ -3,47 +3,75 @@ package api
 import (
 	"context"
 	"fmt"
+	"os"
 
-	"firebase.google.com/go/v4/auth"
+	"github.com/imrishuroy/read-cache-api/auth"
 	db "github.com/imrishuroy/read-cache-api/db/sqlc"
 	"github.com/imrishuroy/read-cache-api/util"
-	"google.golang.org/api/option"
 
-	firebase "firebase.google.com/go/v4"
 	"github.com/gin-gonic/gin"
-	"github.com/rs/zerolog/log"
 )
 
 // Server serves HTTP requests
 type Server struct {
-	config util.Config
-	store  db.Store
-	auth   *auth.Client
-	router *gin.Engine
+	config   util.Config
+	store    db.Store
+	verifier auth.TokenVerifier
+	router   *gin.Engine
 }
 
 func NewServer(config util.Config, store db.Store) (*Server, error) {
-
-	opt := option.WithCredentialsFile("./service-account-key.json")
-	app, err := firebase.NewApp(context.Background(), nil, opt)
-	if err != nil {
-		log.Fatal().Msg("Failed to create Firebase app")
-	}
-
-	fmt.Println("fb connection done ", app)
-
-	auth, err := app.Auth(context.Background())
+	verifier, err := newTokenVerifier(config)
 	if err != nil {
-		log.Fatal().Err(err).Msg("Failed to create Firebase auth client")
+		return nil, fmt.Errorf("cannot create token verifier: %w", err)
 	}
 
-	server := &Server{config: config, store: store, auth: auth}
+	server := &Server{config: config, store: store, verifier: verifier}
 
 	server.setupRouter()
 
 	return server, nil
 }
 
+// newTokenVerifier builds the token verifier selected by config.AuthProvider
+func newTokenVerifier(config util.Config) (auth.TokenVerifier, error) {
+	switch config.AuthProvider {
+	case "", "firebase":
+		credentialsFile := config.FirebaseCredentialsFile
+		if credentialsFile == "" {
+			credentialsFile = "./service-account-key.json"
+		}
+		return auth.NewFirebaseVerifier(context.Background(), credentialsFile)
+	case "jwt":
+		return newJWTVerifier(config)
+	default:
+		return nil, fmt.Errorf("unsupported auth provider %s", config.AuthProvider)
+	}
+}
+
+func newJWTVerifier(config util.Config) (*auth.JWTVerifier, error) {
+	switch config.JWTAlgorithm {
+	case "", auth.AlgorithmHS256:
+		return auth.NewHS256Verifier(config.JWTSecretKey, config.JWTIssuer)
+	case auth.AlgorithmRS256:
+		publicKey, err := os.ReadFile(config.JWTPublicKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read jwt public key: %w", err)
+		}
+
+		var privateKey []byte
+		if config.JWTPrivateKeyFile != "" {
+			privateKey, err = os.ReadFile(config.JWTPrivateKeyFile)
+			if err != nil {
+				return nil, fmt.Errorf("cannot read jwt private key: %w", err)
+			}
+		}
+		return auth.NewRS256Verifier(publicKey, privateKey, config.JWTIssuer)
+	default:
+		return nil, fmt.Errorf("unsupported jwt algorithm %s", config.JWTAlgorithm)
+	}
+}
+
 // Start HTTP server on a specific address
 func (server *Server) Start(address string) error {
 	return server.router.Run(address)
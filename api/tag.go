@@ -4,8 +4,8 @@ import (
 	"net/http"
 	"strings"
 
-	"firebase.google.com/go/v4/auth"
 	"github.com/gin-gonic/gin"
+	"github.com/imrishuroy/read-cache-api/auth"
 	db "github.com/imrishuroy/read-cache-api/db/sqlc"
 )
 
@@ -49,12 +49,60 @@ func (server *Server) listTags(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, tags)
 }
 
+type searchTagsRequest struct {
+	Query string `form:"q" binding:"required,max=255"`
+	Limit int32  `form:"limit" binding:"omitempty,min=1,max=20"`
+}
+
+const defaultTagSearchLimit = 10
+
+// searchTags returns prefix and fuzzy matches for the tag picker's autocomplete
+func (server *Server) searchTags(ctx *gin.Context) {
+	var req searchTagsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	// tag names are stored lowercase
+	query := strings.ToLower(strings.TrimSpace(req.Query))
+	if query == "" {
+		ctx.JSON(http.StatusOK, []db.SearchTagsRow{})
+		return
+	}
+
+	limit := req.Limit
+	if limit == 0 {
+		limit = defaultTagSearchLimit
+	}
+
+	arg := db.SearchTagsParams{
+		Query:         query,
+		PrefixPattern: escapeLikePattern(query) + "%",
+		Limit:         limit,
+	}
+
+	tags, err := server.store.SearchTags(ctx, arg)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, tags)
+}
+
+// escapeLikePattern escapes the LIKE wildcards so s only matches literally
+func escapeLikePattern(s string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+	return replacer.Replace(s)
+}
+
 type tagIDsRequest struct {
 	TagIDs []int32 `json:"tag_ids"`
 }
 
 type cacheIDsRequest struct {
-	CacheID int64 `uri:"cache_id"`
+	CacheID int64 `uri:"cache_id" binding:"required,min=1"`
 }
 
 func (server *Server) addTagToCache(ctx *gin.Context) {
@@ -71,30 +119,75 @@ func (server *Server) addTagToCache(ctx *gin.Context) {
 		return
 	}
 
-	for _, tagID := range tagIDsRequest.TagIDs {
-		arg := db.AddTagToCacheParams{
-			CacheID: cacheIDsRequest.CacheID,
-			TagID:   tagID,
-		}
+	if _, ok := server.authorizeCache(ctx, cacheIDsRequest.CacheID, editCache); !ok {
+		return
+	}
 
-		_, err := server.store.AddTagToCache(ctx, arg)
-		if err != nil {
-			errorCode := db.ErrorCode(err)
+	arg := db.SetCacheTagsTxParams{
+		CacheID: cacheIDsRequest.CacheID,
+		TagIDs:  tagIDsRequest.TagIDs,
+	}
 
-			if errorCode == db.UniqueViolation {
-				ctx.JSON(http.StatusForbidden, gin.H{"error": "tag already exists"})
-				return
-			}
+	_, err := server.store.SetCacheTagsTx(ctx, arg)
+	if err != nil {
+		errorCode := db.ErrorCode(err)
 
-			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		if errorCode == db.UniqueViolation {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": "tag already exists"})
 			return
+		}
 
+		if errorCode == db.ForeignKeyViolation {
+			ctx.JSON(http.StatusForbidden, errorResponse(err))
+			return
 		}
+
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
 	}
 
 	ctx.JSON(http.StatusOK, successResponse())
 }
 
+// setCacheTags replaces all tags of a cache with the requested ones
+func (server *Server) setCacheTags(ctx *gin.Context) {
+	var tagIDsRequest tagIDsRequest
+	if err := ctx.ShouldBindJSON(&tagIDsRequest); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var cacheIDsRequest cacheIDsRequest
+	if err := ctx.ShouldBindUri(&cacheIDsRequest); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if _, ok := server.authorizeCache(ctx, cacheIDsRequest.CacheID, editCache); !ok {
+		return
+	}
+
+	arg := db.SetCacheTagsTxParams{
+		CacheID:         cacheIDsRequest.CacheID,
+		TagIDs:          tagIDsRequest.TagIDs,
+		ReplaceExisting: true,
+	}
+
+	result, err := server.store.SetCacheTagsTx(ctx, arg)
+	if err != nil {
+		errorCode := db.ErrorCode(err)
+		if errorCode == db.ForeignKeyViolation || errorCode == db.UniqueViolation {
+			ctx.JSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result.Tags)
+}
+
 type listCacheTagsRequest struct {
 	CacheID int64 `uri:"cache_id" binding:"required,min=1"`
 }
@@ -107,6 +200,10 @@ func (server *Server) listCacheTags(ctx *gin.Context) {
 		return
 	}
 
+	if _, ok := server.authorizeCache(ctx, req.CacheID, viewCache); !ok {
+		return
+	}
+
 	tags, err := server.store.ListCacheTags(ctx, req.CacheID)
 
 	if err != nil {
@@ -128,14 +225,14 @@ func (server *Server) subscribeTag(ctx *gin.Context) {
 		return
 	}
 
-	authPayload := ctx.MustGet(authorizationPayloadKey).(*auth.Token)
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*auth.Principal)
 
 	arg := db.SubscribeTagParams{
 		UserID: authPayload.UID,
 		TagID:  req.TagID,
 	}
 
-	_, err := server.store.SubscribeTag(ctx, arg)
+	_, err := server.store.SubscribeTagTx(ctx, arg)
 	if err != nil {
 		errorCode := db.ErrorCode(err)
 
@@ -164,7 +261,7 @@ func (server *Server) unsubscribeTag(ctx *gin.Context) {
 		return
 	}
 
-	authPayload := ctx.MustGet(authorizationPayloadKey).(*auth.Token)
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*auth.Principal)
 
 	arg := db.UnsubscribeTagParams{
 		UserID: authPayload.UID,
@@ -181,7 +278,7 @@ func (server *Server) unsubscribeTag(ctx *gin.Context) {
 }
 
 func (server *Server) listUserSubscriptions(ctx *gin.Context) {
-	authPayload := ctx.MustGet(authorizationPayloadKey).(*auth.Token)
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*auth.Principal)
 
 	tags, err := server.store.ListUserSubscriptions(ctx, authPayload.UID)
 	if err != nil {
@@ -202,21 +299,10 @@ func (server *Server) deleteTag(ctx *gin.Context) {
 		ctx.JSON(http.StatusBadRequest, errorResponse(err))
 		return
 	}
-	err1 := server.store.DeleteTagFromUserTagsTable(ctx, req.TagID)
-	if err1 != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err1))
-		return
-	}
 
-	err2 := server.store.DeleteTagFromCacheTagsTable(ctx, req.TagID)
-	if err2 != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err2))
-		return
-	}
-
-	err3 := server.store.DeleteTagFromTagsTable(ctx, req.TagID)
-	if err3 != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err3))
+	err := server.store.DeleteTagTx(ctx, req.TagID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
@@ -234,7 +320,17 @@ func (server *Server) deleteCacheTags(ctx *gin.Context) {
 		return
 	}
 
-	err := server.store.DeleteCacheTag(ctx, req.CacheID)
+	if _, ok := server.authorizeCache(ctx, req.CacheID, editCache); !ok {
+		return
+	}
+
+	// replacing the tags with none records cache.tagged like any other change
+	arg := db.SetCacheTagsTxParams{
+		CacheID:         req.CacheID,
+		ReplaceExisting: true,
+	}
+
+	_, err := server.store.SetCacheTagsTx(ctx, arg)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
@@ -0,0 +1,397 @@
+package api
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/imrishuroy/read-cache-api/auth"
+	mockdb "github.com/imrishuroy/read-cache-api/db/mock"
+	db "github.com/imrishuroy/read-cache-api/db/sqlc"
+	"github.com/imrishuroy/read-cache-api/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddTagToCacheAPI(t *testing.T) {
+	owner := util.RandomOwner()
+	privateCache := randomCache(owner, false)
+	publicCache := randomCache(owner, true)
+
+	testCases := []struct {
+		name          string
+		cache         db.Cache
+		uid           string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "OK",
+			cache: privateCache,
+			uid:   owner,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetCache(gomock.Any(), gomock.Eq(privateCache.ID)).
+					Times(1).
+					Return(privateCache, nil)
+				arg := db.SetCacheTagsTxParams{
+					CacheID: privateCache.ID,
+					TagIDs:  []int32{1, 2},
+				}
+				store.EXPECT().
+					SetCacheTagsTx(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(db.SetCacheTagsTxResult{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:  "OtherUserPublic",
+			cache: publicCache,
+			uid:   util.RandomOwner(),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetCache(gomock.Any(), gomock.Eq(publicCache.ID)).
+					Times(1).
+					Return(publicCache, nil)
+				store.EXPECT().
+					SetCacheTagsTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:  "OtherUserPrivate",
+			cache: privateCache,
+			uid:   util.RandomOwner(),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetCache(gomock.Any(), gomock.Eq(privateCache.ID)).
+					Times(1).
+					Return(privateCache, nil)
+				store.EXPECT().
+					SetCacheTagsTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			body := gin.H{"tag_ids": []int32{1, 2}}
+			url := fmt.Sprintf("/api/caches/%d/add-tag", tc.cache.ID)
+			recorder := serveTestRequest(t, tc.buildStubs, tc.uid, http.MethodPost, url, body)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestListCacheTagsAPI(t *testing.T) {
+	owner := util.RandomOwner()
+	privateCache := randomCache(owner, false)
+	publicCache := randomCache(owner, true)
+
+	testCases := []struct {
+		name          string
+		cache         db.Cache
+		uid           string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "OwnerPrivate",
+			cache: privateCache,
+			uid:   owner,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetCache(gomock.Any(), gomock.Eq(privateCache.ID)).
+					Times(1).
+					Return(privateCache, nil)
+				store.EXPECT().
+					ListCacheTags(gomock.Any(), gomock.Eq(privateCache.ID)).
+					Times(1).
+					Return([]db.Tag{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:  "OtherUserPublic",
+			cache: publicCache,
+			uid:   util.RandomOwner(),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetCache(gomock.Any(), gomock.Eq(publicCache.ID)).
+					Times(1).
+					Return(publicCache, nil)
+				store.EXPECT().
+					ListCacheTags(gomock.Any(), gomock.Eq(publicCache.ID)).
+					Times(1).
+					Return([]db.Tag{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:  "OtherUserPrivate",
+			cache: privateCache,
+			uid:   util.RandomOwner(),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetCache(gomock.Any(), gomock.Eq(privateCache.ID)).
+					Times(1).
+					Return(privateCache, nil)
+				store.EXPECT().
+					ListCacheTags(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			url := fmt.Sprintf("/api/caches/%d/tags", tc.cache.ID)
+			recorder := serveTestRequest(t, tc.buildStubs, tc.uid, http.MethodGet, url, nil)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestDeleteCacheTagsAPI(t *testing.T) {
+	owner := util.RandomOwner()
+	privateCache := randomCache(owner, false)
+	publicCache := randomCache(owner, true)
+
+	testCases := []struct {
+		name          string
+		cache         db.Cache
+		uid           string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "OK",
+			cache: privateCache,
+			uid:   owner,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetCache(gomock.Any(), gomock.Eq(privateCache.ID)).
+					Times(1).
+					Return(privateCache, nil)
+				arg := db.SetCacheTagsTxParams{
+					CacheID:         privateCache.ID,
+					ReplaceExisting: true,
+				}
+				store.EXPECT().
+					SetCacheTagsTx(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(db.SetCacheTagsTxResult{Tags: []db.Tag{}}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:  "OtherUserPublic",
+			cache: publicCache,
+			uid:   util.RandomOwner(),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetCache(gomock.Any(), gomock.Eq(publicCache.ID)).
+					Times(1).
+					Return(publicCache, nil)
+				store.EXPECT().
+					SetCacheTagsTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:  "InternalError",
+			cache: privateCache,
+			uid:   owner,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetCache(gomock.Any(), gomock.Eq(privateCache.ID)).
+					Times(1).
+					Return(db.Cache{}, sql.ErrConnDone)
+				store.EXPECT().
+					SetCacheTagsTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			url := fmt.Sprintf("/api/caches/%d/tags", tc.cache.ID)
+			recorder := serveTestRequest(t, tc.buildStubs, tc.uid, http.MethodDelete, url, nil)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestDeleteTagAPI(t *testing.T) {
+	uid := util.RandomOwner()
+	tagID := int32(util.RandomInt(1, 1000))
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "Admin",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(uid)).
+					Times(1).
+					Return(db.User{ID: uid, Role: auth.RoleAdmin}, nil)
+				store.EXPECT().
+					DeleteTagTx(gomock.Any(), gomock.Eq(tagID)).
+					Times(1).
+					Return(nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "Moderator",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(uid)).
+					Times(1).
+					Return(db.User{ID: uid, Role: auth.RoleModerator}, nil)
+				store.EXPECT().
+					DeleteTagTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+				require.Contains(t, recorder.Body.String(), auth.RoleAdmin)
+			},
+		},
+		{
+			name: "User",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(uid)).
+					Times(1).
+					Return(db.User{ID: uid, Role: auth.RoleUser}, nil)
+				store.EXPECT().
+					DeleteTagTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			url := fmt.Sprintf("/api/tags/%d", tagID)
+			recorder := serveTestRequest(t, tc.buildStubs, uid, http.MethodDelete, url, nil)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestSearchTagsAPI(t *testing.T) {
+	uid := util.RandomOwner()
+
+	testCases := []struct {
+		name          string
+		query         string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "OK",
+			query: "q=GoLang",
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.SearchTagsParams{
+					Query:         "golang",
+					PrefixPattern: "golang%",
+					Limit:         defaultTagSearchLimit,
+				}
+				store.EXPECT().
+					SearchTags(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return([]db.SearchTagsRow{{TagID: 1, TagName: "golang", Similarity: 1, UsageCount: 3}}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Contains(t, recorder.Body.String(), `"usage_count":3`)
+			},
+		},
+		{
+			name:  "EscapesWildcards",
+			query: "q=c_%25&limit=5",
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.SearchTagsParams{
+					Query:         "c_%",
+					PrefixPattern: `c\_\%%`,
+					Limit:         5,
+				}
+				store.EXPECT().
+					SearchTags(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return([]db.SearchTagsRow{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:  "LimitTooLarge",
+			query: "q=go&limit=100",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					SearchTags(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := serveTestRequest(t, tc.buildStubs, uid, http.MethodGet, "/api/tags/search?"+tc.query, nil)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -0,0 +1,169 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/imrishuroy/read-cache-api/auth"
+	db "github.com/imrishuroy/read-cache-api/db/sqlc"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// timelineMaxUnseen caps the unseen count, the app shows anything above it as "99+"
+const timelineMaxUnseen = 100
+
+var errTimelineCursorOnly = errors.New("page_id is not supported on the feed, use cursor")
+
+// timelineResponse is a page of the timeline. last_seen_at is the caller's
+// marker, items created after it are new to them.
+type timelineResponse struct {
+	pageResponse[cacheResponse]
+	LastSeenAt *time.Time `json:"last_seen_at"`
+}
+
+type timelineMarkerResponse struct {
+	LastSeenAt *time.Time `json:"last_seen_at"`
+	Unseen     int64      `json:"unseen"`
+}
+
+type markTimelineSeenRequest struct {
+	// SeenAt is the created_at of the newest cache the app showed. It
+	// defaults to now, and the marker never moves back.
+	SeenAt *time.Time `json:"seen_at"`
+}
+
+// listTimeline lists public caches carrying any tag the caller subscribed
+// to, newest first. The caller's own caches are left out and a cache with
+// several subscribed tags shows up once.
+func (server *Server) listTimeline(ctx *gin.Context) {
+	var req pageRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	p, err := server.resolvePage(req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if p.legacy {
+		ctx.JSON(http.StatusBadRequest, errorResponse(errTimelineCursorOnly))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*auth.Principal)
+
+	lastSeenAt, err := server.timelineLastSeenAt(ctx, authPayload.UID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	arg := db.ListTimelineCachesParams{
+		UserID:          authPayload.UID,
+		CursorCreatedAt: p.cursorCreatedAt(),
+		CursorID:        p.cursorID(),
+		PageLimit:       p.fetchLimit(),
+	}
+	caches, err := server.store.ListTimelineCaches(ctx, arg)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp, err := server.cachePageResponse(ctx, caches, p)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, timelineResponse{pageResponse: rsp, LastSeenAt: lastSeenAt})
+}
+
+// getTimelineUnseen counts the timeline caches created since the caller's
+// marker, for the "new for you" badge
+func (server *Server) getTimelineUnseen(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*auth.Principal)
+
+	lastSeenAt, err := server.timelineLastSeenAt(ctx, authPayload.UID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp, err := server.newTimelineMarkerResponse(ctx, authPayload.UID, lastSeenAt)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+// markTimelineSeen moves the caller's marker forward
+func (server *Server) markTimelineSeen(ctx *gin.Context) {
+	// the body is optional
+	var req markTimelineSeenRequest
+	if ctx.Request.ContentLength != 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+	}
+
+	// a clock ahead of ours mustn't hide caches saved in the meantime
+	seenAt := time.Now()
+	if req.SeenAt != nil && req.SeenAt.Before(seenAt) {
+		seenAt = *req.SeenAt
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*auth.Principal)
+
+	marker, err := server.store.MarkTimelineSeen(ctx, db.MarkTimelineSeenParams{
+		UserID:     authPayload.UID,
+		LastSeenAt: seenAt,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp, err := server.newTimelineMarkerResponse(ctx, authPayload.UID, &marker.LastSeenAt)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+// timelineLastSeenAt is the caller's marker, nil until they first mark the timeline seen
+func (server *Server) timelineLastSeenAt(ctx *gin.Context, userID string) (*time.Time, error) {
+	marker, err := server.store.GetTimelineMarker(ctx, userID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &marker.LastSeenAt, nil
+}
+
+func (server *Server) newTimelineMarkerResponse(ctx *gin.Context, userID string, lastSeenAt *time.Time) (timelineMarkerResponse, error) {
+	arg := db.CountUnseenTimelineCachesParams{
+		UserID:   userID,
+		MaxCount: timelineMaxUnseen,
+	}
+	if lastSeenAt != nil {
+		arg.Since = pgtype.Timestamptz{Time: *lastSeenAt, Valid: true}
+	}
+
+	unseen, err := server.store.CountUnseenTimelineCaches(ctx, arg)
+	if err != nil {
+		return timelineMarkerResponse{}, err
+	}
+	return timelineMarkerResponse{LastSeenAt: lastSeenAt, Unseen: unseen}, nil
+}
@@ -0,0 +1,280 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/imrishuroy/read-cache-api/db/mock"
+	db "github.com/imrishuroy/read-cache-api/db/sqlc"
+	"github.com/imrishuroy/read-cache-api/util"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListTimelineAPI(t *testing.T) {
+	user := util.RandomOwner()
+	lastSeenAt := time.Now().UTC().Add(-time.Hour).Truncate(time.Second)
+	caches := []db.Cache{randomCache(util.RandomOwner(), true), randomCache(util.RandomOwner(), true)}
+
+	testCases := []struct {
+		name          string
+		query         string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "OK",
+			query: "?page_size=1",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetTimelineMarker(gomock.Any(), user).
+					Times(1).
+					Return(db.TimelineMarker{UserID: user, LastSeenAt: lastSeenAt}, nil)
+				store.EXPECT().
+					ListTimelineCaches(gomock.Any(), db.ListTimelineCachesParams{
+						UserID:    user,
+						PageLimit: 2,
+					}).
+					Times(1).
+					Return(caches, nil)
+				store.EXPECT().
+					ListLikedCacheIDs(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return([]int64{}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp struct {
+					Items      []cacheResponse `json:"items"`
+					NextCursor *string         `json:"next_cursor"`
+					LastSeenAt *time.Time      `json:"last_seen_at"`
+				}
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.Len(t, rsp.Items, 1)
+				require.Equal(t, caches[0].ID, rsp.Items[0].ID)
+				require.NotNil(t, rsp.NextCursor)
+				require.NotNil(t, rsp.LastSeenAt)
+				require.True(t, lastSeenAt.Equal(*rsp.LastSeenAt))
+			},
+		},
+		{
+			name:  "NeverSeen",
+			query: "",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetTimelineMarker(gomock.Any(), user).
+					Times(1).
+					Return(db.TimelineMarker{}, db.ErrRecordNotFound)
+				store.EXPECT().
+					ListTimelineCaches(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return([]db.Cache{}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.JSONEq(t, `{"items":[],"next_cursor":null,"last_seen_at":null}`, recorder.Body.String())
+			},
+		},
+		{
+			name:  "Cursor",
+			query: "?cursor=" + encodeCursor(pageCursor{CreatedAt: lastSeenAt, ID: 42}),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetTimelineMarker(gomock.Any(), user).
+					Times(1).
+					Return(db.TimelineMarker{}, db.ErrRecordNotFound)
+				store.EXPECT().
+					ListTimelineCaches(gomock.Any(), db.ListTimelineCachesParams{
+						UserID:          user,
+						CursorCreatedAt: pgtype.Timestamptz{Time: lastSeenAt, Valid: true},
+						CursorID:        pgtype.Int8{Int64: 42, Valid: true},
+						PageLimit:       defaultPageSize + 1,
+					}).
+					Times(1).
+					Return([]db.Cache{}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:  "LegacyPage",
+			query: "?page_id=1&page_size=5",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListTimelineCaches(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "InternalError",
+			query: "",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetTimelineMarker(gomock.Any(), user).
+					Times(1).
+					Return(db.TimelineMarker{}, sql.ErrConnDone)
+				store.EXPECT().
+					ListTimelineCaches(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := serveTestRequest(t, tc.buildStubs, user, http.MethodGet, "/api/feed"+tc.query, nil)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestGetTimelineUnseenAPI(t *testing.T) {
+	user := util.RandomOwner()
+	lastSeenAt := time.Now().UTC().Add(-time.Hour).Truncate(time.Second)
+
+	recorder := serveTestRequest(t, func(store *mockdb.MockStore) {
+		store.EXPECT().
+			GetTimelineMarker(gomock.Any(), user).
+			Times(1).
+			Return(db.TimelineMarker{UserID: user, LastSeenAt: lastSeenAt}, nil)
+		store.EXPECT().
+			CountUnseenTimelineCaches(gomock.Any(), db.CountUnseenTimelineCachesParams{
+				UserID:   user,
+				Since:    pgtype.Timestamptz{Time: lastSeenAt, Valid: true},
+				MaxCount: timelineMaxUnseen,
+			}).
+			Times(1).
+			Return(int64(3), nil)
+	}, user, http.MethodGet, "/api/feed/unseen", nil)
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var rsp timelineMarkerResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+	require.Equal(t, int64(3), rsp.Unseen)
+	require.True(t, lastSeenAt.Equal(*rsp.LastSeenAt))
+}
+
+func TestMarkTimelineSeenAPI(t *testing.T) {
+	user := util.RandomOwner()
+	seenAt := time.Now().UTC().Add(-time.Minute).Truncate(time.Second)
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "SeenAt",
+			body: gin.H{"seen_at": seenAt},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					MarkTimelineSeen(gomock.Any(), db.MarkTimelineSeenParams{UserID: user, LastSeenAt: seenAt}).
+					Times(1).
+					Return(db.TimelineMarker{UserID: user, LastSeenAt: seenAt}, nil)
+				store.EXPECT().
+					CountUnseenTimelineCaches(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(int64(0), nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp timelineMarkerResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.Zero(t, rsp.Unseen)
+				require.True(t, seenAt.Equal(*rsp.LastSeenAt))
+			},
+		},
+		{
+			name: "NoBody",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					MarkTimelineSeen(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.MarkTimelineSeenParams) (db.TimelineMarker, error) {
+						require.WithinDuration(t, time.Now(), arg.LastSeenAt, time.Minute)
+						return db.TimelineMarker{UserID: user, LastSeenAt: arg.LastSeenAt}, nil
+					})
+				store.EXPECT().
+					CountUnseenTimelineCaches(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(int64(0), nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "FutureSeenAt",
+			body: gin.H{"seen_at": time.Now().Add(24 * time.Hour)},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					MarkTimelineSeen(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.MarkTimelineSeenParams) (db.TimelineMarker, error) {
+						require.False(t, arg.LastSeenAt.After(time.Now()))
+						return db.TimelineMarker{UserID: user, LastSeenAt: arg.LastSeenAt}, nil
+					})
+				store.EXPECT().
+					CountUnseenTimelineCaches(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(int64(0), nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidSeenAt",
+			body: gin.H{"seen_at": "yesterday"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					MarkTimelineSeen(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "InternalError",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					MarkTimelineSeen(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.TimelineMarker{}, sql.ErrConnDone)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := serveTestRequest(t, tc.buildStubs, user, http.MethodPut, "/api/feed/seen", tc.body)
+			tc.checkResponse(recorder)
+		})
+	}
+}
@@ -1,10 +1,11 @@
 package api
 
 import (
+	"errors"
 	"net/http"
 
-	"firebase.google.com/go/v4/auth"
 	"github.com/gin-gonic/gin"
+	"github.com/imrishuroy/read-cache-api/auth"
 	db "github.com/imrishuroy/read-cache-api/db/sqlc"
 )
 
@@ -20,7 +21,7 @@ func (server *Server) getUser(ctx *gin.Context) {
 		return
 	}
 
-	authPayload := ctx.MustGet(authorizationPayloadKey).(*auth.Token)
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*auth.Principal)
 
 	if req.ID != authPayload.UID {
 		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
@@ -49,13 +50,18 @@ func (server *Server) createUser(ctx *gin.Context) {
 		return
 	}
 
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*auth.Principal)
+
+	// the token's email wins, the body can't claim someone else's address
+	if authPayload.Email != "" {
+		req.Email = authPayload.Email
+	}
+
 	if req.Email == "" || req.Name == "" {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Email and Name are required"})
 		return
 	}
 
-	authPayload := ctx.MustGet(authorizationPayloadKey).(*auth.Token)
-
 	// search if req email already exists in db
 	dbUser, err := server.store.GetUserByEmail(ctx, req.Email)
 	if err != nil {
@@ -92,3 +98,42 @@ func (server *Server) createUser(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, user)
 
 }
+
+type updateUserRoleURI struct {
+	ID string `uri:"id" binding:"required"`
+}
+
+type updateUserRoleRequest struct {
+	Role string `json:"role" binding:"required,oneof=user moderator admin"`
+}
+
+func (server *Server) updateUserRole(ctx *gin.Context) {
+	var uri updateUserRoleURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req updateUserRoleRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	arg := db.UpdateUserRoleParams{
+		ID:   uri.ID,
+		Role: req.Role,
+	}
+
+	user, err := server.store.UpdateUserRole(ctx, arg)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, user)
+}
@@ -0,0 +1,380 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/imrishuroy/read-cache-api/auth"
+	db "github.com/imrishuroy/read-cache-api/db/sqlc"
+	"github.com/imrishuroy/read-cache-api/webhook"
+)
+
+var (
+	errWebhookURL            = errors.New("url must be an absolute http or https URL")
+	errWebhookDeliveriesPage = errors.New("webhook deliveries only support cursor pagination")
+)
+
+// webhookResponse leaves out the signing secret, which is only returned when
+// the endpoint is created
+type webhookResponse struct {
+	ID          int64     `json:"id"`
+	Url         string    `json:"url"`
+	Events      []string  `json:"events"`
+	Description string    `json:"description"`
+	Active      bool      `json:"active"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+func newWebhookResponse(endpoint db.WebhookEndpoint) webhookResponse {
+	return webhookResponse{
+		ID:          endpoint.ID,
+		Url:         endpoint.Url,
+		Events:      endpoint.Events,
+		Description: endpoint.Description,
+		Active:      endpoint.Active,
+		CreatedAt:   endpoint.CreatedAt,
+		UpdatedAt:   endpoint.UpdatedAt,
+	}
+}
+
+type createWebhookResponse struct {
+	webhookResponse
+	Secret string `json:"secret"`
+}
+
+type webhookDeliveryResponse struct {
+	ID             int64      `json:"id"`
+	EventID        int64      `json:"event_id"`
+	EventType      string     `json:"event_type"`
+	Status         string     `json:"status"`
+	Attempts       int32      `json:"attempts"`
+	ResponseStatus *int32     `json:"response_status"`
+	LastError      string     `json:"last_error"`
+	NextAttemptAt  *time.Time `json:"next_attempt_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+	DeliveredAt    *time.Time `json:"delivered_at"`
+}
+
+func newWebhookDeliveryResponse(d db.ListWebhookDeliveriesRow) webhookDeliveryResponse {
+	rsp := webhookDeliveryResponse{
+		ID:        d.ID,
+		EventID:   d.EventID,
+		EventType: d.EventType,
+		Status:    d.Status,
+		Attempts:  d.Attempts,
+		LastError: d.LastError,
+		CreatedAt: d.CreatedAt,
+	}
+	if d.ResponseStatus.Valid {
+		rsp.ResponseStatus = &d.ResponseStatus.Int32
+	}
+	// finished deliveries are never attempted again
+	if d.Status == "pending" {
+		rsp.NextAttemptAt = &d.NextAttemptAt
+	}
+	if d.DeliveredAt.Valid {
+		rsp.DeliveredAt = &d.DeliveredAt.Time
+	}
+	return rsp
+}
+
+type webhookRequest struct {
+	Url         string   `json:"url" binding:"required,max=2048"`
+	Events      []string `json:"events" binding:"required,min=1,dive,required"`
+	Description string   `json:"description" binding:"max=255"`
+	// Active pauses or resumes an endpoint, new endpoints are active
+	Active *bool `json:"active"`
+}
+
+// validate checks the URL and event names, and drops duplicate events
+func (req *webhookRequest) validate() error {
+	u, err := url.Parse(req.Url)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errWebhookURL
+	}
+
+	events := make([]string, 0, len(req.Events))
+	for _, event := range req.Events {
+		if !slices.Contains(db.EventTypes, event) {
+			return fmt.Errorf("unknown event %q, expected one of %s", event, strings.Join(db.EventTypes, ", "))
+		}
+		if !slices.Contains(events, event) {
+			events = append(events, event)
+		}
+	}
+	req.Events = events
+	req.Description = strings.TrimSpace(req.Description)
+	return nil
+}
+
+// createWebhook registers an endpoint for the caller's events. The signing
+// secret is in this response only, receivers use it to verify deliveries.
+func (server *Server) createWebhook(ctx *gin.Context) {
+	var req webhookRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if err := req.validate(); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	secret, err := webhook.NewSecret()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*auth.Principal)
+
+	arg := db.CreateWebhookEndpointParams{
+		UserID:      authPayload.UID,
+		Url:         req.Url,
+		Secret:      secret,
+		Events:      req.Events,
+		Description: req.Description,
+	}
+
+	endpoint, err := server.store.CreateWebhookEndpoint(ctx, arg)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, createWebhookResponse{
+		webhookResponse: newWebhookResponse(endpoint),
+		Secret:          endpoint.Secret,
+	})
+}
+
+func (server *Server) listWebhooks(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*auth.Principal)
+
+	endpoints, err := server.store.ListWebhookEndpoints(ctx, authPayload.UID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp := make([]webhookResponse, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		rsp = append(rsp, newWebhookResponse(endpoint))
+	}
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+type webhookURI struct {
+	ID int64 `uri:"webhook_id" binding:"required,min=1"`
+}
+
+// ownedWebhook loads an endpoint of the caller, other users' endpoints are
+// reported as missing. It writes the error response and returns false on failure.
+func (server *Server) ownedWebhook(ctx *gin.Context, id int64) (db.WebhookEndpoint, bool) {
+	endpoint, err := server.store.GetWebhookEndpoint(ctx, id)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return endpoint, false
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return endpoint, false
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*auth.Principal)
+	if endpoint.UserID != authPayload.UID {
+		ctx.JSON(http.StatusNotFound, errorResponse(db.ErrRecordNotFound))
+		return endpoint, false
+	}
+
+	return endpoint, true
+}
+
+func (server *Server) getWebhook(ctx *gin.Context) {
+	var uri webhookURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	endpoint, ok := server.ownedWebhook(ctx, uri.ID)
+	if !ok {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newWebhookResponse(endpoint))
+}
+
+func (server *Server) updateWebhook(ctx *gin.Context) {
+	var uri webhookURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req webhookRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if err := req.validate(); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	endpoint, ok := server.ownedWebhook(ctx, uri.ID)
+	if !ok {
+		return
+	}
+
+	arg := db.UpdateWebhookEndpointParams{
+		ID:          endpoint.ID,
+		Url:         req.Url,
+		Events:      req.Events,
+		Description: req.Description,
+		Active:      endpoint.Active,
+	}
+	if req.Active != nil {
+		arg.Active = *req.Active
+	}
+
+	endpoint, err := server.store.UpdateWebhookEndpoint(ctx, arg)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newWebhookResponse(endpoint))
+}
+
+// deleteWebhook removes an endpoint together with its delivery log
+func (server *Server) deleteWebhook(ctx *gin.Context) {
+	var uri webhookURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if _, ok := server.ownedWebhook(ctx, uri.ID); !ok {
+		return
+	}
+
+	if err := server.store.DeleteWebhookEndpoint(ctx, uri.ID); err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse())
+}
+
+// listWebhookDeliveries is the delivery log of an endpoint, newest first
+func (server *Server) listWebhookDeliveries(ctx *gin.Context) {
+	var uri webhookURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req pageRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	p, err := server.resolvePage(req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if p.legacy {
+		ctx.JSON(http.StatusBadRequest, errorResponse(errWebhookDeliveriesPage))
+		return
+	}
+
+	if _, ok := server.ownedWebhook(ctx, uri.ID); !ok {
+		return
+	}
+
+	arg := db.ListWebhookDeliveriesParams{
+		EndpointID:      uri.ID,
+		CursorCreatedAt: p.cursorCreatedAt(),
+		CursorID:        p.cursorID(),
+		PageLimit:       p.fetchLimit(),
+	}
+
+	deliveries, err := server.store.ListWebhookDeliveries(ctx, arg)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp := newPageResponse(deliveries, p, func(d db.ListWebhookDeliveriesRow) pageCursor {
+		return pageCursor{CreatedAt: d.CreatedAt, ID: d.ID}
+	})
+
+	items := make([]webhookDeliveryResponse, 0, len(rsp.Items))
+	for _, d := range rsp.Items {
+		items = append(items, newWebhookDeliveryResponse(d))
+	}
+
+	ctx.JSON(http.StatusOK, pageResponse[webhookDeliveryResponse]{
+		Items:      items,
+		NextCursor: rsp.NextCursor,
+	})
+}
+
+type webhookDeliveryURI struct {
+	WebhookID  int64 `uri:"webhook_id" binding:"required,min=1"`
+	DeliveryID int64 `uri:"delivery_id" binding:"required,min=1"`
+}
+
+// redeliverWebhook queues the event of a past delivery again as a new
+// delivery, the original stays in the log as it was
+func (server *Server) redeliverWebhook(ctx *gin.Context) {
+	var uri webhookDeliveryURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if _, ok := server.ownedWebhook(ctx, uri.WebhookID); !ok {
+		return
+	}
+
+	delivery, err := server.store.GetWebhookDelivery(ctx, uri.DeliveryID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if delivery.EndpointID != uri.WebhookID {
+		ctx.JSON(http.StatusNotFound, errorResponse(db.ErrRecordNotFound))
+		return
+	}
+
+	delivery, err = server.store.RedeliverWebhookDelivery(ctx, delivery.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, gin.H{
+		"id":       delivery.ID,
+		"event_id": delivery.EventID,
+		"status":   delivery.Status,
+	})
+}
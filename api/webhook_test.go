@@ -0,0 +1,380 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/imrishuroy/read-cache-api/db/mock"
+	db "github.com/imrishuroy/read-cache-api/db/sqlc"
+	"github.com/imrishuroy/read-cache-api/util"
+	"github.com/stretchr/testify/require"
+)
+
+func randomWebhookEndpoint(owner string) db.WebhookEndpoint {
+	return db.WebhookEndpoint{
+		ID:        util.RandomInt(1, 1000),
+		UserID:    owner,
+		Url:       "https://hooks.example.com/readcache",
+		Secret:    "whsec_" + util.RandomString(48),
+		Events:    []string{db.EventCacheCreated},
+		Active:    true,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+}
+
+func TestCreateWebhookAPI(t *testing.T) {
+	owner := util.RandomOwner()
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{
+				"url":    "https://hooks.example.com/readcache",
+				"events": []string{db.EventCacheCreated, db.EventTagSubscribed, db.EventCacheCreated},
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateWebhookEndpoint(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreateWebhookEndpointParams) (db.WebhookEndpoint, error) {
+						require.Equal(t, owner, arg.UserID)
+						require.Equal(t, []string{db.EventCacheCreated, db.EventTagSubscribed}, arg.Events)
+						require.True(t, strings.HasPrefix(arg.Secret, "whsec_"))
+						return db.WebhookEndpoint{
+							ID:     1,
+							UserID: arg.UserID,
+							Url:    arg.Url,
+							Secret: arg.Secret,
+							Events: arg.Events,
+							Active: true,
+						}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp createWebhookResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.Equal(t, int64(1), rsp.ID)
+				require.True(t, strings.HasPrefix(rsp.Secret, "whsec_"))
+			},
+		},
+		{
+			name: "UnknownEvent",
+			body: gin.H{"url": "https://hooks.example.com/readcache", "events": []string{"cache.exploded"}},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateWebhookEndpoint(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidURL",
+			body: gin.H{"url": "ftp://hooks.example.com", "events": []string{db.EventCacheCreated}},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateWebhookEndpoint(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "NoEvents",
+			body: gin.H{"url": "https://hooks.example.com/readcache", "events": []string{}},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateWebhookEndpoint(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := serveTestRequest(t, tc.buildStubs, owner, http.MethodPost, "/api/webhooks", tc.body)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestGetWebhookAPI(t *testing.T) {
+	owner := util.RandomOwner()
+	endpoint := randomWebhookEndpoint(owner)
+
+	testCases := []struct {
+		name          string
+		uid           string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			uid:  owner,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetWebhookEndpoint(gomock.Any(), gomock.Eq(endpoint.ID)).
+					Times(1).
+					Return(endpoint, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.NotContains(t, recorder.Body.String(), endpoint.Secret)
+			},
+		},
+		{
+			name: "OtherUser",
+			uid:  util.RandomOwner(),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetWebhookEndpoint(gomock.Any(), gomock.Eq(endpoint.ID)).
+					Times(1).
+					Return(endpoint, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "NotFound",
+			uid:  owner,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetWebhookEndpoint(gomock.Any(), gomock.Eq(endpoint.ID)).
+					Times(1).
+					Return(db.WebhookEndpoint{}, db.ErrRecordNotFound)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			url := fmt.Sprintf("/api/webhooks/%d", endpoint.ID)
+			recorder := serveTestRequest(t, tc.buildStubs, tc.uid, http.MethodGet, url, nil)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestUpdateWebhookAPI(t *testing.T) {
+	owner := util.RandomOwner()
+	endpoint := randomWebhookEndpoint(owner)
+
+	buildStubs := func(store *mockdb.MockStore) {
+		store.EXPECT().
+			GetWebhookEndpoint(gomock.Any(), gomock.Eq(endpoint.ID)).
+			Times(1).
+			Return(endpoint, nil)
+
+		arg := db.UpdateWebhookEndpointParams{
+			ID:          endpoint.ID,
+			Url:         "https://hooks.example.com/v2",
+			Events:      []string{db.EventCacheDeleted},
+			Description: "archive sync",
+			Active:      false,
+		}
+		store.EXPECT().
+			UpdateWebhookEndpoint(gomock.Any(), gomock.Eq(arg)).
+			Times(1).
+			Return(endpoint, nil)
+	}
+
+	body := gin.H{
+		"url":         "https://hooks.example.com/v2",
+		"events":      []string{db.EventCacheDeleted},
+		"description": " archive sync ",
+		"active":      false,
+	}
+	url := fmt.Sprintf("/api/webhooks/%d", endpoint.ID)
+	recorder := serveTestRequest(t, buildStubs, owner, http.MethodPut, url, body)
+	require.Equal(t, http.StatusOK, recorder.Code)
+}
+
+func TestDeleteWebhookAPI(t *testing.T) {
+	owner := util.RandomOwner()
+	endpoint := randomWebhookEndpoint(owner)
+
+	buildStubs := func(store *mockdb.MockStore) {
+		store.EXPECT().
+			GetWebhookEndpoint(gomock.Any(), gomock.Eq(endpoint.ID)).
+			Times(1).
+			Return(endpoint, nil)
+		store.EXPECT().
+			DeleteWebhookEndpoint(gomock.Any(), gomock.Eq(endpoint.ID)).
+			Times(1).
+			Return(nil)
+	}
+
+	url := fmt.Sprintf("/api/webhooks/%d", endpoint.ID)
+	recorder := serveTestRequest(t, buildStubs, owner, http.MethodDelete, url, nil)
+	require.Equal(t, http.StatusOK, recorder.Code)
+}
+
+func TestListWebhookDeliveriesAPI(t *testing.T) {
+	owner := util.RandomOwner()
+	endpoint := randomWebhookEndpoint(owner)
+
+	deliveries := make([]db.ListWebhookDeliveriesRow, 3)
+	for i := range deliveries {
+		deliveries[i] = db.ListWebhookDeliveriesRow{
+			ID:         int64(10 - i),
+			EndpointID: endpoint.ID,
+			EventID:    int64(20 - i),
+			EventType:  db.EventCacheCreated,
+			Status:     "succeeded",
+			Attempts:   1,
+			CreatedAt:  time.Now().Add(-time.Duration(i) * time.Minute),
+		}
+	}
+
+	testCases := []struct {
+		name          string
+		query         string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "FirstPage",
+			query: "?page_size=2",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetWebhookEndpoint(gomock.Any(), gomock.Eq(endpoint.ID)).
+					Times(1).
+					Return(endpoint, nil)
+				store.EXPECT().
+					ListWebhookDeliveries(gomock.Any(), gomock.Eq(db.ListWebhookDeliveriesParams{
+						EndpointID: endpoint.ID,
+						PageLimit:  3,
+					})).
+					Times(1).
+					Return(deliveries, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp pageResponse[webhookDeliveryResponse]
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.Len(t, rsp.Items, 2)
+				require.Equal(t, db.EventCacheCreated, rsp.Items[0].EventType)
+				require.Nil(t, rsp.Items[0].NextAttemptAt)
+				require.NotNil(t, rsp.NextCursor)
+			},
+		},
+		{
+			name:  "LegacyPagination",
+			query: "?page_id=1&page_size=5",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListWebhookDeliveries(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			url := fmt.Sprintf("/api/webhooks/%d/deliveries%s", endpoint.ID, tc.query)
+			recorder := serveTestRequest(t, tc.buildStubs, owner, http.MethodGet, url, nil)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestRedeliverWebhookAPI(t *testing.T) {
+	owner := util.RandomOwner()
+	endpoint := randomWebhookEndpoint(owner)
+	delivery := db.WebhookDelivery{ID: 5, EndpointID: endpoint.ID, EventID: 9, Status: "failed"}
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "Accepted",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetWebhookEndpoint(gomock.Any(), gomock.Eq(endpoint.ID)).
+					Times(1).
+					Return(endpoint, nil)
+				store.EXPECT().
+					GetWebhookDelivery(gomock.Any(), gomock.Eq(delivery.ID)).
+					Times(1).
+					Return(delivery, nil)
+				store.EXPECT().
+					RedeliverWebhookDelivery(gomock.Any(), gomock.Eq(delivery.ID)).
+					Times(1).
+					Return(db.WebhookDelivery{ID: 6, EndpointID: endpoint.ID, EventID: delivery.EventID, Status: "pending"}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusAccepted, recorder.Code)
+				require.JSONEq(t, `{"id":6,"event_id":9,"status":"pending"}`, recorder.Body.String())
+			},
+		},
+		{
+			name: "DeliveryOfOtherEndpoint",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetWebhookEndpoint(gomock.Any(), gomock.Eq(endpoint.ID)).
+					Times(1).
+					Return(endpoint, nil)
+
+				other := delivery
+				other.EndpointID = endpoint.ID + 1
+				store.EXPECT().
+					GetWebhookDelivery(gomock.Any(), gomock.Eq(delivery.ID)).
+					Times(1).
+					Return(other, nil)
+				store.EXPECT().
+					RedeliverWebhookDelivery(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			url := fmt.Sprintf("/api/webhooks/%d/deliveries/%d/redeliver", endpoint.ID, delivery.ID)
+			recorder := serveTestRequest(t, tc.buildStubs, owner, http.MethodPost, url, nil)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -0,0 +1,278 @@
+package archive
+
+import (
+	"bytes"
+	"math"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/imrishuroy/read-cache-api/unfurl"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// Article is the readable part of a page
+type Article struct {
+	Title string
+	// HTML only contains allow listed tags and absolute http(s) links
+	HTML   string
+	Text   string
+	Images []string
+}
+
+var (
+	// elements that never hold article content
+	strippedTags = map[atom.Atom]bool{
+		atom.Script: true, atom.Style: true, atom.Noscript: true, atom.Iframe: true,
+		atom.Object: true, atom.Embed: true, atom.Form: true, atom.Button: true,
+		atom.Input: true, atom.Select: true, atom.Textarea: true, atom.Nav: true,
+		atom.Aside: true, atom.Footer: true, atom.Svg: true, atom.Canvas: true,
+		atom.Template: true, atom.Link: true, atom.Meta: true, atom.Head: true,
+	}
+
+	unlikelyCandidates = regexp.MustCompile(`(?i)ad-|advert|banner|breadcrumb|comment|cookie|disqus|menu|modal|newsletter|pagination|popup|promo|related|share|sidebar|social|sponsor|subscribe|widget`)
+	maybeCandidates    = regexp.MustCompile(`(?i)and|article|body|column|content|main|post|story`)
+	positiveWeight     = regexp.MustCompile(`(?i)article|body|content|entry|main|page|post|story|text`)
+	negativeWeight     = regexp.MustCompile(`(?i)comment|footer|masthead|media|meta|outbrain|promo|related|share|shoutbox|sidebar|social|sponsor|tags|widget`)
+)
+
+// minimum text length for a paragraph to count towards its ancestors' score
+const minParagraphLength = 25
+
+// Extract finds the main article of an html page, the way Readability does:
+// paragraphs score their parent and grandparent by length and comma count,
+// class names nudge the scores, and the best scoring element together with
+// its strong siblings becomes the article. base resolves relative links.
+func Extract(page []byte, base *url.URL) (Article, error) {
+	md, err := unfurl.ParseMetadata(bytes.NewReader(page), base)
+	if err != nil {
+		return Article{}, err
+	}
+
+	doc, err := html.Parse(bytes.NewReader(page))
+	if err != nil {
+		return Article{}, err
+	}
+
+	body := findFirst(doc, atom.Body)
+	if body == nil {
+		body = doc
+	}
+	removeUnlikely(body)
+
+	content := &html.Node{Type: html.ElementNode, Data: "div", DataAtom: atom.Div}
+	s := sanitizer{base: base}
+	for _, n := range articleNodes(body) {
+		if n.DataAtom == atom.Body {
+			s.children(content, n)
+			continue
+		}
+		s.node(content, n)
+	}
+
+	var buf bytes.Buffer
+	for c := content.FirstChild; c != nil; c = c.NextSibling {
+		if err := html.Render(&buf, c); err != nil {
+			return Article{}, err
+		}
+	}
+
+	return Article{
+		Title:  md.Title,
+		HTML:   buf.String(),
+		Text:   plainText(content),
+		Images: s.images,
+	}, nil
+}
+
+func findFirst(n *html.Node, a atom.Atom) *html.Node {
+	if n.Type == html.ElementNode && n.DataAtom == a {
+		return n
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if found := findFirst(c, a); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// removeUnlikely drops elements that can't be content and boilerplate
+// containers such as comment sections and share bars
+func removeUnlikely(n *html.Node) {
+	for c := n.FirstChild; c != nil; {
+		next := c.NextSibling
+		if c.Type == html.CommentNode ||
+			(c.Type == html.ElementNode && (strippedTags[c.DataAtom] || isUnlikely(c))) {
+			n.RemoveChild(c)
+		} else {
+			removeUnlikely(c)
+		}
+		c = next
+	}
+}
+
+func isUnlikely(n *html.Node) bool {
+	switch n.DataAtom {
+	case atom.Article, atom.Main, atom.A, atom.Body:
+		return false
+	}
+	match := attr(n, "class") + " " + attr(n, "id")
+	return unlikelyCandidates.MatchString(match) && !maybeCandidates.MatchString(match)
+}
+
+// articleNodes returns the top candidate followed by siblings that look like
+// part of the same article, in document order
+func articleNodes(body *html.Node) []*html.Node {
+	scores := map[*html.Node]float64{}
+
+	var score func(n *html.Node)
+	score = func(n *html.Node) {
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type != html.ElementNode {
+				continue
+			}
+			switch c.DataAtom {
+			case atom.P, atom.Pre, atom.Td, atom.Blockquote:
+				scoreParagraph(c, scores)
+			}
+			score(c)
+		}
+	}
+	score(body)
+
+	var top *html.Node
+	for n, s := range scores {
+		s *= 1 - linkDensity(n)
+		scores[n] = s
+		if top == nil || s > scores[top] {
+			top = n
+		}
+	}
+	if top == nil || top.Parent == nil {
+		return []*html.Node{body}
+	}
+
+	threshold := math.Max(10, scores[top]*0.2)
+	var nodes []*html.Node
+	for sibling := top.Parent.FirstChild; sibling != nil; sibling = sibling.NextSibling {
+		if sibling.Type != html.ElementNode {
+			continue
+		}
+		if sibling == top || scores[sibling] >= threshold || isContentParagraph(sibling) {
+			nodes = append(nodes, sibling)
+		}
+	}
+	return nodes
+}
+
+func scoreParagraph(p *html.Node, scores map[*html.Node]float64) {
+	text := innerText(p)
+	if len(text) < minParagraphLength {
+		return
+	}
+
+	s := 1 + float64(strings.Count(text, ",")) + math.Min(float64(len(text))/100, 3)
+
+	parent := p.Parent
+	if parent == nil || parent.Type != html.ElementNode {
+		return
+	}
+	initCandidate(parent, scores)
+	scores[parent] += s
+
+	grandparent := parent.Parent
+	if grandparent != nil && grandparent.Type == html.ElementNode {
+		initCandidate(grandparent, scores)
+		scores[grandparent] += s / 2
+	}
+}
+
+func initCandidate(n *html.Node, scores map[*html.Node]float64) {
+	if _, ok := scores[n]; ok {
+		return
+	}
+
+	var s float64
+	switch n.DataAtom {
+	case atom.Article:
+		s = 10
+	case atom.Div, atom.Main, atom.Section:
+		s = 5
+	case atom.Pre, atom.Td, atom.Blockquote:
+		s = 3
+	case atom.Ol, atom.Ul, atom.Dl, atom.Form:
+		s = -3
+	case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6, atom.Th:
+		s = -5
+	}
+
+	for _, name := range []string{attr(n, "class"), attr(n, "id")} {
+		if name == "" {
+			continue
+		}
+		if positiveWeight.MatchString(name) {
+			s += 25
+		}
+		if negativeWeight.MatchString(name) {
+			s -= 25
+		}
+	}
+	scores[n] = s
+}
+
+// isContentParagraph catches stray paragraphs next to the top candidate
+func isContentParagraph(n *html.Node) bool {
+	if n.DataAtom != atom.P {
+		return false
+	}
+	text := innerText(n)
+	return len(text) > 80 && linkDensity(n) < 0.25
+}
+
+func linkDensity(n *html.Node) float64 {
+	textLength := len(innerText(n))
+	if textLength == 0 {
+		return 0
+	}
+
+	var linkLength int
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode && n.DataAtom == atom.A {
+			linkLength += len(innerText(n))
+			return
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(n)
+
+	return float64(linkLength) / float64(textLength)
+}
+
+func innerText(n *html.Node) string {
+	var b strings.Builder
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			b.WriteString(n.Data)
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(n)
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if strings.EqualFold(a.Key, key) {
+			return a.Val
+		}
+	}
+	return ""
+}
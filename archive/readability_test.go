@@ -0,0 +1,75 @@
+package archive
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const articlePage = `<!DOCTYPE html>
+<html><head>
+<title>Understanding Go channels | Example Blog</title>
+<meta property="og:title" content="Understanding Go channels">
+<script>track("view")</script>
+<style>body { color: red }</style>
+</head>
+<body>
+<header class="masthead"><a href="/">Example Blog</a></header>
+<nav><a href="/">Home</a> <a href="/about">About</a></nav>
+<div class="layout">
+  <div class="post-content" id="main">
+    <h1>Understanding Go channels</h1>
+    <p>Channels are the pipes that connect concurrent goroutines, you can send values into channels from one goroutine and receive those values into another goroutine.</p>
+    <p onclick="steal()">By default sends and receives block until both the sender and receiver are ready, which lets goroutines synchronize without explicit locks or condition variables.</p>
+    <figure><img data-src="/images/channels.png" src="data:image/gif;base64,R0lGOD" alt="Channel diagram"><figcaption>Two goroutines, one channel</figcaption></figure>
+    <p>Buffered channels accept a limited number of values without a corresponding receiver, read more in <a href="https://go.dev/ref/spec" style="color:red">the spec</a> or <a href="javascript:alert(1)">here</a>.</p>
+    <iframe src="https://ads.example.com/frame"></iframe>
+  </div>
+  <div class="sidebar-widget">
+    <p>Subscribe to our newsletter, get the best posts, tips, and tricks delivered weekly to your inbox.</p>
+  </div>
+</div>
+<div id="comments" class="comment-list">
+  <p>Great post, thanks a lot, this really helped me understand the select statement better.</p>
+</div>
+<footer><p>Copyright Example Blog, all rights reserved, do not copy without permission.</p></footer>
+</body></html>`
+
+func TestExtract(t *testing.T) {
+	base, err := url.Parse("https://blog.example.com/posts/channels")
+	require.NoError(t, err)
+
+	article, err := Extract([]byte(articlePage), base)
+	require.NoError(t, err)
+
+	require.Equal(t, "Understanding Go channels", article.Title)
+	require.Equal(t, []string{"https://blog.example.com/images/channels.png"}, article.Images)
+
+	// the article body is kept
+	require.Contains(t, article.HTML, "<h1>Understanding Go channels</h1>")
+	require.Contains(t, article.HTML, `<img src="https://blog.example.com/images/channels.png" alt="Channel diagram"/>`)
+	require.Contains(t, article.HTML, `<a href="https://go.dev/ref/spec" rel="nofollow noopener noreferrer">the spec</a>`)
+
+	// boilerplate and active content is not
+	for _, unwanted := range []string{"script", "style", "onclick", "iframe", "javascript:", "newsletter", "Great post", "Copyright", "About", "class="} {
+		require.NotContains(t, article.HTML, unwanted)
+	}
+
+	require.Equal(t, strings.Join([]string{
+		"Understanding Go channels",
+		"Channels are the pipes that connect concurrent goroutines, you can send values into channels from one goroutine and receive those values into another goroutine.",
+		"By default sends and receives block until both the sender and receiver are ready, which lets goroutines synchronize without explicit locks or condition variables.",
+		"Two goroutines, one channel",
+		"Buffered channels accept a limited number of values without a corresponding receiver, read more in the spec or here.",
+	}, "\n\n"), article.Text)
+}
+
+func TestExtractWithoutParagraphs(t *testing.T) {
+	article, err := Extract([]byte(`<html><body><div>Just a line of text<br>and another</div></body></html>`), nil)
+	require.NoError(t, err)
+
+	require.Equal(t, "Just a line of text\nand another", article.Text)
+	require.Empty(t, article.Images)
+}
@@ -0,0 +1,162 @@
+package archive
+
+import (
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// allowedTags is everything a snapshot may contain, other elements are
+// unwrapped so their text survives without their markup
+var allowedTags = map[atom.Atom]bool{
+	atom.P: true, atom.Br: true, atom.Hr: true,
+	atom.H1: true, atom.H2: true, atom.H3: true, atom.H4: true, atom.H5: true, atom.H6: true,
+	atom.Ul: true, atom.Ol: true, atom.Li: true, atom.Dl: true, atom.Dt: true, atom.Dd: true,
+	atom.Blockquote: true, atom.Pre: true, atom.Code: true,
+	atom.Em: true, atom.Strong: true, atom.B: true, atom.I: true, atom.U: true,
+	atom.S: true, atom.Sub: true, atom.Sup: true, atom.Mark: true, atom.Small: true,
+	atom.A: true, atom.Img: true, atom.Figure: true, atom.Figcaption: true,
+	atom.Table: true, atom.Thead: true, atom.Tbody: true, atom.Tr: true, atom.Th: true, atom.Td: true,
+}
+
+// blockTags are separated by blank lines in the plain text snapshot
+var blockTags = map[atom.Atom]bool{
+	atom.P: true, atom.Div: true, atom.Hr: true,
+	atom.H1: true, atom.H2: true, atom.H3: true, atom.H4: true, atom.H5: true, atom.H6: true,
+	atom.Ul: true, atom.Ol: true, atom.Li: true, atom.Dl: true, atom.Dt: true, atom.Dd: true,
+	atom.Blockquote: true, atom.Pre: true, atom.Figure: true, atom.Figcaption: true,
+	atom.Table: true, atom.Tr: true,
+}
+
+// sanitizer copies nodes into a fresh tree keeping only allow listed tags,
+// link and image urls are made absolute and anything but http(s) is dropped
+type sanitizer struct {
+	base   *url.URL
+	images []string
+}
+
+func (s *sanitizer) children(dst, src *html.Node) {
+	for c := src.FirstChild; c != nil; c = c.NextSibling {
+		s.node(dst, c)
+	}
+}
+
+func (s *sanitizer) node(dst, src *html.Node) {
+	switch src.Type {
+	case html.TextNode:
+		dst.AppendChild(&html.Node{Type: html.TextNode, Data: src.Data})
+	case html.ElementNode:
+		if strippedTags[src.DataAtom] {
+			return
+		}
+		if !allowedTags[src.DataAtom] {
+			s.children(dst, src)
+			return
+		}
+
+		el := &html.Node{Type: html.ElementNode, Data: src.DataAtom.String(), DataAtom: src.DataAtom}
+		switch src.DataAtom {
+		case atom.A:
+			if href := s.resolve(attr(src, "href")); href != "" {
+				el.Attr = []html.Attribute{
+					{Key: "href", Val: href},
+					{Key: "rel", Val: "nofollow noopener noreferrer"},
+				}
+			}
+		case atom.Img:
+			// lazy loaded images keep the real url in data-src
+			imgURL := s.resolve(firstNonEmpty(attr(src, "data-src"), attr(src, "src")))
+			if imgURL == "" {
+				return
+			}
+			el.Attr = []html.Attribute{{Key: "src", Val: imgURL}}
+			if alt := attr(src, "alt"); alt != "" {
+				el.Attr = append(el.Attr, html.Attribute{Key: "alt", Val: alt})
+			}
+			s.images = append(s.images, imgURL)
+		}
+
+		dst.AppendChild(el)
+		s.children(el, src)
+	}
+}
+
+func (s *sanitizer) resolve(ref string) string {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return ""
+	}
+
+	u, err := url.Parse(ref)
+	if err != nil {
+		return ""
+	}
+	if s.base != nil {
+		u = s.base.ResolveReference(u)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return ""
+	}
+	return u.String()
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if strings.TrimSpace(v) != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// plainText renders a sanitized tree as paragraphs separated by blank lines
+func plainText(n *html.Node) string {
+	var b strings.Builder
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		switch n.Type {
+		case html.TextNode:
+			b.WriteString(n.Data)
+		case html.ElementNode:
+			if n.DataAtom == atom.Br {
+				b.WriteString("\n")
+				return
+			}
+			block := blockTags[n.DataAtom]
+			if block {
+				b.WriteString("\n\n")
+			}
+			for c := n.FirstChild; c != nil; c = c.NextSibling {
+				walk(c)
+			}
+			if block {
+				b.WriteString("\n\n")
+			}
+			return
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(n)
+
+	var paragraphs []string
+	var lines []string
+	for _, line := range strings.Split(b.String(), "\n") {
+		line = strings.Join(strings.Fields(line), " ")
+		if line == "" {
+			if len(lines) > 0 {
+				paragraphs = append(paragraphs, strings.Join(lines, "\n"))
+				lines = nil
+			}
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if len(lines) > 0 {
+		paragraphs = append(paragraphs, strings.Join(lines, "\n"))
+	}
+	return strings.Join(paragraphs, "\n\n")
+}
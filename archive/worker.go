@@ -0,0 +1,111 @@
+package archive
+
+import (
+	"context"
+	"time"
+
+	db "github.com/imrishuroy/read-cache-api/db/sqlc"
+	"github.com/imrishuroy/read-cache-api/jobs"
+	"github.com/imrishuroy/read-cache-api/unfurl"
+)
+
+const (
+	defaultPollInterval = 10 * time.Second
+	defaultBatchSize    = 5
+	defaultMaxAttempts  = 5
+)
+
+// WorkerConfig tunes a Worker, zero values fall back to sane defaults
+type WorkerConfig struct {
+	jobs.Config
+	MaxAttempts int32
+}
+
+// Worker archives the snapshots queued by cache creation and re-snapshot
+// requests. Claims lease the rows the same way the unfurl worker does, so a
+// crashed worker's snapshots are retried once the lease runs out.
+type Worker struct {
+	store   db.Store
+	fetcher *unfurl.Fetcher
+	config  WorkerConfig
+	lease   time.Duration
+}
+
+// NewWorker creates a Worker that downloads pages with fetcher
+func NewWorker(store db.Store, fetcher *unfurl.Fetcher, config WorkerConfig) *Worker {
+	config.Config = config.Config.WithDefaults(defaultPollInterval, defaultBatchSize)
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = defaultMaxAttempts
+	}
+
+	return &Worker{
+		store:   store,
+		fetcher: fetcher,
+		config:  config,
+		lease:   2*fetcher.Timeout() + time.Minute,
+	}
+}
+
+// Run polls for due snapshots until ctx is cancelled
+func (worker *Worker) Run(ctx context.Context) {
+	jobs.Loop(ctx, worker.config.PollInterval, "cache snapshots", worker.ProcessDue)
+}
+
+// ProcessDue claims one batch of due snapshots and archives them, it returns
+// how many snapshots were claimed
+func (worker *Worker) ProcessDue(ctx context.Context) (int, error) {
+	snapshots, err := worker.store.ClaimDueCacheSnapshots(ctx, db.ClaimDueCacheSnapshotsParams{
+		LeaseSeconds: int32(worker.lease / time.Second),
+		BatchSize:    worker.config.BatchSize,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	for _, snapshot := range snapshots {
+		if err := worker.process(ctx, snapshot); err != nil {
+			return len(snapshots), err
+		}
+	}
+	return len(snapshots), nil
+}
+
+func (worker *Worker) process(ctx context.Context, snapshot db.CacheSnapshot) error {
+	article, archiveErr := worker.archive(ctx, snapshot.Url)
+	if archiveErr == nil {
+		return worker.store.CompleteCacheSnapshot(ctx, db.CompleteCacheSnapshotParams{
+			CacheID: snapshot.CacheID,
+			Title:   article.Title,
+			Html:    article.HTML,
+			Text:    article.Text,
+			Images:  article.Images,
+		})
+	}
+
+	if ctx.Err() != nil {
+		// shutting down, the lease hands the snapshot to the next run
+		return ctx.Err()
+	}
+
+	attempts := snapshot.Attempts + 1
+	if unfurl.IsPermanent(archiveErr) || attempts >= worker.config.MaxAttempts {
+		return worker.store.FailCacheSnapshot(ctx, db.FailCacheSnapshotParams{
+			CacheID:   snapshot.CacheID,
+			LastError: archiveErr.Error(),
+		})
+	}
+
+	return worker.store.RetryCacheSnapshot(ctx, db.RetryCacheSnapshotParams{
+		CacheID:       snapshot.CacheID,
+		LastError:     archiveErr.Error(),
+		NextAttemptAt: time.Now().Add(unfurl.RetryDelay(attempts)),
+	})
+}
+
+func (worker *Worker) archive(ctx context.Context, rawURL string) (Article, error) {
+	page, err := worker.fetcher.FetchPage(ctx, rawURL)
+	if err != nil {
+		return Article{}, err
+	}
+	return Extract(page.Body, page.URL)
+}
@@ -0,0 +1,94 @@
+package archive
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/imrishuroy/read-cache-api/db/mock"
+	db "github.com/imrishuroy/read-cache-api/db/sqlc"
+	"github.com/imrishuroy/read-cache-api/unfurl"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkerProcessDue(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/article":
+			fmt.Fprint(w, articlePage)
+		case "/gone":
+			w.WriteHeader(http.StatusGone)
+		default:
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer server.Close()
+
+	testCases := []struct {
+		name       string
+		snapshot   db.CacheSnapshot
+		buildStubs func(store *mockdb.MockStore, snapshot db.CacheSnapshot)
+	}{
+		{
+			name:     "Complete",
+			snapshot: db.CacheSnapshot{CacheID: 1, Url: server.URL + "/article"},
+			buildStubs: func(store *mockdb.MockStore, snapshot db.CacheSnapshot) {
+				store.EXPECT().
+					CompleteCacheSnapshot(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CompleteCacheSnapshotParams) error {
+						require.Equal(t, snapshot.CacheID, arg.CacheID)
+						require.Equal(t, "Understanding Go channels", arg.Title)
+						require.Contains(t, arg.Text, "Buffered channels")
+						require.Equal(t, []string{server.URL + "/images/channels.png"}, arg.Images)
+						return nil
+					})
+			},
+		},
+		{
+			name:     "Retry",
+			snapshot: db.CacheSnapshot{CacheID: 2, Url: server.URL + "/flaky"},
+			buildStubs: func(store *mockdb.MockStore, snapshot db.CacheSnapshot) {
+				store.EXPECT().
+					RetryCacheSnapshot(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil)
+			},
+		},
+		{
+			name:     "PermanentFailure",
+			snapshot: db.CacheSnapshot{CacheID: 3, Url: server.URL + "/gone"},
+			buildStubs: func(store *mockdb.MockStore, snapshot db.CacheSnapshot) {
+				store.EXPECT().
+					FailCacheSnapshot(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().
+				ClaimDueCacheSnapshots(gomock.Any(), gomock.Any()).
+				Times(1).
+				Return([]db.CacheSnapshot{tc.snapshot}, nil)
+			tc.buildStubs(store, tc.snapshot)
+
+			fetcher := unfurl.NewFetcher(unfurl.Config{AllowPrivateNetworks: true})
+			worker := NewWorker(store, fetcher, WorkerConfig{})
+			n, err := worker.ProcessDue(context.Background())
+			require.NoError(t, err)
+			require.Equal(t, 1, n)
+		})
+	}
+}
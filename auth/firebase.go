@@ -0,0 +1,47 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+
+	firebase "firebase.google.com/go/v4"
+	fbauth "firebase.google.com/go/v4/auth"
+	"google.golang.org/api/option"
+)
+
+// FirebaseVerifier verifies Firebase ID tokens
+type FirebaseVerifier struct {
+	client *fbauth.Client
+}
+
+// NewFirebaseVerifier creates a FirebaseVerifier from a service account credentials file
+func NewFirebaseVerifier(ctx context.Context, credentialsFile string) (*FirebaseVerifier, error) {
+	opt := option.WithCredentialsFile(credentialsFile)
+	app, err := firebase.NewApp(ctx, nil, opt)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create firebase app: %w", err)
+	}
+
+	client, err := app.Auth(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create firebase auth client: %w", err)
+	}
+
+	return &FirebaseVerifier{client: client}, nil
+}
+
+// VerifyToken verifies a Firebase ID token
+func (verifier *FirebaseVerifier) VerifyToken(ctx context.Context, token string) (*Principal, error) {
+	idToken, err := verifier.client.VerifyIDToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	email, _ := idToken.Claims["email"].(string)
+
+	return &Principal{
+		UID:    idToken.UID,
+		Email:  email,
+		Claims: idToken.Claims,
+	}, nil
+}
@@ -0,0 +1,137 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+	"time"
+
+	"github.com/imrishuroy/read-cache-api/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHS256Verifier(t *testing.T) {
+	verifier, err := NewHS256Verifier(util.RandomString(32), "read-cache")
+	require.NoError(t, err)
+
+	uid := util.RandomOwner()
+	email := util.RandomEmail()
+
+	token, err := verifier.CreateToken(uid, email, time.Minute)
+	require.NoError(t, err)
+	require.NotEmpty(t, token)
+
+	principal, err := verifier.VerifyToken(context.Background(), token)
+	require.NoError(t, err)
+	require.NotEmpty(t, principal)
+
+	require.Equal(t, uid, principal.UID)
+	require.Equal(t, email, principal.Email)
+	require.Equal(t, email, principal.VerifiedEmail())
+	require.Equal(t, "read-cache", principal.Claims["iss"])
+}
+
+func TestVerifiedEmail(t *testing.T) {
+	principal := &Principal{UID: util.RandomOwner(), Email: util.RandomEmail()}
+	require.Empty(t, principal.VerifiedEmail())
+
+	principal.Claims = map[string]interface{}{ClaimEmailVerified: false}
+	require.Empty(t, principal.VerifiedEmail())
+
+	principal.Claims[ClaimEmailVerified] = true
+	require.Equal(t, principal.Email, principal.VerifiedEmail())
+}
+
+func TestHS256VerifierShortKey(t *testing.T) {
+	verifier, err := NewHS256Verifier(util.RandomString(10), "")
+	require.Error(t, err)
+	require.Nil(t, verifier)
+}
+
+func TestExpiredJWTToken(t *testing.T) {
+	verifier, err := NewHS256Verifier(util.RandomString(32), "")
+	require.NoError(t, err)
+
+	token, err := verifier.CreateToken(util.RandomOwner(), util.RandomEmail(), -time.Minute)
+	require.NoError(t, err)
+
+	principal, err := verifier.VerifyToken(context.Background(), token)
+	require.ErrorIs(t, err, ErrExpiredToken)
+	require.Nil(t, principal)
+}
+
+func TestJWTTokenWrongKey(t *testing.T) {
+	verifier1, err := NewHS256Verifier(util.RandomString(32), "")
+	require.NoError(t, err)
+	verifier2, err := NewHS256Verifier(util.RandomString(32), "")
+	require.NoError(t, err)
+
+	token, err := verifier1.CreateToken(util.RandomOwner(), util.RandomEmail(), time.Minute)
+	require.NoError(t, err)
+
+	principal, err := verifier2.VerifyToken(context.Background(), token)
+	require.ErrorIs(t, err, ErrInvalidToken)
+	require.Nil(t, principal)
+}
+
+func TestJWTTokenWrongIssuer(t *testing.T) {
+	secretKey := util.RandomString(32)
+	verifier1, err := NewHS256Verifier(secretKey, "someone-else")
+	require.NoError(t, err)
+	verifier2, err := NewHS256Verifier(secretKey, "read-cache")
+	require.NoError(t, err)
+
+	token, err := verifier1.CreateToken(util.RandomOwner(), util.RandomEmail(), time.Minute)
+	require.NoError(t, err)
+
+	principal, err := verifier2.VerifyToken(context.Background(), token)
+	require.ErrorIs(t, err, ErrInvalidToken)
+	require.Nil(t, principal)
+}
+
+func TestRS256Verifier(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	privateKeyPEM := pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(privateKey),
+	})
+	publicKeyDER, err := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+	require.NoError(t, err)
+	publicKeyPEM := pem.EncodeToMemory(&pem.Block{
+		Type:  "PUBLIC KEY",
+		Bytes: publicKeyDER,
+	})
+
+	signer, err := NewRS256Verifier(publicKeyPEM, privateKeyPEM, "")
+	require.NoError(t, err)
+
+	uid := util.RandomOwner()
+	token, err := signer.CreateToken(uid, util.RandomEmail(), time.Minute)
+	require.NoError(t, err)
+
+	// a verifier with only the public key can verify but not issue
+	verifier, err := NewRS256Verifier(publicKeyPEM, nil, "")
+	require.NoError(t, err)
+
+	principal, err := verifier.VerifyToken(context.Background(), token)
+	require.NoError(t, err)
+	require.Equal(t, uid, principal.UID)
+
+	_, err = verifier.CreateToken(uid, util.RandomEmail(), time.Minute)
+	require.Error(t, err)
+
+	// an HS256 token must not be accepted by an RS256 verifier
+	hsVerifier, err := NewHS256Verifier(util.RandomString(32), "")
+	require.NoError(t, err)
+	hsToken, err := hsVerifier.CreateToken(uid, util.RandomEmail(), time.Minute)
+	require.NoError(t, err)
+
+	principal, err = verifier.VerifyToken(context.Background(), hsToken)
+	require.ErrorIs(t, err, ErrInvalidToken)
+	require.Nil(t, principal)
+}
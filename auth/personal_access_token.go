@@ -0,0 +1,45 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// PersonalAccessTokenPrefix marks a bearer token as a personal access token
+// rather than an ID token issued by the configured TokenVerifier
+const PersonalAccessTokenPrefix = "rc_pat_"
+
+const personalAccessTokenBytes = 32
+
+// Scopes a personal access token can be granted
+const (
+	ScopeRead  = "read"
+	ScopeWrite = "write"
+)
+
+// NewPersonalAccessToken generates a random personal access token and returns it with its hash.
+// Only the hash should be persisted; the token is shown to the user once.
+func NewPersonalAccessToken() (token string, hash string, err error) {
+	buf := make([]byte, personalAccessTokenBytes)
+	if _, err = rand.Read(buf); err != nil {
+		return "", "", fmt.Errorf("cannot generate personal access token: %w", err)
+	}
+
+	token = PersonalAccessTokenPrefix + base64.RawURLEncoding.EncodeToString(buf)
+	return token, HashPersonalAccessToken(token), nil
+}
+
+// HashPersonalAccessToken returns the hex encoded SHA-256 hash of a personal access token
+func HashPersonalAccessToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// IsPersonalAccessToken reports whether a bearer token looks like a personal access token
+func IsPersonalAccessToken(token string) bool {
+	return strings.HasPrefix(token, PersonalAccessTokenPrefix)
+}
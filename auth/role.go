@@ -0,0 +1,42 @@
+package auth
+
+// Roles a user can hold, from least to most privileged
+const (
+	RoleUser      = "user"
+	RoleModerator = "moderator"
+	RoleAdmin     = "admin"
+)
+
+// ClaimRole is the custom claim (e.g. a Firebase custom claim) that carries a user's role
+const ClaimRole = "role"
+
+var roleRank = map[string]int{
+	RoleUser:      1,
+	RoleModerator: 2,
+	RoleAdmin:     3,
+}
+
+// IsValidRole reports whether role is one of the known roles
+func IsValidRole(role string) bool {
+	_, ok := roleRank[role]
+	return ok
+}
+
+// HasRole reports whether a user holding role is allowed to act as required.
+// Roles are hierarchical, so an admin satisfies a moderator requirement.
+func HasRole(role string, required string) bool {
+	rank, ok := roleRank[role]
+	if !ok {
+		return false
+	}
+	return rank >= roleRank[required]
+}
+
+// Role returns the role carried in the token claims, or an empty string if there is none
+func (principal *Principal) Role() string {
+	role, _ := principal.Claims[ClaimRole].(string)
+	if !IsValidRole(role) {
+		return ""
+	}
+	return role
+}
@@ -0,0 +1,41 @@
+package auth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHasRole(t *testing.T) {
+	testCases := []struct {
+		role     string
+		required string
+		allowed  bool
+	}{
+		{RoleUser, RoleUser, true},
+		{RoleUser, RoleModerator, false},
+		{RoleUser, RoleAdmin, false},
+		{RoleModerator, RoleUser, true},
+		{RoleModerator, RoleModerator, true},
+		{RoleModerator, RoleAdmin, false},
+		{RoleAdmin, RoleModerator, true},
+		{RoleAdmin, RoleAdmin, true},
+		{"", RoleUser, false},
+		{"superuser", RoleUser, false},
+	}
+
+	for _, tc := range testCases {
+		require.Equal(t, tc.allowed, HasRole(tc.role, tc.required), "%s requires %s", tc.role, tc.required)
+	}
+}
+
+func TestPrincipalRole(t *testing.T) {
+	principal := &Principal{Claims: map[string]interface{}{ClaimRole: RoleAdmin}}
+	require.Equal(t, RoleAdmin, principal.Role())
+
+	principal = &Principal{Claims: map[string]interface{}{ClaimRole: "root"}}
+	require.Empty(t, principal.Role())
+
+	principal = &Principal{}
+	require.Empty(t, principal.Role())
+}
@@ -0,0 +1,53 @@
+package auth
+
+import (
+	"context"
+	"errors"
+)
+
+var (
+	ErrInvalidToken = errors.New("token is invalid")
+	ErrExpiredToken = errors.New("token has expired")
+)
+
+// Claims set on principals authenticated with a personal access token
+const (
+	ClaimPersonalAccessTokenID = "pat_id"
+	ClaimScope                 = "scope"
+)
+
+// ClaimEmailVerified is set by the identity provider once the email address was confirmed
+const ClaimEmailVerified = "email_verified"
+
+// Principal is the provider-neutral identity of an authenticated caller
+type Principal struct {
+	UID    string                 `json:"uid"`
+	Email  string                 `json:"email"`
+	Claims map[string]interface{} `json:"claims"`
+}
+
+// IsPersonalAccessToken reports whether the principal authenticated with a personal access token
+func (principal *Principal) IsPersonalAccessToken() bool {
+	_, ok := principal.Claims[ClaimPersonalAccessTokenID]
+	return ok
+}
+
+// VerifiedEmail returns the principal's email when the token vouches for it, or an empty string
+func (principal *Principal) VerifiedEmail() string {
+	if verified, _ := principal.Claims[ClaimEmailVerified].(bool); !verified {
+		return ""
+	}
+	return principal.Email
+}
+
+// Scope returns the scope granted to a personal access token, or an empty string for ID tokens
+func (principal *Principal) Scope() string {
+	scope, _ := principal.Claims[ClaimScope].(string)
+	return scope
+}
+
+// TokenVerifier is an interface for verifying bearer tokens
+type TokenVerifier interface {
+	// VerifyToken checks if the token is valid and returns the principal it was issued to
+	VerifyToken(ctx context.Context, token string) (*Principal, error)
+}
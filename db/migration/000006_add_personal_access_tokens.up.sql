@@ -0,0 +1,15 @@
+CREATE TABLE "personal_access_tokens" (
+  "id" bigserial PRIMARY KEY,
+  "user_id" varchar NOT NULL,
+  "name" varchar NOT NULL,
+  "token_hash" varchar UNIQUE NOT NULL,
+  "scope" varchar NOT NULL DEFAULT 'read' CHECK ("scope" IN ('read', 'write')),
+  "last_used_at" timestamptz,
+  "last_used_ip" varchar,
+  "expires_at" timestamptz,
+  "revoked_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  FOREIGN KEY ("user_id") REFERENCES users("id")
+);
+
+CREATE INDEX ON "personal_access_tokens" ("user_id");
@@ -0,0 +1,3 @@
+ALTER TABLE "users" ADD "role" varchar NOT NULL DEFAULT 'user';
+
+ALTER TABLE "users" ADD CONSTRAINT "users_role_check" CHECK ("role" IN ('user', 'moderator', 'admin'));
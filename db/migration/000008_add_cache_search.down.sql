@@ -0,0 +1 @@
+ALTER TABLE "caches" DROP COLUMN "search_vector";
@@ -0,0 +1,7 @@
+ALTER TABLE "caches" ADD "search_vector" tsvector
+  GENERATED ALWAYS AS (
+    setweight(to_tsvector('english', coalesce("title", '')), 'A') ||
+    setweight(to_tsvector('english', coalesce("content", '')), 'B')
+  ) STORED;
+
+CREATE INDEX ON "caches" USING GIN ("search_vector");
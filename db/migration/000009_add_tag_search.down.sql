@@ -0,0 +1 @@
+DROP INDEX IF EXISTS "tags_tag_name_trgm_idx";
@@ -0,0 +1,3 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX "tags_tag_name_trgm_idx" ON "tags" USING GIN ("tag_name" gin_trgm_ops);
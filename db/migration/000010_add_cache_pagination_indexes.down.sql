@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS "caches_public_created_at_id_idx";
+
+DROP INDEX IF EXISTS "caches_owner_created_at_id_idx";
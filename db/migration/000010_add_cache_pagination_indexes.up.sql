@@ -0,0 +1,3 @@
+CREATE INDEX "caches_owner_created_at_id_idx" ON "caches" ("owner", "created_at" DESC, "id" DESC);
+
+CREATE INDEX "caches_public_created_at_id_idx" ON "caches" ("created_at" DESC, "id" DESC) WHERE "is_public" = TRUE;
@@ -0,0 +1,18 @@
+CREATE TABLE "link_previews" (
+  "cache_id" bigint PRIMARY KEY,
+  "url" varchar NOT NULL,
+  "status" varchar NOT NULL DEFAULT 'pending' CHECK ("status" IN ('pending', 'done', 'failed')),
+  "title" varchar NOT NULL DEFAULT '',
+  "description" varchar NOT NULL DEFAULT '',
+  "site_name" varchar NOT NULL DEFAULT '',
+  "image_url" varchar NOT NULL DEFAULT '',
+  "canonical_url" varchar NOT NULL DEFAULT '',
+  "attempts" int NOT NULL DEFAULT 0,
+  "last_error" varchar NOT NULL DEFAULT '',
+  "next_attempt_at" timestamptz NOT NULL DEFAULT (now()),
+  "fetched_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  FOREIGN KEY ("cache_id") REFERENCES caches("id") ON DELETE CASCADE
+);
+
+CREATE INDEX ON "link_previews" ("next_attempt_at") WHERE "status" = 'pending';
@@ -0,0 +1,17 @@
+CREATE TABLE "cache_snapshots" (
+  "cache_id" bigint PRIMARY KEY,
+  "url" varchar NOT NULL,
+  "status" varchar NOT NULL DEFAULT 'pending' CHECK ("status" IN ('pending', 'done', 'failed')),
+  "title" varchar NOT NULL DEFAULT '',
+  "html" text NOT NULL DEFAULT '',
+  "text" text NOT NULL DEFAULT '',
+  "images" text[] NOT NULL DEFAULT '{}',
+  "attempts" int NOT NULL DEFAULT 0,
+  "last_error" varchar NOT NULL DEFAULT '',
+  "next_attempt_at" timestamptz NOT NULL DEFAULT (now()),
+  "archived_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  FOREIGN KEY ("cache_id") REFERENCES caches("id") ON DELETE CASCADE
+);
+
+CREATE INDEX ON "cache_snapshots" ("next_attempt_at") WHERE "status" = 'pending';
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS "caches_owner_normalized_url_key";
+
+ALTER TABLE "caches" DROP COLUMN IF EXISTS "normalized_url";
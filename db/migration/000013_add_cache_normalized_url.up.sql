@@ -0,0 +1,3 @@
+ALTER TABLE "caches" ADD COLUMN "normalized_url" varchar NOT NULL DEFAULT '';
+
+CREATE UNIQUE INDEX "caches_owner_normalized_url_key" ON "caches" ("owner", "normalized_url") WHERE "normalized_url" <> '';
@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS "caches_owner_status_created_at_id_idx";
+
+ALTER TABLE "caches" DROP COLUMN IF EXISTS "archived_at";
+ALTER TABLE "caches" DROP COLUMN IF EXISTS "read_at";
+ALTER TABLE "caches" DROP COLUMN IF EXISTS "progress";
+ALTER TABLE "caches" DROP COLUMN IF EXISTS "status";
+
+DROP TYPE IF EXISTS "reading_status";
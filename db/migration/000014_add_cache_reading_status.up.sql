@@ -0,0 +1,8 @@
+CREATE TYPE "reading_status" AS ENUM ('unread', 'reading', 'read', 'archived');
+
+ALTER TABLE "caches" ADD COLUMN "status" reading_status NOT NULL DEFAULT 'unread';
+ALTER TABLE "caches" ADD COLUMN "progress" int NOT NULL DEFAULT 0 CHECK ("progress" BETWEEN 0 AND 100);
+ALTER TABLE "caches" ADD COLUMN "read_at" timestamptz;
+ALTER TABLE "caches" ADD COLUMN "archived_at" timestamptz;
+
+CREATE INDEX "caches_owner_status_created_at_id_idx" ON "caches" ("owner", "status", "created_at" DESC, "id" DESC);
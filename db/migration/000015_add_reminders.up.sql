@@ -0,0 +1,20 @@
+CREATE TABLE "reminders" (
+  "id" bigserial PRIMARY KEY,
+  "cache_id" bigint NOT NULL,
+  "user_id" varchar NOT NULL,
+  "note" varchar NOT NULL DEFAULT '',
+  "remind_at" timestamptz NOT NULL,
+  "rrule" varchar NOT NULL DEFAULT '',
+  "time_zone" varchar NOT NULL DEFAULT 'UTC',
+  "next_fire_at" timestamptz,
+  "fire_count" int NOT NULL DEFAULT 0,
+  "last_fired_at" timestamptz,
+  "attempts" int NOT NULL DEFAULT 0,
+  "last_error" varchar NOT NULL DEFAULT '',
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  FOREIGN KEY ("cache_id") REFERENCES caches("id") ON DELETE CASCADE,
+  FOREIGN KEY ("user_id") REFERENCES users("id")
+);
+
+CREATE INDEX ON "reminders" ("next_fire_at") WHERE "next_fire_at" IS NOT NULL;
+CREATE INDEX ON "reminders" ("user_id", "cache_id");
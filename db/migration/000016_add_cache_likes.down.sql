@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS "cache_likes";
+
+ALTER TABLE "caches" DROP COLUMN IF EXISTS "like_count";
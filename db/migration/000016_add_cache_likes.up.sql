@@ -0,0 +1,14 @@
+ALTER TABLE "caches" ADD COLUMN "like_count" int NOT NULL DEFAULT 0;
+
+CREATE TABLE "cache_likes" (
+  "cache_id" bigint NOT NULL,
+  "user_id" varchar NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("cache_id", "user_id"),
+  FOREIGN KEY ("cache_id") REFERENCES caches("id") ON DELETE CASCADE,
+  FOREIGN KEY ("user_id") REFERENCES users("id") ON DELETE CASCADE
+);
+
+-- "most liked this week" counts recent likes per cache
+CREATE INDEX ON "cache_likes" ("created_at", "cache_id");
+CREATE INDEX ON "cache_likes" ("user_id");
@@ -0,0 +1,18 @@
+CREATE TABLE "comments" (
+  "id" bigserial PRIMARY KEY,
+  "cache_id" bigint NOT NULL,
+  "author" varchar NOT NULL,
+  "parent_id" bigint,
+  "body" text NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "edited_at" timestamptz,
+  "deleted_at" timestamptz,
+  "hidden_at" timestamptz,
+  FOREIGN KEY ("cache_id") REFERENCES caches("id") ON DELETE CASCADE,
+  FOREIGN KEY ("author") REFERENCES users("id") ON DELETE CASCADE,
+  FOREIGN KEY ("parent_id") REFERENCES comments("id") ON DELETE CASCADE
+);
+
+-- top level comments of a cache and the replies of a comment, oldest first
+CREATE INDEX ON "comments" ("cache_id", "created_at", "id") WHERE "parent_id" IS NULL;
+CREATE INDEX ON "comments" ("parent_id", "created_at", "id");
@@ -0,0 +1,27 @@
+CREATE TABLE "collections" (
+  "id" bigserial PRIMARY KEY,
+  "owner" varchar NOT NULL,
+  "name" varchar NOT NULL,
+  "description" text NOT NULL DEFAULT '',
+  "icon" varchar NOT NULL DEFAULT '',
+  "is_public" boolean NOT NULL DEFAULT false,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now()),
+  FOREIGN KEY ("owner") REFERENCES users("id") ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX ON "collections" ("owner", "name");
+
+-- deleting a collection only drops its memberships, never the caches
+CREATE TABLE "collection_caches" (
+  "collection_id" bigint NOT NULL,
+  "cache_id" bigint NOT NULL,
+  "position" int NOT NULL,
+  "added_at" timestamptz NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("collection_id", "cache_id"),
+  FOREIGN KEY ("collection_id") REFERENCES collections("id") ON DELETE CASCADE,
+  FOREIGN KEY ("cache_id") REFERENCES caches("id") ON DELETE CASCADE
+);
+
+CREATE INDEX ON "collection_caches" ("collection_id", "position", "cache_id");
+CREATE INDEX ON "collection_caches" ("cache_id");
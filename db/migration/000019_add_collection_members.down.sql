@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS "collection_invitations";
+DROP TABLE IF EXISTS "collection_members";
+
+DROP TYPE IF EXISTS "invitation_status";
+DROP TYPE IF EXISTS "collection_role";
@@ -0,0 +1,39 @@
+-- declared from least to most access, so greatest() picks the stronger role
+CREATE TYPE "collection_role" AS ENUM ('viewer', 'editor', 'owner');
+CREATE TYPE "invitation_status" AS ENUM ('pending', 'accepted', 'revoked');
+
+CREATE TABLE "collection_members" (
+  "collection_id" bigint NOT NULL,
+  "user_id" varchar NOT NULL,
+  "role" collection_role NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("collection_id", "user_id"),
+  FOREIGN KEY ("collection_id") REFERENCES collections("id") ON DELETE CASCADE,
+  FOREIGN KEY ("user_id") REFERENCES users("id") ON DELETE CASCADE
+);
+
+CREATE INDEX ON "collection_members" ("user_id");
+
+-- every existing collection is owned by the user who created it
+INSERT INTO "collection_members" ("collection_id", "user_id", "role")
+SELECT "id", "owner", 'owner' FROM "collections";
+
+CREATE TABLE "collection_invitations" (
+  "id" bigserial PRIMARY KEY,
+  "collection_id" bigint NOT NULL,
+  "inviter" varchar NOT NULL,
+  "invitee_email" varchar NOT NULL,
+  "invitee_id" varchar,
+  "role" collection_role NOT NULL,
+  "status" invitation_status NOT NULL DEFAULT 'pending',
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "responded_at" timestamptz,
+  FOREIGN KEY ("collection_id") REFERENCES collections("id") ON DELETE CASCADE,
+  FOREIGN KEY ("inviter") REFERENCES users("id") ON DELETE CASCADE,
+  FOREIGN KEY ("invitee_id") REFERENCES users("id") ON DELETE CASCADE
+);
+
+-- one open invitation per address and collection
+CREATE UNIQUE INDEX ON "collection_invitations" ("collection_id", lower("invitee_email")) WHERE "status" = 'pending';
+CREATE INDEX ON "collection_invitations" ("invitee_id") WHERE "status" = 'pending';
+CREATE INDEX ON "collection_invitations" (lower("invitee_email")) WHERE "status" = 'pending';
@@ -0,0 +1,41 @@
+CREATE TABLE "import_jobs" (
+  "id" bigserial PRIMARY KEY,
+  "user_id" varchar NOT NULL,
+  "format" varchar NOT NULL,
+  "filename" varchar NOT NULL DEFAULT '',
+  "status" varchar NOT NULL DEFAULT 'pending' CHECK ("status" IN ('pending', 'running', 'done', 'failed')),
+  "total" int NOT NULL,
+  "processed" int NOT NULL DEFAULT 0,
+  "created_count" int NOT NULL DEFAULT 0,
+  "skipped_count" int NOT NULL DEFAULT 0,
+  "failed_count" int NOT NULL DEFAULT 0,
+  "attempts" int NOT NULL DEFAULT 0,
+  "last_error" varchar NOT NULL DEFAULT '',
+  "next_attempt_at" timestamptz NOT NULL DEFAULT (now()),
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "finished_at" timestamptz,
+  FOREIGN KEY ("user_id") REFERENCES users("id") ON DELETE CASCADE
+);
+
+CREATE INDEX ON "import_jobs" ("user_id", "created_at");
+CREATE INDEX ON "import_jobs" ("next_attempt_at") WHERE "status" IN ('pending', 'running');
+
+-- one row per bookmark in the uploaded file, the per item report of the job
+CREATE TABLE "import_items" (
+  "job_id" bigint NOT NULL,
+  "position" int NOT NULL,
+  "url" varchar NOT NULL,
+  "title" varchar NOT NULL DEFAULT '',
+  "tags" varchar[] NOT NULL DEFAULT '{}',
+  "added_at" timestamptz,
+  "archived" boolean NOT NULL DEFAULT false,
+  "status" varchar NOT NULL DEFAULT 'pending' CHECK ("status" IN ('pending', 'created', 'skipped', 'failed')),
+  "cache_id" bigint,
+  "error" varchar NOT NULL DEFAULT '',
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("job_id", "position"),
+  FOREIGN KEY ("job_id") REFERENCES import_jobs("id") ON DELETE CASCADE,
+  FOREIGN KEY ("cache_id") REFERENCES caches("id") ON DELETE SET NULL
+);
+
+CREATE INDEX ON "import_items" ("cache_id");
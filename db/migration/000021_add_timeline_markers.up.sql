@@ -0,0 +1,6 @@
+CREATE TABLE "timeline_markers" (
+  "user_id" varchar PRIMARY KEY,
+  "last_seen_at" timestamptz NOT NULL,
+  "updated_at" timestamptz NOT NULL DEFAULT (now()),
+  FOREIGN KEY ("user_id") REFERENCES users("id") ON DELETE CASCADE
+);
@@ -0,0 +1,13 @@
+CREATE TABLE "digest_settings" (
+  "user_id" varchar PRIMARY KEY,
+  "frequency" varchar NOT NULL DEFAULT 'off' CHECK ("frequency" IN ('off', 'daily', 'weekly')),
+  "last_sent_at" timestamptz NOT NULL DEFAULT (now()),
+  "next_send_at" timestamptz,
+  "attempts" int NOT NULL DEFAULT 0,
+  "last_error" varchar NOT NULL DEFAULT '',
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now()),
+  FOREIGN KEY ("user_id") REFERENCES users("id") ON DELETE CASCADE
+);
+
+CREATE INDEX ON "digest_settings" ("next_send_at") WHERE "next_send_at" IS NOT NULL;
@@ -0,0 +1,46 @@
+CREATE TABLE "webhook_endpoints" (
+  "id" bigserial PRIMARY KEY,
+  "user_id" varchar NOT NULL,
+  "url" varchar NOT NULL,
+  "secret" varchar NOT NULL,
+  "events" varchar[] NOT NULL,
+  "description" varchar NOT NULL DEFAULT '',
+  "active" boolean NOT NULL DEFAULT TRUE,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now()),
+  FOREIGN KEY ("user_id") REFERENCES users("id") ON DELETE CASCADE
+);
+
+CREATE INDEX ON "webhook_endpoints" ("user_id");
+
+-- the transactional outbox, rows are written in the same transaction as the
+-- change they describe and fanned out to deliveries by the webhook dispatcher
+CREATE TABLE "webhook_events" (
+  "id" bigserial PRIMARY KEY,
+  "user_id" varchar NOT NULL,
+  "event_type" varchar NOT NULL,
+  "payload" jsonb NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "dispatched_at" timestamptz,
+  FOREIGN KEY ("user_id") REFERENCES users("id") ON DELETE CASCADE
+);
+
+CREATE INDEX ON "webhook_events" ("id") WHERE "dispatched_at" IS NULL;
+
+CREATE TABLE "webhook_deliveries" (
+  "id" bigserial PRIMARY KEY,
+  "endpoint_id" bigint NOT NULL,
+  "event_id" bigint NOT NULL,
+  "status" varchar NOT NULL DEFAULT 'pending' CHECK ("status" IN ('pending', 'succeeded', 'failed')),
+  "attempts" int NOT NULL DEFAULT 0,
+  "response_status" int,
+  "last_error" varchar NOT NULL DEFAULT '',
+  "next_attempt_at" timestamptz NOT NULL DEFAULT (now()),
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "delivered_at" timestamptz,
+  FOREIGN KEY ("endpoint_id") REFERENCES webhook_endpoints("id") ON DELETE CASCADE,
+  FOREIGN KEY ("event_id") REFERENCES webhook_events("id") ON DELETE CASCADE
+);
+
+CREATE INDEX ON "webhook_deliveries" ("endpoint_id", "created_at");
+CREATE INDEX ON "webhook_deliveries" ("next_attempt_at") WHERE "status" = 'pending';
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS "inbound_hook_requests";
+DROP TABLE IF EXISTS "inbound_hooks";
@@ -0,0 +1,28 @@
+-- inbound hooks let automation tools save caches without an ID token, the
+-- hook URL carries a secret token of which only the hash is stored
+CREATE TABLE "inbound_hooks" (
+  "id" bigserial PRIMARY KEY,
+  "user_id" varchar NOT NULL,
+  "name" varchar NOT NULL,
+  "token_hash" varchar NOT NULL UNIQUE,
+  "secret" varchar NOT NULL,
+  "require_signature" boolean NOT NULL DEFAULT FALSE,
+  "is_public" boolean NOT NULL DEFAULT FALSE,
+  "last_used_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  FOREIGN KEY ("user_id") REFERENCES users("id") ON DELETE CASCADE
+);
+
+CREATE INDEX ON "inbound_hooks" ("user_id");
+
+-- the client supplied ids seen by a hook, a repeated id answers with the
+-- cache the first request created instead of saving it again
+CREATE TABLE "inbound_hook_requests" (
+  "hook_id" bigint NOT NULL,
+  "client_id" varchar NOT NULL,
+  "cache_id" bigint NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("hook_id", "client_id"),
+  FOREIGN KEY ("hook_id") REFERENCES inbound_hooks("id") ON DELETE CASCADE,
+  FOREIGN KEY ("cache_id") REFERENCES caches("id") ON DELETE CASCADE
+);
@@ -0,0 +1,6 @@
+ALTER TABLE "import_items" DROP COLUMN IF EXISTS "archived_at";
+ALTER TABLE "import_items" DROP COLUMN IF EXISTS "read_at";
+ALTER TABLE "import_items" DROP COLUMN IF EXISTS "progress";
+ALTER TABLE "import_items" DROP COLUMN IF EXISTS "reading_status";
+ALTER TABLE "import_items" DROP COLUMN IF EXISTS "is_public";
+ALTER TABLE "import_items" DROP COLUMN IF EXISTS "content";
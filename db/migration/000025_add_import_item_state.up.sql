@@ -0,0 +1,8 @@
+-- ReadCache exports carry notes and the visibility and reading state of each
+-- cache, other formats leave these at their defaults
+ALTER TABLE "import_items" ADD COLUMN "content" varchar NOT NULL DEFAULT '';
+ALTER TABLE "import_items" ADD COLUMN "is_public" boolean NOT NULL DEFAULT false;
+ALTER TABLE "import_items" ADD COLUMN "reading_status" reading_status;
+ALTER TABLE "import_items" ADD COLUMN "progress" int NOT NULL DEFAULT 0 CHECK ("progress" BETWEEN 0 AND 100);
+ALTER TABLE "import_items" ADD COLUMN "read_at" timestamptz;
+ALTER TABLE "import_items" ADD COLUMN "archived_at" timestamptz;
@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS "caches_search_vector_idx";
+DROP FUNCTION IF EXISTS cache_search_vector(varchar, varchar);
+
+ALTER TABLE "caches" ADD "search_vector" tsvector
+  GENERATED ALWAYS AS (
+    setweight(to_tsvector('english', coalesce("title", '')), 'A') ||
+    setweight(to_tsvector('english', coalesce("content", '')), 'B')
+  ) STORED;
+
+CREATE INDEX ON "caches" USING GIN ("search_vector");
@@ -0,0 +1,12 @@
+-- the search vector is computed by a function and only kept in an expression
+-- index, as a stored column it was read with every cache row
+CREATE FUNCTION cache_search_vector(title varchar, content varchar) RETURNS tsvector
+LANGUAGE sql IMMUTABLE PARALLEL SAFE
+AS $$
+  SELECT setweight(to_tsvector('english', coalesce(title, '')), 'A') ||
+    setweight(to_tsvector('english', coalesce(content, '')), 'B')
+$$;
+
+ALTER TABLE "caches" DROP COLUMN "search_vector";
+
+CREATE INDEX "caches_search_vector_idx" ON "caches" USING GIN (cache_search_vector("title", "content"));
@@ -0,0 +1 @@
+ALTER TABLE "caches" DROP COLUMN IF EXISTS "updated_at";
@@ -0,0 +1,5 @@
+-- when a cache was last edited, feeds are dated by it so an edited item is
+-- picked up by readers that revalidate with If-Modified-Since
+ALTER TABLE "caches" ADD COLUMN "updated_at" timestamptz NOT NULL DEFAULT (now());
+
+UPDATE "caches" SET "updated_at" = "created_at";
@@ -0,0 +1,2 @@
+-- enum values can't be dropped, declined invitations read as revoked again
+UPDATE "collection_invitations" SET "status" = 'revoked' WHERE "status" = 'declined';
@@ -0,0 +1 @@
+ALTER TYPE "invitation_status" ADD VALUE IF NOT EXISTS 'declined';
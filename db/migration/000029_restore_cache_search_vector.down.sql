@@ -0,0 +1,12 @@
+DROP FUNCTION IF EXISTS html_escape(text);
+
+ALTER TABLE "caches" DROP COLUMN IF EXISTS "search_vector";
+
+CREATE FUNCTION cache_search_vector(title varchar, content varchar) RETURNS tsvector
+LANGUAGE sql IMMUTABLE PARALLEL SAFE
+AS $$
+  SELECT setweight(to_tsvector('english', coalesce(title, '')), 'A') ||
+    setweight(to_tsvector('english', coalesce(content, '')), 'B')
+$$;
+
+CREATE INDEX "caches_search_vector_idx" ON "caches" USING GIN (cache_search_vector("title", "content"));
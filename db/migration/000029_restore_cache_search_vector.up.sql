@@ -0,0 +1,20 @@
+-- search_vector goes back to the generated column 000008 added, 000026 had
+-- replaced it with an expression index
+DROP INDEX IF EXISTS "caches_search_vector_idx";
+DROP FUNCTION IF EXISTS cache_search_vector(varchar, varchar);
+
+ALTER TABLE "caches" ADD "search_vector" tsvector
+  GENERATED ALWAYS AS (
+    setweight(to_tsvector('english', coalesce("title", '')), 'A') ||
+    setweight(to_tsvector('english', coalesce("content", '')), 'B')
+  ) STORED;
+
+CREATE INDEX ON "caches" USING GIN ("search_vector");
+
+-- search highlights are HTML, the text around their <mark> tags is escaped with this
+CREATE FUNCTION html_escape(s text) RETURNS text
+LANGUAGE sql IMMUTABLE PARALLEL SAFE
+AS $$
+  SELECT replace(replace(replace(replace(replace(s,
+    '&', '&amp;'), '<', '&lt;'), '>', '&gt;'), '"', '&quot;'), '''', '&#39;')
+$$;
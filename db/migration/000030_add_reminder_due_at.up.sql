@@ -0,0 +1,3 @@
+-- the scheduled time of the occurrence being delivered, next_fire_at is moved
+-- by leases and retries while due_at keeps the time it was due
+ALTER TABLE "reminders" ADD COLUMN "due_at" timestamptz;
@@ -0,0 +1,9 @@
+-- when the link was saved, an import keeps the date the bookmark was added
+-- elsewhere here while created_at stays the time the cache was inserted, so
+-- imported caches still count as new for the feed and the digests
+ALTER TABLE "caches" ADD COLUMN "saved_at" timestamptz;
+
+UPDATE "caches" SET "saved_at" = "created_at";
+
+ALTER TABLE "caches" ALTER COLUMN "saved_at" SET NOT NULL;
+ALTER TABLE "caches" ALTER COLUMN "saved_at" SET DEFAULT (now());
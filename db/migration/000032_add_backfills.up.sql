@@ -0,0 +1,6 @@
+-- backfills the server runs at startup, a row marks one as finished so it
+-- doesn't scan the tables again on every boot
+CREATE TABLE "backfills" (
+  "name" varchar PRIMARY KEY,
+  "finished_at" timestamptz NOT NULL DEFAULT (now())
+);
@@ -35,108 +35,2213 @@ func (m *MockStore) EXPECT() *MockStoreMockRecorder {
 	return m.recorder
 }
 
+// AcceptCollectionInvitationTx mocks base method.
+func (m *MockStore) AcceptCollectionInvitationTx(arg0 context.Context, arg1 db.RespondToCollectionInvitationParams) (db.CollectionMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AcceptCollectionInvitationTx", arg0, arg1)
+	ret0, _ := ret[0].(db.CollectionMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AcceptCollectionInvitationTx indicates an expected call of AcceptCollectionInvitationTx.
+func (mr *MockStoreMockRecorder) AcceptCollectionInvitationTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcceptCollectionInvitationTx", reflect.TypeOf((*MockStore)(nil).AcceptCollectionInvitationTx), arg0, arg1)
+}
+
+// AddCacheLikeCount mocks base method.
+func (m *MockStore) AddCacheLikeCount(arg0 context.Context, arg1 db.AddCacheLikeCountParams) (int32, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddCacheLikeCount", arg0, arg1)
+	ret0, _ := ret[0].(int32)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddCacheLikeCount indicates an expected call of AddCacheLikeCount.
+func (mr *MockStoreMockRecorder) AddCacheLikeCount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddCacheLikeCount", reflect.TypeOf((*MockStore)(nil).AddCacheLikeCount), arg0, arg1)
+}
+
+// AddCacheToCollection mocks base method.
+func (m *MockStore) AddCacheToCollection(arg0 context.Context, arg1 db.AddCacheToCollectionParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddCacheToCollection", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddCacheToCollection indicates an expected call of AddCacheToCollection.
+func (mr *MockStoreMockRecorder) AddCacheToCollection(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddCacheToCollection", reflect.TypeOf((*MockStore)(nil).AddCacheToCollection), arg0, arg1)
+}
+
+// AddCacheToCollectionTx mocks base method.
+func (m *MockStore) AddCacheToCollectionTx(arg0 context.Context, arg1 db.AddCacheToCollectionParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddCacheToCollectionTx", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddCacheToCollectionTx indicates an expected call of AddCacheToCollectionTx.
+func (mr *MockStoreMockRecorder) AddCacheToCollectionTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddCacheToCollectionTx", reflect.TypeOf((*MockStore)(nil).AddCacheToCollectionTx), arg0, arg1)
+}
+
+// AddTagToCache mocks base method.
+func (m *MockStore) AddTagToCache(arg0 context.Context, arg1 db.AddTagToCacheParams) (db.CacheTag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddTagToCache", arg0, arg1)
+	ret0, _ := ret[0].(db.CacheTag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddTagToCache indicates an expected call of AddTagToCache.
+func (mr *MockStoreMockRecorder) AddTagToCache(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddTagToCache", reflect.TypeOf((*MockStore)(nil).AddTagToCache), arg0, arg1)
+}
+
+// ClaimDueCacheSnapshots mocks base method.
+func (m *MockStore) ClaimDueCacheSnapshots(arg0 context.Context, arg1 db.ClaimDueCacheSnapshotsParams) ([]db.CacheSnapshot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimDueCacheSnapshots", arg0, arg1)
+	ret0, _ := ret[0].([]db.CacheSnapshot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimDueCacheSnapshots indicates an expected call of ClaimDueCacheSnapshots.
+func (mr *MockStoreMockRecorder) ClaimDueCacheSnapshots(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimDueCacheSnapshots", reflect.TypeOf((*MockStore)(nil).ClaimDueCacheSnapshots), arg0, arg1)
+}
+
+// ClaimDueDigests mocks base method.
+func (m *MockStore) ClaimDueDigests(arg0 context.Context, arg1 db.ClaimDueDigestsParams) ([]db.ClaimDueDigestsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimDueDigests", arg0, arg1)
+	ret0, _ := ret[0].([]db.ClaimDueDigestsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimDueDigests indicates an expected call of ClaimDueDigests.
+func (mr *MockStoreMockRecorder) ClaimDueDigests(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimDueDigests", reflect.TypeOf((*MockStore)(nil).ClaimDueDigests), arg0, arg1)
+}
+
+// ClaimDueImportJobs mocks base method.
+func (m *MockStore) ClaimDueImportJobs(arg0 context.Context, arg1 db.ClaimDueImportJobsParams) ([]db.ImportJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimDueImportJobs", arg0, arg1)
+	ret0, _ := ret[0].([]db.ImportJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimDueImportJobs indicates an expected call of ClaimDueImportJobs.
+func (mr *MockStoreMockRecorder) ClaimDueImportJobs(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimDueImportJobs", reflect.TypeOf((*MockStore)(nil).ClaimDueImportJobs), arg0, arg1)
+}
+
+// ClaimDueLinkPreviews mocks base method.
+func (m *MockStore) ClaimDueLinkPreviews(arg0 context.Context, arg1 db.ClaimDueLinkPreviewsParams) ([]db.LinkPreview, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimDueLinkPreviews", arg0, arg1)
+	ret0, _ := ret[0].([]db.LinkPreview)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimDueLinkPreviews indicates an expected call of ClaimDueLinkPreviews.
+func (mr *MockStoreMockRecorder) ClaimDueLinkPreviews(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimDueLinkPreviews", reflect.TypeOf((*MockStore)(nil).ClaimDueLinkPreviews), arg0, arg1)
+}
+
+// ClaimDueReminders mocks base method.
+func (m *MockStore) ClaimDueReminders(arg0 context.Context, arg1 db.ClaimDueRemindersParams) ([]db.ClaimDueRemindersRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimDueReminders", arg0, arg1)
+	ret0, _ := ret[0].([]db.ClaimDueRemindersRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimDueReminders indicates an expected call of ClaimDueReminders.
+func (mr *MockStoreMockRecorder) ClaimDueReminders(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimDueReminders", reflect.TypeOf((*MockStore)(nil).ClaimDueReminders), arg0, arg1)
+}
+
+// ClaimDueWebhookDeliveries mocks base method.
+func (m *MockStore) ClaimDueWebhookDeliveries(arg0 context.Context, arg1 db.ClaimDueWebhookDeliveriesParams) ([]db.ClaimDueWebhookDeliveriesRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimDueWebhookDeliveries", arg0, arg1)
+	ret0, _ := ret[0].([]db.ClaimDueWebhookDeliveriesRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimDueWebhookDeliveries indicates an expected call of ClaimDueWebhookDeliveries.
+func (mr *MockStoreMockRecorder) ClaimDueWebhookDeliveries(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimDueWebhookDeliveries", reflect.TypeOf((*MockStore)(nil).ClaimDueWebhookDeliveries), arg0, arg1)
+}
+
+// CompleteCacheSnapshot mocks base method.
+func (m *MockStore) CompleteCacheSnapshot(arg0 context.Context, arg1 db.CompleteCacheSnapshotParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteCacheSnapshot", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CompleteCacheSnapshot indicates an expected call of CompleteCacheSnapshot.
+func (mr *MockStoreMockRecorder) CompleteCacheSnapshot(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteCacheSnapshot", reflect.TypeOf((*MockStore)(nil).CompleteCacheSnapshot), arg0, arg1)
+}
+
+// CompleteDigest mocks base method.
+func (m *MockStore) CompleteDigest(arg0 context.Context, arg1 db.CompleteDigestParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteDigest", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CompleteDigest indicates an expected call of CompleteDigest.
+func (mr *MockStoreMockRecorder) CompleteDigest(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteDigest", reflect.TypeOf((*MockStore)(nil).CompleteDigest), arg0, arg1)
+}
+
+// CompleteImportJob mocks base method.
+func (m *MockStore) CompleteImportJob(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteImportJob", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CompleteImportJob indicates an expected call of CompleteImportJob.
+func (mr *MockStoreMockRecorder) CompleteImportJob(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteImportJob", reflect.TypeOf((*MockStore)(nil).CompleteImportJob), arg0, arg1)
+}
+
+// CompleteLinkPreview mocks base method.
+func (m *MockStore) CompleteLinkPreview(arg0 context.Context, arg1 db.CompleteLinkPreviewParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteLinkPreview", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CompleteLinkPreview indicates an expected call of CompleteLinkPreview.
+func (mr *MockStoreMockRecorder) CompleteLinkPreview(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteLinkPreview", reflect.TypeOf((*MockStore)(nil).CompleteLinkPreview), arg0, arg1)
+}
+
+// CompleteReminderFire mocks base method.
+func (m *MockStore) CompleteReminderFire(arg0 context.Context, arg1 db.CompleteReminderFireParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteReminderFire", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CompleteReminderFire indicates an expected call of CompleteReminderFire.
+func (mr *MockStoreMockRecorder) CompleteReminderFire(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteReminderFire", reflect.TypeOf((*MockStore)(nil).CompleteReminderFire), arg0, arg1)
+}
+
+// CompleteWebhookDelivery mocks base method.
+func (m *MockStore) CompleteWebhookDelivery(arg0 context.Context, arg1 db.CompleteWebhookDeliveryParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteWebhookDelivery", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CompleteWebhookDelivery indicates an expected call of CompleteWebhookDelivery.
+func (mr *MockStoreMockRecorder) CompleteWebhookDelivery(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteWebhookDelivery", reflect.TypeOf((*MockStore)(nil).CompleteWebhookDelivery), arg0, arg1)
+}
+
+// CountCollectionOwners mocks base method.
+func (m *MockStore) CountCollectionOwners(arg0 context.Context, arg1 int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountCollectionOwners", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountCollectionOwners indicates an expected call of CountCollectionOwners.
+func (mr *MockStoreMockRecorder) CountCollectionOwners(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountCollectionOwners", reflect.TypeOf((*MockStore)(nil).CountCollectionOwners), arg0, arg1)
+}
+
+// CountUnseenTimelineCaches mocks base method.
+func (m *MockStore) CountUnseenTimelineCaches(arg0 context.Context, arg1 db.CountUnseenTimelineCachesParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountUnseenTimelineCaches", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountUnseenTimelineCaches indicates an expected call of CountUnseenTimelineCaches.
+func (mr *MockStoreMockRecorder) CountUnseenTimelineCaches(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountUnseenTimelineCaches", reflect.TypeOf((*MockStore)(nil).CountUnseenTimelineCaches), arg0, arg1)
+}
+
 // CreateCache mocks base method.
 func (m *MockStore) CreateCache(arg0 context.Context, arg1 db.CreateCacheParams) (db.Cache, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateCache", arg0, arg1)
-	ret0, _ := ret[0].(db.Cache)
+	ret := m.ctrl.Call(m, "CreateCache", arg0, arg1)
+	ret0, _ := ret[0].(db.Cache)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateCache indicates an expected call of CreateCache.
+func (mr *MockStoreMockRecorder) CreateCache(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCache", reflect.TypeOf((*MockStore)(nil).CreateCache), arg0, arg1)
+}
+
+// CreateCacheLike mocks base method.
+func (m *MockStore) CreateCacheLike(arg0 context.Context, arg1 db.CreateCacheLikeParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCacheLike", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateCacheLike indicates an expected call of CreateCacheLike.
+func (mr *MockStoreMockRecorder) CreateCacheLike(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCacheLike", reflect.TypeOf((*MockStore)(nil).CreateCacheLike), arg0, arg1)
+}
+
+// CreateCacheWithTagsTx mocks base method.
+func (m *MockStore) CreateCacheWithTagsTx(arg0 context.Context, arg1 db.CreateCacheWithTagsTxParams) (db.CreateCacheWithTagsTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCacheWithTagsTx", arg0, arg1)
+	ret0, _ := ret[0].(db.CreateCacheWithTagsTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateCacheWithTagsTx indicates an expected call of CreateCacheWithTagsTx.
+func (mr *MockStoreMockRecorder) CreateCacheWithTagsTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCacheWithTagsTx", reflect.TypeOf((*MockStore)(nil).CreateCacheWithTagsTx), arg0, arg1)
+}
+
+// CreateCollection mocks base method.
+func (m *MockStore) CreateCollection(arg0 context.Context, arg1 db.CreateCollectionParams) (db.Collection, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCollection", arg0, arg1)
+	ret0, _ := ret[0].(db.Collection)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateCollection indicates an expected call of CreateCollection.
+func (mr *MockStoreMockRecorder) CreateCollection(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCollection", reflect.TypeOf((*MockStore)(nil).CreateCollection), arg0, arg1)
+}
+
+// CreateCollectionInvitation mocks base method.
+func (m *MockStore) CreateCollectionInvitation(arg0 context.Context, arg1 db.CreateCollectionInvitationParams) (db.CollectionInvitation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCollectionInvitation", arg0, arg1)
+	ret0, _ := ret[0].(db.CollectionInvitation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateCollectionInvitation indicates an expected call of CreateCollectionInvitation.
+func (mr *MockStoreMockRecorder) CreateCollectionInvitation(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCollectionInvitation", reflect.TypeOf((*MockStore)(nil).CreateCollectionInvitation), arg0, arg1)
+}
+
+// CreateCollectionTx mocks base method.
+func (m *MockStore) CreateCollectionTx(arg0 context.Context, arg1 db.CreateCollectionParams) (db.Collection, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCollectionTx", arg0, arg1)
+	ret0, _ := ret[0].(db.Collection)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateCollectionTx indicates an expected call of CreateCollectionTx.
+func (mr *MockStoreMockRecorder) CreateCollectionTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCollectionTx", reflect.TypeOf((*MockStore)(nil).CreateCollectionTx), arg0, arg1)
+}
+
+// CreateComment mocks base method.
+func (m *MockStore) CreateComment(arg0 context.Context, arg1 db.CreateCommentParams) (db.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateComment", arg0, arg1)
+	ret0, _ := ret[0].(db.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateComment indicates an expected call of CreateComment.
+func (mr *MockStoreMockRecorder) CreateComment(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateComment", reflect.TypeOf((*MockStore)(nil).CreateComment), arg0, arg1)
+}
+
+// CreateImportItems mocks base method.
+func (m *MockStore) CreateImportItems(arg0 context.Context, arg1 db.CreateImportItemsParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateImportItems", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateImportItems indicates an expected call of CreateImportItems.
+func (mr *MockStoreMockRecorder) CreateImportItems(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateImportItems", reflect.TypeOf((*MockStore)(nil).CreateImportItems), arg0, arg1)
+}
+
+// CreateImportJob mocks base method.
+func (m *MockStore) CreateImportJob(arg0 context.Context, arg1 db.CreateImportJobParams) (db.ImportJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateImportJob", arg0, arg1)
+	ret0, _ := ret[0].(db.ImportJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateImportJob indicates an expected call of CreateImportJob.
+func (mr *MockStoreMockRecorder) CreateImportJob(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateImportJob", reflect.TypeOf((*MockStore)(nil).CreateImportJob), arg0, arg1)
+}
+
+// CreateImportJobTx mocks base method.
+func (m *MockStore) CreateImportJobTx(arg0 context.Context, arg1 db.CreateImportJobTxParams) (db.ImportJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateImportJobTx", arg0, arg1)
+	ret0, _ := ret[0].(db.ImportJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateImportJobTx indicates an expected call of CreateImportJobTx.
+func (mr *MockStoreMockRecorder) CreateImportJobTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateImportJobTx", reflect.TypeOf((*MockStore)(nil).CreateImportJobTx), arg0, arg1)
+}
+
+// CreateInboundHook mocks base method.
+func (m *MockStore) CreateInboundHook(arg0 context.Context, arg1 db.CreateInboundHookParams) (db.InboundHook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateInboundHook", arg0, arg1)
+	ret0, _ := ret[0].(db.InboundHook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateInboundHook indicates an expected call of CreateInboundHook.
+func (mr *MockStoreMockRecorder) CreateInboundHook(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateInboundHook", reflect.TypeOf((*MockStore)(nil).CreateInboundHook), arg0, arg1)
+}
+
+// CreateInboundHookRequest mocks base method.
+func (m *MockStore) CreateInboundHookRequest(arg0 context.Context, arg1 db.CreateInboundHookRequestParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateInboundHookRequest", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateInboundHookRequest indicates an expected call of CreateInboundHookRequest.
+func (mr *MockStoreMockRecorder) CreateInboundHookRequest(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateInboundHookRequest", reflect.TypeOf((*MockStore)(nil).CreateInboundHookRequest), arg0, arg1)
+}
+
+// CreateLinkPreview mocks base method.
+func (m *MockStore) CreateLinkPreview(arg0 context.Context, arg1 db.CreateLinkPreviewParams) (db.LinkPreview, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateLinkPreview", arg0, arg1)
+	ret0, _ := ret[0].(db.LinkPreview)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateLinkPreview indicates an expected call of CreateLinkPreview.
+func (mr *MockStoreMockRecorder) CreateLinkPreview(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLinkPreview", reflect.TypeOf((*MockStore)(nil).CreateLinkPreview), arg0, arg1)
+}
+
+// CreatePersonalAccessToken mocks base method.
+func (m *MockStore) CreatePersonalAccessToken(arg0 context.Context, arg1 db.CreatePersonalAccessTokenParams) (db.PersonalAccessToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePersonalAccessToken", arg0, arg1)
+	ret0, _ := ret[0].(db.PersonalAccessToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePersonalAccessToken indicates an expected call of CreatePersonalAccessToken.
+func (mr *MockStoreMockRecorder) CreatePersonalAccessToken(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePersonalAccessToken", reflect.TypeOf((*MockStore)(nil).CreatePersonalAccessToken), arg0, arg1)
+}
+
+// CreateReminder mocks base method.
+func (m *MockStore) CreateReminder(arg0 context.Context, arg1 db.CreateReminderParams) (db.Reminder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateReminder", arg0, arg1)
+	ret0, _ := ret[0].(db.Reminder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateReminder indicates an expected call of CreateReminder.
+func (mr *MockStoreMockRecorder) CreateReminder(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateReminder", reflect.TypeOf((*MockStore)(nil).CreateReminder), arg0, arg1)
+}
+
+// CreateTag mocks base method.
+func (m *MockStore) CreateTag(arg0 context.Context, arg1 string) (db.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTag", arg0, arg1)
+	ret0, _ := ret[0].(db.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTag indicates an expected call of CreateTag.
+func (mr *MockStoreMockRecorder) CreateTag(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTag", reflect.TypeOf((*MockStore)(nil).CreateTag), arg0, arg1)
+}
+
+// CreateUser mocks base method.
+func (m *MockStore) CreateUser(arg0 context.Context, arg1 db.CreateUserParams) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUser", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateUser indicates an expected call of CreateUser.
+func (mr *MockStoreMockRecorder) CreateUser(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockStore)(nil).CreateUser), arg0, arg1)
+}
+
+// CreateWebhookEndpoint mocks base method.
+func (m *MockStore) CreateWebhookEndpoint(arg0 context.Context, arg1 db.CreateWebhookEndpointParams) (db.WebhookEndpoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebhookEndpoint", arg0, arg1)
+	ret0, _ := ret[0].(db.WebhookEndpoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWebhookEndpoint indicates an expected call of CreateWebhookEndpoint.
+func (mr *MockStoreMockRecorder) CreateWebhookEndpoint(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhookEndpoint", reflect.TypeOf((*MockStore)(nil).CreateWebhookEndpoint), arg0, arg1)
+}
+
+// CreateWebhookEvent mocks base method.
+func (m *MockStore) CreateWebhookEvent(arg0 context.Context, arg1 db.CreateWebhookEventParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebhookEvent", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateWebhookEvent indicates an expected call of CreateWebhookEvent.
+func (mr *MockStoreMockRecorder) CreateWebhookEvent(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhookEvent", reflect.TypeOf((*MockStore)(nil).CreateWebhookEvent), arg0, arg1)
+}
+
+// DeleteCache mocks base method.
+func (m *MockStore) DeleteCache(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCache", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCache indicates an expected call of DeleteCache.
+func (mr *MockStoreMockRecorder) DeleteCache(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCache", reflect.TypeOf((*MockStore)(nil).DeleteCache), arg0, arg1)
+}
+
+// DeleteCacheLike mocks base method.
+func (m *MockStore) DeleteCacheLike(arg0 context.Context, arg1 db.DeleteCacheLikeParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCacheLike", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteCacheLike indicates an expected call of DeleteCacheLike.
+func (mr *MockStoreMockRecorder) DeleteCacheLike(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCacheLike", reflect.TypeOf((*MockStore)(nil).DeleteCacheLike), arg0, arg1)
+}
+
+// DeleteCacheTag mocks base method.
+func (m *MockStore) DeleteCacheTag(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCacheTag", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCacheTag indicates an expected call of DeleteCacheTag.
+func (mr *MockStoreMockRecorder) DeleteCacheTag(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCacheTag", reflect.TypeOf((*MockStore)(nil).DeleteCacheTag), arg0, arg1)
+}
+
+// DeleteCacheTx mocks base method.
+func (m *MockStore) DeleteCacheTx(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCacheTx", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCacheTx indicates an expected call of DeleteCacheTx.
+func (mr *MockStoreMockRecorder) DeleteCacheTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCacheTx", reflect.TypeOf((*MockStore)(nil).DeleteCacheTx), arg0, arg1)
+}
+
+// DeleteCollection mocks base method.
+func (m *MockStore) DeleteCollection(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCollection", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCollection indicates an expected call of DeleteCollection.
+func (mr *MockStoreMockRecorder) DeleteCollection(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCollection", reflect.TypeOf((*MockStore)(nil).DeleteCollection), arg0, arg1)
+}
+
+// DeleteCollectionMember mocks base method.
+func (m *MockStore) DeleteCollectionMember(arg0 context.Context, arg1 db.DeleteCollectionMemberParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCollectionMember", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCollectionMember indicates an expected call of DeleteCollectionMember.
+func (mr *MockStoreMockRecorder) DeleteCollectionMember(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCollectionMember", reflect.TypeOf((*MockStore)(nil).DeleteCollectionMember), arg0, arg1)
+}
+
+// DeleteInboundHook mocks base method.
+func (m *MockStore) DeleteInboundHook(arg0 context.Context, arg1 db.DeleteInboundHookParams) (db.InboundHook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteInboundHook", arg0, arg1)
+	ret0, _ := ret[0].(db.InboundHook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteInboundHook indicates an expected call of DeleteInboundHook.
+func (mr *MockStoreMockRecorder) DeleteInboundHook(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteInboundHook", reflect.TypeOf((*MockStore)(nil).DeleteInboundHook), arg0, arg1)
+}
+
+// DeleteReminder mocks base method.
+func (m *MockStore) DeleteReminder(arg0 context.Context, arg1 db.DeleteReminderParams) (db.Reminder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteReminder", arg0, arg1)
+	ret0, _ := ret[0].(db.Reminder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteReminder indicates an expected call of DeleteReminder.
+func (mr *MockStoreMockRecorder) DeleteReminder(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteReminder", reflect.TypeOf((*MockStore)(nil).DeleteReminder), arg0, arg1)
+}
+
+// DeleteTagFromCacheTagsTable mocks base method.
+func (m *MockStore) DeleteTagFromCacheTagsTable(arg0 context.Context, arg1 int32) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTagFromCacheTagsTable", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTagFromCacheTagsTable indicates an expected call of DeleteTagFromCacheTagsTable.
+func (mr *MockStoreMockRecorder) DeleteTagFromCacheTagsTable(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTagFromCacheTagsTable", reflect.TypeOf((*MockStore)(nil).DeleteTagFromCacheTagsTable), arg0, arg1)
+}
+
+// DeleteTagFromTagsTable mocks base method.
+func (m *MockStore) DeleteTagFromTagsTable(arg0 context.Context, arg1 int32) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTagFromTagsTable", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTagFromTagsTable indicates an expected call of DeleteTagFromTagsTable.
+func (mr *MockStoreMockRecorder) DeleteTagFromTagsTable(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTagFromTagsTable", reflect.TypeOf((*MockStore)(nil).DeleteTagFromTagsTable), arg0, arg1)
+}
+
+// DeleteTagFromUserTagsTable mocks base method.
+func (m *MockStore) DeleteTagFromUserTagsTable(arg0 context.Context, arg1 int32) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTagFromUserTagsTable", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTagFromUserTagsTable indicates an expected call of DeleteTagFromUserTagsTable.
+func (mr *MockStoreMockRecorder) DeleteTagFromUserTagsTable(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTagFromUserTagsTable", reflect.TypeOf((*MockStore)(nil).DeleteTagFromUserTagsTable), arg0, arg1)
+}
+
+// DeleteTagTx mocks base method.
+func (m *MockStore) DeleteTagTx(arg0 context.Context, arg1 int32) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTagTx", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTagTx indicates an expected call of DeleteTagTx.
+func (mr *MockStoreMockRecorder) DeleteTagTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTagTx", reflect.TypeOf((*MockStore)(nil).DeleteTagTx), arg0, arg1)
+}
+
+// DeleteWebhookEndpoint mocks base method.
+func (m *MockStore) DeleteWebhookEndpoint(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebhookEndpoint", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWebhookEndpoint indicates an expected call of DeleteWebhookEndpoint.
+func (mr *MockStoreMockRecorder) DeleteWebhookEndpoint(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhookEndpoint", reflect.TypeOf((*MockStore)(nil).DeleteWebhookEndpoint), arg0, arg1)
+}
+
+// DispatchWebhookEvents mocks base method.
+func (m *MockStore) DispatchWebhookEvents(arg0 context.Context, arg1 int32) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DispatchWebhookEvents", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DispatchWebhookEvents indicates an expected call of DispatchWebhookEvents.
+func (mr *MockStoreMockRecorder) DispatchWebhookEvents(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DispatchWebhookEvents", reflect.TypeOf((*MockStore)(nil).DispatchWebhookEvents), arg0, arg1)
+}
+
+// ExtendImportJobLease mocks base method.
+func (m *MockStore) ExtendImportJobLease(arg0 context.Context, arg1 db.ExtendImportJobLeaseParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExtendImportJobLease", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExtendImportJobLease indicates an expected call of ExtendImportJobLease.
+func (mr *MockStoreMockRecorder) ExtendImportJobLease(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExtendImportJobLease", reflect.TypeOf((*MockStore)(nil).ExtendImportJobLease), arg0, arg1)
+}
+
+// FailCacheSnapshot mocks base method.
+func (m *MockStore) FailCacheSnapshot(arg0 context.Context, arg1 db.FailCacheSnapshotParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FailCacheSnapshot", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FailCacheSnapshot indicates an expected call of FailCacheSnapshot.
+func (mr *MockStoreMockRecorder) FailCacheSnapshot(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FailCacheSnapshot", reflect.TypeOf((*MockStore)(nil).FailCacheSnapshot), arg0, arg1)
+}
+
+// FailImportJob mocks base method.
+func (m *MockStore) FailImportJob(arg0 context.Context, arg1 db.FailImportJobParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FailImportJob", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FailImportJob indicates an expected call of FailImportJob.
+func (mr *MockStoreMockRecorder) FailImportJob(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FailImportJob", reflect.TypeOf((*MockStore)(nil).FailImportJob), arg0, arg1)
+}
+
+// FailLinkPreview mocks base method.
+func (m *MockStore) FailLinkPreview(arg0 context.Context, arg1 db.FailLinkPreviewParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FailLinkPreview", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FailLinkPreview indicates an expected call of FailLinkPreview.
+func (mr *MockStoreMockRecorder) FailLinkPreview(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FailLinkPreview", reflect.TypeOf((*MockStore)(nil).FailLinkPreview), arg0, arg1)
+}
+
+// FailWebhookDelivery mocks base method.
+func (m *MockStore) FailWebhookDelivery(arg0 context.Context, arg1 db.FailWebhookDeliveryParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FailWebhookDelivery", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FailWebhookDelivery indicates an expected call of FailWebhookDelivery.
+func (mr *MockStoreMockRecorder) FailWebhookDelivery(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FailWebhookDelivery", reflect.TypeOf((*MockStore)(nil).FailWebhookDelivery), arg0, arg1)
+}
+
+// FinishBackfill mocks base method.
+func (m *MockStore) FinishBackfill(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FinishBackfill", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FinishBackfill indicates an expected call of FinishBackfill.
+func (mr *MockStoreMockRecorder) FinishBackfill(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinishBackfill", reflect.TypeOf((*MockStore)(nil).FinishBackfill), arg0, arg1)
+}
+
+// FinishImportItem mocks base method.
+func (m *MockStore) FinishImportItem(arg0 context.Context, arg1 db.FinishImportItemParams) (db.ImportItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FinishImportItem", arg0, arg1)
+	ret0, _ := ret[0].(db.ImportItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FinishImportItem indicates an expected call of FinishImportItem.
+func (mr *MockStoreMockRecorder) FinishImportItem(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinishImportItem", reflect.TypeOf((*MockStore)(nil).FinishImportItem), arg0, arg1)
+}
+
+// GetBackfill mocks base method.
+func (m *MockStore) GetBackfill(arg0 context.Context, arg1 string) (db.Backfill, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBackfill", arg0, arg1)
+	ret0, _ := ret[0].(db.Backfill)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBackfill indicates an expected call of GetBackfill.
+func (mr *MockStoreMockRecorder) GetBackfill(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBackfill", reflect.TypeOf((*MockStore)(nil).GetBackfill), arg0, arg1)
+}
+
+// GetCache mocks base method.
+func (m *MockStore) GetCache(arg0 context.Context, arg1 int64) (db.Cache, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCache", arg0, arg1)
+	ret0, _ := ret[0].(db.Cache)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCache indicates an expected call of GetCache.
+func (mr *MockStoreMockRecorder) GetCache(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCache", reflect.TypeOf((*MockStore)(nil).GetCache), arg0, arg1)
+}
+
+// GetCacheByNormalizedURL mocks base method.
+func (m *MockStore) GetCacheByNormalizedURL(arg0 context.Context, arg1 db.GetCacheByNormalizedURLParams) (db.Cache, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCacheByNormalizedURL", arg0, arg1)
+	ret0, _ := ret[0].(db.Cache)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCacheByNormalizedURL indicates an expected call of GetCacheByNormalizedURL.
+func (mr *MockStoreMockRecorder) GetCacheByNormalizedURL(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCacheByNormalizedURL", reflect.TypeOf((*MockStore)(nil).GetCacheByNormalizedURL), arg0, arg1)
+}
+
+// GetCacheSnapshot mocks base method.
+func (m *MockStore) GetCacheSnapshot(arg0 context.Context, arg1 int64) (db.CacheSnapshot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCacheSnapshot", arg0, arg1)
+	ret0, _ := ret[0].(db.CacheSnapshot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCacheSnapshot indicates an expected call of GetCacheSnapshot.
+func (mr *MockStoreMockRecorder) GetCacheSnapshot(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCacheSnapshot", reflect.TypeOf((*MockStore)(nil).GetCacheSnapshot), arg0, arg1)
+}
+
+// GetCollection mocks base method.
+func (m *MockStore) GetCollection(arg0 context.Context, arg1 int64) (db.Collection, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCollection", arg0, arg1)
+	ret0, _ := ret[0].(db.Collection)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCollection indicates an expected call of GetCollection.
+func (mr *MockStoreMockRecorder) GetCollection(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCollection", reflect.TypeOf((*MockStore)(nil).GetCollection), arg0, arg1)
+}
+
+// GetCollectionForUpdate mocks base method.
+func (m *MockStore) GetCollectionForUpdate(arg0 context.Context, arg1 int64) (db.Collection, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCollectionForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(db.Collection)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCollectionForUpdate indicates an expected call of GetCollectionForUpdate.
+func (mr *MockStoreMockRecorder) GetCollectionForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCollectionForUpdate", reflect.TypeOf((*MockStore)(nil).GetCollectionForUpdate), arg0, arg1)
+}
+
+// GetCollectionMember mocks base method.
+func (m *MockStore) GetCollectionMember(arg0 context.Context, arg1 db.GetCollectionMemberParams) (db.CollectionMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCollectionMember", arg0, arg1)
+	ret0, _ := ret[0].(db.CollectionMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCollectionMember indicates an expected call of GetCollectionMember.
+func (mr *MockStoreMockRecorder) GetCollectionMember(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCollectionMember", reflect.TypeOf((*MockStore)(nil).GetCollectionMember), arg0, arg1)
+}
+
+// GetComment mocks base method.
+func (m *MockStore) GetComment(arg0 context.Context, arg1 int64) (db.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetComment", arg0, arg1)
+	ret0, _ := ret[0].(db.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetComment indicates an expected call of GetComment.
+func (mr *MockStoreMockRecorder) GetComment(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetComment", reflect.TypeOf((*MockStore)(nil).GetComment), arg0, arg1)
+}
+
+// GetDigestSettings mocks base method.
+func (m *MockStore) GetDigestSettings(arg0 context.Context, arg1 string) (db.DigestSetting, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDigestSettings", arg0, arg1)
+	ret0, _ := ret[0].(db.DigestSetting)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDigestSettings indicates an expected call of GetDigestSettings.
+func (mr *MockStoreMockRecorder) GetDigestSettings(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDigestSettings", reflect.TypeOf((*MockStore)(nil).GetDigestSettings), arg0, arg1)
+}
+
+// GetImportJob mocks base method.
+func (m *MockStore) GetImportJob(arg0 context.Context, arg1 int64) (db.ImportJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetImportJob", arg0, arg1)
+	ret0, _ := ret[0].(db.ImportJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetImportJob indicates an expected call of GetImportJob.
+func (mr *MockStoreMockRecorder) GetImportJob(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetImportJob", reflect.TypeOf((*MockStore)(nil).GetImportJob), arg0, arg1)
+}
+
+// GetInboundHookByTokenHash mocks base method.
+func (m *MockStore) GetInboundHookByTokenHash(arg0 context.Context, arg1 string) (db.InboundHook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInboundHookByTokenHash", arg0, arg1)
+	ret0, _ := ret[0].(db.InboundHook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetInboundHookByTokenHash indicates an expected call of GetInboundHookByTokenHash.
+func (mr *MockStoreMockRecorder) GetInboundHookByTokenHash(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInboundHookByTokenHash", reflect.TypeOf((*MockStore)(nil).GetInboundHookByTokenHash), arg0, arg1)
+}
+
+// GetInboundHookRequest mocks base method.
+func (m *MockStore) GetInboundHookRequest(arg0 context.Context, arg1 db.GetInboundHookRequestParams) (db.InboundHookRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInboundHookRequest", arg0, arg1)
+	ret0, _ := ret[0].(db.InboundHookRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetInboundHookRequest indicates an expected call of GetInboundHookRequest.
+func (mr *MockStoreMockRecorder) GetInboundHookRequest(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInboundHookRequest", reflect.TypeOf((*MockStore)(nil).GetInboundHookRequest), arg0, arg1)
+}
+
+// GetLinkPreview mocks base method.
+func (m *MockStore) GetLinkPreview(arg0 context.Context, arg1 int64) (db.LinkPreview, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLinkPreview", arg0, arg1)
+	ret0, _ := ret[0].(db.LinkPreview)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLinkPreview indicates an expected call of GetLinkPreview.
+func (mr *MockStoreMockRecorder) GetLinkPreview(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLinkPreview", reflect.TypeOf((*MockStore)(nil).GetLinkPreview), arg0, arg1)
+}
+
+// GetPersonalAccessTokenByHash mocks base method.
+func (m *MockStore) GetPersonalAccessTokenByHash(arg0 context.Context, arg1 string) (db.PersonalAccessToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPersonalAccessTokenByHash", arg0, arg1)
+	ret0, _ := ret[0].(db.PersonalAccessToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPersonalAccessTokenByHash indicates an expected call of GetPersonalAccessTokenByHash.
+func (mr *MockStoreMockRecorder) GetPersonalAccessTokenByHash(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPersonalAccessTokenByHash", reflect.TypeOf((*MockStore)(nil).GetPersonalAccessTokenByHash), arg0, arg1)
+}
+
+// GetTag mocks base method.
+func (m *MockStore) GetTag(arg0 context.Context, arg1 int32) (db.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTag", arg0, arg1)
+	ret0, _ := ret[0].(db.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTag indicates an expected call of GetTag.
+func (mr *MockStoreMockRecorder) GetTag(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTag", reflect.TypeOf((*MockStore)(nil).GetTag), arg0, arg1)
+}
+
+// GetTagByName mocks base method.
+func (m *MockStore) GetTagByName(arg0 context.Context, arg1 string) (db.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTagByName", arg0, arg1)
+	ret0, _ := ret[0].(db.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTagByName indicates an expected call of GetTagByName.
+func (mr *MockStoreMockRecorder) GetTagByName(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTagByName", reflect.TypeOf((*MockStore)(nil).GetTagByName), arg0, arg1)
+}
+
+// GetTimelineMarker mocks base method.
+func (m *MockStore) GetTimelineMarker(arg0 context.Context, arg1 string) (db.TimelineMarker, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTimelineMarker", arg0, arg1)
+	ret0, _ := ret[0].(db.TimelineMarker)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTimelineMarker indicates an expected call of GetTimelineMarker.
+func (mr *MockStoreMockRecorder) GetTimelineMarker(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTimelineMarker", reflect.TypeOf((*MockStore)(nil).GetTimelineMarker), arg0, arg1)
+}
+
+// GetUser mocks base method.
+func (m *MockStore) GetUser(arg0 context.Context, arg1 string) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUser", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUser indicates an expected call of GetUser.
+func (mr *MockStoreMockRecorder) GetUser(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUser", reflect.TypeOf((*MockStore)(nil).GetUser), arg0, arg1)
+}
+
+// GetUserByEmail mocks base method.
+func (m *MockStore) GetUserByEmail(arg0 context.Context, arg1 string) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserByEmail", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserByEmail indicates an expected call of GetUserByEmail.
+func (mr *MockStoreMockRecorder) GetUserByEmail(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByEmail", reflect.TypeOf((*MockStore)(nil).GetUserByEmail), arg0, arg1)
+}
+
+// GetWebhookDelivery mocks base method.
+func (m *MockStore) GetWebhookDelivery(arg0 context.Context, arg1 int64) (db.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhookDelivery", arg0, arg1)
+	ret0, _ := ret[0].(db.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhookDelivery indicates an expected call of GetWebhookDelivery.
+func (mr *MockStoreMockRecorder) GetWebhookDelivery(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhookDelivery", reflect.TypeOf((*MockStore)(nil).GetWebhookDelivery), arg0, arg1)
+}
+
+// GetWebhookEndpoint mocks base method.
+func (m *MockStore) GetWebhookEndpoint(arg0 context.Context, arg1 int64) (db.WebhookEndpoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhookEndpoint", arg0, arg1)
+	ret0, _ := ret[0].(db.WebhookEndpoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhookEndpoint indicates an expected call of GetWebhookEndpoint.
+func (mr *MockStoreMockRecorder) GetWebhookEndpoint(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhookEndpoint", reflect.TypeOf((*MockStore)(nil).GetWebhookEndpoint), arg0, arg1)
+}
+
+// ImportCacheTx mocks base method.
+func (m *MockStore) ImportCacheTx(arg0 context.Context, arg1 db.ImportCacheTxParams) (db.ImportItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportCacheTx", arg0, arg1)
+	ret0, _ := ret[0].(db.ImportItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportCacheTx indicates an expected call of ImportCacheTx.
+func (mr *MockStoreMockRecorder) ImportCacheTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportCacheTx", reflect.TypeOf((*MockStore)(nil).ImportCacheTx), arg0, arg1)
+}
+
+// LikeCacheTx mocks base method.
+func (m *MockStore) LikeCacheTx(arg0 context.Context, arg1 db.LikeCacheTxParams) (db.LikeCacheTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LikeCacheTx", arg0, arg1)
+	ret0, _ := ret[0].(db.LikeCacheTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LikeCacheTx indicates an expected call of LikeCacheTx.
+func (mr *MockStoreMockRecorder) LikeCacheTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LikeCacheTx", reflect.TypeOf((*MockStore)(nil).LikeCacheTx), arg0, arg1)
+}
+
+// ListCacheReminders mocks base method.
+func (m *MockStore) ListCacheReminders(arg0 context.Context, arg1 db.ListCacheRemindersParams) ([]db.Reminder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCacheReminders", arg0, arg1)
+	ret0, _ := ret[0].([]db.Reminder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCacheReminders indicates an expected call of ListCacheReminders.
+func (mr *MockStoreMockRecorder) ListCacheReminders(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCacheReminders", reflect.TypeOf((*MockStore)(nil).ListCacheReminders), arg0, arg1)
+}
+
+// ListCacheTags mocks base method.
+func (m *MockStore) ListCacheTags(arg0 context.Context, arg1 int64) ([]db.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCacheTags", arg0, arg1)
+	ret0, _ := ret[0].([]db.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCacheTags indicates an expected call of ListCacheTags.
+func (mr *MockStoreMockRecorder) ListCacheTags(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCacheTags", reflect.TypeOf((*MockStore)(nil).ListCacheTags), arg0, arg1)
+}
+
+// ListCaches mocks base method.
+func (m *MockStore) ListCaches(arg0 context.Context, arg1 db.ListCachesParams) ([]db.Cache, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCaches", arg0, arg1)
+	ret0, _ := ret[0].([]db.Cache)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCaches indicates an expected call of ListCaches.
+func (mr *MockStoreMockRecorder) ListCaches(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCaches", reflect.TypeOf((*MockStore)(nil).ListCaches), arg0, arg1)
+}
+
+// ListCachesByCursor mocks base method.
+func (m *MockStore) ListCachesByCursor(arg0 context.Context, arg1 db.ListCachesByCursorParams) ([]db.Cache, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCachesByCursor", arg0, arg1)
+	ret0, _ := ret[0].([]db.Cache)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCachesByCursor indicates an expected call of ListCachesByCursor.
+func (mr *MockStoreMockRecorder) ListCachesByCursor(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCachesByCursor", reflect.TypeOf((*MockStore)(nil).ListCachesByCursor), arg0, arg1)
+}
+
+// ListCachesForExport mocks base method.
+func (m *MockStore) ListCachesForExport(arg0 context.Context, arg1 db.ListCachesForExportParams) ([]db.ListCachesForExportRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCachesForExport", arg0, arg1)
+	ret0, _ := ret[0].([]db.ListCachesForExportRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCachesForExport indicates an expected call of ListCachesForExport.
+func (mr *MockStoreMockRecorder) ListCachesForExport(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCachesForExport", reflect.TypeOf((*MockStore)(nil).ListCachesForExport), arg0, arg1)
+}
+
+// ListCachesWithoutNormalizedURL mocks base method.
+func (m *MockStore) ListCachesWithoutNormalizedURL(arg0 context.Context, arg1 db.ListCachesWithoutNormalizedURLParams) ([]db.ListCachesWithoutNormalizedURLRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCachesWithoutNormalizedURL", arg0, arg1)
+	ret0, _ := ret[0].([]db.ListCachesWithoutNormalizedURLRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCachesWithoutNormalizedURL indicates an expected call of ListCachesWithoutNormalizedURL.
+func (mr *MockStoreMockRecorder) ListCachesWithoutNormalizedURL(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCachesWithoutNormalizedURL", reflect.TypeOf((*MockStore)(nil).ListCachesWithoutNormalizedURL), arg0, arg1)
+}
+
+// ListCollectionCacheIDsForUpdate mocks base method.
+func (m *MockStore) ListCollectionCacheIDsForUpdate(arg0 context.Context, arg1 int64) ([]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCollectionCacheIDsForUpdate", arg0, arg1)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCollectionCacheIDsForUpdate indicates an expected call of ListCollectionCacheIDsForUpdate.
+func (mr *MockStoreMockRecorder) ListCollectionCacheIDsForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCollectionCacheIDsForUpdate", reflect.TypeOf((*MockStore)(nil).ListCollectionCacheIDsForUpdate), arg0, arg1)
+}
+
+// ListCollectionCaches mocks base method.
+func (m *MockStore) ListCollectionCaches(arg0 context.Context, arg1 db.ListCollectionCachesParams) ([]db.ListCollectionCachesRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCollectionCaches", arg0, arg1)
+	ret0, _ := ret[0].([]db.ListCollectionCachesRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCollectionCaches indicates an expected call of ListCollectionCaches.
+func (mr *MockStoreMockRecorder) ListCollectionCaches(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCollectionCaches", reflect.TypeOf((*MockStore)(nil).ListCollectionCaches), arg0, arg1)
+}
+
+// ListCollectionInvitations mocks base method.
+func (m *MockStore) ListCollectionInvitations(arg0 context.Context, arg1 int64) ([]db.CollectionInvitation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCollectionInvitations", arg0, arg1)
+	ret0, _ := ret[0].([]db.CollectionInvitation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCollectionInvitations indicates an expected call of ListCollectionInvitations.
+func (mr *MockStoreMockRecorder) ListCollectionInvitations(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCollectionInvitations", reflect.TypeOf((*MockStore)(nil).ListCollectionInvitations), arg0, arg1)
+}
+
+// ListCollectionMembers mocks base method.
+func (m *MockStore) ListCollectionMembers(arg0 context.Context, arg1 int64) ([]db.ListCollectionMembersRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCollectionMembers", arg0, arg1)
+	ret0, _ := ret[0].([]db.ListCollectionMembersRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCollectionMembers indicates an expected call of ListCollectionMembers.
+func (mr *MockStoreMockRecorder) ListCollectionMembers(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCollectionMembers", reflect.TypeOf((*MockStore)(nil).ListCollectionMembers), arg0, arg1)
+}
+
+// ListCollections mocks base method.
+func (m *MockStore) ListCollections(arg0 context.Context, arg1 string) ([]db.ListCollectionsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCollections", arg0, arg1)
+	ret0, _ := ret[0].([]db.ListCollectionsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCollections indicates an expected call of ListCollections.
+func (mr *MockStoreMockRecorder) ListCollections(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCollections", reflect.TypeOf((*MockStore)(nil).ListCollections), arg0, arg1)
+}
+
+// ListCommentReplies mocks base method.
+func (m *MockStore) ListCommentReplies(arg0 context.Context, arg1 db.ListCommentRepliesParams) ([]db.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCommentReplies", arg0, arg1)
+	ret0, _ := ret[0].([]db.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCommentReplies indicates an expected call of ListCommentReplies.
+func (mr *MockStoreMockRecorder) ListCommentReplies(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCommentReplies", reflect.TypeOf((*MockStore)(nil).ListCommentReplies), arg0, arg1)
+}
+
+// ListComments mocks base method.
+func (m *MockStore) ListComments(arg0 context.Context, arg1 db.ListCommentsParams) ([]db.ListCommentsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListComments", arg0, arg1)
+	ret0, _ := ret[0].([]db.ListCommentsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListComments indicates an expected call of ListComments.
+func (mr *MockStoreMockRecorder) ListComments(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListComments", reflect.TypeOf((*MockStore)(nil).ListComments), arg0, arg1)
+}
+
+// ListDigestCaches mocks base method.
+func (m *MockStore) ListDigestCaches(arg0 context.Context, arg1 db.ListDigestCachesParams) ([]db.ListDigestCachesRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDigestCaches", arg0, arg1)
+	ret0, _ := ret[0].([]db.ListDigestCachesRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDigestCaches indicates an expected call of ListDigestCaches.
+func (mr *MockStoreMockRecorder) ListDigestCaches(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDigestCaches", reflect.TypeOf((*MockStore)(nil).ListDigestCaches), arg0, arg1)
+}
+
+// ListFeedCaches mocks base method.
+func (m *MockStore) ListFeedCaches(arg0 context.Context, arg1 db.ListFeedCachesParams) ([]db.ListFeedCachesRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListFeedCaches", arg0, arg1)
+	ret0, _ := ret[0].([]db.ListFeedCachesRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListFeedCaches indicates an expected call of ListFeedCaches.
+func (mr *MockStoreMockRecorder) ListFeedCaches(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListFeedCaches", reflect.TypeOf((*MockStore)(nil).ListFeedCaches), arg0, arg1)
+}
+
+// ListImportItems mocks base method.
+func (m *MockStore) ListImportItems(arg0 context.Context, arg1 db.ListImportItemsParams) ([]db.ImportItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListImportItems", arg0, arg1)
+	ret0, _ := ret[0].([]db.ImportItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListImportItems indicates an expected call of ListImportItems.
+func (mr *MockStoreMockRecorder) ListImportItems(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListImportItems", reflect.TypeOf((*MockStore)(nil).ListImportItems), arg0, arg1)
+}
+
+// ListImportJobs mocks base method.
+func (m *MockStore) ListImportJobs(arg0 context.Context, arg1 db.ListImportJobsParams) ([]db.ImportJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListImportJobs", arg0, arg1)
+	ret0, _ := ret[0].([]db.ImportJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListImportJobs indicates an expected call of ListImportJobs.
+func (mr *MockStoreMockRecorder) ListImportJobs(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListImportJobs", reflect.TypeOf((*MockStore)(nil).ListImportJobs), arg0, arg1)
+}
+
+// ListInboundHooks mocks base method.
+func (m *MockStore) ListInboundHooks(arg0 context.Context, arg1 string) ([]db.InboundHook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListInboundHooks", arg0, arg1)
+	ret0, _ := ret[0].([]db.InboundHook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListInboundHooks indicates an expected call of ListInboundHooks.
+func (mr *MockStoreMockRecorder) ListInboundHooks(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListInboundHooks", reflect.TypeOf((*MockStore)(nil).ListInboundHooks), arg0, arg1)
+}
+
+// ListLikedCacheIDs mocks base method.
+func (m *MockStore) ListLikedCacheIDs(arg0 context.Context, arg1 db.ListLikedCacheIDsParams) ([]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListLikedCacheIDs", arg0, arg1)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListLikedCacheIDs indicates an expected call of ListLikedCacheIDs.
+func (mr *MockStoreMockRecorder) ListLikedCacheIDs(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLikedCacheIDs", reflect.TypeOf((*MockStore)(nil).ListLikedCacheIDs), arg0, arg1)
+}
+
+// ListMostLikedPublicCaches mocks base method.
+func (m *MockStore) ListMostLikedPublicCaches(arg0 context.Context, arg1 db.ListMostLikedPublicCachesParams) ([]db.ListMostLikedPublicCachesRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListMostLikedPublicCaches", arg0, arg1)
+	ret0, _ := ret[0].([]db.ListMostLikedPublicCachesRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListMostLikedPublicCaches indicates an expected call of ListMostLikedPublicCaches.
+func (mr *MockStoreMockRecorder) ListMostLikedPublicCaches(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMostLikedPublicCaches", reflect.TypeOf((*MockStore)(nil).ListMostLikedPublicCaches), arg0, arg1)
+}
+
+// ListPendingImportItems mocks base method.
+func (m *MockStore) ListPendingImportItems(arg0 context.Context, arg1 db.ListPendingImportItemsParams) ([]db.ImportItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPendingImportItems", arg0, arg1)
+	ret0, _ := ret[0].([]db.ImportItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPendingImportItems indicates an expected call of ListPendingImportItems.
+func (mr *MockStoreMockRecorder) ListPendingImportItems(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPendingImportItems", reflect.TypeOf((*MockStore)(nil).ListPendingImportItems), arg0, arg1)
+}
+
+// ListPendingInvitationsForUser mocks base method.
+func (m *MockStore) ListPendingInvitationsForUser(arg0 context.Context, arg1 db.ListPendingInvitationsForUserParams) ([]db.ListPendingInvitationsForUserRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPendingInvitationsForUser", arg0, arg1)
+	ret0, _ := ret[0].([]db.ListPendingInvitationsForUserRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPendingInvitationsForUser indicates an expected call of ListPendingInvitationsForUser.
+func (mr *MockStoreMockRecorder) ListPendingInvitationsForUser(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPendingInvitationsForUser", reflect.TypeOf((*MockStore)(nil).ListPendingInvitationsForUser), arg0, arg1)
+}
+
+// ListPersonalAccessTokens mocks base method.
+func (m *MockStore) ListPersonalAccessTokens(arg0 context.Context, arg1 string) ([]db.PersonalAccessToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPersonalAccessTokens", arg0, arg1)
+	ret0, _ := ret[0].([]db.PersonalAccessToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPersonalAccessTokens indicates an expected call of ListPersonalAccessTokens.
+func (mr *MockStoreMockRecorder) ListPersonalAccessTokens(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPersonalAccessTokens", reflect.TypeOf((*MockStore)(nil).ListPersonalAccessTokens), arg0, arg1)
+}
+
+// ListPublicCaches mocks base method.
+func (m *MockStore) ListPublicCaches(arg0 context.Context, arg1 db.ListPublicCachesParams) ([]db.Cache, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPublicCaches", arg0, arg1)
+	ret0, _ := ret[0].([]db.Cache)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPublicCaches indicates an expected call of ListPublicCaches.
+func (mr *MockStoreMockRecorder) ListPublicCaches(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPublicCaches", reflect.TypeOf((*MockStore)(nil).ListPublicCaches), arg0, arg1)
+}
+
+// ListPublicCachesByCursor mocks base method.
+func (m *MockStore) ListPublicCachesByCursor(arg0 context.Context, arg1 db.ListPublicCachesByCursorParams) ([]db.Cache, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPublicCachesByCursor", arg0, arg1)
+	ret0, _ := ret[0].([]db.Cache)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPublicCachesByCursor indicates an expected call of ListPublicCachesByCursor.
+func (mr *MockStoreMockRecorder) ListPublicCachesByCursor(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPublicCachesByCursor", reflect.TypeOf((*MockStore)(nil).ListPublicCachesByCursor), arg0, arg1)
+}
+
+// ListPublicCachesByTags mocks base method.
+func (m *MockStore) ListPublicCachesByTags(arg0 context.Context, arg1 db.ListPublicCachesByTagsParams) ([]db.Cache, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPublicCachesByTags", arg0, arg1)
+	ret0, _ := ret[0].([]db.Cache)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPublicCachesByTags indicates an expected call of ListPublicCachesByTags.
+func (mr *MockStoreMockRecorder) ListPublicCachesByTags(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPublicCachesByTags", reflect.TypeOf((*MockStore)(nil).ListPublicCachesByTags), arg0, arg1)
+}
+
+// ListPublicCachesByTagsCursor mocks base method.
+func (m *MockStore) ListPublicCachesByTagsCursor(arg0 context.Context, arg1 db.ListPublicCachesByTagsCursorParams) ([]db.Cache, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPublicCachesByTagsCursor", arg0, arg1)
+	ret0, _ := ret[0].([]db.Cache)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPublicCachesByTagsCursor indicates an expected call of ListPublicCachesByTagsCursor.
+func (mr *MockStoreMockRecorder) ListPublicCachesByTagsCursor(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPublicCachesByTagsCursor", reflect.TypeOf((*MockStore)(nil).ListPublicCachesByTagsCursor), arg0, arg1)
+}
+
+// ListReminders mocks base method.
+func (m *MockStore) ListReminders(arg0 context.Context, arg1 string) ([]db.Reminder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListReminders", arg0, arg1)
+	ret0, _ := ret[0].([]db.Reminder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListReminders indicates an expected call of ListReminders.
+func (mr *MockStoreMockRecorder) ListReminders(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListReminders", reflect.TypeOf((*MockStore)(nil).ListReminders), arg0, arg1)
+}
+
+// ListTaggedCaches mocks base method.
+func (m *MockStore) ListTaggedCaches(arg0 context.Context, arg1 int32) ([]db.ListTaggedCachesRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTaggedCaches", arg0, arg1)
+	ret0, _ := ret[0].([]db.ListTaggedCachesRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTaggedCaches indicates an expected call of ListTaggedCaches.
+func (mr *MockStoreMockRecorder) ListTaggedCaches(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTaggedCaches", reflect.TypeOf((*MockStore)(nil).ListTaggedCaches), arg0, arg1)
+}
+
+// ListTags mocks base method.
+func (m *MockStore) ListTags(arg0 context.Context) ([]db.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTags", arg0)
+	ret0, _ := ret[0].([]db.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTags indicates an expected call of ListTags.
+func (mr *MockStoreMockRecorder) ListTags(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTags", reflect.TypeOf((*MockStore)(nil).ListTags), arg0)
+}
+
+// ListTimelineCaches mocks base method.
+func (m *MockStore) ListTimelineCaches(arg0 context.Context, arg1 db.ListTimelineCachesParams) ([]db.Cache, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTimelineCaches", arg0, arg1)
+	ret0, _ := ret[0].([]db.Cache)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTimelineCaches indicates an expected call of ListTimelineCaches.
+func (mr *MockStoreMockRecorder) ListTimelineCaches(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTimelineCaches", reflect.TypeOf((*MockStore)(nil).ListTimelineCaches), arg0, arg1)
+}
+
+// ListUserSubscriptions mocks base method.
+func (m *MockStore) ListUserSubscriptions(arg0 context.Context, arg1 string) ([]db.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUserSubscriptions", arg0, arg1)
+	ret0, _ := ret[0].([]db.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUserSubscriptions indicates an expected call of ListUserSubscriptions.
+func (mr *MockStoreMockRecorder) ListUserSubscriptions(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUserSubscriptions", reflect.TypeOf((*MockStore)(nil).ListUserSubscriptions), arg0, arg1)
+}
+
+// ListWebhookDeliveries mocks base method.
+func (m *MockStore) ListWebhookDeliveries(arg0 context.Context, arg1 db.ListWebhookDeliveriesParams) ([]db.ListWebhookDeliveriesRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWebhookDeliveries", arg0, arg1)
+	ret0, _ := ret[0].([]db.ListWebhookDeliveriesRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWebhookDeliveries indicates an expected call of ListWebhookDeliveries.
+func (mr *MockStoreMockRecorder) ListWebhookDeliveries(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWebhookDeliveries", reflect.TypeOf((*MockStore)(nil).ListWebhookDeliveries), arg0, arg1)
+}
+
+// ListWebhookEndpoints mocks base method.
+func (m *MockStore) ListWebhookEndpoints(arg0 context.Context, arg1 string) ([]db.WebhookEndpoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWebhookEndpoints", arg0, arg1)
+	ret0, _ := ret[0].([]db.WebhookEndpoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWebhookEndpoints indicates an expected call of ListWebhookEndpoints.
+func (mr *MockStoreMockRecorder) ListWebhookEndpoints(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWebhookEndpoints", reflect.TypeOf((*MockStore)(nil).ListWebhookEndpoints), arg0, arg1)
+}
+
+// LockCollection mocks base method.
+func (m *MockStore) LockCollection(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockCollection", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LockCollection indicates an expected call of LockCollection.
+func (mr *MockStoreMockRecorder) LockCollection(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockCollection", reflect.TypeOf((*MockStore)(nil).LockCollection), arg0, arg1)
+}
+
+// MarkTimelineSeen mocks base method.
+func (m *MockStore) MarkTimelineSeen(arg0 context.Context, arg1 db.MarkTimelineSeenParams) (db.TimelineMarker, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkTimelineSeen", arg0, arg1)
+	ret0, _ := ret[0].(db.TimelineMarker)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkTimelineSeen indicates an expected call of MarkTimelineSeen.
+func (mr *MockStoreMockRecorder) MarkTimelineSeen(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkTimelineSeen", reflect.TypeOf((*MockStore)(nil).MarkTimelineSeen), arg0, arg1)
+}
+
+// RedeliverWebhookDelivery mocks base method.
+func (m *MockStore) RedeliverWebhookDelivery(arg0 context.Context, arg1 int64) (db.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RedeliverWebhookDelivery", arg0, arg1)
+	ret0, _ := ret[0].(db.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RedeliverWebhookDelivery indicates an expected call of RedeliverWebhookDelivery.
+func (mr *MockStoreMockRecorder) RedeliverWebhookDelivery(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RedeliverWebhookDelivery", reflect.TypeOf((*MockStore)(nil).RedeliverWebhookDelivery), arg0, arg1)
+}
+
+// RemoveCacheFromCollection mocks base method.
+func (m *MockStore) RemoveCacheFromCollection(arg0 context.Context, arg1 db.RemoveCacheFromCollectionParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveCacheFromCollection", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RemoveCacheFromCollection indicates an expected call of RemoveCacheFromCollection.
+func (mr *MockStoreMockRecorder) RemoveCacheFromCollection(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveCacheFromCollection", reflect.TypeOf((*MockStore)(nil).RemoveCacheFromCollection), arg0, arg1)
+}
+
+// RemoveCollectionMemberTx mocks base method.
+func (m *MockStore) RemoveCollectionMemberTx(arg0 context.Context, arg1 db.DeleteCollectionMemberParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveCollectionMemberTx", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveCollectionMemberTx indicates an expected call of RemoveCollectionMemberTx.
+func (mr *MockStoreMockRecorder) RemoveCollectionMemberTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveCollectionMemberTx", reflect.TypeOf((*MockStore)(nil).RemoveCollectionMemberTx), arg0, arg1)
+}
+
+// ReorderCollectionTx mocks base method.
+func (m *MockStore) ReorderCollectionTx(arg0 context.Context, arg1 db.ReorderCollectionTxParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReorderCollectionTx", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReorderCollectionTx indicates an expected call of ReorderCollectionTx.
+func (mr *MockStoreMockRecorder) ReorderCollectionTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReorderCollectionTx", reflect.TypeOf((*MockStore)(nil).ReorderCollectionTx), arg0, arg1)
+}
+
+// RequestCacheSnapshot mocks base method.
+func (m *MockStore) RequestCacheSnapshot(arg0 context.Context, arg1 db.RequestCacheSnapshotParams) (db.CacheSnapshot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequestCacheSnapshot", arg0, arg1)
+	ret0, _ := ret[0].(db.CacheSnapshot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RequestCacheSnapshot indicates an expected call of RequestCacheSnapshot.
+func (mr *MockStoreMockRecorder) RequestCacheSnapshot(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequestCacheSnapshot", reflect.TypeOf((*MockStore)(nil).RequestCacheSnapshot), arg0, arg1)
+}
+
+// RespondToCollectionInvitation mocks base method.
+func (m *MockStore) RespondToCollectionInvitation(arg0 context.Context, arg1 db.RespondToCollectionInvitationParams) (db.CollectionInvitation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RespondToCollectionInvitation", arg0, arg1)
+	ret0, _ := ret[0].(db.CollectionInvitation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RespondToCollectionInvitation indicates an expected call of RespondToCollectionInvitation.
+func (mr *MockStoreMockRecorder) RespondToCollectionInvitation(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RespondToCollectionInvitation", reflect.TypeOf((*MockStore)(nil).RespondToCollectionInvitation), arg0, arg1)
+}
+
+// RetryCacheSnapshot mocks base method.
+func (m *MockStore) RetryCacheSnapshot(arg0 context.Context, arg1 db.RetryCacheSnapshotParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetryCacheSnapshot", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RetryCacheSnapshot indicates an expected call of RetryCacheSnapshot.
+func (mr *MockStoreMockRecorder) RetryCacheSnapshot(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetryCacheSnapshot", reflect.TypeOf((*MockStore)(nil).RetryCacheSnapshot), arg0, arg1)
+}
+
+// RetryDigest mocks base method.
+func (m *MockStore) RetryDigest(arg0 context.Context, arg1 db.RetryDigestParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetryDigest", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RetryDigest indicates an expected call of RetryDigest.
+func (mr *MockStoreMockRecorder) RetryDigest(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetryDigest", reflect.TypeOf((*MockStore)(nil).RetryDigest), arg0, arg1)
+}
+
+// RetryImportJob mocks base method.
+func (m *MockStore) RetryImportJob(arg0 context.Context, arg1 db.RetryImportJobParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetryImportJob", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RetryImportJob indicates an expected call of RetryImportJob.
+func (mr *MockStoreMockRecorder) RetryImportJob(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetryImportJob", reflect.TypeOf((*MockStore)(nil).RetryImportJob), arg0, arg1)
+}
+
+// RetryLinkPreview mocks base method.
+func (m *MockStore) RetryLinkPreview(arg0 context.Context, arg1 db.RetryLinkPreviewParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetryLinkPreview", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RetryLinkPreview indicates an expected call of RetryLinkPreview.
+func (mr *MockStoreMockRecorder) RetryLinkPreview(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetryLinkPreview", reflect.TypeOf((*MockStore)(nil).RetryLinkPreview), arg0, arg1)
+}
+
+// RetryReminder mocks base method.
+func (m *MockStore) RetryReminder(arg0 context.Context, arg1 db.RetryReminderParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetryReminder", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RetryReminder indicates an expected call of RetryReminder.
+func (mr *MockStoreMockRecorder) RetryReminder(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetryReminder", reflect.TypeOf((*MockStore)(nil).RetryReminder), arg0, arg1)
+}
+
+// RetryWebhookDelivery mocks base method.
+func (m *MockStore) RetryWebhookDelivery(arg0 context.Context, arg1 db.RetryWebhookDeliveryParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetryWebhookDelivery", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RetryWebhookDelivery indicates an expected call of RetryWebhookDelivery.
+func (mr *MockStoreMockRecorder) RetryWebhookDelivery(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetryWebhookDelivery", reflect.TypeOf((*MockStore)(nil).RetryWebhookDelivery), arg0, arg1)
+}
+
+// RevokeCollectionInvitation mocks base method.
+func (m *MockStore) RevokeCollectionInvitation(arg0 context.Context, arg1 db.RevokeCollectionInvitationParams) (db.CollectionInvitation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeCollectionInvitation", arg0, arg1)
+	ret0, _ := ret[0].(db.CollectionInvitation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevokeCollectionInvitation indicates an expected call of RevokeCollectionInvitation.
+func (mr *MockStoreMockRecorder) RevokeCollectionInvitation(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeCollectionInvitation", reflect.TypeOf((*MockStore)(nil).RevokeCollectionInvitation), arg0, arg1)
+}
+
+// RevokePersonalAccessToken mocks base method.
+func (m *MockStore) RevokePersonalAccessToken(arg0 context.Context, arg1 db.RevokePersonalAccessTokenParams) (db.PersonalAccessToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokePersonalAccessToken", arg0, arg1)
+	ret0, _ := ret[0].(db.PersonalAccessToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevokePersonalAccessToken indicates an expected call of RevokePersonalAccessToken.
+func (mr *MockStoreMockRecorder) RevokePersonalAccessToken(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokePersonalAccessToken", reflect.TypeOf((*MockStore)(nil).RevokePersonalAccessToken), arg0, arg1)
+}
+
+// SaveInboundCacheTx mocks base method.
+func (m *MockStore) SaveInboundCacheTx(arg0 context.Context, arg1 db.SaveInboundCacheTxParams) (db.SaveInboundCacheTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveInboundCacheTx", arg0, arg1)
+	ret0, _ := ret[0].(db.SaveInboundCacheTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SaveInboundCacheTx indicates an expected call of SaveInboundCacheTx.
+func (mr *MockStoreMockRecorder) SaveInboundCacheTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveInboundCacheTx", reflect.TypeOf((*MockStore)(nil).SaveInboundCacheTx), arg0, arg1)
+}
+
+// SearchCaches mocks base method.
+func (m *MockStore) SearchCaches(arg0 context.Context, arg1 db.SearchCachesParams) ([]db.SearchCachesRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchCaches", arg0, arg1)
+	ret0, _ := ret[0].([]db.SearchCachesRow)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CreateCache indicates an expected call of CreateCache.
-func (mr *MockStoreMockRecorder) CreateCache(arg0, arg1 interface{}) *gomock.Call {
+// SearchCaches indicates an expected call of SearchCaches.
+func (mr *MockStoreMockRecorder) SearchCaches(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCache", reflect.TypeOf((*MockStore)(nil).CreateCache), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchCaches", reflect.TypeOf((*MockStore)(nil).SearchCaches), arg0, arg1)
 }
 
-// CreateUser mocks base method.
-func (m *MockStore) CreateUser(arg0 context.Context, arg1 db.CreateUserParams) (db.User, error) {
+// SearchCachesByCursor mocks base method.
+func (m *MockStore) SearchCachesByCursor(arg0 context.Context, arg1 db.SearchCachesByCursorParams) ([]db.SearchCachesByCursorRow, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateUser", arg0, arg1)
-	ret0, _ := ret[0].(db.User)
+	ret := m.ctrl.Call(m, "SearchCachesByCursor", arg0, arg1)
+	ret0, _ := ret[0].([]db.SearchCachesByCursorRow)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CreateUser indicates an expected call of CreateUser.
-func (mr *MockStoreMockRecorder) CreateUser(arg0, arg1 interface{}) *gomock.Call {
+// SearchCachesByCursor indicates an expected call of SearchCachesByCursor.
+func (mr *MockStoreMockRecorder) SearchCachesByCursor(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockStore)(nil).CreateUser), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchCachesByCursor", reflect.TypeOf((*MockStore)(nil).SearchCachesByCursor), arg0, arg1)
 }
 
-// DeleteCache mocks base method.
-func (m *MockStore) DeleteCache(arg0 context.Context, arg1 int64) error {
+// SearchTags mocks base method.
+func (m *MockStore) SearchTags(arg0 context.Context, arg1 db.SearchTagsParams) ([]db.SearchTagsRow, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteCache", arg0, arg1)
+	ret := m.ctrl.Call(m, "SearchTags", arg0, arg1)
+	ret0, _ := ret[0].([]db.SearchTagsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchTags indicates an expected call of SearchTags.
+func (mr *MockStoreMockRecorder) SearchTags(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchTags", reflect.TypeOf((*MockStore)(nil).SearchTags), arg0, arg1)
+}
+
+// SetCacheReadingState mocks base method.
+func (m *MockStore) SetCacheReadingState(arg0 context.Context, arg1 db.SetCacheReadingStateParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetCacheReadingState", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// DeleteCache indicates an expected call of DeleteCache.
-func (mr *MockStoreMockRecorder) DeleteCache(arg0, arg1 interface{}) *gomock.Call {
+// SetCacheReadingState indicates an expected call of SetCacheReadingState.
+func (mr *MockStoreMockRecorder) SetCacheReadingState(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCache", reflect.TypeOf((*MockStore)(nil).DeleteCache), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetCacheReadingState", reflect.TypeOf((*MockStore)(nil).SetCacheReadingState), arg0, arg1)
 }
 
-// GetCache mocks base method.
-func (m *MockStore) GetCache(arg0 context.Context, arg1 int64) (db.Cache, error) {
+// SetCacheSavedAt mocks base method.
+func (m *MockStore) SetCacheSavedAt(arg0 context.Context, arg1 db.SetCacheSavedAtParams) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetCache", arg0, arg1)
-	ret0, _ := ret[0].(db.Cache)
+	ret := m.ctrl.Call(m, "SetCacheSavedAt", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetCacheSavedAt indicates an expected call of SetCacheSavedAt.
+func (mr *MockStoreMockRecorder) SetCacheSavedAt(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetCacheSavedAt", reflect.TypeOf((*MockStore)(nil).SetCacheSavedAt), arg0, arg1)
+}
+
+// SetCacheTagsTx mocks base method.
+func (m *MockStore) SetCacheTagsTx(arg0 context.Context, arg1 db.SetCacheTagsTxParams) (db.SetCacheTagsTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetCacheTagsTx", arg0, arg1)
+	ret0, _ := ret[0].(db.SetCacheTagsTxResult)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetCache indicates an expected call of GetCache.
-func (mr *MockStoreMockRecorder) GetCache(arg0, arg1 interface{}) *gomock.Call {
+// SetCacheTagsTx indicates an expected call of SetCacheTagsTx.
+func (mr *MockStoreMockRecorder) SetCacheTagsTx(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCache", reflect.TypeOf((*MockStore)(nil).GetCache), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetCacheTagsTx", reflect.TypeOf((*MockStore)(nil).SetCacheTagsTx), arg0, arg1)
 }
 
-// GetUser mocks base method.
-func (m *MockStore) GetUser(arg0 context.Context, arg1 string) (db.User, error) {
+// SetCollectionCachePositions mocks base method.
+func (m *MockStore) SetCollectionCachePositions(arg0 context.Context, arg1 db.SetCollectionCachePositionsParams) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUser", arg0, arg1)
-	ret0, _ := ret[0].(db.User)
+	ret := m.ctrl.Call(m, "SetCollectionCachePositions", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetCollectionCachePositions indicates an expected call of SetCollectionCachePositions.
+func (mr *MockStoreMockRecorder) SetCollectionCachePositions(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetCollectionCachePositions", reflect.TypeOf((*MockStore)(nil).SetCollectionCachePositions), arg0, arg1)
+}
+
+// SetCommentHidden mocks base method.
+func (m *MockStore) SetCommentHidden(arg0 context.Context, arg1 db.SetCommentHiddenParams) (db.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetCommentHidden", arg0, arg1)
+	ret0, _ := ret[0].(db.Comment)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetUser indicates an expected call of GetUser.
-func (mr *MockStoreMockRecorder) GetUser(arg0, arg1 interface{}) *gomock.Call {
+// SetCommentHidden indicates an expected call of SetCommentHidden.
+func (mr *MockStoreMockRecorder) SetCommentHidden(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUser", reflect.TypeOf((*MockStore)(nil).GetUser), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetCommentHidden", reflect.TypeOf((*MockStore)(nil).SetCommentHidden), arg0, arg1)
 }
 
-// GetUserByEmail mocks base method.
-func (m *MockStore) GetUserByEmail(arg0 context.Context, arg1 string) (db.User, error) {
+// SkipDigest mocks base method.
+func (m *MockStore) SkipDigest(arg0 context.Context, arg1 db.SkipDigestParams) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUserByEmail", arg0, arg1)
-	ret0, _ := ret[0].(db.User)
+	ret := m.ctrl.Call(m, "SkipDigest", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SkipDigest indicates an expected call of SkipDigest.
+func (mr *MockStoreMockRecorder) SkipDigest(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SkipDigest", reflect.TypeOf((*MockStore)(nil).SkipDigest), arg0, arg1)
+}
+
+// SkipReminderFire mocks base method.
+func (m *MockStore) SkipReminderFire(arg0 context.Context, arg1 db.SkipReminderFireParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SkipReminderFire", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SkipReminderFire indicates an expected call of SkipReminderFire.
+func (mr *MockStoreMockRecorder) SkipReminderFire(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SkipReminderFire", reflect.TypeOf((*MockStore)(nil).SkipReminderFire), arg0, arg1)
+}
+
+// SoftDeleteComment mocks base method.
+func (m *MockStore) SoftDeleteComment(arg0 context.Context, arg1 int64) (db.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SoftDeleteComment", arg0, arg1)
+	ret0, _ := ret[0].(db.Comment)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetUserByEmail indicates an expected call of GetUserByEmail.
-func (mr *MockStoreMockRecorder) GetUserByEmail(arg0, arg1 interface{}) *gomock.Call {
+// SoftDeleteComment indicates an expected call of SoftDeleteComment.
+func (mr *MockStoreMockRecorder) SoftDeleteComment(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByEmail", reflect.TypeOf((*MockStore)(nil).GetUserByEmail), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SoftDeleteComment", reflect.TypeOf((*MockStore)(nil).SoftDeleteComment), arg0, arg1)
 }
 
-// ListCaches mocks base method.
-func (m *MockStore) ListCaches(arg0 context.Context, arg1 db.ListCachesParams) ([]db.Cache, error) {
+// SubscribeTag mocks base method.
+func (m *MockStore) SubscribeTag(arg0 context.Context, arg1 db.SubscribeTagParams) (db.UserTag, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListCaches", arg0, arg1)
-	ret0, _ := ret[0].([]db.Cache)
+	ret := m.ctrl.Call(m, "SubscribeTag", arg0, arg1)
+	ret0, _ := ret[0].(db.UserTag)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// ListCaches indicates an expected call of ListCaches.
-func (mr *MockStoreMockRecorder) ListCaches(arg0, arg1 interface{}) *gomock.Call {
+// SubscribeTag indicates an expected call of SubscribeTag.
+func (mr *MockStoreMockRecorder) SubscribeTag(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCaches", reflect.TypeOf((*MockStore)(nil).ListCaches), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscribeTag", reflect.TypeOf((*MockStore)(nil).SubscribeTag), arg0, arg1)
+}
+
+// SubscribeTagTx mocks base method.
+func (m *MockStore) SubscribeTagTx(arg0 context.Context, arg1 db.SubscribeTagParams) (db.UserTag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubscribeTagTx", arg0, arg1)
+	ret0, _ := ret[0].(db.UserTag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SubscribeTagTx indicates an expected call of SubscribeTagTx.
+func (mr *MockStoreMockRecorder) SubscribeTagTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscribeTagTx", reflect.TypeOf((*MockStore)(nil).SubscribeTagTx), arg0, arg1)
+}
+
+// TouchInboundHook mocks base method.
+func (m *MockStore) TouchInboundHook(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TouchInboundHook", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TouchInboundHook indicates an expected call of TouchInboundHook.
+func (mr *MockStoreMockRecorder) TouchInboundHook(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TouchInboundHook", reflect.TypeOf((*MockStore)(nil).TouchInboundHook), arg0, arg1)
+}
+
+// UnlikeCacheTx mocks base method.
+func (m *MockStore) UnlikeCacheTx(arg0 context.Context, arg1 db.LikeCacheTxParams) (db.LikeCacheTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnlikeCacheTx", arg0, arg1)
+	ret0, _ := ret[0].(db.LikeCacheTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UnlikeCacheTx indicates an expected call of UnlikeCacheTx.
+func (mr *MockStoreMockRecorder) UnlikeCacheTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnlikeCacheTx", reflect.TypeOf((*MockStore)(nil).UnlikeCacheTx), arg0, arg1)
+}
+
+// UnsubscribeDigest mocks base method.
+func (m *MockStore) UnsubscribeDigest(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnsubscribeDigest", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnsubscribeDigest indicates an expected call of UnsubscribeDigest.
+func (mr *MockStoreMockRecorder) UnsubscribeDigest(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnsubscribeDigest", reflect.TypeOf((*MockStore)(nil).UnsubscribeDigest), arg0, arg1)
+}
+
+// UnsubscribeTag mocks base method.
+func (m *MockStore) UnsubscribeTag(arg0 context.Context, arg1 db.UnsubscribeTagParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnsubscribeTag", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnsubscribeTag indicates an expected call of UnsubscribeTag.
+func (mr *MockStoreMockRecorder) UnsubscribeTag(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnsubscribeTag", reflect.TypeOf((*MockStore)(nil).UnsubscribeTag), arg0, arg1)
 }
 
 // UpdateCache mocks base method.
@@ -154,6 +2259,154 @@ func (mr *MockStoreMockRecorder) UpdateCache(arg0, arg1 interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCache", reflect.TypeOf((*MockStore)(nil).UpdateCache), arg0, arg1)
 }
 
+// UpdateCacheNormalizedURL mocks base method.
+func (m *MockStore) UpdateCacheNormalizedURL(arg0 context.Context, arg1 db.UpdateCacheNormalizedURLParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCacheNormalizedURL", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateCacheNormalizedURL indicates an expected call of UpdateCacheNormalizedURL.
+func (mr *MockStoreMockRecorder) UpdateCacheNormalizedURL(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCacheNormalizedURL", reflect.TypeOf((*MockStore)(nil).UpdateCacheNormalizedURL), arg0, arg1)
+}
+
+// UpdateCacheProgress mocks base method.
+func (m *MockStore) UpdateCacheProgress(arg0 context.Context, arg1 db.UpdateCacheProgressParams) (db.Cache, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCacheProgress", arg0, arg1)
+	ret0, _ := ret[0].(db.Cache)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateCacheProgress indicates an expected call of UpdateCacheProgress.
+func (mr *MockStoreMockRecorder) UpdateCacheProgress(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCacheProgress", reflect.TypeOf((*MockStore)(nil).UpdateCacheProgress), arg0, arg1)
+}
+
+// UpdateCacheStatus mocks base method.
+func (m *MockStore) UpdateCacheStatus(arg0 context.Context, arg1 db.UpdateCacheStatusParams) (db.Cache, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCacheStatus", arg0, arg1)
+	ret0, _ := ret[0].(db.Cache)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateCacheStatus indicates an expected call of UpdateCacheStatus.
+func (mr *MockStoreMockRecorder) UpdateCacheStatus(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCacheStatus", reflect.TypeOf((*MockStore)(nil).UpdateCacheStatus), arg0, arg1)
+}
+
+// UpdateCacheTx mocks base method.
+func (m *MockStore) UpdateCacheTx(arg0 context.Context, arg1 db.UpdateCacheParams) (db.Cache, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCacheTx", arg0, arg1)
+	ret0, _ := ret[0].(db.Cache)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateCacheTx indicates an expected call of UpdateCacheTx.
+func (mr *MockStoreMockRecorder) UpdateCacheTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCacheTx", reflect.TypeOf((*MockStore)(nil).UpdateCacheTx), arg0, arg1)
+}
+
+// UpdateCollection mocks base method.
+func (m *MockStore) UpdateCollection(arg0 context.Context, arg1 db.UpdateCollectionParams) (db.Collection, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCollection", arg0, arg1)
+	ret0, _ := ret[0].(db.Collection)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateCollection indicates an expected call of UpdateCollection.
+func (mr *MockStoreMockRecorder) UpdateCollection(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCollection", reflect.TypeOf((*MockStore)(nil).UpdateCollection), arg0, arg1)
+}
+
+// UpdateCollectionMemberRole mocks base method.
+func (m *MockStore) UpdateCollectionMemberRole(arg0 context.Context, arg1 db.UpdateCollectionMemberRoleParams) (db.CollectionMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCollectionMemberRole", arg0, arg1)
+	ret0, _ := ret[0].(db.CollectionMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateCollectionMemberRole indicates an expected call of UpdateCollectionMemberRole.
+func (mr *MockStoreMockRecorder) UpdateCollectionMemberRole(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCollectionMemberRole", reflect.TypeOf((*MockStore)(nil).UpdateCollectionMemberRole), arg0, arg1)
+}
+
+// UpdateCollectionMemberTx mocks base method.
+func (m *MockStore) UpdateCollectionMemberTx(arg0 context.Context, arg1 db.UpdateCollectionMemberRoleParams) (db.CollectionMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCollectionMemberTx", arg0, arg1)
+	ret0, _ := ret[0].(db.CollectionMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateCollectionMemberTx indicates an expected call of UpdateCollectionMemberTx.
+func (mr *MockStoreMockRecorder) UpdateCollectionMemberTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCollectionMemberTx", reflect.TypeOf((*MockStore)(nil).UpdateCollectionMemberTx), arg0, arg1)
+}
+
+// UpdateCommentBody mocks base method.
+func (m *MockStore) UpdateCommentBody(arg0 context.Context, arg1 db.UpdateCommentBodyParams) (db.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCommentBody", arg0, arg1)
+	ret0, _ := ret[0].(db.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateCommentBody indicates an expected call of UpdateCommentBody.
+func (mr *MockStoreMockRecorder) UpdateCommentBody(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCommentBody", reflect.TypeOf((*MockStore)(nil).UpdateCommentBody), arg0, arg1)
+}
+
+// UpdatePersonalAccessTokenLastUsed mocks base method.
+func (m *MockStore) UpdatePersonalAccessTokenLastUsed(arg0 context.Context, arg1 db.UpdatePersonalAccessTokenLastUsedParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePersonalAccessTokenLastUsed", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePersonalAccessTokenLastUsed indicates an expected call of UpdatePersonalAccessTokenLastUsed.
+func (mr *MockStoreMockRecorder) UpdatePersonalAccessTokenLastUsed(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePersonalAccessTokenLastUsed", reflect.TypeOf((*MockStore)(nil).UpdatePersonalAccessTokenLastUsed), arg0, arg1)
+}
+
+// UpdatePersonalAccessTokenName mocks base method.
+func (m *MockStore) UpdatePersonalAccessTokenName(arg0 context.Context, arg1 db.UpdatePersonalAccessTokenNameParams) (db.PersonalAccessToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePersonalAccessTokenName", arg0, arg1)
+	ret0, _ := ret[0].(db.PersonalAccessToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdatePersonalAccessTokenName indicates an expected call of UpdatePersonalAccessTokenName.
+func (mr *MockStoreMockRecorder) UpdatePersonalAccessTokenName(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePersonalAccessTokenName", reflect.TypeOf((*MockStore)(nil).UpdatePersonalAccessTokenName), arg0, arg1)
+}
+
 // UpdateUser mocks base method.
 func (m *MockStore) UpdateUser(arg0 context.Context, arg1 db.UpdateUserParams) (db.User, error) {
 	m.ctrl.T.Helper()
@@ -168,3 +2421,78 @@ func (mr *MockStoreMockRecorder) UpdateUser(arg0, arg1 interface{}) *gomock.Call
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUser", reflect.TypeOf((*MockStore)(nil).UpdateUser), arg0, arg1)
 }
+
+// UpdateUserRole mocks base method.
+func (m *MockStore) UpdateUserRole(arg0 context.Context, arg1 db.UpdateUserRoleParams) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserRole", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateUserRole indicates an expected call of UpdateUserRole.
+func (mr *MockStoreMockRecorder) UpdateUserRole(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserRole", reflect.TypeOf((*MockStore)(nil).UpdateUserRole), arg0, arg1)
+}
+
+// UpdateWebhookEndpoint mocks base method.
+func (m *MockStore) UpdateWebhookEndpoint(arg0 context.Context, arg1 db.UpdateWebhookEndpointParams) (db.WebhookEndpoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWebhookEndpoint", arg0, arg1)
+	ret0, _ := ret[0].(db.WebhookEndpoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateWebhookEndpoint indicates an expected call of UpdateWebhookEndpoint.
+func (mr *MockStoreMockRecorder) UpdateWebhookEndpoint(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWebhookEndpoint", reflect.TypeOf((*MockStore)(nil).UpdateWebhookEndpoint), arg0, arg1)
+}
+
+// UpsertCollectionMember mocks base method.
+func (m *MockStore) UpsertCollectionMember(arg0 context.Context, arg1 db.UpsertCollectionMemberParams) (db.CollectionMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertCollectionMember", arg0, arg1)
+	ret0, _ := ret[0].(db.CollectionMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertCollectionMember indicates an expected call of UpsertCollectionMember.
+func (mr *MockStoreMockRecorder) UpsertCollectionMember(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertCollectionMember", reflect.TypeOf((*MockStore)(nil).UpsertCollectionMember), arg0, arg1)
+}
+
+// UpsertDigestSettings mocks base method.
+func (m *MockStore) UpsertDigestSettings(arg0 context.Context, arg1 db.UpsertDigestSettingsParams) (db.DigestSetting, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertDigestSettings", arg0, arg1)
+	ret0, _ := ret[0].(db.DigestSetting)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertDigestSettings indicates an expected call of UpsertDigestSettings.
+func (mr *MockStoreMockRecorder) UpsertDigestSettings(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertDigestSettings", reflect.TypeOf((*MockStore)(nil).UpsertDigestSettings), arg0, arg1)
+}
+
+// UpsertTag mocks base method.
+func (m *MockStore) UpsertTag(arg0 context.Context, arg1 string) (db.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertTag", arg0, arg1)
+	ret0, _ := ret[0].(db.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertTag indicates an expected call of UpsertTag.
+func (mr *MockStoreMockRecorder) UpsertTag(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertTag", reflect.TypeOf((*MockStore)(nil).UpsertTag), arg0, arg1)
+}
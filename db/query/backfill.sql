@@ -0,0 +1,11 @@
+-- name: GetBackfill :one
+SELECT * FROM backfills
+WHERE name = $1 LIMIT 1;
+
+-- name: FinishBackfill :exec
+INSERT INTO backfills (
+  name
+) VALUES (
+  $1
+)
+ON CONFLICT (name) DO NOTHING;
@@ -3,30 +3,131 @@ INSERT INTO caches (
   owner,
   title, 
   content,
-  is_public
+  is_public,
+  normalized_url
 ) VALUES (
-  $1, $2, $3, $4
+  $1, $2, $3, $4, $5
 ) RETURNING *;
    
 -- name: GetCache :one
 SELECT * FROM caches
 WHERE id = $1 LIMIT 1;
 
--- name: ListCaches :many
+-- name: GetCacheByNormalizedURL :one
 SELECT * FROM caches
-WHERE owner =$1
-ORDER BY created_at DESC
+WHERE owner = $1 AND normalized_url = $2 LIMIT 1;
+
+-- name: ListCaches :many
+SELECT c.* FROM caches c
+WHERE c.owner = $1
+AND (coalesce(cardinality(sqlc.arg(tag_ids)::int[]), 0) = 0
+  OR (sqlc.arg(match_all)::boolean AND (
+    SELECT count(*) FROM cache_tags ct
+    WHERE ct.cache_id = c.id AND ct.tag_id = ANY(sqlc.arg(tag_ids)::int[])
+  ) = cardinality(sqlc.arg(tag_ids)::int[]))
+  OR (NOT sqlc.arg(match_all)::boolean AND EXISTS (
+    SELECT 1 FROM cache_tags ct
+    WHERE ct.cache_id = c.id AND ct.tag_id = ANY(sqlc.arg(tag_ids)::int[])
+  )))
+AND NOT EXISTS (
+  SELECT 1 FROM cache_tags ct
+  WHERE ct.cache_id = c.id AND ct.tag_id = ANY(sqlc.arg(exclude_tag_ids)::int[])
+)
+AND (CASE
+  WHEN sqlc.narg(status)::reading_status IS NOT NULL THEN c.status = sqlc.narg(status)::reading_status
+  ELSE sqlc.arg(include_archived)::boolean OR c.status <> 'archived'
+END)
+ORDER BY c.created_at DESC, c.id DESC
 LIMIT $2
 OFFSET $3;
 
+-- name: ListCachesByCursor :many
+SELECT c.* FROM caches c
+WHERE c.owner = sqlc.arg(owner)
+AND (coalesce(cardinality(sqlc.arg(tag_ids)::int[]), 0) = 0
+  OR (sqlc.arg(match_all)::boolean AND (
+    SELECT count(*) FROM cache_tags ct
+    WHERE ct.cache_id = c.id AND ct.tag_id = ANY(sqlc.arg(tag_ids)::int[])
+  ) = cardinality(sqlc.arg(tag_ids)::int[]))
+  OR (NOT sqlc.arg(match_all)::boolean AND EXISTS (
+    SELECT 1 FROM cache_tags ct
+    WHERE ct.cache_id = c.id AND ct.tag_id = ANY(sqlc.arg(tag_ids)::int[])
+  )))
+AND NOT EXISTS (
+  SELECT 1 FROM cache_tags ct
+  WHERE ct.cache_id = c.id AND ct.tag_id = ANY(sqlc.arg(exclude_tag_ids)::int[])
+)
+AND (CASE
+  WHEN sqlc.narg(status)::reading_status IS NOT NULL THEN c.status = sqlc.narg(status)::reading_status
+  ELSE sqlc.arg(include_archived)::boolean OR c.status <> 'archived'
+END)
+AND (sqlc.narg(cursor_created_at)::timestamptz IS NULL
+  OR (c.created_at, c.id) < (sqlc.narg(cursor_created_at)::timestamptz, sqlc.narg(cursor_id)::bigint))
+ORDER BY c.created_at DESC, c.id DESC
+LIMIT sqlc.arg(page_limit);
+
 -- name: UpdateCache :one
 UPDATE caches
 SET title = $2,
     content = $3,
-    is_public = $4
+    is_public = $4,
+    normalized_url = $5,
+    updated_at = now()
 WHERE id = $1
 RETURNING *;
 
+-- name: UpdateCacheNormalizedURL :exec
+UPDATE caches c
+SET normalized_url = $2
+WHERE c.id = $1 AND NOT EXISTS (
+  SELECT 1 FROM caches d
+  WHERE d.owner = c.owner AND d.normalized_url = $2 AND d.id <> c.id
+);
+
+-- name: ListCachesWithoutNormalizedURL :many
+SELECT id, content FROM caches
+WHERE normalized_url = ''
+AND content ~* '^\s*https?://\S+\s*$'
+AND id > sqlc.arg(after_id)
+ORDER BY id
+LIMIT sqlc.arg(page_limit);
+
+-- name: UpdateCacheStatus :one
+UPDATE caches
+SET status = sqlc.arg(status)::reading_status,
+    read_at = CASE
+      WHEN sqlc.arg(status)::reading_status = 'read' THEN coalesce(read_at, now())
+      WHEN sqlc.arg(status)::reading_status = 'unread' THEN NULL
+      ELSE read_at
+    END,
+    archived_at = CASE
+      WHEN sqlc.arg(status)::reading_status = 'archived' THEN coalesce(archived_at, now())
+      ELSE NULL
+    END,
+    progress = CASE
+      WHEN sqlc.arg(status)::reading_status = 'read' THEN 100
+      WHEN sqlc.arg(status)::reading_status = 'unread' THEN 0
+      ELSE progress
+    END
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: UpdateCacheProgress :one
+UPDATE caches
+SET progress = sqlc.arg(progress)::int,
+    status = CASE
+      WHEN status = 'archived' THEN status
+      WHEN sqlc.arg(progress)::int >= 100 THEN 'read'
+      WHEN sqlc.arg(progress)::int > 0 AND status = 'unread' THEN 'reading'
+      ELSE status
+    END,
+    read_at = CASE
+      WHEN status <> 'archived' AND sqlc.arg(progress)::int >= 100 THEN coalesce(read_at, now())
+      ELSE read_at
+    END
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
 -- name: DeleteCache :exec
 DELETE FROM caches
 WHERE id = $1;
@@ -35,24 +136,88 @@ WHERE id = $1;
 SELECT c.*
 FROM caches c
 WHERE c.is_public = TRUE
+ORDER BY c.created_at DESC, c.id DESC
 LIMIT $1
 OFFSET $2;
 
+-- name: ListPublicCachesByCursor :many
+SELECT c.*
+FROM caches c
+WHERE c.is_public = TRUE
+AND (sqlc.narg(cursor_created_at)::timestamptz IS NULL
+  OR (c.created_at, c.id) < (sqlc.narg(cursor_created_at)::timestamptz, sqlc.narg(cursor_id)::bigint))
+ORDER BY c.created_at DESC, c.id DESC
+LIMIT sqlc.arg(page_limit);
+
 -- name: ListPublicCachesByTags :many
 SELECT c.*
 FROM caches c
-JOIN cache_tags ct ON c.id = ct.cache_id
-JOIN tags t ON ct.tag_id = t.tag_id
 WHERE c.is_public = TRUE
-AND t.tag_id = ANY(sqlc.arg(tag_ids)::int[])
+AND (coalesce(cardinality(sqlc.arg(tag_ids)::int[]), 0) = 0
+  OR (sqlc.arg(match_all)::boolean AND (
+    SELECT count(*) FROM cache_tags ct
+    WHERE ct.cache_id = c.id AND ct.tag_id = ANY(sqlc.arg(tag_ids)::int[])
+  ) = cardinality(sqlc.arg(tag_ids)::int[]))
+  OR (NOT sqlc.arg(match_all)::boolean AND EXISTS (
+    SELECT 1 FROM cache_tags ct
+    WHERE ct.cache_id = c.id AND ct.tag_id = ANY(sqlc.arg(tag_ids)::int[])
+  )))
+AND NOT EXISTS (
+  SELECT 1 FROM cache_tags ct
+  WHERE ct.cache_id = c.id AND ct.tag_id = ANY(sqlc.arg(exclude_tag_ids)::int[])
+)
+ORDER BY c.created_at DESC, c.id DESC
 LIMIT $1
 OFFSET $2;
 
+-- name: ListPublicCachesByTagsCursor :many
+SELECT c.*
+FROM caches c
+WHERE c.is_public = TRUE
+AND (coalesce(cardinality(sqlc.arg(tag_ids)::int[]), 0) = 0
+  OR (sqlc.arg(match_all)::boolean AND (
+    SELECT count(*) FROM cache_tags ct
+    WHERE ct.cache_id = c.id AND ct.tag_id = ANY(sqlc.arg(tag_ids)::int[])
+  ) = cardinality(sqlc.arg(tag_ids)::int[]))
+  OR (NOT sqlc.arg(match_all)::boolean AND EXISTS (
+    SELECT 1 FROM cache_tags ct
+    WHERE ct.cache_id = c.id AND ct.tag_id = ANY(sqlc.arg(tag_ids)::int[])
+  )))
+AND NOT EXISTS (
+  SELECT 1 FROM cache_tags ct
+  WHERE ct.cache_id = c.id AND ct.tag_id = ANY(sqlc.arg(exclude_tag_ids)::int[])
+)
+AND (sqlc.narg(cursor_created_at)::timestamptz IS NULL
+  OR (c.created_at, c.id) < (sqlc.narg(cursor_created_at)::timestamptz, sqlc.narg(cursor_id)::bigint))
+ORDER BY c.created_at DESC, c.id DESC
+LIMIT sqlc.arg(page_limit);
 
+-- name: SearchCaches :many
+SELECT sqlc.embed(c),
+  ts_rank(c.search_vector, to_tsquery('english', sqlc.arg(query)))::real AS rank,
+  ts_headline('english', html_escape(c.title), to_tsquery('english', sqlc.arg(query)),
+    'StartSel=<mark>, StopSel=</mark>, HighlightAll=true')::text AS title_highlight,
+  ts_headline('english', html_escape(c.content), to_tsquery('english', sqlc.arg(query)),
+    'StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MaxWords=30, MinWords=10')::text AS content_snippet
+FROM caches c
+WHERE c.search_vector @@ to_tsquery('english', sqlc.arg(query))
+AND (c.owner = sqlc.arg(owner) OR c.is_public = TRUE)
+ORDER BY rank DESC, c.id DESC
+LIMIT sqlc.arg(page_limit)
+OFFSET sqlc.arg(page_offset);
 
-
-
-
-
-
-
+-- name: SearchCachesByCursor :many
+SELECT sqlc.embed(c),
+  ts_rank(c.search_vector, to_tsquery('english', sqlc.arg(query)))::real AS rank,
+  ts_headline('english', html_escape(c.title), to_tsquery('english', sqlc.arg(query)),
+    'StartSel=<mark>, StopSel=</mark>, HighlightAll=true')::text AS title_highlight,
+  ts_headline('english', html_escape(c.content), to_tsquery('english', sqlc.arg(query)),
+    'StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MaxWords=30, MinWords=10')::text AS content_snippet
+FROM caches c
+WHERE c.search_vector @@ to_tsquery('english', sqlc.arg(query))
+AND (c.owner = sqlc.arg(owner) OR c.is_public = TRUE)
+AND (sqlc.narg(cursor_rank)::real IS NULL
+  OR (ts_rank(c.search_vector, to_tsquery('english', sqlc.arg(query)))::real, c.id)
+    < (sqlc.narg(cursor_rank)::real, sqlc.narg(cursor_id)::bigint))
+ORDER BY rank DESC, c.id DESC
+LIMIT sqlc.arg(page_limit);
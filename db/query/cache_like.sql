@@ -0,0 +1,48 @@
+-- name: CreateCacheLike :execrows
+INSERT INTO cache_likes (
+  cache_id,
+  user_id
+) VALUES (
+  $1, $2
+) ON CONFLICT DO NOTHING;
+
+-- name: DeleteCacheLike :execrows
+DELETE FROM cache_likes
+WHERE cache_id = $1 AND user_id = $2;
+
+-- name: AddCacheLikeCount :one
+UPDATE caches
+SET like_count = like_count + sqlc.arg(delta)
+WHERE id = sqlc.arg(id)
+RETURNING like_count;
+
+-- name: ListLikedCacheIDs :many
+SELECT cache_id FROM cache_likes
+WHERE user_id = sqlc.arg(user_id) AND cache_id = ANY(sqlc.arg(cache_ids)::bigint[]);
+
+-- name: ListMostLikedPublicCaches :many
+SELECT sqlc.embed(c), w.recent_likes
+FROM caches c
+JOIN (
+  SELECT cache_id, count(*) AS recent_likes FROM cache_likes
+  WHERE created_at >= sqlc.arg(liked_since)
+  GROUP BY cache_id
+) w ON w.cache_id = c.id
+WHERE c.is_public = TRUE
+AND (coalesce(cardinality(sqlc.arg(tag_ids)::int[]), 0) = 0
+  OR (sqlc.arg(match_all)::boolean AND (
+    SELECT count(*) FROM cache_tags ct
+    WHERE ct.cache_id = c.id AND ct.tag_id = ANY(sqlc.arg(tag_ids)::int[])
+  ) = cardinality(sqlc.arg(tag_ids)::int[]))
+  OR (NOT sqlc.arg(match_all)::boolean AND EXISTS (
+    SELECT 1 FROM cache_tags ct
+    WHERE ct.cache_id = c.id AND ct.tag_id = ANY(sqlc.arg(tag_ids)::int[])
+  )))
+AND NOT EXISTS (
+  SELECT 1 FROM cache_tags ct
+  WHERE ct.cache_id = c.id AND ct.tag_id = ANY(sqlc.arg(exclude_tag_ids)::int[])
+)
+AND (sqlc.narg(cursor_likes)::bigint IS NULL
+  OR (w.recent_likes, c.id) < (sqlc.narg(cursor_likes)::bigint, sqlc.narg(cursor_id)::bigint))
+ORDER BY w.recent_likes DESC, c.id DESC
+LIMIT sqlc.arg(page_limit);
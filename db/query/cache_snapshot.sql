@@ -0,0 +1,55 @@
+-- name: RequestCacheSnapshot :one
+INSERT INTO cache_snapshots (
+  cache_id,
+  url
+) VALUES (
+  $1, $2
+) ON CONFLICT (cache_id) DO UPDATE
+SET url = EXCLUDED.url,
+    status = 'pending',
+    attempts = 0,
+    last_error = '',
+    next_attempt_at = now()
+RETURNING *;
+
+-- name: GetCacheSnapshot :one
+SELECT * FROM cache_snapshots
+WHERE cache_id = $1 LIMIT 1;
+
+-- name: ClaimDueCacheSnapshots :many
+UPDATE cache_snapshots
+SET next_attempt_at = now() + make_interval(secs => sqlc.arg(lease_seconds)::int)
+WHERE cache_id IN (
+  SELECT cs.cache_id FROM cache_snapshots cs
+  WHERE cs.status = 'pending' AND cs.next_attempt_at <= now()
+  ORDER BY cs.next_attempt_at
+  LIMIT sqlc.arg(batch_size)
+  FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: CompleteCacheSnapshot :exec
+UPDATE cache_snapshots
+SET status = 'done',
+    title = $2,
+    html = $3,
+    text = $4,
+    images = $5,
+    attempts = attempts + 1,
+    last_error = '',
+    archived_at = now()
+WHERE cache_id = $1;
+
+-- name: RetryCacheSnapshot :exec
+UPDATE cache_snapshots
+SET attempts = attempts + 1,
+    last_error = $2,
+    next_attempt_at = $3
+WHERE cache_id = $1;
+
+-- name: FailCacheSnapshot :exec
+UPDATE cache_snapshots
+SET status = 'failed',
+    attempts = attempts + 1,
+    last_error = $2
+WHERE cache_id = $1;
@@ -0,0 +1,94 @@
+-- name: CreateCollection :one
+INSERT INTO collections (
+  owner,
+  name,
+  description,
+  icon,
+  is_public
+) VALUES (
+  $1, $2, $3, $4, $5
+) RETURNING *;
+
+-- name: GetCollection :one
+SELECT * FROM collections
+WHERE id = $1 LIMIT 1;
+
+-- name: GetCollectionForUpdate :one
+SELECT * FROM collections
+WHERE id = $1 LIMIT 1
+FOR UPDATE;
+
+-- name: ListCollections :many
+SELECT c.*, m.role, (
+  SELECT count(*) FROM collection_caches cc
+  WHERE cc.collection_id = c.id
+) AS cache_count
+FROM collections c
+JOIN collection_members m ON m.collection_id = c.id
+WHERE m.user_id = $1
+ORDER BY c.name, c.id;
+
+-- name: UpdateCollection :one
+UPDATE collections
+SET name = $2,
+    description = $3,
+    icon = $4,
+    is_public = $5,
+    updated_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: DeleteCollection :exec
+DELETE FROM collections
+WHERE id = $1;
+
+-- name: AddCacheToCollection :execrows
+INSERT INTO collection_caches (collection_id, cache_id, position)
+SELECT sqlc.arg(collection_id)::bigint, sqlc.arg(cache_id)::bigint, coalesce(max(position), 0) + 1
+FROM collection_caches
+WHERE collection_id = sqlc.arg(collection_id)
+ON CONFLICT DO NOTHING;
+
+-- name: RemoveCacheFromCollection :execrows
+DELETE FROM collection_caches
+WHERE collection_id = $1 AND cache_id = $2;
+
+-- name: ListCollectionCacheIDsForUpdate :many
+SELECT cache_id FROM collection_caches
+WHERE collection_id = $1
+ORDER BY position, cache_id
+FOR UPDATE;
+
+-- name: SetCollectionCachePositions :exec
+UPDATE collection_caches cc
+SET position = o.position
+FROM unnest(sqlc.arg(cache_ids)::bigint[]) WITH ORDINALITY AS o(cache_id, position)
+WHERE cc.collection_id = sqlc.arg(collection_id) AND cc.cache_id = o.cache_id;
+
+-- name: ListCollectionCaches :many
+SELECT sqlc.embed(c), cc.position
+FROM collection_caches cc
+JOIN caches c ON c.id = cc.cache_id
+WHERE cc.collection_id = sqlc.arg(collection_id)
+AND (c.owner = sqlc.arg(viewer) OR c.is_public)
+AND (coalesce(cardinality(sqlc.arg(tag_ids)::int[]), 0) = 0
+  OR (sqlc.arg(match_all)::boolean AND (
+    SELECT count(*) FROM cache_tags ct
+    WHERE ct.cache_id = c.id AND ct.tag_id = ANY(sqlc.arg(tag_ids)::int[])
+  ) = cardinality(sqlc.arg(tag_ids)::int[]))
+  OR (NOT sqlc.arg(match_all)::boolean AND EXISTS (
+    SELECT 1 FROM cache_tags ct
+    WHERE ct.cache_id = c.id AND ct.tag_id = ANY(sqlc.arg(tag_ids)::int[])
+  )))
+AND NOT EXISTS (
+  SELECT 1 FROM cache_tags ct
+  WHERE ct.cache_id = c.id AND ct.tag_id = ANY(sqlc.arg(exclude_tag_ids)::int[])
+)
+AND (CASE
+  WHEN sqlc.narg(status)::reading_status IS NOT NULL THEN c.status = sqlc.narg(status)::reading_status
+  ELSE sqlc.arg(include_archived)::boolean OR c.status <> 'archived'
+END)
+AND (sqlc.narg(cursor_position)::int IS NULL
+  OR (cc.position, c.id) > (sqlc.narg(cursor_position)::int, sqlc.narg(cursor_id)::bigint))
+ORDER BY cc.position, c.id
+LIMIT sqlc.arg(page_limit);
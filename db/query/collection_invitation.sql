@@ -0,0 +1,41 @@
+-- name: CreateCollectionInvitation :one
+INSERT INTO collection_invitations (
+  collection_id,
+  inviter,
+  invitee_email,
+  invitee_id,
+  role
+) VALUES (
+  $1, $2, $3, $4, $5
+) RETURNING *;
+
+-- name: ListCollectionInvitations :many
+SELECT * FROM collection_invitations
+WHERE collection_id = $1
+ORDER BY created_at DESC, id DESC;
+
+-- name: ListPendingInvitationsForUser :many
+SELECT i.*, c.name AS collection_name
+FROM collection_invitations i
+JOIN collections c ON c.id = i.collection_id
+WHERE i.status = 'pending'
+AND (i.invitee_id = sqlc.arg(user_id)
+  OR (sqlc.arg(email)::varchar <> '' AND lower(i.invitee_email) = lower(sqlc.arg(email)::varchar)))
+ORDER BY i.created_at DESC, i.id DESC;
+
+-- name: RespondToCollectionInvitation :one
+UPDATE collection_invitations
+SET status = sqlc.arg(status),
+    invitee_id = sqlc.arg(user_id),
+    responded_at = now()
+WHERE id = sqlc.arg(id) AND status = 'pending'
+AND (invitee_id = sqlc.arg(user_id)
+  OR (sqlc.arg(email)::varchar <> '' AND lower(invitee_email) = lower(sqlc.arg(email)::varchar)))
+RETURNING *;
+
+-- name: RevokeCollectionInvitation :one
+UPDATE collection_invitations
+SET status = 'revoked',
+    responded_at = now()
+WHERE id = $1 AND collection_id = $2 AND status = 'pending'
+RETURNING *;
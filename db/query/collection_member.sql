@@ -0,0 +1,40 @@
+-- name: UpsertCollectionMember :one
+INSERT INTO collection_members (
+  collection_id,
+  user_id,
+  role
+) VALUES (
+  $1, $2, $3
+) ON CONFLICT (collection_id, user_id)
+DO UPDATE SET role = greatest(collection_members.role, EXCLUDED.role)
+RETURNING *;
+
+-- name: GetCollectionMember :one
+SELECT * FROM collection_members
+WHERE collection_id = $1 AND user_id = $2 LIMIT 1;
+
+-- name: ListCollectionMembers :many
+SELECT m.*, u.email, u.name
+FROM collection_members m
+JOIN users u ON u.id = m.user_id
+WHERE m.collection_id = $1
+ORDER BY m.role DESC, m.created_at, m.user_id;
+
+-- name: UpdateCollectionMemberRole :one
+UPDATE collection_members
+SET role = $3
+WHERE collection_id = $1 AND user_id = $2
+RETURNING *;
+
+-- name: DeleteCollectionMember :exec
+DELETE FROM collection_members
+WHERE collection_id = $1 AND user_id = $2;
+
+-- name: CountCollectionOwners :one
+SELECT count(*) FROM collection_members
+WHERE collection_id = $1 AND role = 'owner';
+
+-- name: LockCollection :exec
+SELECT id FROM collections
+WHERE id = $1
+FOR UPDATE;
@@ -0,0 +1,53 @@
+-- name: CreateComment :one
+INSERT INTO comments (
+  cache_id,
+  author,
+  parent_id,
+  body
+) VALUES (
+  $1, $2, $3, $4
+) RETURNING *;
+
+-- name: GetComment :one
+SELECT * FROM comments
+WHERE id = $1 LIMIT 1;
+
+-- name: ListComments :many
+SELECT c.*, (
+  SELECT count(*) FROM comments r
+  WHERE r.parent_id = c.id AND r.deleted_at IS NULL
+) AS reply_count
+FROM comments c
+WHERE c.cache_id = sqlc.arg(cache_id) AND c.parent_id IS NULL
+AND (sqlc.narg(cursor_created_at)::timestamptz IS NULL
+  OR (c.created_at, c.id) > (sqlc.narg(cursor_created_at)::timestamptz, sqlc.narg(cursor_id)::bigint))
+ORDER BY c.created_at, c.id
+LIMIT sqlc.arg(page_limit);
+
+-- name: ListCommentReplies :many
+SELECT * FROM comments
+WHERE parent_id = sqlc.arg(parent_id)
+AND (sqlc.narg(cursor_created_at)::timestamptz IS NULL
+  OR (created_at, id) > (sqlc.narg(cursor_created_at)::timestamptz, sqlc.narg(cursor_id)::bigint))
+ORDER BY created_at, id
+LIMIT sqlc.arg(page_limit);
+
+-- name: UpdateCommentBody :one
+UPDATE comments
+SET body = $2,
+    edited_at = now()
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING *;
+
+-- name: SoftDeleteComment :one
+UPDATE comments
+SET body = '',
+    deleted_at = now()
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING *;
+
+-- name: SetCommentHidden :one
+UPDATE comments
+SET hidden_at = CASE WHEN sqlc.arg(hidden)::boolean THEN coalesce(hidden_at, now()) END
+WHERE id = sqlc.arg(id)
+RETURNING *;
@@ -0,0 +1,98 @@
+-- name: GetDigestSettings :one
+SELECT * FROM digest_settings
+WHERE user_id = $1 LIMIT 1;
+
+-- name: UpsertDigestSettings :one
+INSERT INTO digest_settings (
+  user_id,
+  frequency,
+  next_send_at
+) VALUES (
+  sqlc.arg(user_id),
+  sqlc.arg(frequency)::varchar,
+  CASE sqlc.arg(frequency)::varchar
+    WHEN 'daily' THEN now() + interval '1 day'
+    WHEN 'weekly' THEN now() + interval '7 days'
+  END
+)
+ON CONFLICT (user_id) DO UPDATE
+SET frequency = EXCLUDED.frequency,
+  next_send_at = CASE
+    WHEN EXCLUDED.frequency = digest_settings.frequency THEN digest_settings.next_send_at
+    ELSE EXCLUDED.next_send_at
+  END,
+  last_sent_at = CASE
+    WHEN digest_settings.frequency = 'off' THEN now()
+    ELSE digest_settings.last_sent_at
+  END,
+  attempts = 0,
+  last_error = '',
+  updated_at = now()
+RETURNING *;
+
+-- name: ClaimDueDigests :many
+WITH due AS (
+  SELECT user_id FROM digest_settings
+  WHERE next_send_at <= now()
+  ORDER BY next_send_at
+  LIMIT sqlc.arg(batch_size)
+  FOR UPDATE SKIP LOCKED
+)
+UPDATE digest_settings d
+SET next_send_at = now() + make_interval(secs => sqlc.arg(lease_seconds)::int)
+FROM due, users u
+WHERE d.user_id = due.user_id AND u.id = d.user_id
+RETURNING d.user_id, d.frequency, d.last_sent_at, d.attempts, u.email AS user_email, u.name AS user_name;
+
+-- name: ListDigestCaches :many
+SELECT c.id, c.title, c.content, c.created_at,
+  u.name AS owner_name,
+  coalesce(array_agg(t.tag_name ORDER BY t.tag_name) FILTER (WHERE ut.tag_id IS NOT NULL), '{}')::varchar[] AS tags
+FROM caches c
+JOIN users u ON u.id = c.owner
+JOIN cache_tags ct ON ct.cache_id = c.id
+JOIN tags t ON t.tag_id = ct.tag_id
+LEFT JOIN user_tags ut ON ut.tag_id = ct.tag_id AND ut.user_id = sqlc.arg(user_id)::varchar
+WHERE c.is_public = TRUE
+AND c.owner <> sqlc.arg(user_id)::varchar
+AND c.created_at > sqlc.arg(since)::timestamptz
+AND c.created_at <= sqlc.arg(until)::timestamptz
+GROUP BY c.id, u.name
+HAVING count(ut.tag_id) > 0
+ORDER BY c.created_at DESC, c.id DESC
+LIMIT sqlc.arg(page_limit);
+
+-- name: CompleteDigest :exec
+UPDATE digest_settings
+SET last_sent_at = sqlc.arg(sent_at)::timestamptz,
+  next_send_at = CASE frequency
+    WHEN 'daily' THEN sqlc.arg(sent_at)::timestamptz + interval '1 day'
+    WHEN 'weekly' THEN sqlc.arg(sent_at)::timestamptz + interval '7 days'
+  END,
+  attempts = 0,
+  last_error = ''
+WHERE user_id = sqlc.arg(user_id);
+
+-- name: RetryDigest :exec
+UPDATE digest_settings
+SET next_send_at = CASE WHEN frequency = 'off' THEN NULL ELSE sqlc.arg(next_send_at)::timestamptz END,
+  attempts = attempts + 1,
+  last_error = sqlc.arg(last_error)
+WHERE user_id = sqlc.arg(user_id);
+
+-- name: SkipDigest :exec
+UPDATE digest_settings
+SET next_send_at = CASE frequency
+    WHEN 'daily' THEN now() + interval '1 day'
+    WHEN 'weekly' THEN now() + interval '7 days'
+  END,
+  attempts = 0,
+  last_error = sqlc.arg(last_error)
+WHERE user_id = sqlc.arg(user_id);
+
+-- name: UnsubscribeDigest :exec
+UPDATE digest_settings
+SET frequency = 'off',
+  next_send_at = NULL,
+  updated_at = now()
+WHERE user_id = $1;
@@ -0,0 +1,12 @@
+-- name: ListCachesForExport :many
+SELECT c.*,
+  coalesce(array_agg(t.tag_name ORDER BY t.tag_name) FILTER (WHERE t.tag_id IS NOT NULL), '{}')::varchar[] AS tags
+FROM caches c
+LEFT JOIN cache_tags ct ON ct.cache_id = c.id
+LEFT JOIN tags t ON t.tag_id = ct.tag_id
+WHERE c.owner = sqlc.arg(owner)
+AND (sqlc.narg(cursor_created_at)::timestamptz IS NULL
+  OR (c.created_at, c.id) > (sqlc.narg(cursor_created_at)::timestamptz, sqlc.narg(cursor_id)::bigint))
+GROUP BY c.id
+ORDER BY c.created_at, c.id
+LIMIT sqlc.arg(page_limit);
@@ -0,0 +1,19 @@
+-- name: ListFeedCaches :many
+SELECT c.*,
+  u.name AS owner_name,
+  coalesce(lp.description, '')::varchar AS description,
+  coalesce(lp.image_url, '')::varchar AS image_url,
+  coalesce(array_agg(t.tag_name ORDER BY t.tag_name) FILTER (WHERE t.tag_id IS NOT NULL), '{}')::varchar[] AS tags
+FROM caches c
+JOIN users u ON u.id = c.owner
+LEFT JOIN link_previews lp ON lp.cache_id = c.id AND lp.status = 'done'
+LEFT JOIN cache_tags ct ON ct.cache_id = c.id
+LEFT JOIN tags t ON t.tag_id = ct.tag_id
+WHERE c.is_public = TRUE
+AND (sqlc.narg(owner)::varchar IS NULL OR c.owner = sqlc.narg(owner)::varchar)
+AND (sqlc.narg(tag_id)::int IS NULL OR EXISTS (
+  SELECT 1 FROM cache_tags f WHERE f.cache_id = c.id AND f.tag_id = sqlc.narg(tag_id)::int
+))
+GROUP BY c.id, u.name, lp.description, lp.image_url
+ORDER BY c.created_at DESC, c.id DESC
+LIMIT sqlc.arg(page_limit);
@@ -0,0 +1,125 @@
+-- name: CreateImportJob :one
+INSERT INTO import_jobs (
+  user_id,
+  format,
+  filename,
+  total
+) VALUES (
+  $1, $2, $3, $4
+) RETURNING *;
+
+-- name: CreateImportItems :execrows
+INSERT INTO import_items (
+  job_id, position, url, title, tags, added_at, archived,
+  content, is_public, reading_status, progress, read_at, archived_at
+)
+SELECT sqlc.arg(job_id)::bigint, i.position, i.url, i.title, coalesce(i.tags, '{}'), i.added_at, coalesce(i.archived, false),
+  coalesce(i.content, ''), coalesce(i.is_public, false), nullif(i.reading_status, '')::reading_status,
+  coalesce(i.progress, 0), i.read_at, i.archived_at
+FROM jsonb_to_recordset(sqlc.arg(items)::jsonb) AS i(
+  position int, url varchar, title varchar, tags varchar[], added_at timestamptz, archived boolean,
+  content varchar, is_public boolean, reading_status varchar, progress int, read_at timestamptz, archived_at timestamptz
+);
+
+-- name: GetImportJob :one
+SELECT * FROM import_jobs
+WHERE id = $1 LIMIT 1;
+
+-- name: ListImportJobs :many
+SELECT * FROM import_jobs
+WHERE user_id = $1
+ORDER BY created_at DESC, id DESC
+LIMIT $2;
+
+-- name: ClaimDueImportJobs :many
+UPDATE import_jobs
+SET status = 'running',
+    next_attempt_at = now() + make_interval(secs => sqlc.arg(lease_seconds)::int)
+WHERE id IN (
+  SELECT j.id FROM import_jobs j
+  WHERE j.status IN ('pending', 'running') AND j.next_attempt_at <= now()
+  ORDER BY j.next_attempt_at
+  LIMIT sqlc.arg(batch_size)
+  FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: ExtendImportJobLease :exec
+UPDATE import_jobs
+SET next_attempt_at = now() + make_interval(secs => sqlc.arg(lease_seconds)::int)
+WHERE id = sqlc.arg(id);
+
+-- name: ListPendingImportItems :many
+SELECT * FROM import_items
+WHERE job_id = $1 AND status = 'pending'
+ORDER BY position
+LIMIT $2;
+
+-- name: FinishImportItem :one
+WITH item AS (
+  UPDATE import_items
+  SET status = sqlc.arg(status),
+      cache_id = sqlc.narg(cache_id),
+      error = sqlc.arg(error)
+  WHERE job_id = sqlc.arg(job_id) AND position = sqlc.arg(position) AND status = 'pending'
+  RETURNING *
+), job AS (
+  UPDATE import_jobs
+  SET processed = processed + 1,
+      created_count = created_count + (sqlc.arg(status)::varchar = 'created')::int,
+      skipped_count = skipped_count + (sqlc.arg(status)::varchar = 'skipped')::int,
+      failed_count = failed_count + (sqlc.arg(status)::varchar = 'failed')::int
+  WHERE id = sqlc.arg(job_id) AND EXISTS (SELECT 1 FROM item)
+)
+SELECT * FROM item;
+
+-- name: ListImportItems :many
+SELECT * FROM import_items
+WHERE job_id = sqlc.arg(job_id)
+AND (sqlc.narg(status)::varchar IS NULL OR status = sqlc.narg(status)::varchar)
+AND (sqlc.narg(cursor_created_at)::timestamptz IS NULL
+  OR (created_at, position) > (sqlc.narg(cursor_created_at)::timestamptz, sqlc.narg(cursor_id)::int))
+ORDER BY created_at, position
+LIMIT sqlc.arg(page_limit);
+
+-- name: CompleteImportJob :exec
+UPDATE import_jobs
+SET status = 'done',
+    last_error = '',
+    finished_at = now()
+WHERE id = $1;
+
+-- name: RetryImportJob :exec
+UPDATE import_jobs
+SET attempts = attempts + 1,
+    last_error = $2,
+    next_attempt_at = $3
+WHERE id = $1;
+
+-- name: FailImportJob :exec
+UPDATE import_jobs
+SET status = 'failed',
+    attempts = attempts + 1,
+    last_error = $2,
+    finished_at = now()
+WHERE id = $1;
+
+-- name: SetCacheSavedAt :exec
+UPDATE caches
+SET saved_at = $2
+WHERE id = $1;
+
+-- name: SetCacheReadingState :exec
+UPDATE caches
+SET status = sqlc.arg(status)::reading_status,
+    progress = sqlc.arg(progress),
+    read_at = CASE
+      WHEN sqlc.arg(status)::reading_status = 'read' THEN coalesce(sqlc.narg(read_at), now())
+      WHEN sqlc.arg(status)::reading_status = 'unread' THEN NULL
+      ELSE sqlc.narg(read_at)
+    END,
+    archived_at = CASE
+      WHEN sqlc.arg(status)::reading_status = 'archived' THEN coalesce(sqlc.narg(archived_at), now())
+      ELSE NULL
+    END
+WHERE id = sqlc.arg(id);
@@ -0,0 +1,43 @@
+-- name: CreateInboundHook :one
+INSERT INTO inbound_hooks (
+  user_id,
+  name,
+  token_hash,
+  secret,
+  require_signature,
+  is_public
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+) RETURNING *;
+
+-- name: GetInboundHookByTokenHash :one
+SELECT * FROM inbound_hooks
+WHERE token_hash = $1 LIMIT 1;
+
+-- name: ListInboundHooks :many
+SELECT * FROM inbound_hooks
+WHERE user_id = $1
+ORDER BY created_at, id;
+
+-- name: DeleteInboundHook :one
+DELETE FROM inbound_hooks
+WHERE id = $1 AND user_id = $2
+RETURNING *;
+
+-- name: TouchInboundHook :exec
+UPDATE inbound_hooks
+SET last_used_at = now()
+WHERE id = $1;
+
+-- name: GetInboundHookRequest :one
+SELECT * FROM inbound_hook_requests
+WHERE hook_id = $1 AND client_id = $2 LIMIT 1;
+
+-- name: CreateInboundHookRequest :exec
+INSERT INTO inbound_hook_requests (
+  hook_id,
+  client_id,
+  cache_id
+) VALUES (
+  $1, $2, $3
+);
@@ -0,0 +1,50 @@
+-- name: CreateLinkPreview :one
+INSERT INTO link_previews (
+  cache_id,
+  url
+) VALUES (
+  $1, $2
+) RETURNING *;
+
+-- name: GetLinkPreview :one
+SELECT * FROM link_previews
+WHERE cache_id = $1 LIMIT 1;
+
+-- name: ClaimDueLinkPreviews :many
+UPDATE link_previews
+SET next_attempt_at = now() + make_interval(secs => sqlc.arg(lease_seconds)::int)
+WHERE cache_id IN (
+  SELECT lp.cache_id FROM link_previews lp
+  WHERE lp.status = 'pending' AND lp.next_attempt_at <= now()
+  ORDER BY lp.next_attempt_at
+  LIMIT sqlc.arg(batch_size)
+  FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: CompleteLinkPreview :exec
+UPDATE link_previews
+SET status = 'done',
+    title = $2,
+    description = $3,
+    site_name = $4,
+    image_url = $5,
+    canonical_url = $6,
+    attempts = attempts + 1,
+    last_error = '',
+    fetched_at = now()
+WHERE cache_id = $1;
+
+-- name: RetryLinkPreview :exec
+UPDATE link_previews
+SET attempts = attempts + 1,
+    last_error = $2,
+    next_attempt_at = $3
+WHERE cache_id = $1;
+
+-- name: FailLinkPreview :exec
+UPDATE link_previews
+SET status = 'failed',
+    attempts = attempts + 1,
+    last_error = $2
+WHERE cache_id = $1;
@@ -0,0 +1,77 @@
+-- name: CreateReminder :one
+INSERT INTO reminders (
+  cache_id,
+  user_id,
+  note,
+  remind_at,
+  rrule,
+  time_zone,
+  next_fire_at
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7
+) RETURNING *;
+
+-- name: ListReminders :many
+SELECT * FROM reminders
+WHERE user_id = $1 AND next_fire_at IS NOT NULL
+ORDER BY next_fire_at;
+
+-- name: ListCacheReminders :many
+SELECT * FROM reminders
+WHERE cache_id = $1 AND user_id = $2
+ORDER BY created_at DESC;
+
+-- name: DeleteReminder :one
+DELETE FROM reminders
+WHERE id = $1 AND user_id = $2
+RETURNING *;
+
+-- name: ClaimDueReminders :many
+WITH due AS (
+  SELECT r.id, coalesce(r.due_at, r.next_fire_at) AS due_at,
+    (c.is_public = TRUE OR c.owner = r.user_id) AS visible
+  FROM reminders r
+  JOIN caches c ON c.id = r.cache_id
+  WHERE r.next_fire_at <= now()
+  ORDER BY r.next_fire_at
+  LIMIT sqlc.arg(batch_size)
+  FOR UPDATE OF r SKIP LOCKED
+), cancelled AS (
+  UPDATE reminders
+  SET next_fire_at = NULL,
+      due_at = NULL,
+      last_error = 'the cache is no longer visible to the reminder''s user'
+  WHERE id IN (SELECT id FROM due WHERE NOT visible)
+)
+UPDATE reminders r
+SET next_fire_at = now() + make_interval(secs => sqlc.arg(lease_seconds)::int),
+    due_at = due.due_at
+FROM due, caches c, users u
+WHERE r.id = due.id AND due.visible AND c.id = r.cache_id AND u.id = r.user_id
+RETURNING r.id, r.cache_id, r.user_id, r.note, r.remind_at, r.rrule, r.time_zone, r.attempts,
+  r.due_at, c.title AS cache_title, c.content AS cache_content, u.email AS user_email;
+
+-- name: CompleteReminderFire :exec
+UPDATE reminders
+SET next_fire_at = $2,
+    due_at = NULL,
+    fire_count = fire_count + 1,
+    last_fired_at = now(),
+    attempts = 0,
+    last_error = ''
+WHERE id = $1;
+
+-- name: RetryReminder :exec
+UPDATE reminders
+SET next_fire_at = $2,
+    attempts = attempts + 1,
+    last_error = $3
+WHERE id = $1;
+
+-- name: SkipReminderFire :exec
+UPDATE reminders
+SET next_fire_at = $2,
+    due_at = NULL,
+    attempts = 0,
+    last_error = $3
+WHERE id = $1;
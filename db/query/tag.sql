@@ -5,9 +5,42 @@ INSERT INTO tags (
   $1
 ) RETURNING *;
 
+-- name: UpsertTag :one
+INSERT INTO tags (
+  tag_name
+) VALUES (
+  $1
+)
+ON CONFLICT (tag_name) DO UPDATE SET tag_name = EXCLUDED.tag_name
+RETURNING *;
+
+-- name: GetTag :one
+SELECT * FROM tags
+WHERE tag_id = $1 LIMIT 1;
+
+-- name: GetTagByName :one
+SELECT * FROM tags
+WHERE tag_name = $1 LIMIT 1;
+
 -- name: ListTags :many
 SELECT * FROM tags;
 
+-- name: SearchTags :many
+SELECT t.tag_id, t.tag_name,
+  similarity(t.tag_name, sqlc.arg(query))::real AS similarity,
+  (SELECT count(*) FROM cache_tags ct WHERE ct.tag_id = t.tag_id) AS usage_count
+FROM tags t
+WHERE t.tag_name ILIKE sqlc.arg(prefix_pattern) OR t.tag_name % sqlc.arg(query)
+ORDER BY t.tag_name ILIKE sqlc.arg(prefix_pattern) DESC, similarity DESC, usage_count DESC, t.tag_name
+LIMIT $1;
+
+-- name: ListTaggedCaches :many
+SELECT c.id, c.owner
+FROM cache_tags ct
+JOIN caches c ON c.id = ct.cache_id
+WHERE ct.tag_id = $1
+ORDER BY c.id;
+
 -- name: DeleteTagFromCacheTagsTable :exec
 DELETE FROM cache_tags 
 WHERE tag_id = $1;
@@ -0,0 +1,45 @@
+-- name: ListTimelineCaches :many
+SELECT c.*
+FROM caches c
+WHERE c.is_public = TRUE
+AND c.owner <> sqlc.arg(user_id)::varchar
+AND EXISTS (
+  SELECT 1 FROM cache_tags ct
+  JOIN user_tags ut ON ut.tag_id = ct.tag_id
+  WHERE ct.cache_id = c.id AND ut.user_id = sqlc.arg(user_id)::varchar
+)
+AND (sqlc.narg(cursor_created_at)::timestamptz IS NULL
+  OR (c.created_at, c.id) < (sqlc.narg(cursor_created_at)::timestamptz, sqlc.narg(cursor_id)::bigint))
+ORDER BY c.created_at DESC, c.id DESC
+LIMIT sqlc.arg(page_limit);
+
+-- name: CountUnseenTimelineCaches :one
+SELECT count(*) FROM (
+  SELECT 1
+  FROM caches c
+  WHERE c.is_public = TRUE
+  AND c.owner <> sqlc.arg(user_id)::varchar
+  AND EXISTS (
+    SELECT 1 FROM cache_tags ct
+    JOIN user_tags ut ON ut.tag_id = ct.tag_id
+    WHERE ct.cache_id = c.id AND ut.user_id = sqlc.arg(user_id)::varchar
+  )
+  AND (sqlc.narg(since)::timestamptz IS NULL OR c.created_at > sqlc.narg(since)::timestamptz)
+  LIMIT sqlc.arg(max_count)
+) unseen;
+
+-- name: GetTimelineMarker :one
+SELECT * FROM timeline_markers
+WHERE user_id = $1 LIMIT 1;
+
+-- name: MarkTimelineSeen :one
+INSERT INTO timeline_markers (
+  user_id,
+  last_seen_at
+) VALUES (
+  $1, $2
+)
+ON CONFLICT (user_id) DO UPDATE
+SET last_seen_at = GREATEST(timeline_markers.last_seen_at, EXCLUDED.last_seen_at),
+  updated_at = now()
+RETURNING *;
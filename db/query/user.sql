@@ -22,3 +22,9 @@ SET
 WHERE
   id = sqlc.narg(id)
 RETURNING *;
+
+-- name: UpdateUserRole :one
+UPDATE users
+SET role = $2
+WHERE id = $1
+RETURNING *;
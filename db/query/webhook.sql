@@ -0,0 +1,127 @@
+-- name: CreateWebhookEndpoint :one
+INSERT INTO webhook_endpoints (
+  user_id,
+  url,
+  secret,
+  events,
+  description
+) VALUES (
+  $1, $2, $3, $4, $5
+) RETURNING *;
+
+-- name: GetWebhookEndpoint :one
+SELECT * FROM webhook_endpoints
+WHERE id = $1 LIMIT 1;
+
+-- name: ListWebhookEndpoints :many
+SELECT * FROM webhook_endpoints
+WHERE user_id = $1
+ORDER BY created_at, id;
+
+-- name: UpdateWebhookEndpoint :one
+UPDATE webhook_endpoints
+SET url = $2,
+  events = $3,
+  description = $4,
+  active = $5,
+  updated_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: DeleteWebhookEndpoint :exec
+DELETE FROM webhook_endpoints
+WHERE id = $1;
+
+-- name: CreateWebhookEvent :exec
+INSERT INTO webhook_events (
+  user_id,
+  event_type,
+  payload
+)
+SELECT sqlc.arg(user_id)::varchar, sqlc.arg(event_type)::varchar, sqlc.arg(payload)::jsonb
+WHERE EXISTS (
+  SELECT 1 FROM webhook_endpoints
+  WHERE user_id = sqlc.arg(user_id)::varchar AND active AND sqlc.arg(event_type)::varchar = ANY(events)
+);
+
+-- name: DispatchWebhookEvents :execrows
+WITH due AS (
+  SELECT id FROM webhook_events
+  WHERE dispatched_at IS NULL
+  ORDER BY id
+  LIMIT sqlc.arg(batch_size)
+  FOR UPDATE SKIP LOCKED
+), fanned_out AS (
+  INSERT INTO webhook_deliveries (endpoint_id, event_id)
+  SELECT ep.id, ev.id
+  FROM webhook_events ev
+  JOIN due ON due.id = ev.id
+  JOIN webhook_endpoints ep ON ep.user_id = ev.user_id AND ep.active AND ev.event_type = ANY(ep.events)
+)
+UPDATE webhook_events
+SET dispatched_at = now()
+WHERE id IN (SELECT id FROM due);
+
+-- name: ClaimDueWebhookDeliveries :many
+WITH due AS (
+  SELECT d.id FROM webhook_deliveries d
+  JOIN webhook_endpoints ep ON ep.id = d.endpoint_id
+  WHERE d.status = 'pending' AND d.next_attempt_at <= now() AND ep.active
+  ORDER BY d.next_attempt_at
+  LIMIT sqlc.arg(batch_size)
+  FOR UPDATE OF d SKIP LOCKED
+)
+UPDATE webhook_deliveries d
+SET next_attempt_at = now() + make_interval(secs => sqlc.arg(lease_seconds)::int)
+FROM due, webhook_endpoints ep, webhook_events ev
+WHERE d.id = due.id AND ep.id = d.endpoint_id AND ev.id = d.event_id
+RETURNING d.id, d.endpoint_id, d.event_id, d.attempts, ep.url, ep.secret,
+  ev.event_type, ev.payload, ev.created_at AS event_created_at;
+
+-- name: CompleteWebhookDelivery :exec
+UPDATE webhook_deliveries
+SET status = 'succeeded',
+  attempts = attempts + 1,
+  response_status = $2,
+  last_error = '',
+  delivered_at = now()
+WHERE id = $1;
+
+-- name: RetryWebhookDelivery :exec
+UPDATE webhook_deliveries
+SET attempts = attempts + 1,
+  response_status = $2,
+  last_error = $3,
+  next_attempt_at = $4
+WHERE id = $1;
+
+-- name: FailWebhookDelivery :exec
+UPDATE webhook_deliveries
+SET status = 'failed',
+  attempts = attempts + 1,
+  response_status = $2,
+  last_error = $3
+WHERE id = $1;
+
+-- name: GetWebhookDelivery :one
+SELECT * FROM webhook_deliveries
+WHERE id = $1 LIMIT 1;
+
+-- name: ListWebhookDeliveries :many
+SELECT d.*, ev.event_type
+FROM webhook_deliveries d
+JOIN webhook_events ev ON ev.id = d.event_id
+WHERE d.endpoint_id = sqlc.arg(endpoint_id)
+AND (sqlc.narg(cursor_created_at)::timestamptz IS NULL
+  OR (d.created_at, d.id) < (sqlc.narg(cursor_created_at)::timestamptz, sqlc.narg(cursor_id)::bigint))
+ORDER BY d.created_at DESC, d.id DESC
+LIMIT sqlc.arg(page_limit);
+
+-- name: RedeliverWebhookDelivery :one
+INSERT INTO webhook_deliveries (
+  endpoint_id,
+  event_id
+)
+SELECT endpoint_id, event_id FROM webhook_deliveries
+WHERE webhook_deliveries.id = $1
+RETURNING *;
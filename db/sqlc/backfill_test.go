@@ -0,0 +1,33 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/imrishuroy/read-cache-api/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFinishBackfill(t *testing.T) {
+	name := "test_" + util.RandomString(8)
+
+	_, err := testStore.GetBackfill(context.Background(), name)
+	require.ErrorIs(t, err, ErrRecordNotFound)
+
+	err = testStore.FinishBackfill(context.Background(), name)
+	require.NoError(t, err)
+
+	backfill, err := testStore.GetBackfill(context.Background(), name)
+	require.NoError(t, err)
+	require.Equal(t, name, backfill.Name)
+	require.WithinDuration(t, time.Now(), backfill.FinishedAt, time.Minute)
+
+	// finishing it again keeps the first run
+	err = testStore.FinishBackfill(context.Background(), name)
+	require.NoError(t, err)
+
+	again, err := testStore.GetBackfill(context.Background(), name)
+	require.NoError(t, err)
+	require.Equal(t, backfill.FinishedAt, again.FinishedAt)
+}
@@ -16,17 +16,19 @@ INSERT INTO caches (
   owner,
   title, 
   content,
-  is_public
+  is_public,
+  normalized_url
 ) VALUES (
-  $1, $2, $3, $4
-) RETURNING id, owner, title, content, created_at, is_public
+  $1, $2, $3, $4, $5
+) RETURNING id, owner, title, content, created_at, is_public, normalized_url, status, progress, read_at, archived_at, like_count, updated_at, search_vector, saved_at
 `
 
 type CreateCacheParams struct {
-	Owner    string      `json:"owner"`
-	Title    string      `json:"title"`
-	Content  string      `json:"content"`
-	IsPublic pgtype.Bool `json:"is_public"`
+	Owner         string      `json:"owner"`
+	Title         string      `json:"title"`
+	Content       string      `json:"content"`
+	IsPublic      pgtype.Bool `json:"is_public"`
+	NormalizedUrl string      `json:"normalized_url"`
 }
 
 func (q *Queries) CreateCache(ctx context.Context, arg CreateCacheParams) (Cache, error) {
@@ -35,6 +37,7 @@ func (q *Queries) CreateCache(ctx context.Context, arg CreateCacheParams) (Cache
 		arg.Title,
 		arg.Content,
 		arg.IsPublic,
+		arg.NormalizedUrl,
 	)
 	var i Cache
 	err := row.Scan(
@@ -44,6 +47,15 @@ func (q *Queries) CreateCache(ctx context.Context, arg CreateCacheParams) (Cache
 		&i.Content,
 		&i.CreatedAt,
 		&i.IsPublic,
+		&i.NormalizedUrl,
+		&i.Status,
+		&i.Progress,
+		&i.ReadAt,
+		&i.ArchivedAt,
+		&i.LikeCount,
+		&i.UpdatedAt,
+		&i.SearchVector,
+		&i.SavedAt,
 	)
 	return i, err
 }
@@ -59,7 +71,7 @@ func (q *Queries) DeleteCache(ctx context.Context, id int64) error {
 }
 
 const getCache = `-- name: GetCache :one
-SELECT id, owner, title, content, created_at, is_public FROM caches
+SELECT id, owner, title, content, created_at, is_public, normalized_url, status, progress, read_at, archived_at, like_count, updated_at, search_vector, saved_at FROM caches
 WHERE id = $1 LIMIT 1
 `
 
@@ -73,26 +85,99 @@ func (q *Queries) GetCache(ctx context.Context, id int64) (Cache, error) {
 		&i.Content,
 		&i.CreatedAt,
 		&i.IsPublic,
+		&i.NormalizedUrl,
+		&i.Status,
+		&i.Progress,
+		&i.ReadAt,
+		&i.ArchivedAt,
+		&i.LikeCount,
+		&i.UpdatedAt,
+		&i.SearchVector,
+		&i.SavedAt,
+	)
+	return i, err
+}
+
+const getCacheByNormalizedURL = `-- name: GetCacheByNormalizedURL :one
+SELECT id, owner, title, content, created_at, is_public, normalized_url, status, progress, read_at, archived_at, like_count, updated_at, search_vector, saved_at FROM caches
+WHERE owner = $1 AND normalized_url = $2 LIMIT 1
+`
+
+type GetCacheByNormalizedURLParams struct {
+	Owner         string `json:"owner"`
+	NormalizedUrl string `json:"normalized_url"`
+}
+
+func (q *Queries) GetCacheByNormalizedURL(ctx context.Context, arg GetCacheByNormalizedURLParams) (Cache, error) {
+	row := q.db.QueryRow(ctx, getCacheByNormalizedURL, arg.Owner, arg.NormalizedUrl)
+	var i Cache
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Title,
+		&i.Content,
+		&i.CreatedAt,
+		&i.IsPublic,
+		&i.NormalizedUrl,
+		&i.Status,
+		&i.Progress,
+		&i.ReadAt,
+		&i.ArchivedAt,
+		&i.LikeCount,
+		&i.UpdatedAt,
+		&i.SearchVector,
+		&i.SavedAt,
 	)
 	return i, err
 }
 
 const listCaches = `-- name: ListCaches :many
-SELECT id, owner, title, content, created_at, is_public FROM caches
-WHERE owner =$1
-ORDER BY created_at DESC
+SELECT c.id, c.owner, c.title, c.content, c.created_at, c.is_public, c.normalized_url, c.status, c.progress, c.read_at, c.archived_at, c.like_count, c.updated_at, c.search_vector, c.saved_at FROM caches c
+WHERE c.owner = $1
+AND (coalesce(cardinality($4::int[]), 0) = 0
+  OR ($5::boolean AND (
+    SELECT count(*) FROM cache_tags ct
+    WHERE ct.cache_id = c.id AND ct.tag_id = ANY($4::int[])
+  ) = cardinality($4::int[]))
+  OR (NOT $5::boolean AND EXISTS (
+    SELECT 1 FROM cache_tags ct
+    WHERE ct.cache_id = c.id AND ct.tag_id = ANY($4::int[])
+  )))
+AND NOT EXISTS (
+  SELECT 1 FROM cache_tags ct
+  WHERE ct.cache_id = c.id AND ct.tag_id = ANY($6::int[])
+)
+AND (CASE
+  WHEN $7::reading_status IS NOT NULL THEN c.status = $7::reading_status
+  ELSE $8::boolean OR c.status <> 'archived'
+END)
+ORDER BY c.created_at DESC, c.id DESC
 LIMIT $2
 OFFSET $3
 `
 
 type ListCachesParams struct {
-	Owner  string `json:"owner"`
-	Limit  int32  `json:"limit"`
-	Offset int32  `json:"offset"`
+	Owner           string            `json:"owner"`
+	Limit           int32             `json:"limit"`
+	Offset          int32             `json:"offset"`
+	TagIds          []int32           `json:"tag_ids"`
+	MatchAll        bool              `json:"match_all"`
+	ExcludeTagIds   []int32           `json:"exclude_tag_ids"`
+	Status          NullReadingStatus `json:"status"`
+	IncludeArchived bool              `json:"include_archived"`
 }
 
 func (q *Queries) ListCaches(ctx context.Context, arg ListCachesParams) ([]Cache, error) {
-	rows, err := q.db.Query(ctx, listCaches, arg.Owner, arg.Limit, arg.Offset)
+	rows, err := q.db.Query(ctx, listCaches,
+		arg.Owner,
+		arg.Limit,
+		arg.Offset,
+		arg.TagIds,
+		arg.MatchAll,
+		arg.ExcludeTagIds,
+		arg.Status,
+		arg.IncludeArchived,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -107,6 +192,15 @@ func (q *Queries) ListCaches(ctx context.Context, arg ListCachesParams) ([]Cache
 			&i.Content,
 			&i.CreatedAt,
 			&i.IsPublic,
+			&i.NormalizedUrl,
+			&i.Status,
+			&i.Progress,
+			&i.ReadAt,
+			&i.ArchivedAt,
+			&i.LikeCount,
+			&i.UpdatedAt,
+			&i.SearchVector,
+			&i.SavedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -118,10 +212,134 @@ func (q *Queries) ListCaches(ctx context.Context, arg ListCachesParams) ([]Cache
 	return items, nil
 }
 
+const listCachesByCursor = `-- name: ListCachesByCursor :many
+SELECT c.id, c.owner, c.title, c.content, c.created_at, c.is_public, c.normalized_url, c.status, c.progress, c.read_at, c.archived_at, c.like_count, c.updated_at, c.search_vector, c.saved_at FROM caches c
+WHERE c.owner = $1
+AND (coalesce(cardinality($2::int[]), 0) = 0
+  OR ($3::boolean AND (
+    SELECT count(*) FROM cache_tags ct
+    WHERE ct.cache_id = c.id AND ct.tag_id = ANY($2::int[])
+  ) = cardinality($2::int[]))
+  OR (NOT $3::boolean AND EXISTS (
+    SELECT 1 FROM cache_tags ct
+    WHERE ct.cache_id = c.id AND ct.tag_id = ANY($2::int[])
+  )))
+AND NOT EXISTS (
+  SELECT 1 FROM cache_tags ct
+  WHERE ct.cache_id = c.id AND ct.tag_id = ANY($4::int[])
+)
+AND (CASE
+  WHEN $5::reading_status IS NOT NULL THEN c.status = $5::reading_status
+  ELSE $6::boolean OR c.status <> 'archived'
+END)
+AND ($7::timestamptz IS NULL
+  OR (c.created_at, c.id) < ($7::timestamptz, $8::bigint))
+ORDER BY c.created_at DESC, c.id DESC
+LIMIT $9
+`
+
+type ListCachesByCursorParams struct {
+	Owner           string             `json:"owner"`
+	TagIds          []int32            `json:"tag_ids"`
+	MatchAll        bool               `json:"match_all"`
+	ExcludeTagIds   []int32            `json:"exclude_tag_ids"`
+	Status          NullReadingStatus  `json:"status"`
+	IncludeArchived bool               `json:"include_archived"`
+	CursorCreatedAt pgtype.Timestamptz `json:"cursor_created_at"`
+	CursorID        pgtype.Int8        `json:"cursor_id"`
+	PageLimit       int32              `json:"page_limit"`
+}
+
+func (q *Queries) ListCachesByCursor(ctx context.Context, arg ListCachesByCursorParams) ([]Cache, error) {
+	rows, err := q.db.Query(ctx, listCachesByCursor,
+		arg.Owner,
+		arg.TagIds,
+		arg.MatchAll,
+		arg.ExcludeTagIds,
+		arg.Status,
+		arg.IncludeArchived,
+		arg.CursorCreatedAt,
+		arg.CursorID,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Cache{}
+	for rows.Next() {
+		var i Cache
+		if err := rows.Scan(
+			&i.ID,
+			&i.Owner,
+			&i.Title,
+			&i.Content,
+			&i.CreatedAt,
+			&i.IsPublic,
+			&i.NormalizedUrl,
+			&i.Status,
+			&i.Progress,
+			&i.ReadAt,
+			&i.ArchivedAt,
+			&i.LikeCount,
+			&i.UpdatedAt,
+			&i.SearchVector,
+			&i.SavedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listCachesWithoutNormalizedURL = `-- name: ListCachesWithoutNormalizedURL :many
+SELECT id, content FROM caches
+WHERE normalized_url = ''
+AND content ~* '^\s*https?://\S+\s*$'
+AND id > $1
+ORDER BY id
+LIMIT $2
+`
+
+type ListCachesWithoutNormalizedURLParams struct {
+	AfterID   int64 `json:"after_id"`
+	PageLimit int32 `json:"page_limit"`
+}
+
+type ListCachesWithoutNormalizedURLRow struct {
+	ID      int64  `json:"id"`
+	Content string `json:"content"`
+}
+
+func (q *Queries) ListCachesWithoutNormalizedURL(ctx context.Context, arg ListCachesWithoutNormalizedURLParams) ([]ListCachesWithoutNormalizedURLRow, error) {
+	rows, err := q.db.Query(ctx, listCachesWithoutNormalizedURL, arg.AfterID, arg.PageLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListCachesWithoutNormalizedURLRow{}
+	for rows.Next() {
+		var i ListCachesWithoutNormalizedURLRow
+		if err := rows.Scan(&i.ID, &i.Content); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listPublicCaches = `-- name: ListPublicCaches :many
-SELECT c.id, c.owner, c.title, c.content, c.created_at, c.is_public
+SELECT c.id, c.owner, c.title, c.content, c.created_at, c.is_public, c.normalized_url, c.status, c.progress, c.read_at, c.archived_at, c.like_count, c.updated_at, c.search_vector, c.saved_at
 FROM caches c
 WHERE c.is_public = TRUE
+ORDER BY c.created_at DESC, c.id DESC
 LIMIT $1
 OFFSET $2
 `
@@ -147,6 +365,67 @@ func (q *Queries) ListPublicCaches(ctx context.Context, arg ListPublicCachesPara
 			&i.Content,
 			&i.CreatedAt,
 			&i.IsPublic,
+			&i.NormalizedUrl,
+			&i.Status,
+			&i.Progress,
+			&i.ReadAt,
+			&i.ArchivedAt,
+			&i.LikeCount,
+			&i.UpdatedAt,
+			&i.SearchVector,
+			&i.SavedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPublicCachesByCursor = `-- name: ListPublicCachesByCursor :many
+SELECT c.id, c.owner, c.title, c.content, c.created_at, c.is_public, c.normalized_url, c.status, c.progress, c.read_at, c.archived_at, c.like_count, c.updated_at, c.search_vector, c.saved_at
+FROM caches c
+WHERE c.is_public = TRUE
+AND ($1::timestamptz IS NULL
+  OR (c.created_at, c.id) < ($1::timestamptz, $2::bigint))
+ORDER BY c.created_at DESC, c.id DESC
+LIMIT $3
+`
+
+type ListPublicCachesByCursorParams struct {
+	CursorCreatedAt pgtype.Timestamptz `json:"cursor_created_at"`
+	CursorID        pgtype.Int8        `json:"cursor_id"`
+	PageLimit       int32              `json:"page_limit"`
+}
+
+func (q *Queries) ListPublicCachesByCursor(ctx context.Context, arg ListPublicCachesByCursorParams) ([]Cache, error) {
+	rows, err := q.db.Query(ctx, listPublicCachesByCursor, arg.CursorCreatedAt, arg.CursorID, arg.PageLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Cache{}
+	for rows.Next() {
+		var i Cache
+		if err := rows.Scan(
+			&i.ID,
+			&i.Owner,
+			&i.Title,
+			&i.Content,
+			&i.CreatedAt,
+			&i.IsPublic,
+			&i.NormalizedUrl,
+			&i.Status,
+			&i.Progress,
+			&i.ReadAt,
+			&i.ArchivedAt,
+			&i.LikeCount,
+			&i.UpdatedAt,
+			&i.SearchVector,
+			&i.SavedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -159,24 +438,43 @@ func (q *Queries) ListPublicCaches(ctx context.Context, arg ListPublicCachesPara
 }
 
 const listPublicCachesByTags = `-- name: ListPublicCachesByTags :many
-SELECT c.id, c.owner, c.title, c.content, c.created_at, c.is_public
+SELECT c.id, c.owner, c.title, c.content, c.created_at, c.is_public, c.normalized_url, c.status, c.progress, c.read_at, c.archived_at, c.like_count, c.updated_at, c.search_vector, c.saved_at
 FROM caches c
-JOIN cache_tags ct ON c.id = ct.cache_id
-JOIN tags t ON ct.tag_id = t.tag_id
 WHERE c.is_public = TRUE
-AND t.tag_id = ANY($3::int[])
+AND (coalesce(cardinality($3::int[]), 0) = 0
+  OR ($4::boolean AND (
+    SELECT count(*) FROM cache_tags ct
+    WHERE ct.cache_id = c.id AND ct.tag_id = ANY($3::int[])
+  ) = cardinality($3::int[]))
+  OR (NOT $4::boolean AND EXISTS (
+    SELECT 1 FROM cache_tags ct
+    WHERE ct.cache_id = c.id AND ct.tag_id = ANY($3::int[])
+  )))
+AND NOT EXISTS (
+  SELECT 1 FROM cache_tags ct
+  WHERE ct.cache_id = c.id AND ct.tag_id = ANY($5::int[])
+)
+ORDER BY c.created_at DESC, c.id DESC
 LIMIT $1
 OFFSET $2
 `
 
 type ListPublicCachesByTagsParams struct {
-	Limit  int32   `json:"limit"`
-	Offset int32   `json:"offset"`
-	TagIds []int32 `json:"tag_ids"`
+	Limit         int32   `json:"limit"`
+	Offset        int32   `json:"offset"`
+	TagIds        []int32 `json:"tag_ids"`
+	MatchAll      bool    `json:"match_all"`
+	ExcludeTagIds []int32 `json:"exclude_tag_ids"`
 }
 
 func (q *Queries) ListPublicCachesByTags(ctx context.Context, arg ListPublicCachesByTagsParams) ([]Cache, error) {
-	rows, err := q.db.Query(ctx, listPublicCachesByTags, arg.Limit, arg.Offset, arg.TagIds)
+	rows, err := q.db.Query(ctx, listPublicCachesByTags,
+		arg.Limit,
+		arg.Offset,
+		arg.TagIds,
+		arg.MatchAll,
+		arg.ExcludeTagIds,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -191,6 +489,240 @@ func (q *Queries) ListPublicCachesByTags(ctx context.Context, arg ListPublicCach
 			&i.Content,
 			&i.CreatedAt,
 			&i.IsPublic,
+			&i.NormalizedUrl,
+			&i.Status,
+			&i.Progress,
+			&i.ReadAt,
+			&i.ArchivedAt,
+			&i.LikeCount,
+			&i.UpdatedAt,
+			&i.SearchVector,
+			&i.SavedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPublicCachesByTagsCursor = `-- name: ListPublicCachesByTagsCursor :many
+SELECT c.id, c.owner, c.title, c.content, c.created_at, c.is_public, c.normalized_url, c.status, c.progress, c.read_at, c.archived_at, c.like_count, c.updated_at, c.search_vector, c.saved_at
+FROM caches c
+WHERE c.is_public = TRUE
+AND (coalesce(cardinality($1::int[]), 0) = 0
+  OR ($2::boolean AND (
+    SELECT count(*) FROM cache_tags ct
+    WHERE ct.cache_id = c.id AND ct.tag_id = ANY($1::int[])
+  ) = cardinality($1::int[]))
+  OR (NOT $2::boolean AND EXISTS (
+    SELECT 1 FROM cache_tags ct
+    WHERE ct.cache_id = c.id AND ct.tag_id = ANY($1::int[])
+  )))
+AND NOT EXISTS (
+  SELECT 1 FROM cache_tags ct
+  WHERE ct.cache_id = c.id AND ct.tag_id = ANY($3::int[])
+)
+AND ($4::timestamptz IS NULL
+  OR (c.created_at, c.id) < ($4::timestamptz, $5::bigint))
+ORDER BY c.created_at DESC, c.id DESC
+LIMIT $6
+`
+
+type ListPublicCachesByTagsCursorParams struct {
+	TagIds          []int32            `json:"tag_ids"`
+	MatchAll        bool               `json:"match_all"`
+	ExcludeTagIds   []int32            `json:"exclude_tag_ids"`
+	CursorCreatedAt pgtype.Timestamptz `json:"cursor_created_at"`
+	CursorID        pgtype.Int8        `json:"cursor_id"`
+	PageLimit       int32              `json:"page_limit"`
+}
+
+func (q *Queries) ListPublicCachesByTagsCursor(ctx context.Context, arg ListPublicCachesByTagsCursorParams) ([]Cache, error) {
+	rows, err := q.db.Query(ctx, listPublicCachesByTagsCursor,
+		arg.TagIds,
+		arg.MatchAll,
+		arg.ExcludeTagIds,
+		arg.CursorCreatedAt,
+		arg.CursorID,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Cache{}
+	for rows.Next() {
+		var i Cache
+		if err := rows.Scan(
+			&i.ID,
+			&i.Owner,
+			&i.Title,
+			&i.Content,
+			&i.CreatedAt,
+			&i.IsPublic,
+			&i.NormalizedUrl,
+			&i.Status,
+			&i.Progress,
+			&i.ReadAt,
+			&i.ArchivedAt,
+			&i.LikeCount,
+			&i.UpdatedAt,
+			&i.SearchVector,
+			&i.SavedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const searchCaches = `-- name: SearchCaches :many
+SELECT c.id, c.owner, c.title, c.content, c.created_at, c.is_public, c.normalized_url, c.status, c.progress, c.read_at, c.archived_at, c.like_count, c.updated_at, c.search_vector, c.saved_at,
+  ts_rank(c.search_vector, to_tsquery('english', $1))::real AS rank,
+  ts_headline('english', html_escape(c.title), to_tsquery('english', $1),
+    'StartSel=<mark>, StopSel=</mark>, HighlightAll=true')::text AS title_highlight,
+  ts_headline('english', html_escape(c.content), to_tsquery('english', $1),
+    'StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MaxWords=30, MinWords=10')::text AS content_snippet
+FROM caches c
+WHERE c.search_vector @@ to_tsquery('english', $1)
+AND (c.owner = $2 OR c.is_public = TRUE)
+ORDER BY rank DESC, c.id DESC
+LIMIT $3
+OFFSET $4
+`
+
+type SearchCachesParams struct {
+	Query      string `json:"query"`
+	Owner      string `json:"owner"`
+	PageLimit  int32  `json:"page_limit"`
+	PageOffset int32  `json:"page_offset"`
+}
+
+type SearchCachesRow struct {
+	Cache          Cache   `json:"cache"`
+	Rank           float32 `json:"rank"`
+	TitleHighlight string  `json:"title_highlight"`
+	ContentSnippet string  `json:"content_snippet"`
+}
+
+func (q *Queries) SearchCaches(ctx context.Context, arg SearchCachesParams) ([]SearchCachesRow, error) {
+	rows, err := q.db.Query(ctx, searchCaches,
+		arg.Query,
+		arg.Owner,
+		arg.PageLimit,
+		arg.PageOffset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []SearchCachesRow{}
+	for rows.Next() {
+		var i SearchCachesRow
+		if err := rows.Scan(
+			&i.Cache.ID,
+			&i.Cache.Owner,
+			&i.Cache.Title,
+			&i.Cache.Content,
+			&i.Cache.CreatedAt,
+			&i.Cache.IsPublic,
+			&i.Cache.NormalizedUrl,
+			&i.Cache.Status,
+			&i.Cache.Progress,
+			&i.Cache.ReadAt,
+			&i.Cache.ArchivedAt,
+			&i.Cache.LikeCount,
+			&i.Cache.UpdatedAt,
+			&i.Cache.SearchVector,
+			&i.Cache.SavedAt,
+			&i.Rank,
+			&i.TitleHighlight,
+			&i.ContentSnippet,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const searchCachesByCursor = `-- name: SearchCachesByCursor :many
+SELECT c.id, c.owner, c.title, c.content, c.created_at, c.is_public, c.normalized_url, c.status, c.progress, c.read_at, c.archived_at, c.like_count, c.updated_at, c.search_vector, c.saved_at,
+  ts_rank(c.search_vector, to_tsquery('english', $1))::real AS rank,
+  ts_headline('english', html_escape(c.title), to_tsquery('english', $1),
+    'StartSel=<mark>, StopSel=</mark>, HighlightAll=true')::text AS title_highlight,
+  ts_headline('english', html_escape(c.content), to_tsquery('english', $1),
+    'StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MaxWords=30, MinWords=10')::text AS content_snippet
+FROM caches c
+WHERE c.search_vector @@ to_tsquery('english', $1)
+AND (c.owner = $2 OR c.is_public = TRUE)
+AND ($3::real IS NULL
+  OR (ts_rank(c.search_vector, to_tsquery('english', $1))::real, c.id)
+    < ($3::real, $4::bigint))
+ORDER BY rank DESC, c.id DESC
+LIMIT $5
+`
+
+type SearchCachesByCursorParams struct {
+	Query      string        `json:"query"`
+	Owner      string        `json:"owner"`
+	CursorRank pgtype.Float4 `json:"cursor_rank"`
+	CursorID   pgtype.Int8   `json:"cursor_id"`
+	PageLimit  int32         `json:"page_limit"`
+}
+
+type SearchCachesByCursorRow struct {
+	Cache          Cache   `json:"cache"`
+	Rank           float32 `json:"rank"`
+	TitleHighlight string  `json:"title_highlight"`
+	ContentSnippet string  `json:"content_snippet"`
+}
+
+func (q *Queries) SearchCachesByCursor(ctx context.Context, arg SearchCachesByCursorParams) ([]SearchCachesByCursorRow, error) {
+	rows, err := q.db.Query(ctx, searchCachesByCursor,
+		arg.Query,
+		arg.Owner,
+		arg.CursorRank,
+		arg.CursorID,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []SearchCachesByCursorRow{}
+	for rows.Next() {
+		var i SearchCachesByCursorRow
+		if err := rows.Scan(
+			&i.Cache.ID,
+			&i.Cache.Owner,
+			&i.Cache.Title,
+			&i.Cache.Content,
+			&i.Cache.CreatedAt,
+			&i.Cache.IsPublic,
+			&i.Cache.NormalizedUrl,
+			&i.Cache.Status,
+			&i.Cache.Progress,
+			&i.Cache.ReadAt,
+			&i.Cache.ArchivedAt,
+			&i.Cache.LikeCount,
+			&i.Cache.UpdatedAt,
+			&i.Cache.SearchVector,
+			&i.Cache.SavedAt,
+			&i.Rank,
+			&i.TitleHighlight,
+			&i.ContentSnippet,
 		); err != nil {
 			return nil, err
 		}
@@ -206,16 +738,19 @@ const updateCache = `-- name: UpdateCache :one
 UPDATE caches
 SET title = $2,
     content = $3,
-    is_public = $4
+    is_public = $4,
+    normalized_url = $5,
+    updated_at = now()
 WHERE id = $1
-RETURNING id, owner, title, content, created_at, is_public
+RETURNING id, owner, title, content, created_at, is_public, normalized_url, status, progress, read_at, archived_at, like_count, updated_at, search_vector, saved_at
 `
 
 type UpdateCacheParams struct {
-	ID       int64       `json:"id"`
-	Title    string      `json:"title"`
-	Content  string      `json:"content"`
-	IsPublic pgtype.Bool `json:"is_public"`
+	ID            int64       `json:"id"`
+	Title         string      `json:"title"`
+	Content       string      `json:"content"`
+	IsPublic      pgtype.Bool `json:"is_public"`
+	NormalizedUrl string      `json:"normalized_url"`
 }
 
 func (q *Queries) UpdateCache(ctx context.Context, arg UpdateCacheParams) (Cache, error) {
@@ -224,7 +759,121 @@ func (q *Queries) UpdateCache(ctx context.Context, arg UpdateCacheParams) (Cache
 		arg.Title,
 		arg.Content,
 		arg.IsPublic,
+		arg.NormalizedUrl,
+	)
+	var i Cache
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Title,
+		&i.Content,
+		&i.CreatedAt,
+		&i.IsPublic,
+		&i.NormalizedUrl,
+		&i.Status,
+		&i.Progress,
+		&i.ReadAt,
+		&i.ArchivedAt,
+		&i.LikeCount,
+		&i.UpdatedAt,
+		&i.SearchVector,
+		&i.SavedAt,
+	)
+	return i, err
+}
+
+const updateCacheNormalizedURL = `-- name: UpdateCacheNormalizedURL :exec
+UPDATE caches c
+SET normalized_url = $2
+WHERE c.id = $1 AND NOT EXISTS (
+  SELECT 1 FROM caches d
+  WHERE d.owner = c.owner AND d.normalized_url = $2 AND d.id <> c.id
+)
+`
+
+type UpdateCacheNormalizedURLParams struct {
+	ID            int64  `json:"id"`
+	NormalizedUrl string `json:"normalized_url"`
+}
+
+func (q *Queries) UpdateCacheNormalizedURL(ctx context.Context, arg UpdateCacheNormalizedURLParams) error {
+	_, err := q.db.Exec(ctx, updateCacheNormalizedURL, arg.ID, arg.NormalizedUrl)
+	return err
+}
+
+const updateCacheProgress = `-- name: UpdateCacheProgress :one
+UPDATE caches
+SET progress = $1::int,
+    status = CASE
+      WHEN status = 'archived' THEN status
+      WHEN $1::int >= 100 THEN 'read'
+      WHEN $1::int > 0 AND status = 'unread' THEN 'reading'
+      ELSE status
+    END,
+    read_at = CASE
+      WHEN status <> 'archived' AND $1::int >= 100 THEN coalesce(read_at, now())
+      ELSE read_at
+    END
+WHERE id = $2
+RETURNING id, owner, title, content, created_at, is_public, normalized_url, status, progress, read_at, archived_at, like_count, updated_at, search_vector, saved_at
+`
+
+type UpdateCacheProgressParams struct {
+	Progress int32 `json:"progress"`
+	ID       int64 `json:"id"`
+}
+
+func (q *Queries) UpdateCacheProgress(ctx context.Context, arg UpdateCacheProgressParams) (Cache, error) {
+	row := q.db.QueryRow(ctx, updateCacheProgress, arg.Progress, arg.ID)
+	var i Cache
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Title,
+		&i.Content,
+		&i.CreatedAt,
+		&i.IsPublic,
+		&i.NormalizedUrl,
+		&i.Status,
+		&i.Progress,
+		&i.ReadAt,
+		&i.ArchivedAt,
+		&i.LikeCount,
+		&i.UpdatedAt,
+		&i.SearchVector,
+		&i.SavedAt,
 	)
+	return i, err
+}
+
+const updateCacheStatus = `-- name: UpdateCacheStatus :one
+UPDATE caches
+SET status = $1::reading_status,
+    read_at = CASE
+      WHEN $1::reading_status = 'read' THEN coalesce(read_at, now())
+      WHEN $1::reading_status = 'unread' THEN NULL
+      ELSE read_at
+    END,
+    archived_at = CASE
+      WHEN $1::reading_status = 'archived' THEN coalesce(archived_at, now())
+      ELSE NULL
+    END,
+    progress = CASE
+      WHEN $1::reading_status = 'read' THEN 100
+      WHEN $1::reading_status = 'unread' THEN 0
+      ELSE progress
+    END
+WHERE id = $2
+RETURNING id, owner, title, content, created_at, is_public, normalized_url, status, progress, read_at, archived_at, like_count, updated_at, search_vector, saved_at
+`
+
+type UpdateCacheStatusParams struct {
+	Status ReadingStatus `json:"status"`
+	ID     int64         `json:"id"`
+}
+
+func (q *Queries) UpdateCacheStatus(ctx context.Context, arg UpdateCacheStatusParams) (Cache, error) {
+	row := q.db.QueryRow(ctx, updateCacheStatus, arg.Status, arg.ID)
 	var i Cache
 	err := row.Scan(
 		&i.ID,
@@ -233,6 +882,15 @@ func (q *Queries) UpdateCache(ctx context.Context, arg UpdateCacheParams) (Cache
 		&i.Content,
 		&i.CreatedAt,
 		&i.IsPublic,
+		&i.NormalizedUrl,
+		&i.Status,
+		&i.Progress,
+		&i.ReadAt,
+		&i.ArchivedAt,
+		&i.LikeCount,
+		&i.UpdatedAt,
+		&i.SearchVector,
+		&i.SavedAt,
 	)
 	return i, err
 }
@@ -0,0 +1,117 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/imrishuroy/read-cache-api/util"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLikeCacheTx(t *testing.T) {
+	cache := createRandomCache(t)
+	user := createRandomUser(t)
+	arg := LikeCacheTxParams{CacheID: cache.ID, UserID: user.ID}
+
+	result, err := testStore.LikeCacheTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, int32(1), result.LikeCount)
+
+	// liking twice keeps one like
+	result, err = testStore.LikeCacheTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, int32(1), result.LikeCount)
+
+	liked, err := testStore.ListLikedCacheIDs(context.Background(), ListLikedCacheIDsParams{
+		UserID:   user.ID,
+		CacheIds: []int64{cache.ID},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []int64{cache.ID}, liked)
+
+	result, err = testStore.UnlikeCacheTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Zero(t, result.LikeCount)
+
+	result, err = testStore.UnlikeCacheTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Zero(t, result.LikeCount)
+}
+
+func TestLikeCacheTxConcurrent(t *testing.T) {
+	cache := createRandomCache(t)
+
+	n := 5
+	errs := make(chan error, n)
+	for i := 0; i < n; i++ {
+		user := createRandomUser(t)
+		go func() {
+			_, err := testStore.LikeCacheTx(context.Background(), LikeCacheTxParams{
+				CacheID: cache.ID,
+				UserID:  user.ID,
+			})
+			errs <- err
+		}()
+	}
+	for i := 0; i < n; i++ {
+		require.NoError(t, <-errs)
+	}
+
+	updated, err := testStore.GetCache(context.Background(), cache.ID)
+	require.NoError(t, err)
+	require.Equal(t, int32(n), updated.LikeCount)
+}
+
+func TestListMostLikedPublicCaches(t *testing.T) {
+	owner := createRandomUser(t)
+
+	var caches []Cache
+	for i := 0; i < 2; i++ {
+		cache, err := testStore.CreateCache(context.Background(), CreateCacheParams{
+			Owner:    owner.ID,
+			Title:    util.RandomTitle(),
+			Content:  util.RandomContent(),
+			IsPublic: pgtype.Bool{Bool: true, Valid: true},
+		})
+		require.NoError(t, err)
+		caches = append(caches, cache)
+	}
+
+	// the second cache gets more likes than the first
+	for i, cache := range caches {
+		for j := 0; j <= i; j++ {
+			_, err := testStore.LikeCacheTx(context.Background(), LikeCacheTxParams{
+				CacheID: cache.ID,
+				UserID:  createRandomUser(t).ID,
+			})
+			require.NoError(t, err)
+		}
+	}
+
+	rows, err := testStore.ListMostLikedPublicCaches(context.Background(), ListMostLikedPublicCachesParams{
+		LikedSince: time.Now().Add(-7 * 24 * time.Hour),
+		PageLimit:  1000,
+	})
+	require.NoError(t, err)
+
+	position := map[int64]int{}
+	for i, row := range rows {
+		position[row.Cache.ID] = i
+		if i > 0 {
+			require.LessOrEqual(t, row.RecentLikes, rows[i-1].RecentLikes)
+		}
+	}
+	require.Contains(t, position, caches[0].ID)
+	require.Contains(t, position, caches[1].ID)
+	require.Less(t, position[caches[1].ID], position[caches[0].ID])
+
+	// likes older than the window don't count
+	rows, err = testStore.ListMostLikedPublicCaches(context.Background(), ListMostLikedPublicCachesParams{
+		LikedSince: time.Now().Add(time.Hour),
+		PageLimit:  1000,
+	})
+	require.NoError(t, err)
+	require.Empty(t, rows)
+}
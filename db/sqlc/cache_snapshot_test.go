@@ -0,0 +1,47 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacheSnapshotLifecycle(t *testing.T) {
+	preview := createRandomLinkPreview(t)
+
+	// cache creation queued the snapshot next to the preview
+	snapshot, err := testStore.GetCacheSnapshot(context.Background(), preview.CacheID)
+	require.NoError(t, err)
+	require.Equal(t, "pending", snapshot.Status)
+	require.Equal(t, preview.Url, snapshot.Url)
+	require.Empty(t, snapshot.Images)
+
+	arg := CompleteCacheSnapshotParams{
+		CacheID: snapshot.CacheID,
+		Title:   "Archived",
+		Html:    "<p>archived text</p>",
+		Text:    "archived text",
+		Images:  []string{"https://example.com/cover.png"},
+	}
+	err = testStore.CompleteCacheSnapshot(context.Background(), arg)
+	require.NoError(t, err)
+
+	done, err := testStore.GetCacheSnapshot(context.Background(), snapshot.CacheID)
+	require.NoError(t, err)
+	require.Equal(t, "done", done.Status)
+	require.Equal(t, arg.Images, done.Images)
+	require.True(t, done.ArchivedAt.Valid)
+
+	// re-snapshotting resets the retry state but keeps the previous content
+	requested, err := testStore.RequestCacheSnapshot(context.Background(), RequestCacheSnapshotParams{
+		CacheID: snapshot.CacheID,
+		Url:     snapshot.Url,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "pending", requested.Status)
+	require.Zero(t, requested.Attempts)
+	require.Equal(t, arg.Text, requested.Text)
+	require.WithinDuration(t, time.Now(), requested.NextAttemptAt, time.Minute)
+}
@@ -2,10 +2,12 @@ package db
 
 import (
 	"context"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/imrishuroy/read-cache-api/util"
+	"github.com/jackc/pgx/v5/pgtype"
 
 	"github.com/stretchr/testify/require"
 )
@@ -67,6 +69,7 @@ func TestUpdateCache(t *testing.T) {
 	require.Equal(t, cache1.Title, cache2.Title)
 	require.Equal(t, arg.Content, cache2.Content)
 	require.WithinDuration(t, cache1.CreatedAt, cache2.CreatedAt, time.Second)
+	require.False(t, cache2.UpdatedAt.Before(cache1.UpdatedAt))
 }
 
 func TestDeleteCache(t *testing.T) {
@@ -102,3 +105,354 @@ func TestListCache(t *testing.T) {
 	}
 
 }
+
+func TestListCachesByCursor(t *testing.T) {
+	user := createRandomUser(t)
+	for i := 0; i < 5; i++ {
+		_, err := testStore.CreateCache(context.Background(), CreateCacheParams{
+			Owner:   user.ID,
+			Title:   util.RandomTitle(),
+			Content: util.RandomContent(),
+		})
+		require.NoError(t, err)
+	}
+
+	page1, err := testStore.ListCachesByCursor(context.Background(), ListCachesByCursorParams{
+		Owner:     user.ID,
+		PageLimit: 3,
+	})
+	require.NoError(t, err)
+	require.Len(t, page1, 3)
+
+	last := page1[len(page1)-1]
+	page2, err := testStore.ListCachesByCursor(context.Background(), ListCachesByCursorParams{
+		Owner:           user.ID,
+		CursorCreatedAt: pgtype.Timestamptz{Time: last.CreatedAt, Valid: true},
+		CursorID:        pgtype.Int8{Int64: last.ID, Valid: true},
+		PageLimit:       3,
+	})
+	require.NoError(t, err)
+	require.Len(t, page2, 2)
+
+	// pages never overlap and keep (created_at, id) order
+	seen := map[int64]bool{}
+	for _, cache := range append(page1, page2...) {
+		require.False(t, seen[cache.ID])
+		seen[cache.ID] = true
+	}
+	require.True(t, page2[0].CreatedAt.Before(last.CreatedAt) ||
+		(page2[0].CreatedAt.Equal(last.CreatedAt) && page2[0].ID < last.ID))
+}
+
+func TestListCachesByTagFilter(t *testing.T) {
+	user := createRandomUser(t)
+	tag1 := createRandomTag(t)
+	tag2 := createRandomTag(t)
+	tag3 := createRandomTag(t)
+
+	newCache := func(tagIDs ...int32) Cache {
+		cache, err := testStore.CreateCache(context.Background(), CreateCacheParams{
+			Owner:   user.ID,
+			Title:   util.RandomTitle(),
+			Content: util.RandomContent(),
+		})
+		require.NoError(t, err)
+		for _, tagID := range tagIDs {
+			_, err = testStore.AddTagToCache(context.Background(), AddTagToCacheParams{CacheID: cache.ID, TagID: tagID})
+			require.NoError(t, err)
+		}
+		return cache
+	}
+
+	both := newCache(tag1.TagID, tag2.TagID)
+	onlyFirst := newCache(tag1.TagID)
+	excluded := newCache(tag1.TagID, tag3.TagID)
+	newCache()
+
+	ids := func(caches []Cache) []int64 {
+		result := make([]int64, len(caches))
+		for i, cache := range caches {
+			result[i] = cache.ID
+		}
+		return result
+	}
+
+	// a cache matching several requested tags is listed once
+	anyCaches, err := testStore.ListCachesByCursor(context.Background(), ListCachesByCursorParams{
+		Owner:     user.ID,
+		TagIds:    []int32{tag1.TagID, tag2.TagID},
+		PageLimit: 10,
+	})
+	require.NoError(t, err)
+	require.ElementsMatch(t, []int64{both.ID, onlyFirst.ID, excluded.ID}, ids(anyCaches))
+
+	allCaches, err := testStore.ListCachesByCursor(context.Background(), ListCachesByCursorParams{
+		Owner:     user.ID,
+		TagIds:    []int32{tag1.TagID, tag2.TagID},
+		MatchAll:  true,
+		PageLimit: 10,
+	})
+	require.NoError(t, err)
+	require.ElementsMatch(t, []int64{both.ID}, ids(allCaches))
+
+	filtered, err := testStore.ListCachesByCursor(context.Background(), ListCachesByCursorParams{
+		Owner:         user.ID,
+		TagIds:        []int32{tag1.TagID},
+		ExcludeTagIds: []int32{tag3.TagID},
+		PageLimit:     10,
+	})
+	require.NoError(t, err)
+	require.ElementsMatch(t, []int64{both.ID, onlyFirst.ID}, ids(filtered))
+}
+
+func TestCacheNormalizedURLIsUniquePerOwner(t *testing.T) {
+	user := createRandomUser(t)
+	normalized := "https://example.com/" + util.RandomString(10)
+
+	arg := CreateCacheParams{
+		Owner:         user.ID,
+		Title:         util.RandomTitle(),
+		Content:       normalized + "?utm_source=x",
+		NormalizedUrl: normalized,
+	}
+	cache, err := testStore.CreateCache(context.Background(), arg)
+	require.NoError(t, err)
+
+	_, err = testStore.CreateCache(context.Background(), arg)
+	require.Equal(t, UniqueViolation, ErrorCode(err))
+
+	found, err := testStore.GetCacheByNormalizedURL(context.Background(), GetCacheByNormalizedURLParams{
+		Owner:         user.ID,
+		NormalizedUrl: normalized,
+	})
+	require.NoError(t, err)
+	require.Equal(t, cache.ID, found.ID)
+
+	// other owners and notes without a link are not affected
+	other := createRandomUser(t)
+	arg.Owner = other.ID
+	_, err = testStore.CreateCache(context.Background(), arg)
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		_, err = testStore.CreateCache(context.Background(), CreateCacheParams{
+			Owner:   user.ID,
+			Title:   util.RandomTitle(),
+			Content: util.RandomContent(),
+		})
+		require.NoError(t, err)
+	}
+
+	// re-keying by canonical url skips caches that would collide
+	second, err := testStore.CreateCache(context.Background(), CreateCacheParams{
+		Owner:         user.ID,
+		Title:         util.RandomTitle(),
+		Content:       normalized + "/amp",
+		NormalizedUrl: normalized + "/amp",
+	})
+	require.NoError(t, err)
+
+	err = testStore.UpdateCacheNormalizedURL(context.Background(), UpdateCacheNormalizedURLParams{
+		ID:            second.ID,
+		NormalizedUrl: normalized,
+	})
+	require.NoError(t, err)
+
+	unchanged, err := testStore.GetCache(context.Background(), second.ID)
+	require.NoError(t, err)
+	require.Equal(t, normalized+"/amp", unchanged.NormalizedUrl)
+}
+
+func TestListCachesWithoutNormalizedURL(t *testing.T) {
+	user := createRandomUser(t)
+
+	create := func(content string, normalizedURL string) Cache {
+		cache, err := testStore.CreateCache(context.Background(), CreateCacheParams{
+			Owner:         user.ID,
+			Title:         util.RandomTitle(),
+			Content:       content,
+			NormalizedUrl: normalizedURL,
+		})
+		require.NoError(t, err)
+		return cache
+	}
+
+	link := create(" HTTP://example.com/"+util.RandomString(10)+" ", "")
+	create(util.RandomContent(), "")
+	create("read https://example.com/"+util.RandomString(10)+" later", "")
+	keyed := "https://example.com/" + util.RandomString(10)
+	create(keyed, keyed)
+
+	caches, err := testStore.ListCachesWithoutNormalizedURL(context.Background(), ListCachesWithoutNormalizedURLParams{
+		AfterID:   link.ID - 1,
+		PageLimit: 100,
+	})
+	require.NoError(t, err)
+
+	var owned []ListCachesWithoutNormalizedURLRow
+	for _, cache := range caches {
+		if cache.ID <= link.ID+3 {
+			owned = append(owned, cache)
+		}
+	}
+	require.Equal(t, []ListCachesWithoutNormalizedURLRow{{ID: link.ID, Content: link.Content}}, owned)
+}
+
+func TestUpdateCacheStatus(t *testing.T) {
+	cache := createRandomCache(t)
+	require.Equal(t, ReadingStatusUnread, cache.Status)
+	require.Zero(t, cache.Progress)
+
+	read, err := testStore.UpdateCacheStatus(context.Background(), UpdateCacheStatusParams{
+		ID:     cache.ID,
+		Status: ReadingStatusRead,
+	})
+	require.NoError(t, err)
+	require.Equal(t, ReadingStatusRead, read.Status)
+	require.Equal(t, int32(100), read.Progress)
+	require.True(t, read.ReadAt.Valid)
+
+	archived, err := testStore.UpdateCacheStatus(context.Background(), UpdateCacheStatusParams{
+		ID:     cache.ID,
+		Status: ReadingStatusArchived,
+	})
+	require.NoError(t, err)
+	require.True(t, archived.ArchivedAt.Valid)
+	require.Equal(t, read.ReadAt, archived.ReadAt)
+
+	unread, err := testStore.UpdateCacheStatus(context.Background(), UpdateCacheStatusParams{
+		ID:     cache.ID,
+		Status: ReadingStatusUnread,
+	})
+	require.NoError(t, err)
+	require.False(t, unread.ReadAt.Valid)
+	require.False(t, unread.ArchivedAt.Valid)
+	require.Zero(t, unread.Progress)
+}
+
+func TestUpdateCacheProgress(t *testing.T) {
+	cache := createRandomCache(t)
+
+	reading, err := testStore.UpdateCacheProgress(context.Background(), UpdateCacheProgressParams{
+		ID:       cache.ID,
+		Progress: 30,
+	})
+	require.NoError(t, err)
+	require.Equal(t, ReadingStatusReading, reading.Status)
+	require.False(t, reading.ReadAt.Valid)
+
+	read, err := testStore.UpdateCacheProgress(context.Background(), UpdateCacheProgressParams{
+		ID:       cache.ID,
+		Progress: 100,
+	})
+	require.NoError(t, err)
+	require.Equal(t, ReadingStatusRead, read.Status)
+	require.True(t, read.ReadAt.Valid)
+}
+
+func TestListCachesHidesArchived(t *testing.T) {
+	user := createRandomUser(t)
+
+	var archivedID int64
+	for i := 0; i < 3; i++ {
+		cache, err := testStore.CreateCache(context.Background(), CreateCacheParams{
+			Owner:   user.ID,
+			Title:   util.RandomTitle(),
+			Content: util.RandomContent(),
+		})
+		require.NoError(t, err)
+		archivedID = cache.ID
+	}
+	_, err := testStore.UpdateCacheStatus(context.Background(), UpdateCacheStatusParams{
+		ID:     archivedID,
+		Status: ReadingStatusArchived,
+	})
+	require.NoError(t, err)
+
+	inbox, err := testStore.ListCachesByCursor(context.Background(), ListCachesByCursorParams{
+		Owner:     user.ID,
+		PageLimit: 10,
+	})
+	require.NoError(t, err)
+	require.Len(t, inbox, 2)
+
+	archived, err := testStore.ListCachesByCursor(context.Background(), ListCachesByCursorParams{
+		Owner:     user.ID,
+		Status:    NullReadingStatus{ReadingStatus: ReadingStatusArchived, Valid: true},
+		PageLimit: 10,
+	})
+	require.NoError(t, err)
+	require.Len(t, archived, 1)
+	require.Equal(t, archivedID, archived[0].ID)
+
+	all, err := testStore.ListCachesByCursor(context.Background(), ListCachesByCursorParams{
+		Owner:           user.ID,
+		IncludeArchived: true,
+		PageLimit:       10,
+	})
+	require.NoError(t, err)
+	require.Len(t, all, 3)
+}
+
+func TestSearchCaches(t *testing.T) {
+	cache := createRandomCache(t)
+
+	arg := SearchCachesParams{
+		Query:     cache.Title,
+		Owner:     cache.Owner,
+		PageLimit: 5,
+	}
+
+	results, err := testStore.SearchCaches(context.Background(), arg)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.Equal(t, cache.ID, results[0].Cache.ID)
+	require.Contains(t, results[0].TitleHighlight, "<mark>")
+
+	// private caches of other users are not searchable
+	arg.Owner = util.RandomOwner()
+	results, err = testStore.SearchCaches(context.Background(), arg)
+	require.NoError(t, err)
+	require.Empty(t, results)
+}
+
+func TestSearchCachesEscapesHighlights(t *testing.T) {
+	user := createRandomUser(t)
+	word := strings.ToLower(util.RandomString(12))
+
+	var caches []Cache
+	for i := 0; i < 3; i++ {
+		cache, err := testStore.CreateCache(context.Background(), CreateCacheParams{
+			Owner:   user.ID,
+			Title:   word + ` <script>alert("x")</script>`,
+			Content: "<img src=x onerror=alert(1)> " + word,
+		})
+		require.NoError(t, err)
+		caches = append(caches, cache)
+	}
+
+	first, err := testStore.SearchCachesByCursor(context.Background(), SearchCachesByCursorParams{
+		Query:     word,
+		Owner:     user.ID,
+		PageLimit: 2,
+	})
+	require.NoError(t, err)
+	require.Len(t, first, 2)
+	require.Contains(t, first[0].TitleHighlight, "<mark>"+word+"</mark>")
+	require.Contains(t, first[0].TitleHighlight, "&lt;script&gt;")
+	require.NotContains(t, first[0].TitleHighlight, "<script>")
+	require.NotContains(t, first[0].ContentSnippet, "<img")
+
+	// equal ranks are paged by id
+	last := first[len(first)-1]
+	rest, err := testStore.SearchCachesByCursor(context.Background(), SearchCachesByCursorParams{
+		Query:      word,
+		Owner:      user.ID,
+		CursorRank: pgtype.Float4{Float32: last.Rank, Valid: true},
+		CursorID:   pgtype.Int8{Int64: last.Cache.ID, Valid: true},
+		PageLimit:  2,
+	})
+	require.NoError(t, err)
+	require.Len(t, rest, 1)
+	require.Equal(t, caches[0].ID, rest[0].Cache.ID)
+}
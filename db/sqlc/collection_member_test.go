@@ -0,0 +1,197 @@
+package db
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateCollectionTxAddsOwner(t *testing.T) {
+	owner := createRandomUser(t)
+	collection := createRandomCollection(t, owner)
+
+	member, err := testStore.GetCollectionMember(context.Background(), GetCollectionMemberParams{
+		CollectionID: collection.ID,
+		UserID:       owner.ID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, CollectionRoleOwner, member.Role)
+
+	collections, err := testStore.ListCollections(context.Background(), owner.ID)
+	require.NoError(t, err)
+	require.Len(t, collections, 1)
+	require.Equal(t, CollectionRoleOwner, collections[0].Role)
+}
+
+func TestAcceptCollectionInvitationTx(t *testing.T) {
+	owner := createRandomUser(t)
+	collection := createRandomCollection(t, owner)
+	invitee := createRandomUser(t)
+
+	// invited by email only, spelled in another case
+	invitation, err := testStore.CreateCollectionInvitation(context.Background(), CreateCollectionInvitationParams{
+		CollectionID: collection.ID,
+		Inviter:      owner.ID,
+		InviteeEmail: strings.ToUpper(invitee.Email),
+		Role:         CollectionRoleEditor,
+	})
+	require.NoError(t, err)
+	require.Equal(t, InvitationStatusPending, invitation.Status)
+
+	// a second open invitation to the same address is rejected
+	_, err = testStore.CreateCollectionInvitation(context.Background(), CreateCollectionInvitationParams{
+		CollectionID: collection.ID,
+		Inviter:      owner.ID,
+		InviteeEmail: invitee.Email,
+		Role:         CollectionRoleViewer,
+	})
+	require.Equal(t, UniqueViolation, ErrorCode(err))
+
+	pending, err := testStore.ListPendingInvitationsForUser(context.Background(), ListPendingInvitationsForUserParams{
+		UserID: invitee.ID,
+		Email:  invitee.Email,
+	})
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	require.Equal(t, collection.Name, pending[0].CollectionName)
+
+	// nobody else can accept it
+	stranger := createRandomUser(t)
+	_, err = testStore.AcceptCollectionInvitationTx(context.Background(), RespondToCollectionInvitationParams{
+		UserID: stranger.ID,
+		ID:     invitation.ID,
+		Email:  stranger.Email,
+	})
+	require.ErrorIs(t, err, ErrRecordNotFound)
+
+	// without a verified email only invitations addressed to the user id match
+	pending, err = testStore.ListPendingInvitationsForUser(context.Background(), ListPendingInvitationsForUserParams{
+		UserID: invitee.ID,
+	})
+	require.NoError(t, err)
+	require.Empty(t, pending)
+
+	member, err := testStore.AcceptCollectionInvitationTx(context.Background(), RespondToCollectionInvitationParams{
+		UserID: invitee.ID,
+		ID:     invitation.ID,
+		Email:  invitee.Email,
+	})
+	require.NoError(t, err)
+	require.Equal(t, CollectionRoleEditor, member.Role)
+
+	// accepted invitations can't be accepted again
+	_, err = testStore.AcceptCollectionInvitationTx(context.Background(), RespondToCollectionInvitationParams{
+		UserID: invitee.ID,
+		ID:     invitation.ID,
+		Email:  invitee.Email,
+	})
+	require.ErrorIs(t, err, ErrRecordNotFound)
+
+	// a later viewer invitation doesn't demote the editor
+	invitation, err = testStore.CreateCollectionInvitation(context.Background(), CreateCollectionInvitationParams{
+		CollectionID: collection.ID,
+		Inviter:      owner.ID,
+		InviteeEmail: invitee.Email,
+		InviteeID:    pgtype.Text{String: invitee.ID, Valid: true},
+		Role:         CollectionRoleViewer,
+	})
+	require.NoError(t, err)
+
+	member, err = testStore.AcceptCollectionInvitationTx(context.Background(), RespondToCollectionInvitationParams{
+		UserID: invitee.ID,
+		ID:     invitation.ID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, CollectionRoleEditor, member.Role)
+}
+
+func TestRevokeCollectionInvitation(t *testing.T) {
+	owner := createRandomUser(t)
+	collection := createRandomCollection(t, owner)
+	invitee := createRandomUser(t)
+
+	invitation, err := testStore.CreateCollectionInvitation(context.Background(), CreateCollectionInvitationParams{
+		CollectionID: collection.ID,
+		Inviter:      owner.ID,
+		InviteeEmail: invitee.Email,
+		Role:         CollectionRoleViewer,
+	})
+	require.NoError(t, err)
+
+	revoked, err := testStore.RevokeCollectionInvitation(context.Background(), RevokeCollectionInvitationParams{
+		ID:           invitation.ID,
+		CollectionID: collection.ID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, InvitationStatusRevoked, revoked.Status)
+	require.True(t, revoked.RespondedAt.Valid)
+
+	_, err = testStore.AcceptCollectionInvitationTx(context.Background(), RespondToCollectionInvitationParams{
+		UserID: invitee.ID,
+		ID:     invitation.ID,
+		Email:  invitee.Email,
+	})
+	require.ErrorIs(t, err, ErrRecordNotFound)
+}
+
+func TestDeclineCollectionInvitation(t *testing.T) {
+	owner := createRandomUser(t)
+	collection := createRandomCollection(t, owner)
+	invitee := createRandomUser(t)
+
+	invitation, err := testStore.CreateCollectionInvitation(context.Background(), CreateCollectionInvitationParams{
+		CollectionID: collection.ID,
+		Inviter:      owner.ID,
+		InviteeEmail: invitee.Email,
+		InviteeID:    pgtype.Text{String: invitee.ID, Valid: true},
+		Role:         CollectionRoleViewer,
+	})
+	require.NoError(t, err)
+
+	// invitations sent by UID are answered without an email
+	declined, err := testStore.RespondToCollectionInvitation(context.Background(), RespondToCollectionInvitationParams{
+		Status: InvitationStatusDeclined,
+		UserID: invitee.ID,
+		ID:     invitation.ID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, InvitationStatusDeclined, declined.Status)
+	require.True(t, declined.RespondedAt.Valid)
+}
+
+func TestCollectionKeepsAnOwner(t *testing.T) {
+	owner := createRandomUser(t)
+	collection := createRandomCollection(t, owner)
+	key := DeleteCollectionMemberParams{CollectionID: collection.ID, UserID: owner.ID}
+
+	_, err := testStore.UpdateCollectionMemberTx(context.Background(), UpdateCollectionMemberRoleParams{
+		CollectionID: collection.ID,
+		UserID:       owner.ID,
+		Role:         CollectionRoleEditor,
+	})
+	require.ErrorIs(t, err, ErrLastCollectionOwner)
+
+	err = testStore.RemoveCollectionMemberTx(context.Background(), key)
+	require.ErrorIs(t, err, ErrLastCollectionOwner)
+
+	second := createRandomUser(t)
+	_, err = testStore.UpsertCollectionMember(context.Background(), UpsertCollectionMemberParams{
+		CollectionID: collection.ID,
+		UserID:       second.ID,
+		Role:         CollectionRoleOwner,
+	})
+	require.NoError(t, err)
+
+	// with a second owner the first one can leave
+	err = testStore.RemoveCollectionMemberTx(context.Background(), key)
+	require.NoError(t, err)
+
+	members, err := testStore.ListCollectionMembers(context.Background(), collection.ID)
+	require.NoError(t, err)
+	require.Len(t, members, 1)
+	require.Equal(t, second.ID, members[0].UserID)
+	require.Equal(t, second.Email, members[0].Email)
+}
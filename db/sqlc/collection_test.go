@@ -0,0 +1,193 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/imrishuroy/read-cache-api/util"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomCollection(t *testing.T, owner User) Collection {
+	arg := CreateCollectionParams{
+		Owner:       owner.ID,
+		Name:        util.RandomString(10),
+		Description: util.RandomContent(),
+		Icon:        "📚",
+	}
+
+	collection, err := testStore.CreateCollectionTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.Owner, collection.Owner)
+	require.Equal(t, arg.Name, collection.Name)
+	require.Equal(t, arg.Description, collection.Description)
+	require.Equal(t, arg.Icon, collection.Icon)
+	require.False(t, collection.IsPublic)
+	require.NotZero(t, collection.CreatedAt)
+	return collection
+}
+
+func createOwnedCache(t *testing.T, owner User) Cache {
+	cache, err := testStore.CreateCache(context.Background(), CreateCacheParams{
+		Owner:   owner.ID,
+		Title:   util.RandomTitle(),
+		Content: util.RandomContent(),
+	})
+	require.NoError(t, err)
+	return cache
+}
+
+func listCollectionCacheIDs(t *testing.T, collection Collection) []int64 {
+	rows, err := testStore.ListCollectionCaches(context.Background(), ListCollectionCachesParams{
+		CollectionID: collection.ID,
+		Viewer:       collection.Owner,
+		PageLimit:    10,
+	})
+	require.NoError(t, err)
+
+	ids := make([]int64, len(rows))
+	for i, row := range rows {
+		ids[i] = row.Cache.ID
+	}
+	return ids
+}
+
+func TestCreateCollectionDuplicateName(t *testing.T) {
+	owner := createRandomUser(t)
+	collection := createRandomCollection(t, owner)
+
+	_, err := testStore.CreateCollection(context.Background(), CreateCollectionParams{
+		Owner: owner.ID,
+		Name:  collection.Name,
+	})
+	require.Equal(t, UniqueViolation, ErrorCode(err))
+}
+
+func TestCollectionMembership(t *testing.T) {
+	owner := createRandomUser(t)
+	collection := createRandomCollection(t, owner)
+	a := createOwnedCache(t, owner)
+	b := createOwnedCache(t, owner)
+	c := createOwnedCache(t, owner)
+
+	for _, cache := range []Cache{a, b, c} {
+		rows, err := testStore.AddCacheToCollection(context.Background(), AddCacheToCollectionParams{
+			CollectionID: collection.ID,
+			CacheID:      cache.ID,
+		})
+		require.NoError(t, err)
+		require.Equal(t, int64(1), rows)
+	}
+
+	// adding again keeps the cache where it is
+	rows, err := testStore.AddCacheToCollection(context.Background(), AddCacheToCollectionParams{
+		CollectionID: collection.ID,
+		CacheID:      a.ID,
+	})
+	require.NoError(t, err)
+	require.Zero(t, rows)
+	require.Equal(t, []int64{a.ID, b.ID, c.ID}, listCollectionCacheIDs(t, collection))
+
+	err = testStore.ReorderCollectionTx(context.Background(), ReorderCollectionTxParams{
+		CollectionID: collection.ID,
+		CacheIDs:     []int64{c.ID, a.ID, b.ID},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []int64{c.ID, a.ID, b.ID}, listCollectionCacheIDs(t, collection))
+
+	// an order missing a cache is rejected
+	err = testStore.ReorderCollectionTx(context.Background(), ReorderCollectionTxParams{
+		CollectionID: collection.ID,
+		CacheIDs:     []int64{a.ID, b.ID},
+	})
+	require.ErrorIs(t, err, ErrCollectionOrderMismatch)
+
+	rows, err = testStore.RemoveCacheFromCollection(context.Background(), RemoveCacheFromCollectionParams{
+		CollectionID: collection.ID,
+		CacheID:      a.ID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), rows)
+	require.Equal(t, []int64{c.ID, b.ID}, listCollectionCacheIDs(t, collection))
+
+	collections, err := testStore.ListCollections(context.Background(), owner.ID)
+	require.NoError(t, err)
+	require.Len(t, collections, 1)
+	require.Equal(t, int64(2), collections[0].CacheCount)
+}
+
+func TestAddCacheToCollectionTxConcurrent(t *testing.T) {
+	owner := createRandomUser(t)
+	collection := createRandomCollection(t, owner)
+
+	n := 5
+	errs := make(chan error, n)
+	for i := 0; i < n; i++ {
+		cache := createOwnedCache(t, owner)
+		go func() {
+			_, err := testStore.AddCacheToCollectionTx(context.Background(), AddCacheToCollectionParams{
+				CollectionID: collection.ID,
+				CacheID:      cache.ID,
+			})
+			errs <- err
+		}()
+	}
+	for i := 0; i < n; i++ {
+		require.NoError(t, <-errs)
+	}
+
+	// every cache got a position of its own
+	rows, err := testStore.ListCollectionCaches(context.Background(), ListCollectionCachesParams{
+		CollectionID: collection.ID,
+		Viewer:       owner.ID,
+		PageLimit:    int32(n),
+	})
+	require.NoError(t, err)
+	require.Len(t, rows, n)
+	for i, row := range rows {
+		require.Equal(t, int32(i+1), row.Position)
+	}
+}
+
+func TestDeleteCollectionKeepsCaches(t *testing.T) {
+	owner := createRandomUser(t)
+	collection := createRandomCollection(t, owner)
+	cache := createOwnedCache(t, owner)
+
+	_, err := testStore.AddCacheToCollection(context.Background(), AddCacheToCollectionParams{
+		CollectionID: collection.ID,
+		CacheID:      cache.ID,
+	})
+	require.NoError(t, err)
+
+	err = testStore.DeleteCollection(context.Background(), collection.ID)
+	require.NoError(t, err)
+
+	_, err = testStore.GetCollection(context.Background(), collection.ID)
+	require.ErrorIs(t, err, ErrRecordNotFound)
+
+	stillThere, err := testStore.GetCache(context.Background(), cache.ID)
+	require.NoError(t, err)
+	require.Equal(t, cache.ID, stillThere.ID)
+}
+
+func TestListCollectionCachesHidesPrivateCachesFromOthers(t *testing.T) {
+	owner := createRandomUser(t)
+	collection := createRandomCollection(t, owner)
+	private := createOwnedCache(t, owner)
+
+	_, err := testStore.AddCacheToCollection(context.Background(), AddCacheToCollectionParams{
+		CollectionID: collection.ID,
+		CacheID:      private.ID,
+	})
+	require.NoError(t, err)
+
+	other := createRandomUser(t)
+	rows, err := testStore.ListCollectionCaches(context.Background(), ListCollectionCachesParams{
+		CollectionID: collection.ID,
+		Viewer:       other.ID,
+		PageLimit:    10,
+	})
+	require.NoError(t, err)
+	require.Empty(t, rows)
+}
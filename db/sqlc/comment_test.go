@@ -0,0 +1,129 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/imrishuroy/read-cache-api/util"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomComment(t *testing.T, cache Cache, parentID pgtype.Int8) Comment {
+	user := createRandomUser(t)
+	arg := CreateCommentParams{
+		CacheID:  cache.ID,
+		Author:   user.ID,
+		ParentID: parentID,
+		Body:     util.RandomContent(),
+	}
+
+	comment, err := testStore.CreateComment(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.CacheID, comment.CacheID)
+	require.Equal(t, arg.Author, comment.Author)
+	require.Equal(t, arg.ParentID, comment.ParentID)
+	require.Equal(t, arg.Body, comment.Body)
+	require.NotZero(t, comment.CreatedAt)
+	require.False(t, comment.EditedAt.Valid)
+	require.False(t, comment.DeletedAt.Valid)
+	require.False(t, comment.HiddenAt.Valid)
+	return comment
+}
+
+func TestListComments(t *testing.T) {
+	cache := createRandomCache(t)
+	first := createRandomComment(t, cache, pgtype.Int8{})
+	second := createRandomComment(t, cache, pgtype.Int8{})
+	parent := pgtype.Int8{Int64: first.ID, Valid: true}
+	reply := createRandomComment(t, cache, parent)
+	deletedReply := createRandomComment(t, cache, parent)
+
+	_, err := testStore.SoftDeleteComment(context.Background(), deletedReply.ID)
+	require.NoError(t, err)
+
+	comments, err := testStore.ListComments(context.Background(), ListCommentsParams{
+		CacheID:   cache.ID,
+		PageLimit: 10,
+	})
+	require.NoError(t, err)
+	require.Len(t, comments, 2)
+	require.Equal(t, first.ID, comments[0].ID)
+	require.Equal(t, int64(1), comments[0].ReplyCount)
+	require.Equal(t, second.ID, comments[1].ID)
+
+	next, err := testStore.ListComments(context.Background(), ListCommentsParams{
+		CacheID:         cache.ID,
+		CursorCreatedAt: pgtype.Timestamptz{Time: comments[0].CreatedAt, Valid: true},
+		CursorID:        pgtype.Int8{Int64: comments[0].ID, Valid: true},
+		PageLimit:       10,
+	})
+	require.NoError(t, err)
+	require.Len(t, next, 1)
+	require.Equal(t, second.ID, next[0].ID)
+
+	replies, err := testStore.ListCommentReplies(context.Background(), ListCommentRepliesParams{
+		ParentID:  parent,
+		PageLimit: 10,
+	})
+	require.NoError(t, err)
+	require.Len(t, replies, 2)
+	require.Equal(t, reply.ID, replies[0].ID)
+	require.True(t, replies[1].DeletedAt.Valid)
+	require.Empty(t, replies[1].Body)
+}
+
+func TestUpdateAndDeleteComment(t *testing.T) {
+	cache := createRandomCache(t)
+	comment := createRandomComment(t, cache, pgtype.Int8{})
+
+	updated, err := testStore.UpdateCommentBody(context.Background(), UpdateCommentBodyParams{
+		ID:   comment.ID,
+		Body: "edited",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "edited", updated.Body)
+	require.True(t, updated.EditedAt.Valid)
+
+	deleted, err := testStore.SoftDeleteComment(context.Background(), comment.ID)
+	require.NoError(t, err)
+	require.True(t, deleted.DeletedAt.Valid)
+	require.Empty(t, deleted.Body)
+
+	// deleted comments can't be edited or deleted again
+	_, err = testStore.UpdateCommentBody(context.Background(), UpdateCommentBodyParams{
+		ID:   comment.ID,
+		Body: "again",
+	})
+	require.ErrorIs(t, err, ErrRecordNotFound)
+
+	_, err = testStore.SoftDeleteComment(context.Background(), comment.ID)
+	require.ErrorIs(t, err, ErrRecordNotFound)
+}
+
+func TestSetCommentHidden(t *testing.T) {
+	cache := createRandomCache(t)
+	comment := createRandomComment(t, cache, pgtype.Int8{})
+
+	hidden, err := testStore.SetCommentHidden(context.Background(), SetCommentHiddenParams{
+		ID:     comment.ID,
+		Hidden: true,
+	})
+	require.NoError(t, err)
+	require.True(t, hidden.HiddenAt.Valid)
+
+	// hiding again keeps the original time
+	again, err := testStore.SetCommentHidden(context.Background(), SetCommentHiddenParams{
+		ID:     comment.ID,
+		Hidden: true,
+	})
+	require.NoError(t, err)
+	require.Equal(t, hidden.HiddenAt, again.HiddenAt)
+
+	shown, err := testStore.SetCommentHidden(context.Background(), SetCommentHiddenParams{
+		ID:     comment.ID,
+		Hidden: false,
+	})
+	require.NoError(t, err)
+	require.False(t, shown.HiddenAt.Valid)
+}
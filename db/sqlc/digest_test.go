@@ -0,0 +1,142 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpsertDigestSettings(t *testing.T) {
+	user := createRandomUser(t)
+
+	_, err := testStore.GetDigestSettings(context.Background(), user.ID)
+	require.ErrorIs(t, err, ErrRecordNotFound)
+
+	settings, err := testStore.UpsertDigestSettings(context.Background(), UpsertDigestSettingsParams{
+		UserID:    user.ID,
+		Frequency: "daily",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "daily", settings.Frequency)
+	require.True(t, settings.NextSendAt.Valid)
+	require.WithinDuration(t, time.Now().Add(24*time.Hour), settings.NextSendAt.Time, time.Minute)
+
+	// picking the same frequency again keeps the schedule
+	same, err := testStore.UpsertDigestSettings(context.Background(), UpsertDigestSettingsParams{
+		UserID:    user.ID,
+		Frequency: "daily",
+	})
+	require.NoError(t, err)
+	require.True(t, settings.NextSendAt.Time.Equal(same.NextSendAt.Time))
+
+	weekly, err := testStore.UpsertDigestSettings(context.Background(), UpsertDigestSettingsParams{
+		UserID:    user.ID,
+		Frequency: "weekly",
+	})
+	require.NoError(t, err)
+	require.WithinDuration(t, time.Now().Add(7*24*time.Hour), weekly.NextSendAt.Time, time.Minute)
+
+	err = testStore.UnsubscribeDigest(context.Background(), user.ID)
+	require.NoError(t, err)
+
+	settings, err = testStore.GetDigestSettings(context.Background(), user.ID)
+	require.NoError(t, err)
+	require.Equal(t, "off", settings.Frequency)
+	require.False(t, settings.NextSendAt.Valid)
+}
+
+func TestListDigestCaches(t *testing.T) {
+	reader := createRandomUser(t)
+	author := createRandomUser(t)
+	subscribed := createRandomTag(t)
+	other := createRandomTag(t)
+
+	_, err := testStore.SubscribeTag(context.Background(), SubscribeTagParams{
+		UserID: reader.ID,
+		TagID:  subscribed.TagID,
+	})
+	require.NoError(t, err)
+
+	since := time.Now().Add(-time.Second)
+	cache := createPublicCache(t, author)
+	for _, tag := range []Tag{subscribed, other} {
+		_, err := testStore.AddTagToCache(context.Background(), AddTagToCacheParams{
+			CacheID: cache.ID,
+			TagID:   tag.TagID,
+		})
+		require.NoError(t, err)
+	}
+
+	caches, err := testStore.ListDigestCaches(context.Background(), ListDigestCachesParams{
+		UserID:    reader.ID,
+		Since:     since,
+		Until:     time.Now().Add(time.Second),
+		PageLimit: 10,
+	})
+	require.NoError(t, err)
+	require.Len(t, caches, 1)
+	require.Equal(t, cache.ID, caches[0].ID)
+	require.Equal(t, author.Name, caches[0].OwnerName)
+	// only the tags the reader follows
+	require.Equal(t, []string{subscribed.TagName}, caches[0].Tags)
+
+	// caches from before the last digest were already sent
+	caches, err = testStore.ListDigestCaches(context.Background(), ListDigestCachesParams{
+		UserID:    reader.ID,
+		Since:     cache.CreatedAt,
+		Until:     time.Now().Add(time.Second),
+		PageLimit: 10,
+	})
+	require.NoError(t, err)
+	require.Empty(t, caches)
+}
+
+func TestClaimAndCompleteDigest(t *testing.T) {
+	user := createRandomUser(t)
+
+	_, err := testStore.UpsertDigestSettings(context.Background(), UpsertDigestSettingsParams{
+		UserID:    user.ID,
+		Frequency: "daily",
+	})
+	require.NoError(t, err)
+
+	// make it due
+	err = testStore.RetryDigest(context.Background(), RetryDigestParams{
+		UserID:     user.ID,
+		NextSendAt: time.Now().Add(-time.Minute),
+		LastError:  "smtp unavailable",
+	})
+	require.NoError(t, err)
+
+	claimed, err := testStore.ClaimDueDigests(context.Background(), ClaimDueDigestsParams{
+		BatchSize:    1000,
+		LeaseSeconds: 60,
+	})
+	require.NoError(t, err)
+
+	var found *ClaimDueDigestsRow
+	for i := range claimed {
+		if claimed[i].UserID == user.ID {
+			found = &claimed[i]
+		}
+	}
+	require.NotNil(t, found)
+	require.Equal(t, user.Email, found.UserEmail)
+	require.Equal(t, int32(1), found.Attempts)
+
+	sentAt := time.Now().UTC().Truncate(time.Second)
+	err = testStore.CompleteDigest(context.Background(), CompleteDigestParams{
+		UserID: user.ID,
+		SentAt: sentAt,
+	})
+	require.NoError(t, err)
+
+	settings, err := testStore.GetDigestSettings(context.Background(), user.ID)
+	require.NoError(t, err)
+	require.True(t, settings.LastSentAt.Equal(sentAt))
+	require.True(t, settings.NextSendAt.Time.Equal(sentAt.Add(24*time.Hour)))
+	require.Zero(t, settings.Attempts)
+	require.Empty(t, settings.LastError)
+}
@@ -0,0 +1,54 @@
+package db
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListCachesForExport(t *testing.T) {
+	user := createRandomUser(t)
+	tag1 := createRandomTag(t)
+	tag2 := createRandomTag(t)
+
+	var caches []Cache
+	for i := 0; i < 3; i++ {
+		caches = append(caches, createOwnedCache(t, user))
+	}
+	for _, tag := range []Tag{tag1, tag2} {
+		_, err := testStore.AddTagToCache(context.Background(), AddTagToCacheParams{
+			CacheID: caches[0].ID,
+			TagID:   tag.TagID,
+		})
+		require.NoError(t, err)
+	}
+	// someone else's caches are never exported
+	createOwnedCache(t, createRandomUser(t))
+
+	first, err := testStore.ListCachesForExport(context.Background(), ListCachesForExportParams{
+		Owner:     user.ID,
+		PageLimit: 2,
+	})
+	require.NoError(t, err)
+	require.Len(t, first, 2)
+	require.Equal(t, caches[0].ID, first[0].ID)
+
+	tags := []string{tag1.TagName, tag2.TagName}
+	sort.Strings(tags)
+	require.Equal(t, tags, first[0].Tags)
+	require.Empty(t, first[1].Tags)
+
+	last := first[len(first)-1]
+	rest, err := testStore.ListCachesForExport(context.Background(), ListCachesForExportParams{
+		Owner:           user.ID,
+		CursorCreatedAt: pgtype.Timestamptz{Time: last.CreatedAt, Valid: true},
+		CursorID:        pgtype.Int8{Int64: last.ID, Valid: true},
+		PageLimit:       2,
+	})
+	require.NoError(t, err)
+	require.Len(t, rest, 1)
+	require.Equal(t, caches[2].ID, rest[0].ID)
+}
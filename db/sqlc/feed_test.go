@@ -0,0 +1,74 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/imrishuroy/read-cache-api/util"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func createPublicCache(t *testing.T, owner User) Cache {
+	cache, err := testStore.CreateCache(context.Background(), CreateCacheParams{
+		Owner:    owner.ID,
+		Title:    util.RandomTitle(),
+		Content:  util.RandomContent(),
+		IsPublic: pgtype.Bool{Bool: true, Valid: true},
+	})
+	require.NoError(t, err)
+	return cache
+}
+
+func TestListFeedCaches(t *testing.T) {
+	user := createRandomUser(t)
+	tag := createRandomTag(t)
+
+	older := createPublicCache(t, user)
+	newer := createPublicCache(t, user)
+	// private caches never show up in a feed
+	private := createOwnedCache(t, user)
+
+	for _, cache := range []Cache{newer, private} {
+		_, err := testStore.AddTagToCache(context.Background(), AddTagToCacheParams{
+			CacheID: cache.ID,
+			TagID:   tag.TagID,
+		})
+		require.NoError(t, err)
+	}
+
+	byUser, err := testStore.ListFeedCaches(context.Background(), ListFeedCachesParams{
+		Owner:     pgtype.Text{String: user.ID, Valid: true},
+		PageLimit: 10,
+	})
+	require.NoError(t, err)
+	require.Len(t, byUser, 2)
+	require.Equal(t, newer.ID, byUser[0].ID)
+	require.Equal(t, older.ID, byUser[1].ID)
+	require.Equal(t, user.Name, byUser[0].OwnerName)
+	require.Equal(t, []string{tag.TagName}, byUser[0].Tags)
+	require.Empty(t, byUser[1].Tags)
+
+	byTag, err := testStore.ListFeedCaches(context.Background(), ListFeedCachesParams{
+		TagID:     pgtype.Int4{Int32: tag.TagID, Valid: true},
+		PageLimit: 10,
+	})
+	require.NoError(t, err)
+	require.Len(t, byTag, 1)
+	require.Equal(t, newer.ID, byTag[0].ID)
+
+	all, err := testStore.ListFeedCaches(context.Background(), ListFeedCachesParams{PageLimit: 1})
+	require.NoError(t, err)
+	require.Len(t, all, 1)
+}
+
+func TestGetTagByName(t *testing.T) {
+	tag := createRandomTag(t)
+
+	found, err := testStore.GetTagByName(context.Background(), tag.TagName)
+	require.NoError(t, err)
+	require.Equal(t, tag, found)
+
+	_, err = testStore.GetTagByName(context.Background(), util.RandomString(16))
+	require.ErrorIs(t, err, ErrRecordNotFound)
+}
@@ -0,0 +1,202 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/imrishuroy/read-cache-api/util"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportCacheTx(t *testing.T) {
+	user := createRandomUser(t)
+	existingURL := "https://example.com/" + util.RandomString(8)
+	existing, err := testStore.CreateCache(context.Background(), CreateCacheParams{
+		Owner:         user.ID,
+		Title:         util.RandomTitle(),
+		Content:       existingURL,
+		NormalizedUrl: existingURL,
+	})
+	require.NoError(t, err)
+
+	addedAt := time.Now().Add(-365 * 24 * time.Hour).UTC().Truncate(time.Second)
+	tag := "import-" + util.RandomString(8)
+
+	job, err := testStore.CreateImportJobTx(context.Background(), CreateImportJobTxParams{
+		UserID:   user.ID,
+		Format:   "netscape",
+		Filename: "bookmarks.html",
+		Items: []NewImportItem{
+			{Position: 1, URL: "https://example.com/" + util.RandomString(8), Title: "New", Tags: []string{tag}, AddedAt: &addedAt, Archived: true},
+			{Position: 2, URL: existingURL},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "pending", job.Status)
+	require.Equal(t, int32(2), job.Total)
+
+	items, err := testStore.ListPendingImportItems(context.Background(), ListPendingImportItemsParams{JobID: job.ID, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, items, 2)
+	require.Equal(t, []string{tag}, items[0].Tags)
+	require.True(t, items[0].AddedAt.Time.Equal(addedAt))
+
+	created, err := testStore.ImportCacheTx(context.Background(), ImportCacheTxParams{
+		Owner:         user.ID,
+		Item:          items[0],
+		LinkURL:       items[0].Url,
+		NormalizedURL: items[0].Url,
+	})
+	require.NoError(t, err)
+	require.Equal(t, ImportItemCreated, created.Status)
+	require.True(t, created.CacheID.Valid)
+
+	cache, err := testStore.GetCache(context.Background(), created.CacheID.Int64)
+	require.NoError(t, err)
+	require.Equal(t, "New", cache.Title)
+	require.Equal(t, ReadingStatusArchived, cache.Status)
+	require.True(t, cache.SavedAt.Equal(addedAt))
+	// the cache is new to the feed and the digests although its link is old
+	require.WithinDuration(t, time.Now(), cache.CreatedAt, time.Minute)
+
+	tags, err := testStore.ListCacheTags(context.Background(), cache.ID)
+	require.NoError(t, err)
+	require.Len(t, tags, 1)
+	require.Equal(t, tag, tags[0].TagName)
+
+	// the owner already saved the second link
+	skipped, err := testStore.ImportCacheTx(context.Background(), ImportCacheTxParams{
+		Owner:         user.ID,
+		Item:          items[1],
+		LinkURL:       existing.Content,
+		NormalizedURL: existing.NormalizedUrl,
+	})
+	require.NoError(t, err)
+	require.Equal(t, ImportItemSkipped, skipped.Status)
+	require.Equal(t, existing.ID, skipped.CacheID.Int64)
+
+	// a finished item is never imported twice
+	_, err = testStore.ImportCacheTx(context.Background(), ImportCacheTxParams{
+		Owner:         user.ID,
+		Item:          items[0],
+		LinkURL:       items[0].Url + "/again",
+		NormalizedURL: items[0].Url + "/again",
+	})
+	require.True(t, errors.Is(err, ErrRecordNotFound))
+
+	job, err = testStore.GetImportJob(context.Background(), job.ID)
+	require.NoError(t, err)
+	require.Equal(t, int32(2), job.Processed)
+	require.Equal(t, int32(1), job.CreatedCount)
+	require.Equal(t, int32(1), job.SkippedCount)
+	require.Zero(t, job.FailedCount)
+
+	report, err := testStore.ListImportItems(context.Background(), ListImportItemsParams{
+		JobID:     job.ID,
+		Status:    pgtype.Text{String: ImportItemSkipped, Valid: true},
+		PageLimit: 10,
+	})
+	require.NoError(t, err)
+	require.Len(t, report, 1)
+	require.Equal(t, int32(2), report[0].Position)
+}
+
+func TestImportCacheTxReadingState(t *testing.T) {
+	user := createRandomUser(t)
+	readAt := time.Now().Add(-time.Hour).UTC().Truncate(time.Second)
+
+	job, err := testStore.CreateImportJobTx(context.Background(), CreateImportJobTxParams{
+		UserID: user.ID,
+		Format: "json",
+		Items: []NewImportItem{
+			{Position: 1, Title: "Shopping list", Content: "milk\neggs", IsPublic: true, ReadingStatus: "reading", Progress: 40},
+			{Position: 2, URL: "https://example.com/" + util.RandomString(8), Title: "Read", ReadingStatus: "read", Progress: 100, ReadAt: &readAt},
+		},
+	})
+	require.NoError(t, err)
+
+	items, err := testStore.ListPendingImportItems(context.Background(), ListPendingImportItemsParams{JobID: job.ID, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, items, 2)
+	require.Equal(t, NullReadingStatus{ReadingStatus: ReadingStatusReading, Valid: true}, items[0].ReadingStatus)
+
+	// a note has no link and is never matched against the owner's caches
+	note, err := testStore.ImportCacheTx(context.Background(), ImportCacheTxParams{
+		Owner: user.ID,
+		Item:  items[0],
+	})
+	require.NoError(t, err)
+	require.Equal(t, ImportItemCreated, note.Status)
+
+	cache, err := testStore.GetCache(context.Background(), note.CacheID.Int64)
+	require.NoError(t, err)
+	require.Equal(t, "milk\neggs", cache.Content)
+	require.Empty(t, cache.NormalizedUrl)
+	require.True(t, cache.IsPublic.Bool)
+	require.Equal(t, ReadingStatusReading, cache.Status)
+	require.Equal(t, int32(40), cache.Progress)
+
+	read, err := testStore.ImportCacheTx(context.Background(), ImportCacheTxParams{
+		Owner:         user.ID,
+		Item:          items[1],
+		LinkURL:       items[1].Url,
+		NormalizedURL: items[1].Url,
+	})
+	require.NoError(t, err)
+
+	cache, err = testStore.GetCache(context.Background(), read.CacheID.Int64)
+	require.NoError(t, err)
+	require.False(t, cache.IsPublic.Bool)
+	require.Equal(t, ReadingStatusRead, cache.Status)
+	require.Equal(t, int32(100), cache.Progress)
+	require.True(t, cache.ReadAt.Time.Equal(readAt))
+	require.False(t, cache.ArchivedAt.Valid)
+}
+
+func TestClaimAndFailImportJob(t *testing.T) {
+	user := createRandomUser(t)
+
+	job, err := testStore.CreateImportJobTx(context.Background(), CreateImportJobTxParams{
+		UserID: user.ID,
+		Format: "pocket_csv",
+		Items:  []NewImportItem{{Position: 1, URL: "ftp://example.com/file"}},
+	})
+	require.NoError(t, err)
+
+	claimed, err := testStore.ClaimDueImportJobs(context.Background(), ClaimDueImportJobsParams{
+		LeaseSeconds: 60,
+		BatchSize:    1000,
+	})
+	require.NoError(t, err)
+
+	var found bool
+	for _, c := range claimed {
+		if c.ID == job.ID {
+			found = true
+			require.Equal(t, "running", c.Status)
+			require.True(t, c.NextAttemptAt.After(time.Now()))
+		}
+	}
+	require.True(t, found)
+
+	item, err := testStore.FinishImportItem(context.Background(), FinishImportItemParams{
+		Status:   ImportItemFailed,
+		Error:    "unsupported",
+		JobID:    job.ID,
+		Position: 1,
+	})
+	require.NoError(t, err)
+	require.False(t, item.CacheID.Valid)
+
+	err = testStore.CompleteImportJob(context.Background(), job.ID)
+	require.NoError(t, err)
+
+	job, err = testStore.GetImportJob(context.Background(), job.ID)
+	require.NoError(t, err)
+	require.Equal(t, "done", job.Status)
+	require.Equal(t, int32(1), job.FailedCount)
+	require.True(t, job.FinishedAt.Valid)
+}
@@ -0,0 +1,98 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/imrishuroy/read-cache-api/util"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomInboundHook(t *testing.T, owner User) InboundHook {
+	hook, err := testStore.CreateInboundHook(context.Background(), CreateInboundHookParams{
+		UserID:    owner.ID,
+		Name:      util.RandomString(8),
+		TokenHash: util.RandomString(64),
+		Secret:    "whsec_test",
+	})
+	require.NoError(t, err)
+	require.False(t, hook.LastUsedAt.Valid)
+	return hook
+}
+
+func TestSaveInboundCacheTx(t *testing.T) {
+	owner := createRandomUser(t)
+	hook := createRandomInboundHook(t, owner)
+	tag := createRandomTag(t)
+
+	link := "https://example.com/" + util.RandomString(12)
+	arg := SaveInboundCacheTxParams{
+		CreateCacheWithTagsTxParams: CreateCacheWithTagsTxParams{
+			CreateCacheParams: CreateCacheParams{
+				Owner:         owner.ID,
+				Title:         util.RandomTitle(),
+				Content:       link,
+				IsPublic:      pgtype.Bool{Bool: false, Valid: true},
+				NormalizedUrl: link,
+			},
+			TagIDs:  []int32{tag.TagID},
+			LinkURL: link,
+		},
+		TagNames: []string{"inbound-" + util.RandomString(8)},
+		HookID:   hook.ID,
+		ClientID: util.RandomString(16),
+	}
+
+	first, err := testStore.SaveInboundCacheTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.False(t, first.Replayed)
+	require.Equal(t, owner.ID, first.Cache.Owner)
+	require.Len(t, first.Tags, 2)
+
+	// the same client id replays the first cache instead of colliding on the
+	// link, and creates none of its tags
+	arg.TagNames = []string{"inbound-" + util.RandomString(8)}
+	second, err := testStore.SaveInboundCacheTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.True(t, second.Replayed)
+	require.Equal(t, first.Cache.ID, second.Cache.ID)
+	require.Equal(t, first.Tags, second.Tags)
+	_, err = testStore.GetTagByName(context.Background(), arg.TagNames[0])
+	require.ErrorIs(t, err, ErrRecordNotFound)
+
+	hook, err = testStore.GetInboundHookByTokenHash(context.Background(), hook.TokenHash)
+	require.NoError(t, err)
+	require.True(t, hook.LastUsedAt.Valid)
+
+	// without a client id the same link is a duplicate
+	arg.ClientID = ""
+	_, err = testStore.SaveInboundCacheTx(context.Background(), arg)
+	require.Equal(t, UniqueViolation, ErrorCode(err))
+
+	// the rolled back request leaves no tags behind
+	_, err = testStore.GetTagByName(context.Background(), arg.TagNames[0])
+	require.ErrorIs(t, err, ErrRecordNotFound)
+}
+
+func TestDeleteInboundHook(t *testing.T) {
+	owner := createRandomUser(t)
+	hook := createRandomInboundHook(t, owner)
+
+	_, err := testStore.DeleteInboundHook(context.Background(), DeleteInboundHookParams{
+		ID:     hook.ID,
+		UserID: createRandomUser(t).ID,
+	})
+	require.ErrorIs(t, err, ErrRecordNotFound)
+
+	deleted, err := testStore.DeleteInboundHook(context.Background(), DeleteInboundHookParams{
+		ID:     hook.ID,
+		UserID: owner.ID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, hook.ID, deleted.ID)
+
+	hooks, err := testStore.ListInboundHooks(context.Background(), owner.ID)
+	require.NoError(t, err)
+	require.Empty(t, hooks)
+}
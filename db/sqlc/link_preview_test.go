@@ -0,0 +1,114 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/imrishuroy/read-cache-api/util"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomLinkPreview(t *testing.T) LinkPreview {
+	user := createRandomUser(t)
+
+	result, err := testStore.CreateCacheWithTagsTx(context.Background(), CreateCacheWithTagsTxParams{
+		CreateCacheParams: CreateCacheParams{
+			Owner:   user.ID,
+			Title:   util.RandomTitle(),
+			Content: "https://example.com/" + util.RandomString(8),
+		},
+		LinkURL: "https://example.com/" + util.RandomString(8),
+	})
+	require.NoError(t, err)
+
+	preview, err := testStore.GetLinkPreview(context.Background(), result.Cache.ID)
+	require.NoError(t, err)
+	require.Equal(t, "pending", preview.Status)
+	require.Zero(t, preview.Attempts)
+	require.False(t, preview.FetchedAt.Valid)
+
+	return preview
+}
+
+func TestClaimDueLinkPreviews(t *testing.T) {
+	preview := createRandomLinkPreview(t)
+
+	claimed, err := testStore.ClaimDueLinkPreviews(context.Background(), ClaimDueLinkPreviewsParams{
+		LeaseSeconds: 60,
+		BatchSize:    1000,
+	})
+	require.NoError(t, err)
+
+	var found bool
+	for _, p := range claimed {
+		if p.CacheID == preview.CacheID {
+			found = true
+			require.True(t, p.NextAttemptAt.After(time.Now().Add(30*time.Second)))
+		}
+	}
+	require.True(t, found)
+
+	// leased previews are not handed out twice
+	claimed, err = testStore.ClaimDueLinkPreviews(context.Background(), ClaimDueLinkPreviewsParams{
+		LeaseSeconds: 60,
+		BatchSize:    1000,
+	})
+	require.NoError(t, err)
+	for _, p := range claimed {
+		require.NotEqual(t, preview.CacheID, p.CacheID)
+	}
+}
+
+func TestCompleteLinkPreview(t *testing.T) {
+	preview := createRandomLinkPreview(t)
+
+	arg := CompleteLinkPreviewParams{
+		CacheID:      preview.CacheID,
+		Title:        util.RandomTitle(),
+		Description:  util.RandomContent(),
+		SiteName:     "Example",
+		ImageUrl:     "https://example.com/cover.png",
+		CanonicalUrl: preview.Url,
+	}
+	err := testStore.CompleteLinkPreview(context.Background(), arg)
+	require.NoError(t, err)
+
+	done, err := testStore.GetLinkPreview(context.Background(), preview.CacheID)
+	require.NoError(t, err)
+	require.Equal(t, "done", done.Status)
+	require.Equal(t, arg.Title, done.Title)
+	require.Equal(t, arg.ImageUrl, done.ImageUrl)
+	require.Equal(t, int32(1), done.Attempts)
+	require.True(t, done.FetchedAt.Valid)
+}
+
+func TestRetryAndFailLinkPreview(t *testing.T) {
+	preview := createRandomLinkPreview(t)
+	next := time.Now().Add(time.Hour)
+
+	err := testStore.RetryLinkPreview(context.Background(), RetryLinkPreviewParams{
+		CacheID:       preview.CacheID,
+		LastError:     "unexpected status 502",
+		NextAttemptAt: next,
+	})
+	require.NoError(t, err)
+
+	retried, err := testStore.GetLinkPreview(context.Background(), preview.CacheID)
+	require.NoError(t, err)
+	require.Equal(t, "pending", retried.Status)
+	require.Equal(t, int32(1), retried.Attempts)
+	require.WithinDuration(t, next, retried.NextAttemptAt, time.Second)
+
+	err = testStore.FailLinkPreview(context.Background(), FailLinkPreviewParams{
+		CacheID:   preview.CacheID,
+		LastError: "unexpected status 404",
+	})
+	require.NoError(t, err)
+
+	failed, err := testStore.GetLinkPreview(context.Background(), preview.CacheID)
+	require.NoError(t, err)
+	require.Equal(t, "failed", failed.Status)
+	require.Equal(t, int32(2), failed.Attempts)
+	require.Equal(t, "unexpected status 404", failed.LastError)
+}
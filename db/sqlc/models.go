@@ -5,18 +5,180 @@
 package db
 
 import (
+	"database/sql/driver"
+	"fmt"
 	"time"
 
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+type CollectionRole string
+
+const (
+	CollectionRoleViewer CollectionRole = "viewer"
+	CollectionRoleEditor CollectionRole = "editor"
+	CollectionRoleOwner  CollectionRole = "owner"
+)
+
+func (e *CollectionRole) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = CollectionRole(s)
+	case string:
+		*e = CollectionRole(s)
+	default:
+		return fmt.Errorf("unsupported scan type for CollectionRole: %T", src)
+	}
+	return nil
+}
+
+type NullCollectionRole struct {
+	CollectionRole CollectionRole `json:"collection_role"`
+	Valid          bool           `json:"valid"` // Valid is true if CollectionRole is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullCollectionRole) Scan(value interface{}) error {
+	if value == nil {
+		ns.CollectionRole, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.CollectionRole.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullCollectionRole) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.CollectionRole), nil
+}
+
+type InvitationStatus string
+
+const (
+	InvitationStatusPending  InvitationStatus = "pending"
+	InvitationStatusAccepted InvitationStatus = "accepted"
+	InvitationStatusRevoked  InvitationStatus = "revoked"
+	InvitationStatusDeclined InvitationStatus = "declined"
+)
+
+func (e *InvitationStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = InvitationStatus(s)
+	case string:
+		*e = InvitationStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for InvitationStatus: %T", src)
+	}
+	return nil
+}
+
+type NullInvitationStatus struct {
+	InvitationStatus InvitationStatus `json:"invitation_status"`
+	Valid            bool             `json:"valid"` // Valid is true if InvitationStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullInvitationStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.InvitationStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.InvitationStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullInvitationStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.InvitationStatus), nil
+}
+
+type ReadingStatus string
+
+const (
+	ReadingStatusUnread   ReadingStatus = "unread"
+	ReadingStatusReading  ReadingStatus = "reading"
+	ReadingStatusRead     ReadingStatus = "read"
+	ReadingStatusArchived ReadingStatus = "archived"
+)
+
+func (e *ReadingStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = ReadingStatus(s)
+	case string:
+		*e = ReadingStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for ReadingStatus: %T", src)
+	}
+	return nil
+}
+
+type NullReadingStatus struct {
+	ReadingStatus ReadingStatus `json:"reading_status"`
+	Valid         bool          `json:"valid"` // Valid is true if ReadingStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullReadingStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.ReadingStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.ReadingStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullReadingStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.ReadingStatus), nil
+}
+
+type Backfill struct {
+	Name       string    `json:"name"`
+	FinishedAt time.Time `json:"finished_at"`
+}
+
 type Cache struct {
-	ID        int64       `json:"id"`
-	Owner     string      `json:"owner"`
-	Title     string      `json:"title"`
-	Content   string      `json:"content"`
-	CreatedAt time.Time   `json:"created_at"`
-	IsPublic  pgtype.Bool `json:"is_public"`
+	ID            int64              `json:"id"`
+	Owner         string             `json:"owner"`
+	Title         string             `json:"title"`
+	Content       string             `json:"content"`
+	CreatedAt     time.Time          `json:"created_at"`
+	IsPublic      pgtype.Bool        `json:"is_public"`
+	NormalizedUrl string             `json:"normalized_url"`
+	Status        ReadingStatus      `json:"status"`
+	Progress      int32              `json:"progress"`
+	ReadAt        pgtype.Timestamptz `json:"read_at"`
+	ArchivedAt    pgtype.Timestamptz `json:"archived_at"`
+	LikeCount     int32              `json:"like_count"`
+	UpdatedAt     time.Time          `json:"updated_at"`
+	SearchVector  string             `json:"-"`
+	SavedAt       time.Time          `json:"saved_at"`
+}
+
+type CacheSnapshot struct {
+	CacheID       int64              `json:"cache_id"`
+	Url           string             `json:"url"`
+	Status        string             `json:"status"`
+	Title         string             `json:"title"`
+	Html          string             `json:"html"`
+	Text          string             `json:"text"`
+	Images        []string           `json:"images"`
+	Attempts      int32              `json:"attempts"`
+	LastError     string             `json:"last_error"`
+	NextAttemptAt time.Time          `json:"next_attempt_at"`
+	ArchivedAt    pgtype.Timestamptz `json:"archived_at"`
+	CreatedAt     time.Time          `json:"created_at"`
 }
 
 type CacheTag struct {
@@ -24,19 +186,223 @@ type CacheTag struct {
 	TagID   int32 `json:"tag_id"`
 }
 
+type Collection struct {
+	ID          int64     `json:"id"`
+	Owner       string    `json:"owner"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Icon        string    `json:"icon"`
+	IsPublic    bool      `json:"is_public"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type CollectionCache struct {
+	CollectionID int64     `json:"collection_id"`
+	CacheID      int64     `json:"cache_id"`
+	Position     int32     `json:"position"`
+	AddedAt      time.Time `json:"added_at"`
+}
+
+type CollectionInvitation struct {
+	ID           int64              `json:"id"`
+	CollectionID int64              `json:"collection_id"`
+	Inviter      string             `json:"inviter"`
+	InviteeEmail string             `json:"invitee_email"`
+	InviteeID    pgtype.Text        `json:"invitee_id"`
+	Role         CollectionRole     `json:"role"`
+	Status       InvitationStatus   `json:"status"`
+	CreatedAt    time.Time          `json:"created_at"`
+	RespondedAt  pgtype.Timestamptz `json:"responded_at"`
+}
+
+type CollectionMember struct {
+	CollectionID int64          `json:"collection_id"`
+	UserID       string         `json:"user_id"`
+	Role         CollectionRole `json:"role"`
+	CreatedAt    time.Time      `json:"created_at"`
+}
+
+type Comment struct {
+	ID        int64              `json:"id"`
+	CacheID   int64              `json:"cache_id"`
+	Author    string             `json:"author"`
+	ParentID  pgtype.Int8        `json:"parent_id"`
+	Body      string             `json:"body"`
+	CreatedAt time.Time          `json:"created_at"`
+	EditedAt  pgtype.Timestamptz `json:"edited_at"`
+	DeletedAt pgtype.Timestamptz `json:"deleted_at"`
+	HiddenAt  pgtype.Timestamptz `json:"hidden_at"`
+}
+
+type DigestSetting struct {
+	UserID     string             `json:"user_id"`
+	Frequency  string             `json:"frequency"`
+	LastSentAt time.Time          `json:"last_sent_at"`
+	NextSendAt pgtype.Timestamptz `json:"next_send_at"`
+	Attempts   int32              `json:"attempts"`
+	LastError  string             `json:"last_error"`
+	CreatedAt  time.Time          `json:"created_at"`
+	UpdatedAt  time.Time          `json:"updated_at"`
+}
+
+type ImportItem struct {
+	JobID         int64              `json:"job_id"`
+	Position      int32              `json:"position"`
+	Url           string             `json:"url"`
+	Title         string             `json:"title"`
+	Tags          []string           `json:"tags"`
+	AddedAt       pgtype.Timestamptz `json:"added_at"`
+	Archived      bool               `json:"archived"`
+	Status        string             `json:"status"`
+	CacheID       pgtype.Int8        `json:"cache_id"`
+	Error         string             `json:"error"`
+	CreatedAt     time.Time          `json:"created_at"`
+	Content       string             `json:"content"`
+	IsPublic      bool               `json:"is_public"`
+	ReadingStatus NullReadingStatus  `json:"reading_status"`
+	Progress      int32              `json:"progress"`
+	ReadAt        pgtype.Timestamptz `json:"read_at"`
+	ArchivedAt    pgtype.Timestamptz `json:"archived_at"`
+}
+
+type ImportJob struct {
+	ID            int64              `json:"id"`
+	UserID        string             `json:"user_id"`
+	Format        string             `json:"format"`
+	Filename      string             `json:"filename"`
+	Status        string             `json:"status"`
+	Total         int32              `json:"total"`
+	Processed     int32              `json:"processed"`
+	CreatedCount  int32              `json:"created_count"`
+	SkippedCount  int32              `json:"skipped_count"`
+	FailedCount   int32              `json:"failed_count"`
+	Attempts      int32              `json:"attempts"`
+	LastError     string             `json:"last_error"`
+	NextAttemptAt time.Time          `json:"next_attempt_at"`
+	CreatedAt     time.Time          `json:"created_at"`
+	FinishedAt    pgtype.Timestamptz `json:"finished_at"`
+}
+
+type InboundHook struct {
+	ID               int64              `json:"id"`
+	UserID           string             `json:"user_id"`
+	Name             string             `json:"name"`
+	TokenHash        string             `json:"token_hash"`
+	Secret           string             `json:"secret"`
+	RequireSignature bool               `json:"require_signature"`
+	IsPublic         bool               `json:"is_public"`
+	LastUsedAt       pgtype.Timestamptz `json:"last_used_at"`
+	CreatedAt        time.Time          `json:"created_at"`
+}
+
+type InboundHookRequest struct {
+	HookID    int64     `json:"hook_id"`
+	ClientID  string    `json:"client_id"`
+	CacheID   int64     `json:"cache_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type LinkPreview struct {
+	CacheID       int64              `json:"cache_id"`
+	Url           string             `json:"url"`
+	Status        string             `json:"status"`
+	Title         string             `json:"title"`
+	Description   string             `json:"description"`
+	SiteName      string             `json:"site_name"`
+	ImageUrl      string             `json:"image_url"`
+	CanonicalUrl  string             `json:"canonical_url"`
+	Attempts      int32              `json:"attempts"`
+	LastError     string             `json:"last_error"`
+	NextAttemptAt time.Time          `json:"next_attempt_at"`
+	FetchedAt     pgtype.Timestamptz `json:"fetched_at"`
+	CreatedAt     time.Time          `json:"created_at"`
+}
+
+type PersonalAccessToken struct {
+	ID         int64              `json:"id"`
+	UserID     string             `json:"user_id"`
+	Name       string             `json:"name"`
+	TokenHash  string             `json:"token_hash"`
+	Scope      string             `json:"scope"`
+	LastUsedAt pgtype.Timestamptz `json:"last_used_at"`
+	LastUsedIp pgtype.Text        `json:"last_used_ip"`
+	ExpiresAt  pgtype.Timestamptz `json:"expires_at"`
+	RevokedAt  pgtype.Timestamptz `json:"revoked_at"`
+	CreatedAt  time.Time          `json:"created_at"`
+}
+
+type Reminder struct {
+	ID          int64              `json:"id"`
+	CacheID     int64              `json:"cache_id"`
+	UserID      string             `json:"user_id"`
+	Note        string             `json:"note"`
+	RemindAt    time.Time          `json:"remind_at"`
+	Rrule       string             `json:"rrule"`
+	TimeZone    string             `json:"time_zone"`
+	NextFireAt  pgtype.Timestamptz `json:"next_fire_at"`
+	FireCount   int32              `json:"fire_count"`
+	LastFiredAt pgtype.Timestamptz `json:"last_fired_at"`
+	Attempts    int32              `json:"attempts"`
+	LastError   string             `json:"last_error"`
+	CreatedAt   time.Time          `json:"created_at"`
+	DueAt       pgtype.Timestamptz `json:"due_at"`
+}
+
 type Tag struct {
 	TagID   int32  `json:"tag_id"`
 	TagName string `json:"tag_name"`
 }
 
+type TimelineMarker struct {
+	UserID     string    `json:"user_id"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
 type User struct {
 	ID        string    `json:"id"`
 	Email     string    `json:"email"`
 	Name      string    `json:"name"`
 	CreatedAt time.Time `json:"created_at"`
+	Role      string    `json:"role"`
 }
 
 type UserTag struct {
 	UserID string `json:"user_id"`
 	TagID  int32  `json:"tag_id"`
 }
+
+type WebhookDelivery struct {
+	ID             int64              `json:"id"`
+	EndpointID     int64              `json:"endpoint_id"`
+	EventID        int64              `json:"event_id"`
+	Status         string             `json:"status"`
+	Attempts       int32              `json:"attempts"`
+	ResponseStatus pgtype.Int4        `json:"response_status"`
+	LastError      string             `json:"last_error"`
+	NextAttemptAt  time.Time          `json:"next_attempt_at"`
+	CreatedAt      time.Time          `json:"created_at"`
+	DeliveredAt    pgtype.Timestamptz `json:"delivered_at"`
+}
+
+type WebhookEndpoint struct {
+	ID          int64     `json:"id"`
+	UserID      string    `json:"user_id"`
+	Url         string    `json:"url"`
+	Secret      string    `json:"secret"`
+	Events      []string  `json:"events"`
+	Description string    `json:"description"`
+	Active      bool      `json:"active"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type WebhookEvent struct {
+	ID           int64              `json:"id"`
+	UserID       string             `json:"user_id"`
+	EventType    string             `json:"event_type"`
+	Payload      []byte             `json:"payload"`
+	CreatedAt    time.Time          `json:"created_at"`
+	DispatchedAt pgtype.Timestamptz `json:"dispatched_at"`
+}
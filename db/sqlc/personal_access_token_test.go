@@ -0,0 +1,85 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/imrishuroy/read-cache-api/util"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomPersonalAccessToken(t *testing.T) PersonalAccessToken {
+	user := createRandomUser(t)
+
+	arg := CreatePersonalAccessTokenParams{
+		UserID:    user.ID,
+		Name:      util.RandomTitle(),
+		TokenHash: util.RandomString(64),
+		Scope:     "read",
+	}
+
+	token, err := testStore.CreatePersonalAccessToken(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotEmpty(t, token)
+
+	require.Equal(t, arg.UserID, token.UserID)
+	require.Equal(t, arg.Name, token.Name)
+	require.Equal(t, arg.TokenHash, token.TokenHash)
+	require.Equal(t, arg.Scope, token.Scope)
+	require.False(t, token.RevokedAt.Valid)
+	require.False(t, token.LastUsedAt.Valid)
+	require.NotZero(t, token.CreatedAt)
+
+	return token
+}
+
+func TestCreatePersonalAccessToken(t *testing.T) {
+	createRandomPersonalAccessToken(t)
+}
+
+func TestGetPersonalAccessTokenByHash(t *testing.T) {
+	token1 := createRandomPersonalAccessToken(t)
+
+	token2, err := testStore.GetPersonalAccessTokenByHash(context.Background(), token1.TokenHash)
+	require.NoError(t, err)
+	require.Equal(t, token1.ID, token2.ID)
+	require.Equal(t, token1.UserID, token2.UserID)
+}
+
+func TestRevokePersonalAccessToken(t *testing.T) {
+	token1 := createRandomPersonalAccessToken(t)
+
+	arg := RevokePersonalAccessTokenParams{
+		ID:     token1.ID,
+		UserID: token1.UserID,
+	}
+
+	token2, err := testStore.RevokePersonalAccessToken(context.Background(), arg)
+	require.NoError(t, err)
+	require.True(t, token2.RevokedAt.Valid)
+
+	// revoking twice finds nothing to revoke
+	_, err = testStore.RevokePersonalAccessToken(context.Background(), arg)
+	require.EqualError(t, err, ErrRecordNotFound.Error())
+
+	tokens, err := testStore.ListPersonalAccessTokens(context.Background(), token1.UserID)
+	require.NoError(t, err)
+	require.Empty(t, tokens)
+}
+
+func TestUpdatePersonalAccessTokenLastUsed(t *testing.T) {
+	token1 := createRandomPersonalAccessToken(t)
+
+	arg := UpdatePersonalAccessTokenLastUsedParams{
+		ID:         token1.ID,
+		LastUsedIp: pgtype.Text{String: "127.0.0.1", Valid: true},
+	}
+	err := testStore.UpdatePersonalAccessTokenLastUsed(context.Background(), arg)
+	require.NoError(t, err)
+
+	token2, err := testStore.GetPersonalAccessTokenByHash(context.Background(), token1.TokenHash)
+	require.NoError(t, err)
+	require.True(t, token2.LastUsedAt.Valid)
+	require.Equal(t, arg.LastUsedIp, token2.LastUsedIp)
+}
@@ -9,28 +9,156 @@ import (
 )
 
 type Querier interface {
+	AddCacheLikeCount(ctx context.Context, arg AddCacheLikeCountParams) (int32, error)
+	AddCacheToCollection(ctx context.Context, arg AddCacheToCollectionParams) (int64, error)
 	AddTagToCache(ctx context.Context, arg AddTagToCacheParams) (CacheTag, error)
+	ClaimDueCacheSnapshots(ctx context.Context, arg ClaimDueCacheSnapshotsParams) ([]CacheSnapshot, error)
+	ClaimDueDigests(ctx context.Context, arg ClaimDueDigestsParams) ([]ClaimDueDigestsRow, error)
+	ClaimDueImportJobs(ctx context.Context, arg ClaimDueImportJobsParams) ([]ImportJob, error)
+	ClaimDueLinkPreviews(ctx context.Context, arg ClaimDueLinkPreviewsParams) ([]LinkPreview, error)
+	ClaimDueReminders(ctx context.Context, arg ClaimDueRemindersParams) ([]ClaimDueRemindersRow, error)
+	ClaimDueWebhookDeliveries(ctx context.Context, arg ClaimDueWebhookDeliveriesParams) ([]ClaimDueWebhookDeliveriesRow, error)
+	CompleteCacheSnapshot(ctx context.Context, arg CompleteCacheSnapshotParams) error
+	CompleteDigest(ctx context.Context, arg CompleteDigestParams) error
+	CompleteImportJob(ctx context.Context, id int64) error
+	CompleteLinkPreview(ctx context.Context, arg CompleteLinkPreviewParams) error
+	CompleteReminderFire(ctx context.Context, arg CompleteReminderFireParams) error
+	CompleteWebhookDelivery(ctx context.Context, arg CompleteWebhookDeliveryParams) error
+	CountCollectionOwners(ctx context.Context, collectionID int64) (int64, error)
+	CountUnseenTimelineCaches(ctx context.Context, arg CountUnseenTimelineCachesParams) (int64, error)
 	CreateCache(ctx context.Context, arg CreateCacheParams) (Cache, error)
+	CreateCacheLike(ctx context.Context, arg CreateCacheLikeParams) (int64, error)
+	CreateCollection(ctx context.Context, arg CreateCollectionParams) (Collection, error)
+	CreateCollectionInvitation(ctx context.Context, arg CreateCollectionInvitationParams) (CollectionInvitation, error)
+	CreateComment(ctx context.Context, arg CreateCommentParams) (Comment, error)
+	CreateImportItems(ctx context.Context, arg CreateImportItemsParams) (int64, error)
+	CreateImportJob(ctx context.Context, arg CreateImportJobParams) (ImportJob, error)
+	CreateInboundHook(ctx context.Context, arg CreateInboundHookParams) (InboundHook, error)
+	CreateInboundHookRequest(ctx context.Context, arg CreateInboundHookRequestParams) error
+	CreateLinkPreview(ctx context.Context, arg CreateLinkPreviewParams) (LinkPreview, error)
+	CreatePersonalAccessToken(ctx context.Context, arg CreatePersonalAccessTokenParams) (PersonalAccessToken, error)
+	CreateReminder(ctx context.Context, arg CreateReminderParams) (Reminder, error)
 	CreateTag(ctx context.Context, tagName string) (Tag, error)
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
+	CreateWebhookEndpoint(ctx context.Context, arg CreateWebhookEndpointParams) (WebhookEndpoint, error)
+	CreateWebhookEvent(ctx context.Context, arg CreateWebhookEventParams) error
 	DeleteCache(ctx context.Context, id int64) error
+	DeleteCacheLike(ctx context.Context, arg DeleteCacheLikeParams) (int64, error)
 	DeleteCacheTag(ctx context.Context, cacheID int64) error
+	DeleteCollection(ctx context.Context, id int64) error
+	DeleteCollectionMember(ctx context.Context, arg DeleteCollectionMemberParams) error
+	DeleteInboundHook(ctx context.Context, arg DeleteInboundHookParams) (InboundHook, error)
+	DeleteReminder(ctx context.Context, arg DeleteReminderParams) (Reminder, error)
 	DeleteTagFromCacheTagsTable(ctx context.Context, tagID int32) error
 	DeleteTagFromTagsTable(ctx context.Context, tagID int32) error
 	DeleteTagFromUserTagsTable(ctx context.Context, tagID int32) error
+	DeleteWebhookEndpoint(ctx context.Context, id int64) error
+	DispatchWebhookEvents(ctx context.Context, batchSize int32) (int64, error)
+	ExtendImportJobLease(ctx context.Context, arg ExtendImportJobLeaseParams) error
+	FailCacheSnapshot(ctx context.Context, arg FailCacheSnapshotParams) error
+	FailImportJob(ctx context.Context, arg FailImportJobParams) error
+	FailLinkPreview(ctx context.Context, arg FailLinkPreviewParams) error
+	FailWebhookDelivery(ctx context.Context, arg FailWebhookDeliveryParams) error
+	FinishBackfill(ctx context.Context, name string) error
+	FinishImportItem(ctx context.Context, arg FinishImportItemParams) (ImportItem, error)
+	GetBackfill(ctx context.Context, name string) (Backfill, error)
 	GetCache(ctx context.Context, id int64) (Cache, error)
+	GetCacheByNormalizedURL(ctx context.Context, arg GetCacheByNormalizedURLParams) (Cache, error)
+	GetCacheSnapshot(ctx context.Context, cacheID int64) (CacheSnapshot, error)
+	GetCollection(ctx context.Context, id int64) (Collection, error)
+	GetCollectionForUpdate(ctx context.Context, id int64) (Collection, error)
+	GetCollectionMember(ctx context.Context, arg GetCollectionMemberParams) (CollectionMember, error)
+	GetComment(ctx context.Context, id int64) (Comment, error)
+	GetDigestSettings(ctx context.Context, userID string) (DigestSetting, error)
+	GetImportJob(ctx context.Context, id int64) (ImportJob, error)
+	GetInboundHookByTokenHash(ctx context.Context, tokenHash string) (InboundHook, error)
+	GetInboundHookRequest(ctx context.Context, arg GetInboundHookRequestParams) (InboundHookRequest, error)
+	GetLinkPreview(ctx context.Context, cacheID int64) (LinkPreview, error)
+	GetPersonalAccessTokenByHash(ctx context.Context, tokenHash string) (PersonalAccessToken, error)
+	GetTag(ctx context.Context, tagID int32) (Tag, error)
+	GetTagByName(ctx context.Context, tagName string) (Tag, error)
+	GetTimelineMarker(ctx context.Context, userID string) (TimelineMarker, error)
 	GetUser(ctx context.Context, id string) (User, error)
 	GetUserByEmail(ctx context.Context, email string) (User, error)
+	GetWebhookDelivery(ctx context.Context, id int64) (WebhookDelivery, error)
+	GetWebhookEndpoint(ctx context.Context, id int64) (WebhookEndpoint, error)
+	ListCacheReminders(ctx context.Context, arg ListCacheRemindersParams) ([]Reminder, error)
 	ListCacheTags(ctx context.Context, cacheID int64) ([]Tag, error)
 	ListCaches(ctx context.Context, arg ListCachesParams) ([]Cache, error)
+	ListCachesByCursor(ctx context.Context, arg ListCachesByCursorParams) ([]Cache, error)
+	ListCachesForExport(ctx context.Context, arg ListCachesForExportParams) ([]ListCachesForExportRow, error)
+	ListCachesWithoutNormalizedURL(ctx context.Context, arg ListCachesWithoutNormalizedURLParams) ([]ListCachesWithoutNormalizedURLRow, error)
+	ListCollectionCacheIDsForUpdate(ctx context.Context, collectionID int64) ([]int64, error)
+	ListCollectionCaches(ctx context.Context, arg ListCollectionCachesParams) ([]ListCollectionCachesRow, error)
+	ListCollectionInvitations(ctx context.Context, collectionID int64) ([]CollectionInvitation, error)
+	ListCollectionMembers(ctx context.Context, collectionID int64) ([]ListCollectionMembersRow, error)
+	ListCollections(ctx context.Context, userID string) ([]ListCollectionsRow, error)
+	ListCommentReplies(ctx context.Context, arg ListCommentRepliesParams) ([]Comment, error)
+	ListComments(ctx context.Context, arg ListCommentsParams) ([]ListCommentsRow, error)
+	ListDigestCaches(ctx context.Context, arg ListDigestCachesParams) ([]ListDigestCachesRow, error)
+	ListFeedCaches(ctx context.Context, arg ListFeedCachesParams) ([]ListFeedCachesRow, error)
+	ListImportItems(ctx context.Context, arg ListImportItemsParams) ([]ImportItem, error)
+	ListImportJobs(ctx context.Context, arg ListImportJobsParams) ([]ImportJob, error)
+	ListInboundHooks(ctx context.Context, userID string) ([]InboundHook, error)
+	ListLikedCacheIDs(ctx context.Context, arg ListLikedCacheIDsParams) ([]int64, error)
+	ListMostLikedPublicCaches(ctx context.Context, arg ListMostLikedPublicCachesParams) ([]ListMostLikedPublicCachesRow, error)
+	ListPendingImportItems(ctx context.Context, arg ListPendingImportItemsParams) ([]ImportItem, error)
+	ListPendingInvitationsForUser(ctx context.Context, arg ListPendingInvitationsForUserParams) ([]ListPendingInvitationsForUserRow, error)
+	ListPersonalAccessTokens(ctx context.Context, userID string) ([]PersonalAccessToken, error)
 	ListPublicCaches(ctx context.Context, arg ListPublicCachesParams) ([]Cache, error)
+	ListPublicCachesByCursor(ctx context.Context, arg ListPublicCachesByCursorParams) ([]Cache, error)
 	ListPublicCachesByTags(ctx context.Context, arg ListPublicCachesByTagsParams) ([]Cache, error)
+	ListPublicCachesByTagsCursor(ctx context.Context, arg ListPublicCachesByTagsCursorParams) ([]Cache, error)
+	ListReminders(ctx context.Context, userID string) ([]Reminder, error)
+	ListTaggedCaches(ctx context.Context, tagID int32) ([]ListTaggedCachesRow, error)
 	ListTags(ctx context.Context) ([]Tag, error)
+	ListTimelineCaches(ctx context.Context, arg ListTimelineCachesParams) ([]Cache, error)
 	ListUserSubscriptions(ctx context.Context, userID string) ([]Tag, error)
+	ListWebhookDeliveries(ctx context.Context, arg ListWebhookDeliveriesParams) ([]ListWebhookDeliveriesRow, error)
+	ListWebhookEndpoints(ctx context.Context, userID string) ([]WebhookEndpoint, error)
+	LockCollection(ctx context.Context, id int64) error
+	MarkTimelineSeen(ctx context.Context, arg MarkTimelineSeenParams) (TimelineMarker, error)
+	RedeliverWebhookDelivery(ctx context.Context, id int64) (WebhookDelivery, error)
+	RemoveCacheFromCollection(ctx context.Context, arg RemoveCacheFromCollectionParams) (int64, error)
+	RequestCacheSnapshot(ctx context.Context, arg RequestCacheSnapshotParams) (CacheSnapshot, error)
+	RespondToCollectionInvitation(ctx context.Context, arg RespondToCollectionInvitationParams) (CollectionInvitation, error)
+	RetryCacheSnapshot(ctx context.Context, arg RetryCacheSnapshotParams) error
+	RetryDigest(ctx context.Context, arg RetryDigestParams) error
+	RetryImportJob(ctx context.Context, arg RetryImportJobParams) error
+	RetryLinkPreview(ctx context.Context, arg RetryLinkPreviewParams) error
+	RetryReminder(ctx context.Context, arg RetryReminderParams) error
+	RetryWebhookDelivery(ctx context.Context, arg RetryWebhookDeliveryParams) error
+	RevokeCollectionInvitation(ctx context.Context, arg RevokeCollectionInvitationParams) (CollectionInvitation, error)
+	RevokePersonalAccessToken(ctx context.Context, arg RevokePersonalAccessTokenParams) (PersonalAccessToken, error)
+	SearchCaches(ctx context.Context, arg SearchCachesParams) ([]SearchCachesRow, error)
+	SearchCachesByCursor(ctx context.Context, arg SearchCachesByCursorParams) ([]SearchCachesByCursorRow, error)
+	SearchTags(ctx context.Context, arg SearchTagsParams) ([]SearchTagsRow, error)
+	SetCacheReadingState(ctx context.Context, arg SetCacheReadingStateParams) error
+	SetCacheSavedAt(ctx context.Context, arg SetCacheSavedAtParams) error
+	SetCollectionCachePositions(ctx context.Context, arg SetCollectionCachePositionsParams) error
+	SetCommentHidden(ctx context.Context, arg SetCommentHiddenParams) (Comment, error)
+	SkipDigest(ctx context.Context, arg SkipDigestParams) error
+	SkipReminderFire(ctx context.Context, arg SkipReminderFireParams) error
+	SoftDeleteComment(ctx context.Context, id int64) (Comment, error)
 	SubscribeTag(ctx context.Context, arg SubscribeTagParams) (UserTag, error)
+	TouchInboundHook(ctx context.Context, id int64) error
+	UnsubscribeDigest(ctx context.Context, userID string) error
 	UnsubscribeTag(ctx context.Context, arg UnsubscribeTagParams) error
 	UpdateCache(ctx context.Context, arg UpdateCacheParams) (Cache, error)
+	UpdateCacheNormalizedURL(ctx context.Context, arg UpdateCacheNormalizedURLParams) error
+	UpdateCacheProgress(ctx context.Context, arg UpdateCacheProgressParams) (Cache, error)
+	UpdateCacheStatus(ctx context.Context, arg UpdateCacheStatusParams) (Cache, error)
+	UpdateCollection(ctx context.Context, arg UpdateCollectionParams) (Collection, error)
+	UpdateCollectionMemberRole(ctx context.Context, arg UpdateCollectionMemberRoleParams) (CollectionMember, error)
+	UpdateCommentBody(ctx context.Context, arg UpdateCommentBodyParams) (Comment, error)
+	UpdatePersonalAccessTokenLastUsed(ctx context.Context, arg UpdatePersonalAccessTokenLastUsedParams) error
+	UpdatePersonalAccessTokenName(ctx context.Context, arg UpdatePersonalAccessTokenNameParams) (PersonalAccessToken, error)
 	UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error)
+	UpdateUserRole(ctx context.Context, arg UpdateUserRoleParams) (User, error)
+	UpdateWebhookEndpoint(ctx context.Context, arg UpdateWebhookEndpointParams) (WebhookEndpoint, error)
+	UpsertCollectionMember(ctx context.Context, arg UpsertCollectionMemberParams) (CollectionMember, error)
+	UpsertDigestSettings(ctx context.Context, arg UpsertDigestSettingsParams) (DigestSetting, error)
+	UpsertTag(ctx context.Context, tagName string) (Tag, error)
 }
 
 var _ Querier = (*Queries)(nil)
@@ -0,0 +1,210 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomReminder(t *testing.T, nextFireAt time.Time) Reminder {
+	cache := createRandomCache(t)
+
+	arg := CreateReminderParams{
+		CacheID:    cache.ID,
+		UserID:     cache.Owner,
+		Note:       "read this",
+		RemindAt:   nextFireAt,
+		Rrule:      "FREQ=DAILY",
+		TimeZone:   "UTC",
+		NextFireAt: pgtype.Timestamptz{Time: nextFireAt, Valid: true},
+	}
+
+	reminder, err := testStore.CreateReminder(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotZero(t, reminder.ID)
+	require.Equal(t, arg.CacheID, reminder.CacheID)
+	require.Equal(t, arg.Rrule, reminder.Rrule)
+	require.WithinDuration(t, nextFireAt, reminder.NextFireAt.Time, time.Second)
+	require.Zero(t, reminder.FireCount)
+
+	return reminder
+}
+
+func TestClaimDueReminders(t *testing.T) {
+	due := createRandomReminder(t, time.Now().Add(-time.Hour))
+	future := createRandomReminder(t, time.Now().Add(time.Hour))
+
+	claimed, err := testStore.ClaimDueReminders(context.Background(), ClaimDueRemindersParams{
+		BatchSize:    1000,
+		LeaseSeconds: 60,
+	})
+	require.NoError(t, err)
+
+	var found bool
+	for _, r := range claimed {
+		require.NotEqual(t, future.ID, r.ID)
+		if r.ID == due.ID {
+			found = true
+			// the original due time is reported, not the lease
+			require.WithinDuration(t, due.NextFireAt.Time, r.DueAt.Time, time.Second)
+			require.NotEmpty(t, r.UserEmail)
+		}
+	}
+	require.True(t, found)
+
+	// leased reminders are not handed out twice
+	claimed, err = testStore.ClaimDueReminders(context.Background(), ClaimDueRemindersParams{
+		BatchSize:    1000,
+		LeaseSeconds: 60,
+	})
+	require.NoError(t, err)
+	for _, r := range claimed {
+		require.NotEqual(t, due.ID, r.ID)
+	}
+}
+
+func TestCompleteReminderFire(t *testing.T) {
+	reminder := createRandomReminder(t, time.Now().Add(-time.Minute))
+
+	err := testStore.CompleteReminderFire(context.Background(), CompleteReminderFireParams{
+		ID: reminder.ID,
+	})
+	require.NoError(t, err)
+
+	reminders, err := testStore.ListCacheReminders(context.Background(), ListCacheRemindersParams{
+		CacheID: reminder.CacheID,
+		UserID:  reminder.UserID,
+	})
+	require.NoError(t, err)
+	require.Len(t, reminders, 1)
+	require.False(t, reminders[0].NextFireAt.Valid)
+	require.True(t, reminders[0].LastFiredAt.Valid)
+	require.Equal(t, int32(1), reminders[0].FireCount)
+
+	// finished reminders drop off the upcoming list
+	upcoming, err := testStore.ListReminders(context.Background(), reminder.UserID)
+	require.NoError(t, err)
+	require.Empty(t, upcoming)
+}
+
+func TestDeleteReminder(t *testing.T) {
+	reminder := createRandomReminder(t, time.Now().Add(time.Hour))
+
+	_, err := testStore.DeleteReminder(context.Background(), DeleteReminderParams{
+		ID:     reminder.ID,
+		UserID: "someone-else",
+	})
+	require.ErrorIs(t, err, ErrRecordNotFound)
+
+	deleted, err := testStore.DeleteReminder(context.Background(), DeleteReminderParams{
+		ID:     reminder.ID,
+		UserID: reminder.UserID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, reminder.ID, deleted.ID)
+}
+
+func TestRetryReminderKeepsDueAt(t *testing.T) {
+	reminder := createRandomReminder(t, time.Now().Add(-time.Hour))
+
+	claimed, err := testStore.ClaimDueReminders(context.Background(), ClaimDueRemindersParams{
+		BatchSize:    1000,
+		LeaseSeconds: 60,
+	})
+	require.NoError(t, err)
+	require.True(t, containsReminder(claimed, reminder.ID))
+
+	err = testStore.RetryReminder(context.Background(), RetryReminderParams{
+		ID:         reminder.ID,
+		NextFireAt: pgtype.Timestamptz{Time: time.Now().Add(-time.Second), Valid: true},
+		LastError:  "mail server down",
+	})
+	require.NoError(t, err)
+
+	// the retry is still reported as due at the scheduled time
+	claimed, err = testStore.ClaimDueReminders(context.Background(), ClaimDueRemindersParams{
+		BatchSize:    1000,
+		LeaseSeconds: 60,
+	})
+	require.NoError(t, err)
+	for _, r := range claimed {
+		if r.ID == reminder.ID {
+			require.Equal(t, int32(1), r.Attempts)
+			require.WithinDuration(t, reminder.NextFireAt.Time, r.DueAt.Time, time.Second)
+		}
+	}
+	require.True(t, containsReminder(claimed, reminder.ID))
+
+	err = testStore.CompleteReminderFire(context.Background(), CompleteReminderFireParams{
+		ID:         reminder.ID,
+		NextFireAt: pgtype.Timestamptz{Time: time.Now().Add(time.Hour), Valid: true},
+	})
+	require.NoError(t, err)
+
+	reminders, err := testStore.ListCacheReminders(context.Background(), ListCacheRemindersParams{
+		CacheID: reminder.CacheID,
+		UserID:  reminder.UserID,
+	})
+	require.NoError(t, err)
+	require.Len(t, reminders, 1)
+	require.False(t, reminders[0].DueAt.Valid)
+}
+
+func TestClaimDueRemindersCancelsHiddenCaches(t *testing.T) {
+	cache := createRandomCache(t)
+	cache, err := testStore.UpdateCache(context.Background(), UpdateCacheParams{
+		ID:       cache.ID,
+		Title:    cache.Title,
+		Content:  cache.Content,
+		IsPublic: pgtype.Bool{Bool: true, Valid: true},
+	})
+	require.NoError(t, err)
+
+	reader := createRandomUser(t)
+	dueAt := time.Now().Add(-time.Hour)
+	reminder, err := testStore.CreateReminder(context.Background(), CreateReminderParams{
+		CacheID:    cache.ID,
+		UserID:     reader.ID,
+		RemindAt:   dueAt,
+		TimeZone:   "UTC",
+		NextFireAt: pgtype.Timestamptz{Time: dueAt, Valid: true},
+	})
+	require.NoError(t, err)
+
+	// the owner makes the cache private before the reminder fires
+	_, err = testStore.UpdateCache(context.Background(), UpdateCacheParams{
+		ID:       cache.ID,
+		Title:    cache.Title,
+		Content:  cache.Content,
+		IsPublic: pgtype.Bool{Bool: false, Valid: true},
+	})
+	require.NoError(t, err)
+
+	claimed, err := testStore.ClaimDueReminders(context.Background(), ClaimDueRemindersParams{
+		BatchSize:    1000,
+		LeaseSeconds: 60,
+	})
+	require.NoError(t, err)
+	require.False(t, containsReminder(claimed, reminder.ID))
+
+	reminders, err := testStore.ListCacheReminders(context.Background(), ListCacheRemindersParams{
+		CacheID: cache.ID,
+		UserID:  reader.ID,
+	})
+	require.NoError(t, err)
+	require.Len(t, reminders, 1)
+	require.False(t, reminders[0].NextFireAt.Valid)
+	require.NotEmpty(t, reminders[0].LastError)
+}
+
+func containsReminder(claimed []ClaimDueRemindersRow, id int64) bool {
+	for _, r := range claimed {
+		if r.ID == id {
+			return true
+		}
+	}
+	return false
+}
@@ -1,12 +1,31 @@
 package db
 
 import (
+	"context"
+
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 // Store defines all functions to execute db queries and transactions
 type Store interface {
 	Querier
+	DeleteTagTx(ctx context.Context, tagID int32) error
+	SetCacheTagsTx(ctx context.Context, arg SetCacheTagsTxParams) (SetCacheTagsTxResult, error)
+	CreateCacheWithTagsTx(ctx context.Context, arg CreateCacheWithTagsTxParams) (CreateCacheWithTagsTxResult, error)
+	UpdateCacheTx(ctx context.Context, arg UpdateCacheParams) (Cache, error)
+	DeleteCacheTx(ctx context.Context, id int64) error
+	SubscribeTagTx(ctx context.Context, arg SubscribeTagParams) (UserTag, error)
+	LikeCacheTx(ctx context.Context, arg LikeCacheTxParams) (LikeCacheTxResult, error)
+	UnlikeCacheTx(ctx context.Context, arg LikeCacheTxParams) (LikeCacheTxResult, error)
+	ReorderCollectionTx(ctx context.Context, arg ReorderCollectionTxParams) error
+	AddCacheToCollectionTx(ctx context.Context, arg AddCacheToCollectionParams) (int64, error)
+	CreateCollectionTx(ctx context.Context, arg CreateCollectionParams) (Collection, error)
+	AcceptCollectionInvitationTx(ctx context.Context, arg RespondToCollectionInvitationParams) (CollectionMember, error)
+	UpdateCollectionMemberTx(ctx context.Context, arg UpdateCollectionMemberRoleParams) (CollectionMember, error)
+	RemoveCollectionMemberTx(ctx context.Context, arg DeleteCollectionMemberParams) error
+	CreateImportJobTx(ctx context.Context, arg CreateImportJobTxParams) (ImportJob, error)
+	ImportCacheTx(ctx context.Context, arg ImportCacheTxParams) (ImportItem, error)
+	SaveInboundCacheTx(ctx context.Context, arg SaveInboundCacheTxParams) (SaveInboundCacheTxResult, error)
 }
 
 // SQLStore provides all functions to execute SQL queries and transactions
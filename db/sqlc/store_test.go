@@ -0,0 +1,123 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/imrishuroy/read-cache-api/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateCacheWithTagsTx(t *testing.T) {
+	user := createRandomUser(t)
+	tag1 := createRandomTag(t)
+	tag2 := createRandomTag(t)
+
+	arg := CreateCacheWithTagsTxParams{
+		CreateCacheParams: CreateCacheParams{
+			Owner:   user.ID,
+			Title:   util.RandomTitle(),
+			Content: util.RandomContent(),
+		},
+		TagIDs: []int32{tag1.TagID, tag2.TagID},
+	}
+
+	result, err := testStore.CreateCacheWithTagsTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, user.ID, result.Cache.Owner)
+	require.Len(t, result.Tags, 2)
+}
+
+func TestCreateCacheWithTagsTxRollback(t *testing.T) {
+	user := createRandomUser(t)
+	tag := createRandomTag(t)
+
+	// the duplicate tag makes the second insert fail, so the cache must not be created
+	arg := CreateCacheWithTagsTxParams{
+		CreateCacheParams: CreateCacheParams{
+			Owner:   user.ID,
+			Title:   util.RandomTitle(),
+			Content: util.RandomContent(),
+		},
+		TagIDs: []int32{tag.TagID, tag.TagID},
+	}
+
+	_, err := testStore.CreateCacheWithTagsTx(context.Background(), arg)
+	require.Error(t, err)
+	require.Equal(t, UniqueViolation, ErrorCode(err))
+
+	caches, err := testStore.ListCaches(context.Background(), ListCachesParams{
+		Owner: user.ID,
+		Limit: 5,
+	})
+	require.NoError(t, err)
+	require.Empty(t, caches)
+}
+
+func TestSetCacheTagsTx(t *testing.T) {
+	cache := createRandomCache(t)
+	tag1 := createRandomTag(t)
+	tag2 := createRandomTag(t)
+	tag3 := createRandomTag(t)
+
+	result, err := testStore.SetCacheTagsTx(context.Background(), SetCacheTagsTxParams{
+		CacheID: cache.ID,
+		TagIDs:  []int32{tag1.TagID, tag2.TagID},
+	})
+	require.NoError(t, err)
+	require.Len(t, result.Tags, 2)
+
+	// adding an existing tag fails and leaves the other new tag out
+	_, err = testStore.SetCacheTagsTx(context.Background(), SetCacheTagsTxParams{
+		CacheID: cache.ID,
+		TagIDs:  []int32{tag3.TagID, tag1.TagID},
+	})
+	require.Error(t, err)
+
+	tags, err := testStore.ListCacheTags(context.Background(), cache.ID)
+	require.NoError(t, err)
+	require.Len(t, tags, 2)
+
+	result, err = testStore.SetCacheTagsTx(context.Background(), SetCacheTagsTxParams{
+		CacheID:         cache.ID,
+		TagIDs:          []int32{tag3.TagID},
+		ReplaceExisting: true,
+	})
+	require.NoError(t, err)
+	require.Len(t, result.Tags, 1)
+	require.Equal(t, tag3.TagID, result.Tags[0].TagID)
+}
+
+func TestDeleteTagTx(t *testing.T) {
+	cache := createRandomCache(t)
+	tag := createRandomTag(t)
+
+	_, err := testStore.SetCacheTagsTx(context.Background(), SetCacheTagsTxParams{
+		CacheID: cache.ID,
+		TagIDs:  []int32{tag.TagID},
+	})
+	require.NoError(t, err)
+
+	_, err = testStore.SubscribeTag(context.Background(), SubscribeTagParams{
+		UserID: cache.Owner,
+		TagID:  tag.TagID,
+	})
+	require.NoError(t, err)
+
+	tagged, err := testStore.ListTaggedCaches(context.Background(), tag.TagID)
+	require.NoError(t, err)
+	require.Len(t, tagged, 1)
+	require.Equal(t, cache.ID, tagged[0].ID)
+	require.Equal(t, cache.Owner, tagged[0].Owner)
+
+	err = testStore.DeleteTagTx(context.Background(), tag.TagID)
+	require.NoError(t, err)
+
+	tags, err := testStore.ListCacheTags(context.Background(), cache.ID)
+	require.NoError(t, err)
+	require.Empty(t, tags)
+
+	subscriptions, err := testStore.ListUserSubscriptions(context.Background(), cache.Owner)
+	require.NoError(t, err)
+	require.Empty(t, subscriptions)
+}
@@ -85,6 +85,30 @@ func (q *Queries) DeleteTagFromUserTagsTable(ctx context.Context, tagID int32) e
 	return err
 }
 
+const getTag = `-- name: GetTag :one
+SELECT tag_id, tag_name FROM tags
+WHERE tag_id = $1 LIMIT 1
+`
+
+func (q *Queries) GetTag(ctx context.Context, tagID int32) (Tag, error) {
+	row := q.db.QueryRow(ctx, getTag, tagID)
+	var i Tag
+	err := row.Scan(&i.TagID, &i.TagName)
+	return i, err
+}
+
+const getTagByName = `-- name: GetTagByName :one
+SELECT tag_id, tag_name FROM tags
+WHERE tag_name = $1 LIMIT 1
+`
+
+func (q *Queries) GetTagByName(ctx context.Context, tagName string) (Tag, error) {
+	row := q.db.QueryRow(ctx, getTagByName, tagName)
+	var i Tag
+	err := row.Scan(&i.TagID, &i.TagName)
+	return i, err
+}
+
 const listCacheTags = `-- name: ListCacheTags :many
 SELECT t.tag_id, t.tag_name
 FROM cache_tags ct
@@ -112,6 +136,39 @@ func (q *Queries) ListCacheTags(ctx context.Context, cacheID int64) ([]Tag, erro
 	return items, nil
 }
 
+const listTaggedCaches = `-- name: ListTaggedCaches :many
+SELECT c.id, c.owner
+FROM cache_tags ct
+JOIN caches c ON c.id = ct.cache_id
+WHERE ct.tag_id = $1
+ORDER BY c.id
+`
+
+type ListTaggedCachesRow struct {
+	ID    int64  `json:"id"`
+	Owner string `json:"owner"`
+}
+
+func (q *Queries) ListTaggedCaches(ctx context.Context, tagID int32) ([]ListTaggedCachesRow, error) {
+	rows, err := q.db.Query(ctx, listTaggedCaches, tagID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListTaggedCachesRow{}
+	for rows.Next() {
+		var i ListTaggedCachesRow
+		if err := rows.Scan(&i.ID, &i.Owner); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listTags = `-- name: ListTags :many
 SELECT tag_id, tag_name FROM tags
 `
@@ -163,6 +220,54 @@ func (q *Queries) ListUserSubscriptions(ctx context.Context, userID string) ([]T
 	return items, nil
 }
 
+const searchTags = `-- name: SearchTags :many
+SELECT t.tag_id, t.tag_name,
+  similarity(t.tag_name, $2)::real AS similarity,
+  (SELECT count(*) FROM cache_tags ct WHERE ct.tag_id = t.tag_id) AS usage_count
+FROM tags t
+WHERE t.tag_name ILIKE $3 OR t.tag_name % $2
+ORDER BY t.tag_name ILIKE $3 DESC, similarity DESC, usage_count DESC, t.tag_name
+LIMIT $1
+`
+
+type SearchTagsParams struct {
+	Limit         int32  `json:"limit"`
+	Query         string `json:"query"`
+	PrefixPattern string `json:"prefix_pattern"`
+}
+
+type SearchTagsRow struct {
+	TagID      int32   `json:"tag_id"`
+	TagName    string  `json:"tag_name"`
+	Similarity float32 `json:"similarity"`
+	UsageCount int64   `json:"usage_count"`
+}
+
+func (q *Queries) SearchTags(ctx context.Context, arg SearchTagsParams) ([]SearchTagsRow, error) {
+	rows, err := q.db.Query(ctx, searchTags, arg.Limit, arg.Query, arg.PrefixPattern)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []SearchTagsRow{}
+	for rows.Next() {
+		var i SearchTagsRow
+		if err := rows.Scan(
+			&i.TagID,
+			&i.TagName,
+			&i.Similarity,
+			&i.UsageCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const subscribeTag = `-- name: SubscribeTag :one
 INSERT INTO user_tags (
   user_id,
@@ -198,3 +303,20 @@ func (q *Queries) UnsubscribeTag(ctx context.Context, arg UnsubscribeTagParams)
 	_, err := q.db.Exec(ctx, unsubscribeTag, arg.UserID, arg.TagID)
 	return err
 }
+
+const upsertTag = `-- name: UpsertTag :one
+INSERT INTO tags (
+  tag_name
+) VALUES (
+  $1
+)
+ON CONFLICT (tag_name) DO UPDATE SET tag_name = EXCLUDED.tag_name
+RETURNING tag_id, tag_name
+`
+
+func (q *Queries) UpsertTag(ctx context.Context, tagName string) (Tag, error) {
+	row := q.db.QueryRow(ctx, upsertTag, tagName)
+	var i Tag
+	err := row.Scan(&i.TagID, &i.TagName)
+	return i, err
+}
@@ -0,0 +1,45 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/imrishuroy/read-cache-api/util"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomTag(t *testing.T) Tag {
+	tag, err := testStore.CreateTag(context.Background(), util.RandomString(12))
+	require.NoError(t, err)
+	require.NotZero(t, tag.TagID)
+
+	return tag
+}
+
+func TestSearchTags(t *testing.T) {
+	tag := createRandomTag(t)
+
+	// prefix match
+	arg := SearchTagsParams{
+		Query:         tag.TagName[:6],
+		PrefixPattern: tag.TagName[:6] + "%",
+		Limit:         10,
+	}
+	tags, err := testStore.SearchTags(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotEmpty(t, tags)
+	require.Equal(t, tag.TagID, tags[0].TagID)
+
+	// fuzzy match with a typo in the last character
+	typo := tag.TagName[:11] + "0"
+	arg = SearchTagsParams{
+		Query:         typo,
+		PrefixPattern: typo + "%",
+		Limit:         10,
+	}
+	tags, err = testStore.SearchTags(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotEmpty(t, tags)
+	require.Equal(t, tag.TagID, tags[0].TagID)
+	require.Less(t, tags[0].Similarity, float32(1))
+}
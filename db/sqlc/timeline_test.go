@@ -0,0 +1,95 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListTimelineCaches(t *testing.T) {
+	reader := createRandomUser(t)
+	author := createRandomUser(t)
+	tag1 := createRandomTag(t)
+	tag2 := createRandomTag(t)
+
+	for _, tag := range []Tag{tag1, tag2} {
+		_, err := testStore.SubscribeTag(context.Background(), SubscribeTagParams{
+			UserID: reader.ID,
+			TagID:  tag.TagID,
+		})
+		require.NoError(t, err)
+	}
+
+	tagged := createPublicCache(t, author)
+	untagged := createPublicCache(t, author)
+	private := createOwnedCache(t, author)
+	own := createPublicCache(t, reader)
+
+	tagCache := func(cache Cache, tags ...Tag) {
+		for _, tag := range tags {
+			_, err := testStore.AddTagToCache(context.Background(), AddTagToCacheParams{
+				CacheID: cache.ID,
+				TagID:   tag.TagID,
+			})
+			require.NoError(t, err)
+		}
+	}
+	// both subscribed tags, still listed once
+	tagCache(tagged, tag1, tag2)
+	tagCache(private, tag1)
+	tagCache(own, tag1)
+
+	caches, err := testStore.ListTimelineCaches(context.Background(), ListTimelineCachesParams{
+		UserID:    reader.ID,
+		PageLimit: 10,
+	})
+	require.NoError(t, err)
+	require.Len(t, caches, 1)
+	require.Equal(t, tagged.ID, caches[0].ID)
+	require.NotEqual(t, untagged.ID, caches[0].ID)
+
+	unseen, err := testStore.CountUnseenTimelineCaches(context.Background(), CountUnseenTimelineCachesParams{
+		UserID:   reader.ID,
+		MaxCount: 100,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), unseen)
+
+	unseen, err = testStore.CountUnseenTimelineCaches(context.Background(), CountUnseenTimelineCachesParams{
+		UserID:   reader.ID,
+		Since:    pgtype.Timestamptz{Time: tagged.CreatedAt, Valid: true},
+		MaxCount: 100,
+	})
+	require.NoError(t, err)
+	require.Zero(t, unseen)
+}
+
+func TestMarkTimelineSeen(t *testing.T) {
+	user := createRandomUser(t)
+
+	_, err := testStore.GetTimelineMarker(context.Background(), user.ID)
+	require.ErrorIs(t, err, ErrRecordNotFound)
+
+	seenAt := time.Now().UTC().Truncate(time.Second)
+	marker, err := testStore.MarkTimelineSeen(context.Background(), MarkTimelineSeenParams{
+		UserID:     user.ID,
+		LastSeenAt: seenAt,
+	})
+	require.NoError(t, err)
+	require.True(t, marker.LastSeenAt.Equal(seenAt))
+
+	// the marker never moves back
+	marker, err = testStore.MarkTimelineSeen(context.Background(), MarkTimelineSeenParams{
+		UserID:     user.ID,
+		LastSeenAt: seenAt.Add(-time.Hour),
+	})
+	require.NoError(t, err)
+	require.True(t, marker.LastSeenAt.Equal(seenAt))
+
+	marker, err = testStore.GetTimelineMarker(context.Background(), user.ID)
+	require.NoError(t, err)
+	require.True(t, marker.LastSeenAt.Equal(seenAt))
+}
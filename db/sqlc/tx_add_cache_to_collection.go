@@ -0,0 +1,22 @@
+package db
+
+import "context"
+
+// AddCacheToCollectionTx appends a cache to a collection, it returns 0 when
+// the cache is already there. The collection row is locked first, so
+// concurrent adds wait for each other and never read the same max(position).
+func (store *SQLStore) AddCacheToCollectionTx(ctx context.Context, arg AddCacheToCollectionParams) (int64, error) {
+	var rows int64
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		_, err := q.GetCollectionForUpdate(ctx, arg.CollectionID)
+		if err != nil {
+			return err
+		}
+
+		rows, err = q.AddCacheToCollection(ctx, arg)
+		return err
+	})
+
+	return rows, err
+}
@@ -0,0 +1,120 @@
+package db
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrLastCollectionOwner is returned when a change would leave a collection without an owner
+var ErrLastCollectionOwner = errors.New("a collection needs at least one owner")
+
+// CreateCollectionTx creates a collection and makes its creator the first owner
+func (store *SQLStore) CreateCollectionTx(ctx context.Context, arg CreateCollectionParams) (Collection, error) {
+	var collection Collection
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		collection, err = q.CreateCollection(ctx, arg)
+		if err != nil {
+			return err
+		}
+
+		_, err = q.UpsertCollectionMember(ctx, UpsertCollectionMemberParams{
+			CollectionID: collection.ID,
+			UserID:       arg.Owner,
+			Role:         CollectionRoleOwner,
+		})
+		return err
+	})
+
+	return collection, err
+}
+
+// AcceptCollectionInvitationTx marks an invitation addressed to UserID or the
+// verified Email as accepted and grants its role. Accepting never lowers the role of someone
+// who is already a member.
+func (store *SQLStore) AcceptCollectionInvitationTx(ctx context.Context, arg RespondToCollectionInvitationParams) (CollectionMember, error) {
+	var member CollectionMember
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		arg.Status = InvitationStatusAccepted
+		invitation, err := q.RespondToCollectionInvitation(ctx, arg)
+		if err != nil {
+			return err
+		}
+
+		member, err = q.UpsertCollectionMember(ctx, UpsertCollectionMemberParams{
+			CollectionID: invitation.CollectionID,
+			UserID:       arg.UserID,
+			Role:         invitation.Role,
+		})
+		return err
+	})
+
+	return member, err
+}
+
+// UpdateCollectionMemberTx changes the role of a member. Changes to the
+// members of a collection are serialized on the collection row, so two owners
+// demoting each other at the same time can't both succeed.
+func (store *SQLStore) UpdateCollectionMemberTx(ctx context.Context, arg UpdateCollectionMemberRoleParams) (CollectionMember, error) {
+	var member CollectionMember
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		current, err := lockCollectionMember(ctx, q, arg.CollectionID, arg.UserID)
+		if err != nil {
+			return err
+		}
+
+		if current.Role == CollectionRoleOwner && arg.Role != CollectionRoleOwner {
+			if err := ensureAnotherOwner(ctx, q, arg.CollectionID); err != nil {
+				return err
+			}
+		}
+
+		member, err = q.UpdateCollectionMemberRole(ctx, arg)
+		return err
+	})
+
+	return member, err
+}
+
+// RemoveCollectionMemberTx takes a member out of a collection, unless they are its last owner
+func (store *SQLStore) RemoveCollectionMemberTx(ctx context.Context, arg DeleteCollectionMemberParams) error {
+	return store.execTx(ctx, func(q *Queries) error {
+		current, err := lockCollectionMember(ctx, q, arg.CollectionID, arg.UserID)
+		if err != nil {
+			return err
+		}
+
+		if current.Role == CollectionRoleOwner {
+			if err := ensureAnotherOwner(ctx, q, arg.CollectionID); err != nil {
+				return err
+			}
+		}
+
+		return q.DeleteCollectionMember(ctx, arg)
+	})
+}
+
+func lockCollectionMember(ctx context.Context, q *Queries, collectionID int64, userID string) (CollectionMember, error) {
+	if err := q.LockCollection(ctx, collectionID); err != nil {
+		return CollectionMember{}, err
+	}
+	return q.GetCollectionMember(ctx, GetCollectionMemberParams{
+		CollectionID: collectionID,
+		UserID:       userID,
+	})
+}
+
+func ensureAnotherOwner(ctx context.Context, q *Queries, collectionID int64) error {
+	owners, err := q.CountCollectionOwners(ctx, collectionID)
+	if err != nil {
+		return err
+	}
+	if owners <= 1 {
+		return ErrLastCollectionOwner
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package db
+
+import "context"
+
+// CreateCacheWithTagsTxParams contains the input parameters of the create cache with tags transaction
+type CreateCacheWithTagsTxParams struct {
+	CreateCacheParams
+	TagIDs []int32
+	// LinkURL queues a link preview and an article snapshot for the cache when set
+	LinkURL string
+}
+
+// CreateCacheWithTagsTxResult is the result of the create cache with tags transaction
+type CreateCacheWithTagsTxResult struct {
+	Cache Cache
+	Tags  []Tag
+}
+
+// CreateCacheWithTagsTx creates a cache and tags it in a single transaction,
+// recording cache.created
+func (store *SQLStore) CreateCacheWithTagsTx(ctx context.Context, arg CreateCacheWithTagsTxParams) (CreateCacheWithTagsTxResult, error) {
+	var result CreateCacheWithTagsTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		result, err = createCacheWithTags(ctx, q, arg)
+		return err
+	})
+
+	return result, err
+}
+
+func createCacheWithTags(ctx context.Context, q *Queries, arg CreateCacheWithTagsTxParams) (CreateCacheWithTagsTxResult, error) {
+	var result CreateCacheWithTagsTxResult
+	var err error
+
+	result.Cache, err = q.CreateCache(ctx, arg.CreateCacheParams)
+	if err != nil {
+		return result, err
+	}
+
+	err = addTagsToCache(ctx, q, result.Cache.ID, arg.TagIDs)
+	if err != nil {
+		return result, err
+	}
+
+	if arg.LinkURL != "" {
+		_, err = q.CreateLinkPreview(ctx, CreateLinkPreviewParams{
+			CacheID: result.Cache.ID,
+			Url:     arg.LinkURL,
+		})
+		if err != nil {
+			return result, err
+		}
+
+		_, err = q.RequestCacheSnapshot(ctx, RequestCacheSnapshotParams{
+			CacheID: result.Cache.ID,
+			Url:     arg.LinkURL,
+		})
+		if err != nil {
+			return result, err
+		}
+	}
+
+	result.Tags, err = q.ListCacheTags(ctx, result.Cache.ID)
+	if err != nil {
+		return result, err
+	}
+
+	err = recordEvent(ctx, q, result.Cache.Owner, EventCacheCreated, CacheEventPayload{
+		Cache: result.Cache,
+		Tags:  result.Tags,
+	})
+	return result, err
+}
@@ -0,0 +1,41 @@
+package db
+
+import "context"
+
+// DeleteTagTx removes a tag from every cache and subscription and then deletes
+// the tag itself, recording cache.tagged for every cache that carried it
+func (store *SQLStore) DeleteTagTx(ctx context.Context, tagID int32) error {
+	return store.execTx(ctx, func(q *Queries) error {
+		caches, err := q.ListTaggedCaches(ctx, tagID)
+		if err != nil {
+			return err
+		}
+
+		err = q.DeleteTagFromUserTagsTable(ctx, tagID)
+		if err != nil {
+			return err
+		}
+
+		err = q.DeleteTagFromCacheTagsTable(ctx, tagID)
+		if err != nil {
+			return err
+		}
+
+		for _, cache := range caches {
+			tags, err := q.ListCacheTags(ctx, cache.ID)
+			if err != nil {
+				return err
+			}
+
+			err = recordEvent(ctx, q, cache.Owner, EventCacheTagged, CacheTaggedPayload{
+				CacheID: cache.ID,
+				Tags:    tags,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		return q.DeleteTagFromTagsTable(ctx, tagID)
+	})
+}
@@ -0,0 +1,192 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Import item outcomes, the status column of import_items
+const (
+	ImportItemPending = "pending"
+	ImportItemCreated = "created"
+	ImportItemSkipped = "skipped"
+	ImportItemFailed  = "failed"
+)
+
+// NewImportItem is one bookmark or note of an uploaded file. ReadingStatus is
+// empty unless the file carries the full reading state, Archived is then the
+// only state imported.
+type NewImportItem struct {
+	Position      int32      `json:"position"`
+	URL           string     `json:"url"`
+	Title         string     `json:"title"`
+	Content       string     `json:"content"`
+	Tags          []string   `json:"tags"`
+	AddedAt       *time.Time `json:"added_at"`
+	Archived      bool       `json:"archived"`
+	IsPublic      bool       `json:"is_public"`
+	ReadingStatus string     `json:"reading_status,omitempty"`
+	Progress      int32      `json:"progress"`
+	ReadAt        *time.Time `json:"read_at"`
+	ArchivedAt    *time.Time `json:"archived_at"`
+}
+
+// CreateImportJobTxParams contains the input parameters of the create import job transaction
+type CreateImportJobTxParams struct {
+	UserID   string
+	Format   string
+	Filename string
+	Items    []NewImportItem
+}
+
+// CreateImportJobTx queues an import job together with all of its items, the
+// items are sent as a single json document instead of one insert per bookmark
+func (store *SQLStore) CreateImportJobTx(ctx context.Context, arg CreateImportJobTxParams) (ImportJob, error) {
+	var job ImportJob
+
+	items, err := json.Marshal(arg.Items)
+	if err != nil {
+		return job, err
+	}
+
+	err = store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		job, err = q.CreateImportJob(ctx, CreateImportJobParams{
+			UserID:   arg.UserID,
+			Format:   arg.Format,
+			Filename: arg.Filename,
+			Total:    int32(len(arg.Items)),
+		})
+		if err != nil {
+			return err
+		}
+
+		_, err = q.CreateImportItems(ctx, CreateImportItemsParams{
+			JobID: job.ID,
+			Items: items,
+		})
+		return err
+	})
+
+	return job, err
+}
+
+// ImportCacheTxParams contains the input parameters of the import cache
+// transaction, LinkURL and NormalizedURL are empty for a note
+type ImportCacheTxParams struct {
+	Owner         string
+	Item          ImportItem
+	LinkURL       string
+	NormalizedURL string
+}
+
+// ImportCacheTx saves one import item as a cache of the job's owner, or marks
+// it skipped when the owner already saved the link. Notes are never skipped. The item is finished and
+// counted on its job in the same transaction, so an item is never imported
+// twice: when it was finished concurrently the transaction fails with
+// ErrRecordNotFound and the new cache is rolled back.
+func (store *SQLStore) ImportCacheTx(ctx context.Context, arg ImportCacheTxParams) (ImportItem, error) {
+	var result ImportItem
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		if arg.NormalizedURL != "" {
+			existing, err := q.GetCacheByNormalizedURL(ctx, GetCacheByNormalizedURLParams{
+				Owner:         arg.Owner,
+				NormalizedUrl: arg.NormalizedURL,
+			})
+			if err == nil {
+				result, err = q.FinishImportItem(ctx, FinishImportItemParams{
+					Status:   ImportItemSkipped,
+					CacheID:  pgtype.Int8{Int64: existing.ID, Valid: true},
+					Error:    "already saved",
+					JobID:    arg.Item.JobID,
+					Position: arg.Item.Position,
+				})
+				return err
+			}
+			if !errors.Is(err, ErrRecordNotFound) {
+				return err
+			}
+		}
+
+		tagIDs := make([]int32, 0, len(arg.Item.Tags))
+		for _, name := range arg.Item.Tags {
+			tag, err := q.UpsertTag(ctx, name)
+			if err != nil {
+				return err
+			}
+			tagIDs = append(tagIDs, tag.TagID)
+		}
+
+		content := arg.LinkURL
+		if content == "" {
+			content = arg.Item.Content
+		}
+		title := arg.Item.Title
+		if title == "" {
+			title = arg.LinkURL
+		}
+
+		created, err := createCacheWithTags(ctx, q, CreateCacheWithTagsTxParams{
+			CreateCacheParams: CreateCacheParams{
+				Owner:         arg.Owner,
+				Title:         title,
+				Content:       content,
+				IsPublic:      pgtype.Bool{Bool: arg.Item.IsPublic, Valid: true},
+				NormalizedUrl: arg.NormalizedURL,
+			},
+			TagIDs:  tagIDs,
+			LinkURL: arg.LinkURL,
+		})
+		if err != nil {
+			return err
+		}
+
+		if arg.Item.AddedAt.Valid {
+			err = q.SetCacheSavedAt(ctx, SetCacheSavedAtParams{
+				ID:      created.Cache.ID,
+				SavedAt: arg.Item.AddedAt.Time,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		switch {
+		case arg.Item.ReadingStatus.Valid:
+			err = q.SetCacheReadingState(ctx, SetCacheReadingStateParams{
+				Status:     arg.Item.ReadingStatus.ReadingStatus,
+				Progress:   arg.Item.Progress,
+				ReadAt:     arg.Item.ReadAt,
+				ArchivedAt: arg.Item.ArchivedAt,
+				ID:         created.Cache.ID,
+			})
+			if err != nil {
+				return err
+			}
+		case arg.Item.Archived:
+			_, err = q.UpdateCacheStatus(ctx, UpdateCacheStatusParams{
+				Status: ReadingStatusArchived,
+				ID:     created.Cache.ID,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		result, err = q.FinishImportItem(ctx, FinishImportItemParams{
+			Status:   ImportItemCreated,
+			CacheID:  pgtype.Int8{Int64: created.Cache.ID, Valid: true},
+			JobID:    arg.Item.JobID,
+			Position: arg.Item.Position,
+		})
+		return err
+	})
+
+	return result, err
+}
@@ -0,0 +1,56 @@
+package db
+
+import "context"
+
+// LikeCacheTxParams contains the input parameters of the like and unlike transactions
+type LikeCacheTxParams struct {
+	CacheID int64
+	UserID  string
+}
+
+// LikeCacheTxResult is the result of the like and unlike transactions
+type LikeCacheTxResult struct {
+	LikeCount int32
+}
+
+// LikeCacheTx records a like and bumps caches.like_count in the same
+// transaction. Liking twice is a no-op, so the count only moves when a row
+// was actually inserted.
+func (store *SQLStore) LikeCacheTx(ctx context.Context, arg LikeCacheTxParams) (LikeCacheTxResult, error) {
+	var result LikeCacheTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		rows, err := q.CreateCacheLike(ctx, CreateCacheLikeParams(arg))
+		if err != nil {
+			return err
+		}
+
+		result.LikeCount, err = q.AddCacheLikeCount(ctx, AddCacheLikeCountParams{
+			ID:    arg.CacheID,
+			Delta: int32(rows),
+		})
+		return err
+	})
+
+	return result, err
+}
+
+// UnlikeCacheTx removes a like and decrements caches.like_count if there was one
+func (store *SQLStore) UnlikeCacheTx(ctx context.Context, arg LikeCacheTxParams) (LikeCacheTxResult, error) {
+	var result LikeCacheTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		rows, err := q.DeleteCacheLike(ctx, DeleteCacheLikeParams(arg))
+		if err != nil {
+			return err
+		}
+
+		result.LikeCount, err = q.AddCacheLikeCount(ctx, AddCacheLikeCountParams{
+			ID:    arg.CacheID,
+			Delta: -int32(rows),
+		})
+		return err
+	})
+
+	return result, err
+}
@@ -0,0 +1,43 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"slices"
+)
+
+// ErrCollectionOrderMismatch is returned when a new order doesn't list exactly
+// the caches in the collection
+var ErrCollectionOrderMismatch = errors.New("cache_ids must list every cache in the collection exactly once")
+
+// ReorderCollectionTxParams contains the input parameters of the reorder collection transaction
+type ReorderCollectionTxParams struct {
+	CollectionID int64
+	// CacheIDs is the complete membership of the collection in its new order
+	CacheIDs []int64
+}
+
+// ReorderCollectionTx renumbers the caches of a collection in the order of
+// CacheIDs. The membership rows are locked first, so a cache added or removed
+// concurrently makes the order stale and the transaction fails with
+// ErrCollectionOrderMismatch instead of leaving gaps.
+func (store *SQLStore) ReorderCollectionTx(ctx context.Context, arg ReorderCollectionTxParams) error {
+	return store.execTx(ctx, func(q *Queries) error {
+		current, err := q.ListCollectionCacheIDsForUpdate(ctx, arg.CollectionID)
+		if err != nil {
+			return err
+		}
+
+		requested := slices.Clone(arg.CacheIDs)
+		slices.Sort(current)
+		slices.Sort(requested)
+		if !slices.Equal(current, requested) {
+			return ErrCollectionOrderMismatch
+		}
+
+		return q.SetCollectionCachePositions(ctx, SetCollectionCachePositionsParams{
+			CacheIds:     arg.CacheIDs,
+			CollectionID: arg.CollectionID,
+		})
+	})
+}
@@ -0,0 +1,86 @@
+package db
+
+import (
+	"context"
+	"errors"
+)
+
+// SaveInboundCacheTxParams contains the input parameters of the save inbound cache transaction
+type SaveInboundCacheTxParams struct {
+	CreateCacheWithTagsTxParams
+	// TagNames are upserted and added to TagIDs, only for a request that
+	// isn't replayed
+	TagNames []string
+	HookID   int64
+	// ClientID makes the request idempotent when set
+	ClientID string
+}
+
+// SaveInboundCacheTxResult is the result of the save inbound cache transaction
+type SaveInboundCacheTxResult struct {
+	CreateCacheWithTagsTxResult
+	// Replayed is set when the hook saw ClientID before, the result is then
+	// the cache the first request created
+	Replayed bool
+}
+
+// SaveInboundCacheTx saves a cache sent to an inbound hook and marks the hook
+// used. The client id is recorded together with the cache, so a retried
+// request either finds it or rolls back with a unique violation. Tags are
+// upserted in the same transaction and never for a replayed request.
+func (store *SQLStore) SaveInboundCacheTx(ctx context.Context, arg SaveInboundCacheTxParams) (SaveInboundCacheTxResult, error) {
+	var result SaveInboundCacheTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		err := q.TouchInboundHook(ctx, arg.HookID)
+		if err != nil {
+			return err
+		}
+
+		if arg.ClientID != "" {
+			request, err := q.GetInboundHookRequest(ctx, GetInboundHookRequestParams{
+				HookID:   arg.HookID,
+				ClientID: arg.ClientID,
+			})
+			if err == nil {
+				result.Replayed = true
+				result.Cache, err = q.GetCache(ctx, request.CacheID)
+				if err != nil {
+					return err
+				}
+				result.Tags, err = q.ListCacheTags(ctx, request.CacheID)
+				return err
+			}
+			if !errors.Is(err, ErrRecordNotFound) {
+				return err
+			}
+		}
+
+		params := arg.CreateCacheWithTagsTxParams
+		params.TagIDs = make([]int32, 0, len(arg.TagIDs)+len(arg.TagNames))
+		params.TagIDs = append(params.TagIDs, arg.TagIDs...)
+		for _, name := range arg.TagNames {
+			tag, err := q.UpsertTag(ctx, name)
+			if err != nil {
+				return err
+			}
+			params.TagIDs = append(params.TagIDs, tag.TagID)
+		}
+
+		result.CreateCacheWithTagsTxResult, err = createCacheWithTags(ctx, q, params)
+		if err != nil {
+			return err
+		}
+
+		if arg.ClientID == "" {
+			return nil
+		}
+		return q.CreateInboundHookRequest(ctx, CreateInboundHookRequestParams{
+			HookID:   arg.HookID,
+			ClientID: arg.ClientID,
+			CacheID:  result.Cache.ID,
+		})
+	})
+
+	return result, err
+}
@@ -0,0 +1,68 @@
+package db
+
+import "context"
+
+// SetCacheTagsTxParams contains the input parameters of the set cache tags transaction
+type SetCacheTagsTxParams struct {
+	CacheID int64
+	TagIDs  []int32
+	// ReplaceExisting removes the tags the cache already has before adding TagIDs
+	ReplaceExisting bool
+}
+
+// SetCacheTagsTxResult is the result of the set cache tags transaction
+type SetCacheTagsTxResult struct {
+	Tags []Tag
+}
+
+// SetCacheTagsTx tags a cache with all of TagIDs or, if any insert fails, none
+// of them, recording cache.tagged
+func (store *SQLStore) SetCacheTagsTx(ctx context.Context, arg SetCacheTagsTxParams) (SetCacheTagsTxResult, error) {
+	var result SetCacheTagsTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		if arg.ReplaceExisting {
+			err = q.DeleteCacheTag(ctx, arg.CacheID)
+			if err != nil {
+				return err
+			}
+		}
+
+		err = addTagsToCache(ctx, q, arg.CacheID, arg.TagIDs)
+		if err != nil {
+			return err
+		}
+
+		result.Tags, err = q.ListCacheTags(ctx, arg.CacheID)
+		if err != nil {
+			return err
+		}
+
+		cache, err := q.GetCache(ctx, arg.CacheID)
+		if err != nil {
+			return err
+		}
+
+		return recordEvent(ctx, q, cache.Owner, EventCacheTagged, CacheTaggedPayload{
+			CacheID: arg.CacheID,
+			Tags:    result.Tags,
+		})
+	})
+
+	return result, err
+}
+
+func addTagsToCache(ctx context.Context, q *Queries, cacheID int64, tagIDs []int32) error {
+	for _, tagID := range tagIDs {
+		_, err := q.AddTagToCache(ctx, AddTagToCacheParams{
+			CacheID: cacheID,
+			TagID:   tagID,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
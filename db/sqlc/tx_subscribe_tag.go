@@ -0,0 +1,25 @@
+package db
+
+import "context"
+
+// SubscribeTagTx subscribes a user to a tag and records tag.subscribed
+func (store *SQLStore) SubscribeTagTx(ctx context.Context, arg SubscribeTagParams) (UserTag, error) {
+	var userTag UserTag
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		userTag, err = q.SubscribeTag(ctx, arg)
+		if err != nil {
+			return err
+		}
+
+		tag, err := q.GetTag(ctx, arg.TagID)
+		if err != nil {
+			return err
+		}
+
+		return recordEvent(ctx, q, arg.UserID, EventTagSubscribed, TagSubscribedPayload{Tag: tag})
+	})
+
+	return userTag, err
+}
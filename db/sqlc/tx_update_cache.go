@@ -0,0 +1,49 @@
+package db
+
+import "context"
+
+// UpdateCacheTx updates a cache and records cache.updated
+func (store *SQLStore) UpdateCacheTx(ctx context.Context, arg UpdateCacheParams) (Cache, error) {
+	var cache Cache
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		cache, err = q.UpdateCache(ctx, arg)
+		if err != nil {
+			return err
+		}
+
+		return recordEvent(ctx, q, cache.Owner, EventCacheUpdated, CacheEventPayload{Cache: cache})
+	})
+
+	return cache, err
+}
+
+// DeleteCacheTx deletes a cache together with its tags and records
+// cache.deleted with the cache as it was
+func (store *SQLStore) DeleteCacheTx(ctx context.Context, id int64) error {
+	return store.execTx(ctx, func(q *Queries) error {
+		cache, err := q.GetCache(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		tags, err := q.ListCacheTags(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		// cache_tags doesn't cascade, the cache's tags go first
+		err = q.DeleteCacheTag(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		err = q.DeleteCache(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		return recordEvent(ctx, q, cache.Owner, EventCacheDeleted, CacheEventPayload{Cache: cache, Tags: tags})
+	})
+}
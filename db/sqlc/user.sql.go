@@ -18,7 +18,7 @@ INSERT INTO users (
   name
 ) VALUES (
   $1, $2, $3
-) RETURNING id, email, name, created_at
+) RETURNING id, email, name, created_at, role
 `
 
 type CreateUserParams struct {
@@ -35,12 +35,13 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.Email,
 		&i.Name,
 		&i.CreatedAt,
+		&i.Role,
 	)
 	return i, err
 }
 
 const getUser = `-- name: GetUser :one
-SELECT id, email, name, created_at FROM users
+SELECT id, email, name, created_at, role FROM users
 WHERE id = $1 LIMIT 1
 `
 
@@ -52,12 +53,13 @@ func (q *Queries) GetUser(ctx context.Context, id string) (User, error) {
 		&i.Email,
 		&i.Name,
 		&i.CreatedAt,
+		&i.Role,
 	)
 	return i, err
 }
 
 const getUserByEmail = `-- name: GetUserByEmail :one
-SELECT id, email, name, created_at FROM users
+SELECT id, email, name, created_at, role FROM users
 WHERE email = $1 LIMIT 1
 `
 
@@ -69,6 +71,7 @@ func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error
 		&i.Email,
 		&i.Name,
 		&i.CreatedAt,
+		&i.Role,
 	)
 	return i, err
 }
@@ -79,7 +82,7 @@ SET
   name = COALESCE($1, name)
 WHERE
   id = $2
-RETURNING id, email, name, created_at
+RETURNING id, email, name, created_at, role
 `
 
 type UpdateUserParams struct {
@@ -95,6 +98,32 @@ func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, e
 		&i.Email,
 		&i.Name,
 		&i.CreatedAt,
+		&i.Role,
+	)
+	return i, err
+}
+
+const updateUserRole = `-- name: UpdateUserRole :one
+UPDATE users
+SET role = $2
+WHERE id = $1
+RETURNING id, email, name, created_at, role
+`
+
+type UpdateUserRoleParams struct {
+	ID   string `json:"id"`
+	Role string `json:"role"`
+}
+
+func (q *Queries) UpdateUserRole(ctx context.Context, arg UpdateUserRoleParams) (User, error) {
+	row := q.db.QueryRow(ctx, updateUserRole, arg.ID, arg.Role)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.Name,
+		&i.CreatedAt,
+		&i.Role,
 	)
 	return i, err
 }
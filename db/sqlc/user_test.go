@@ -28,3 +28,18 @@ func createRandomUser(t *testing.T) User {
 	return user
 
 }
+
+func TestUpdateUserRole(t *testing.T) {
+	user1 := createRandomUser(t)
+	require.Equal(t, "user", user1.Role)
+
+	arg := UpdateUserRoleParams{
+		ID:   user1.ID,
+		Role: "admin",
+	}
+
+	user2, err := testStore.UpdateUserRole(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, user1.ID, user2.ID)
+	require.Equal(t, arg.Role, user2.Role)
+}
@@ -0,0 +1,57 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// Event types users can subscribe webhook endpoints to
+const (
+	EventCacheCreated  = "cache.created"
+	EventCacheUpdated  = "cache.updated"
+	EventCacheDeleted  = "cache.deleted"
+	EventCacheTagged   = "cache.tagged"
+	EventTagSubscribed = "tag.subscribed"
+)
+
+// EventTypes lists every event type, in the order they are documented
+var EventTypes = []string{
+	EventCacheCreated,
+	EventCacheUpdated,
+	EventCacheDeleted,
+	EventCacheTagged,
+	EventTagSubscribed,
+}
+
+// CacheEventPayload is the data of cache.created, cache.updated and cache.deleted
+type CacheEventPayload struct {
+	Cache Cache `json:"cache"`
+	Tags  []Tag `json:"tags,omitempty"`
+}
+
+// CacheTaggedPayload is the data of cache.tagged, Tags are all the tags the cache has now
+type CacheTaggedPayload struct {
+	CacheID int64 `json:"cache_id"`
+	Tags    []Tag `json:"tags"`
+}
+
+// TagSubscribedPayload is the data of tag.subscribed
+type TagSubscribedPayload struct {
+	Tag Tag `json:"tag"`
+}
+
+// recordEvent writes an event to the webhook outbox with q, so it commits or
+// rolls back together with the change it describes. Nothing is written when
+// userID has no active endpoint for eventType.
+func recordEvent(ctx context.Context, q *Queries, userID string, eventType string, payload any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	return q.CreateWebhookEvent(ctx, CreateWebhookEventParams{
+		UserID:    userID,
+		EventType: eventType,
+		Payload:   data,
+	})
+}
@@ -0,0 +1,122 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomWebhookEndpoint(t *testing.T, owner User, events ...string) WebhookEndpoint {
+	endpoint, err := testStore.CreateWebhookEndpoint(context.Background(), CreateWebhookEndpointParams{
+		UserID: owner.ID,
+		Url:    "https://hooks.example.com/readcache",
+		Secret: "whsec_test",
+		Events: events,
+	})
+	require.NoError(t, err)
+	require.True(t, endpoint.Active)
+	require.Equal(t, events, endpoint.Events)
+	return endpoint
+}
+
+func TestWebhookOutbox(t *testing.T) {
+	owner := createRandomUser(t)
+	endpoint := createRandomWebhookEndpoint(t, owner, EventCacheUpdated)
+	cache := createOwnedCache(t, owner)
+
+	updated, err := testStore.UpdateCacheTx(context.Background(), UpdateCacheParams{
+		ID:       cache.ID,
+		Title:    "Updated title",
+		Content:  cache.Content,
+		IsPublic: cache.IsPublic,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "Updated title", updated.Title)
+
+	// no endpoint subscribes to cache.deleted, so nothing is written for it
+	err = testStore.DeleteCacheTx(context.Background(), cache.ID)
+	require.NoError(t, err)
+
+	_, err = testStore.DispatchWebhookEvents(context.Background(), 1000)
+	require.NoError(t, err)
+
+	deliveries, err := testStore.ListWebhookDeliveries(context.Background(), ListWebhookDeliveriesParams{
+		EndpointID: endpoint.ID,
+		PageLimit:  10,
+	})
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	require.Equal(t, EventCacheUpdated, deliveries[0].EventType)
+	require.Equal(t, "pending", deliveries[0].Status)
+
+	redelivery, err := testStore.RedeliverWebhookDelivery(context.Background(), deliveries[0].ID)
+	require.NoError(t, err)
+	require.NotEqual(t, deliveries[0].ID, redelivery.ID)
+	require.Equal(t, deliveries[0].EventID, redelivery.EventID)
+	require.Equal(t, "pending", redelivery.Status)
+}
+
+func TestWebhookOutboxRollsBackWithHandler(t *testing.T) {
+	owner := createRandomUser(t)
+	endpoint := createRandomWebhookEndpoint(t, owner, EventCacheDeleted)
+
+	// deleting a missing cache fails the transaction before the event is written
+	err := testStore.DeleteCacheTx(context.Background(), -1)
+	require.ErrorIs(t, err, ErrRecordNotFound)
+
+	_, err = testStore.DispatchWebhookEvents(context.Background(), 1000)
+	require.NoError(t, err)
+
+	deliveries, err := testStore.ListWebhookDeliveries(context.Background(), ListWebhookDeliveriesParams{
+		EndpointID: endpoint.ID,
+		PageLimit:  10,
+	})
+	require.NoError(t, err)
+	require.Empty(t, deliveries)
+}
+
+func TestDeleteTaggedCacheTx(t *testing.T) {
+	owner := createRandomUser(t)
+	endpoint := createRandomWebhookEndpoint(t, owner, EventCacheDeleted)
+	cache := createOwnedCache(t, owner)
+	tag := createRandomTag(t)
+
+	_, err := testStore.AddTagToCache(context.Background(), AddTagToCacheParams{CacheID: cache.ID, TagID: tag.TagID})
+	require.NoError(t, err)
+
+	err = testStore.DeleteCacheTx(context.Background(), cache.ID)
+	require.NoError(t, err)
+
+	_, err = testStore.GetCache(context.Background(), cache.ID)
+	require.ErrorIs(t, err, ErrRecordNotFound)
+	tags, err := testStore.ListCacheTags(context.Background(), cache.ID)
+	require.NoError(t, err)
+	require.Empty(t, tags)
+
+	_, err = testStore.DispatchWebhookEvents(context.Background(), 1000)
+	require.NoError(t, err)
+
+	claimed, err := testStore.ClaimDueWebhookDeliveries(context.Background(), ClaimDueWebhookDeliveriesParams{
+		BatchSize:    1000,
+		LeaseSeconds: 60,
+	})
+	require.NoError(t, err)
+
+	var deliveries []ClaimDueWebhookDeliveriesRow
+	for _, delivery := range claimed {
+		if delivery.EndpointID == endpoint.ID {
+			deliveries = append(deliveries, delivery)
+		}
+	}
+	require.Len(t, deliveries, 1)
+	require.Equal(t, EventCacheDeleted, deliveries[0].EventType)
+
+	// the event carries the cache and the tags it had before it was deleted
+	var payload CacheEventPayload
+	require.NoError(t, json.Unmarshal(deliveries[0].Payload, &payload))
+	require.Equal(t, cache.ID, payload.Cache.ID)
+	require.Len(t, payload.Tags, 1)
+	require.Equal(t, tag.TagID, payload.Tags[0].TagID)
+}
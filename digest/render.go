@@ -0,0 +1,51 @@
+package digest
+
+import (
+	"bytes"
+	"embed"
+	htmltemplate "html/template"
+	"strings"
+	texttemplate "text/template"
+	"time"
+)
+
+//go:embed templates
+var templateFS embed.FS
+
+var (
+	funcs        = map[string]any{"join": strings.Join}
+	textTemplate = texttemplate.Must(texttemplate.New("digest.txt").Funcs(funcs).ParseFS(templateFS, "templates/digest.txt"))
+	htmlTemplate = htmltemplate.Must(htmltemplate.New("digest.html").Funcs(funcs).ParseFS(templateFS, "templates/digest.html"))
+)
+
+// Digest is what the templates are rendered with
+type Digest struct {
+	Name      string
+	Frequency string
+	Since     time.Time
+	Caches    []Entry
+	// More is set when there were more new caches than fit in one digest
+	More           bool
+	UnsubscribeURL string
+}
+
+// Entry is one cache in a digest
+type Entry struct {
+	Title string
+	// Link is the saved link, empty for notes
+	Link   string
+	Author string
+	Tags   []string
+}
+
+// Render returns the plain text and HTML bodies of d
+func Render(d Digest) (text string, html string, err error) {
+	var textBuf, htmlBuf bytes.Buffer
+	if err := textTemplate.Execute(&textBuf, d); err != nil {
+		return "", "", err
+	}
+	if err := htmlTemplate.Execute(&htmlBuf, d); err != nil {
+		return "", "", err
+	}
+	return textBuf.String(), htmlBuf.String(), nil
+}
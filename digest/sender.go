@@ -0,0 +1,197 @@
+// Package digest mails users a periodic digest of the new public caches in
+// the tags they subscribed to.
+package digest
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	db "github.com/imrishuroy/read-cache-api/db/sqlc"
+	"github.com/imrishuroy/read-cache-api/jobs"
+	"github.com/imrishuroy/read-cache-api/mail"
+	"github.com/imrishuroy/read-cache-api/reminder"
+	"github.com/imrishuroy/read-cache-api/unfurl"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// FrequencyOff, FrequencyDaily and FrequencyWeekly are the digest settings a user can pick
+	FrequencyOff    = "off"
+	FrequencyDaily  = "daily"
+	FrequencyWeekly = "weekly"
+)
+
+const (
+	defaultPollInterval = time.Minute
+	defaultBatchSize    = 20
+	defaultMaxAttempts  = 5
+	defaultMaxCaches    = 30
+	defaultSendTimeout  = 30 * time.Second
+	leaseMargin         = time.Minute
+)
+
+// SenderConfig tunes a Sender, zero values fall back to sane defaults
+type SenderConfig struct {
+	jobs.Config
+	// MaxAttempts is how often sending one digest is tried before it is skipped
+	MaxAttempts int32
+	// MaxCaches caps the caches listed in one digest
+	MaxCaches int32
+	// SendTimeout bounds a single email
+	SendTimeout time.Duration
+	// PublicURL is the base of the unsubscribe link, Secret signs it
+	PublicURL string
+	Secret    []byte
+}
+
+// Sender mails due digests. Users are claimed with FOR UPDATE SKIP LOCKED
+// under a lease like reminders, so several servers can run a Sender. A digest
+// covers the caches created since the last one was sent, a digest that keeps
+// failing is skipped and its caches roll over into the next one.
+type Sender struct {
+	store  db.Store
+	mailer mail.Mailer
+	config SenderConfig
+	lease  time.Duration
+}
+
+// NewSender creates a Sender mailing through mailer
+func NewSender(store db.Store, mailer mail.Mailer, config SenderConfig) *Sender {
+	config.Config = config.Config.WithDefaults(defaultPollInterval, defaultBatchSize)
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = defaultMaxAttempts
+	}
+	if config.MaxCaches <= 0 {
+		config.MaxCaches = defaultMaxCaches
+	}
+	if config.SendTimeout <= 0 {
+		config.SendTimeout = defaultSendTimeout
+	}
+	config.PublicURL = strings.TrimSuffix(config.PublicURL, "/")
+
+	return &Sender{
+		store:  store,
+		mailer: mailer,
+		config: config,
+		lease:  time.Duration(config.BatchSize)*config.SendTimeout + leaseMargin,
+	}
+}
+
+// Run polls for due digests until ctx is cancelled
+func (sender *Sender) Run(ctx context.Context) {
+	jobs.Loop(ctx, sender.config.PollInterval, "digests", sender.ProcessDue)
+}
+
+// ProcessDue claims one batch of due digests and sends them, it returns how
+// many digests were claimed
+func (sender *Sender) ProcessDue(ctx context.Context) (int, error) {
+	due, err := sender.store.ClaimDueDigests(ctx, db.ClaimDueDigestsParams{
+		BatchSize:    sender.config.BatchSize,
+		LeaseSeconds: int32(sender.lease / time.Second),
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	for _, d := range due {
+		if err := sender.send(ctx, d); err != nil {
+			return len(due), err
+		}
+	}
+	return len(due), nil
+}
+
+func (sender *Sender) send(ctx context.Context, d db.ClaimDueDigestsRow) error {
+	now := time.Now()
+	caches, err := sender.store.ListDigestCaches(ctx, db.ListDigestCachesParams{
+		UserID:    d.UserID,
+		Since:     d.LastSentAt,
+		Until:     now,
+		PageLimit: sender.config.MaxCaches + 1,
+	})
+	if err != nil {
+		return err
+	}
+
+	// nothing new, no mail, and the next digest starts from here
+	if len(caches) == 0 {
+		return sender.store.CompleteDigest(ctx, db.CompleteDigestParams{UserID: d.UserID, SentAt: now})
+	}
+
+	sendErr := sender.mail(ctx, d, caches)
+	if sendErr == nil {
+		return sender.store.CompleteDigest(ctx, db.CompleteDigestParams{UserID: d.UserID, SentAt: now})
+	}
+
+	if ctx.Err() != nil {
+		// shutting down, the lease hands the digest to the next run
+		return ctx.Err()
+	}
+
+	attempts := d.Attempts + 1
+	if attempts >= sender.config.MaxAttempts {
+		log.Error().Err(sendErr).Str("user_id", d.UserID).Msg("giving up on digest")
+		return sender.store.SkipDigest(ctx, db.SkipDigestParams{UserID: d.UserID, LastError: sendErr.Error()})
+	}
+
+	return sender.store.RetryDigest(ctx, db.RetryDigestParams{
+		UserID:     d.UserID,
+		NextSendAt: now.Add(reminder.RetryDelay(attempts)),
+		LastError:  sendErr.Error(),
+	})
+}
+
+func (sender *Sender) mail(ctx context.Context, d db.ClaimDueDigestsRow, caches []db.ListDigestCachesRow) error {
+	if d.UserEmail == "" {
+		return fmt.Errorf("user %s has no email address", d.UserID)
+	}
+
+	digest := Digest{
+		Name:           d.UserName,
+		Frequency:      d.Frequency,
+		Since:          d.LastSentAt,
+		UnsubscribeURL: sender.UnsubscribeURL(d.UserID),
+	}
+	if len(caches) > int(sender.config.MaxCaches) {
+		caches = caches[:sender.config.MaxCaches]
+		digest.More = true
+	}
+	for _, cache := range caches {
+		entry := Entry{Title: cache.Title, Author: cache.OwnerName, Tags: cache.Tags}
+		if link, ok := unfurl.LinkURL(cache.Content); ok {
+			entry.Link = link
+		}
+		if entry.Title == "" {
+			entry.Title = entry.Link
+		}
+		digest.Caches = append(digest.Caches, entry)
+	}
+
+	text, html, err := Render(digest)
+	if err != nil {
+		return err
+	}
+
+	sendCtx, cancel := context.WithTimeout(ctx, sender.config.SendTimeout)
+	defer cancel()
+
+	return sender.mailer.Send(sendCtx, mail.Message{
+		To:      []string{d.UserEmail},
+		Subject: fmt.Sprintf("Your %s ReadCache digest", d.Frequency),
+		Text:    text,
+		HTML:    html,
+		Headers: map[string]string{
+			"List-Unsubscribe":      "<" + digest.UnsubscribeURL + ">",
+			"List-Unsubscribe-Post": "List-Unsubscribe=One-Click",
+		},
+	})
+}
+
+// UnsubscribeURL is the link in a digest that turns digests off for userID
+func (sender *Sender) UnsubscribeURL(userID string) string {
+	return sender.config.PublicURL + "/digest/unsubscribe?token=" +
+		url.QueryEscape(SignUnsubscribeToken(sender.config.Secret, userID))
+}
@@ -0,0 +1,194 @@
+package digest
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/imrishuroy/read-cache-api/db/mock"
+	db "github.com/imrishuroy/read-cache-api/db/sqlc"
+	"github.com/imrishuroy/read-cache-api/mail"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingMailer struct {
+	sent []mail.Message
+	err  error
+}
+
+func (mailer *recordingMailer) Send(_ context.Context, msg mail.Message) error {
+	mailer.sent = append(mailer.sent, msg)
+	return mailer.err
+}
+
+var testSecret = []byte("digest-secret-of-32-characters!!")
+
+func dueDigest(attempts int32) db.ClaimDueDigestsRow {
+	return db.ClaimDueDigestsRow{
+		UserID:     "user-1",
+		Frequency:  FrequencyDaily,
+		LastSentAt: time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC),
+		Attempts:   attempts,
+		UserEmail:  "reader@example.com",
+		UserName:   "Ann",
+	}
+}
+
+var digestCaches = []db.ListDigestCachesRow{
+	{ID: 2, Title: "Go & channels", Content: "https://go.dev/blog/channels", OwnerName: "Bob", Tags: []string{"go"}},
+	{ID: 1, Title: "Shopping <list>", Content: "milk", OwnerName: "Cy"},
+}
+
+func TestSenderProcessDue(t *testing.T) {
+	testCases := []struct {
+		name       string
+		digest     db.ClaimDueDigestsRow
+		caches     []db.ListDigestCachesRow
+		sendErr    error
+		buildStubs func(store *mockdb.MockStore)
+		checkSent  func(t *testing.T, sent []mail.Message)
+	}{
+		{
+			name:   "Sent",
+			digest: dueDigest(0),
+			caches: digestCaches,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CompleteDigest(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CompleteDigestParams) error {
+						require.Equal(t, "user-1", arg.UserID)
+						require.WithinDuration(t, time.Now(), arg.SentAt, time.Minute)
+						return nil
+					})
+			},
+			checkSent: func(t *testing.T, sent []mail.Message) {
+				require.Len(t, sent, 1)
+				msg := sent[0]
+				require.Equal(t, []string{"reader@example.com"}, msg.To)
+				require.Equal(t, "Your daily ReadCache digest", msg.Subject)
+				require.Equal(t, "List-Unsubscribe=One-Click", msg.Headers["List-Unsubscribe-Post"])
+
+				require.Contains(t, msg.Text, "2 new caches in the tags you follow since Mar 1:")
+				require.Contains(t, msg.Text, "* Go & channels\n  https://go.dev/blog/channels\n  by Bob in go\n")
+				require.Contains(t, msg.Text, "* Shopping <list>\n  by Cy\n")
+				require.Contains(t, msg.HTML, `<a href="https://go.dev/blog/channels">Go &amp; channels</a>`)
+				require.Contains(t, msg.HTML, `<strong>Shopping &lt;list&gt;</strong>`)
+				require.NotContains(t, msg.Text, "more in your timeline")
+
+				// the unsubscribe link carries a token for this user
+				link, err := url.Parse(msg.Headers["List-Unsubscribe"][1 : len(msg.Headers["List-Unsubscribe"])-1])
+				require.NoError(t, err)
+				require.Equal(t, "https://readcache.example/digest/unsubscribe", link.Scheme+"://"+link.Host+link.Path)
+				userID, err := VerifyUnsubscribeToken(testSecret, link.Query().Get("token"))
+				require.NoError(t, err)
+				require.Equal(t, "user-1", userID)
+				require.Contains(t, msg.Text, "Unsubscribe: "+link.String())
+			},
+		},
+		{
+			name:   "NothingNew",
+			digest: dueDigest(0),
+			caches: []db.ListDigestCachesRow{},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CompleteDigest(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil)
+			},
+			checkSent: func(t *testing.T, sent []mail.Message) {
+				require.Empty(t, sent)
+			},
+		},
+		{
+			name:   "More",
+			digest: dueDigest(0),
+			caches: append(append([]db.ListDigestCachesRow{}, digestCaches...), digestCaches...),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CompleteDigest(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil)
+			},
+			checkSent: func(t *testing.T, sent []mail.Message) {
+				require.Len(t, sent, 1)
+				require.Contains(t, sent[0].Text, "3+ new caches")
+				require.Contains(t, sent[0].Text, "more in your timeline")
+			},
+		},
+		{
+			name:    "Retry",
+			digest:  dueDigest(1),
+			caches:  digestCaches,
+			sendErr: errors.New("smtp unavailable"),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					RetryDigest(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.RetryDigestParams) error {
+						require.Equal(t, "smtp unavailable", arg.LastError)
+						require.True(t, arg.NextSendAt.After(time.Now()))
+						return nil
+					})
+			},
+			checkSent: func(t *testing.T, sent []mail.Message) {
+				require.Len(t, sent, 1)
+			},
+		},
+		{
+			name:    "GiveUp",
+			digest:  dueDigest(defaultMaxAttempts - 1),
+			caches:  digestCaches,
+			sendErr: errors.New("smtp unavailable"),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					SkipDigest(gomock.Any(), db.SkipDigestParams{UserID: "user-1", LastError: "smtp unavailable"}).
+					Times(1).
+					Return(nil)
+			},
+			checkSent: func(t *testing.T, sent []mail.Message) {
+				require.Len(t, sent, 1)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().
+				ClaimDueDigests(gomock.Any(), gomock.Any()).
+				Times(1).
+				Return([]db.ClaimDueDigestsRow{tc.digest}, nil)
+			store.EXPECT().
+				ListDigestCaches(gomock.Any(), gomock.Any()).
+				Times(1).
+				DoAndReturn(func(_ context.Context, arg db.ListDigestCachesParams) ([]db.ListDigestCachesRow, error) {
+					require.Equal(t, tc.digest.UserID, arg.UserID)
+					require.Equal(t, tc.digest.LastSentAt, arg.Since)
+					require.Equal(t, int32(4), arg.PageLimit)
+					return tc.caches, nil
+				})
+			tc.buildStubs(store)
+
+			mailer := &recordingMailer{err: tc.sendErr}
+			sender := NewSender(store, mailer, SenderConfig{
+				MaxCaches: 3,
+				PublicURL: "https://readcache.example/",
+				Secret:    testSecret,
+			})
+
+			n, err := sender.ProcessDue(context.Background())
+			require.NoError(t, err)
+			require.Equal(t, 1, n)
+			tc.checkSent(t, mailer.sent)
+		})
+	}
+}
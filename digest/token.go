@@ -0,0 +1,62 @@
+package digest
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strings"
+)
+
+// ErrInvalidToken is returned for unsubscribe tokens that weren't signed with our secret
+var ErrInvalidToken = errors.New("invalid unsubscribe token")
+
+// unsubscribePurpose keeps these signatures from being valid for anything
+// else signed with the same secret
+const unsubscribePurpose = "digest-unsubscribe:"
+
+// MinSecretSize is the shortest secret unsubscribe tokens are signed with
+const MinSecretSize = 32
+
+// ValidSecret reports whether secret is long enough to sign unsubscribe tokens
+func ValidSecret(secret []byte) bool {
+	return len(secret) >= MinSecretSize
+}
+
+// SignUnsubscribeToken returns a token that unsubscribes userID from digests
+// without logging in. It doesn't expire, links in old digests keep working.
+func SignUnsubscribeToken(secret []byte, userID string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(userID)) + "." +
+		base64.RawURLEncoding.EncodeToString(sign(secret, userID))
+}
+
+// VerifyUnsubscribeToken returns the user a token was signed for
+func VerifyUnsubscribeToken(secret []byte, token string) (string, error) {
+	if !ValidSecret(secret) {
+		return "", ErrInvalidToken
+	}
+
+	encodedID, encodedMAC, ok := strings.Cut(token, ".")
+	if !ok {
+		return "", ErrInvalidToken
+	}
+	userID, err := base64.RawURLEncoding.DecodeString(encodedID)
+	if err != nil || len(userID) == 0 {
+		return "", ErrInvalidToken
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(encodedMAC)
+	if err != nil {
+		return "", ErrInvalidToken
+	}
+
+	if !hmac.Equal(mac, sign(secret, string(userID))) {
+		return "", ErrInvalidToken
+	}
+	return string(userID), nil
+}
+
+func sign(secret []byte, userID string) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(unsubscribePurpose + userID))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,41 @@
+package digest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnsubscribeToken(t *testing.T) {
+	secret := []byte("digest-secret-of-32-characters!!")
+	token := SignUnsubscribeToken(secret, "user-1")
+
+	userID, err := VerifyUnsubscribeToken(secret, token)
+	require.NoError(t, err)
+	require.Equal(t, "user-1", userID)
+
+	// another secret, a forged user and garbage are all rejected
+	_, err = VerifyUnsubscribeToken([]byte("other-secret"), token)
+	require.ErrorIs(t, err, ErrInvalidToken)
+
+	_, mac, _ := strings.Cut(token, ".")
+	forged := strings.Split(SignUnsubscribeToken(secret, "user-2"), ".")[0] + "." + mac
+	_, err = VerifyUnsubscribeToken(secret, forged)
+	require.ErrorIs(t, err, ErrInvalidToken)
+
+	for _, bad := range []string{"", "user-1", ".", "!!.!!"} {
+		_, err = VerifyUnsubscribeToken(secret, bad)
+		require.ErrorIs(t, err, ErrInvalidToken)
+	}
+
+	// without a secret nothing verifies
+	_, err = VerifyUnsubscribeToken(nil, SignUnsubscribeToken(nil, "user-1"))
+	require.ErrorIs(t, err, ErrInvalidToken)
+
+	// neither does a short one
+	short := []byte("change-me-digest-secret")
+	require.False(t, ValidSecret(short))
+	_, err = VerifyUnsubscribeToken(short, SignUnsubscribeToken(short, "user-1"))
+	require.ErrorIs(t, err, ErrInvalidToken)
+}
@@ -0,0 +1,72 @@
+package exporter
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	db "github.com/imrishuroy/read-cache-api/db/sqlc"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var csvHeader = []string{"id", "title", "content", "url", "tags", "status", "progress", "is_public", "created_at", "read_at", "archived_at"}
+
+type csvWriter struct {
+	w      *csv.Writer
+	header bool
+}
+
+func newCSVWriter(w io.Writer) *csvWriter {
+	return &csvWriter{w: csv.NewWriter(w)}
+}
+
+func (cw *csvWriter) Write(cache db.ListCachesForExportRow) error {
+	if err := cw.writeHeader(); err != nil {
+		return err
+	}
+
+	err := cw.w.Write([]string{
+		strconv.FormatInt(cache.ID, 10),
+		cache.Title,
+		cache.Content,
+		linkURL(cache),
+		strings.Join(cache.Tags, "|"),
+		string(cache.Status),
+		strconv.Itoa(int(cache.Progress)),
+		strconv.FormatBool(cache.IsPublic.Bool),
+		cache.SavedAt.UTC().Format(time.RFC3339),
+		csvTime(cache.ReadAt),
+		csvTime(cache.ArchivedAt),
+	})
+	if err != nil {
+		return err
+	}
+	// flush every row so the response streams instead of filling a buffer
+	cw.w.Flush()
+	return cw.w.Error()
+}
+
+func (cw *csvWriter) Close() error {
+	if err := cw.writeHeader(); err != nil {
+		return err
+	}
+	cw.w.Flush()
+	return cw.w.Error()
+}
+
+func (cw *csvWriter) writeHeader() error {
+	if cw.header {
+		return nil
+	}
+	cw.header = true
+	return cw.w.Write(csvHeader)
+}
+
+func csvTime(t pgtype.Timestamptz) string {
+	if !t.Valid {
+		return ""
+	}
+	return t.Time.UTC().Format(time.RFC3339)
+}
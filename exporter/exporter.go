@@ -0,0 +1,79 @@
+package exporter
+
+import (
+	"fmt"
+	"io"
+
+	db "github.com/imrishuroy/read-cache-api/db/sqlc"
+	"github.com/imrishuroy/read-cache-api/unfurl"
+)
+
+// Format is the kind of file an export is written as
+type Format string
+
+const (
+	// FormatJSON is the lossless export, it can be imported again
+	FormatJSON Format = "json"
+	// FormatCSV is one row per cache with the tags joined by |
+	FormatCSV Format = "csv"
+	// FormatHTML is a Netscape bookmark file browsers and read later apps import
+	FormatHTML Format = "html"
+	// FormatMarkdown is a zip with one markdown file per cache
+	FormatMarkdown Format = "markdown"
+)
+
+// Writer encodes caches one at a time, so an export never holds more than
+// the current batch of caches in memory. Close writes whatever closes the
+// document, it doesn't close the underlying io.Writer.
+type Writer interface {
+	Write(cache db.ListCachesForExportRow) error
+	Close() error
+}
+
+// NewWriter starts an export of format on w
+func NewWriter(format Format, w io.Writer) (Writer, error) {
+	switch format {
+	case FormatJSON:
+		return newJSONWriter(w), nil
+	case FormatCSV:
+		return newCSVWriter(w), nil
+	case FormatHTML:
+		return newHTMLWriter(w), nil
+	case FormatMarkdown:
+		return newMarkdownWriter(w), nil
+	}
+	return nil, fmt.Errorf("unsupported export format %q", format)
+}
+
+// ContentType is the media type of an export of format
+func ContentType(format Format) string {
+	switch format {
+	case FormatJSON:
+		return "application/json"
+	case FormatCSV:
+		return "text/csv; charset=utf-8"
+	case FormatHTML:
+		return "text/html; charset=utf-8"
+	case FormatMarkdown:
+		return "application/zip"
+	}
+	return "application/octet-stream"
+}
+
+// Extension is the file name extension of an export of format
+func Extension(format Format) string {
+	switch format {
+	case FormatMarkdown:
+		return "zip"
+	}
+	return string(format)
+}
+
+// linkURL returns the saved link of a cache, empty for notes
+func linkURL(cache db.ListCachesForExportRow) string {
+	link, ok := unfurl.LinkURL(cache.Content)
+	if !ok {
+		return ""
+	}
+	return link
+}
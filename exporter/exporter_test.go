@@ -0,0 +1,174 @@
+package exporter
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"testing"
+	"time"
+
+	db "github.com/imrishuroy/read-cache-api/db/sqlc"
+	"github.com/imrishuroy/read-cache-api/importer"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	savedAt    = time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC)
+	importedAt = time.Date(2025, 1, 15, 12, 0, 0, 0, time.UTC)
+	testCaches = []db.ListCachesForExportRow{
+		{
+			ID:      1,
+			Title:   `Go & "channels"`,
+			Content: "https://go.dev/blog/channels",
+			// imported, exports keep the date the link was first saved
+			CreatedAt:  importedAt,
+			SavedAt:    savedAt,
+			IsPublic:   pgtype.Bool{Bool: true, Valid: true},
+			Status:     db.ReadingStatusArchived,
+			Progress:   100,
+			ArchivedAt: pgtype.Timestamptz{Time: savedAt.Add(time.Hour), Valid: true},
+			Tags:       []string{"go", "concurrency"},
+		},
+		{
+			ID:        2,
+			Title:     "Shopping list",
+			Content:   "milk\neggs",
+			CreatedAt: savedAt.Add(time.Minute),
+			SavedAt:   savedAt.Add(time.Minute),
+			Status:    db.ReadingStatusUnread,
+		},
+	}
+)
+
+func export(t *testing.T, format Format) []byte {
+	var buf bytes.Buffer
+	w, err := NewWriter(format, &buf)
+	require.NoError(t, err)
+	for _, cache := range testCaches {
+		require.NoError(t, w.Write(cache))
+	}
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func TestJSONExport(t *testing.T) {
+	data := export(t, FormatJSON)
+
+	var doc struct {
+		Version int         `json:"version"`
+		Caches  []jsonCache `json:"caches"`
+	}
+	require.NoError(t, json.Unmarshal(data, &doc))
+	require.Equal(t, 1, doc.Version)
+	require.Len(t, doc.Caches, 2)
+	require.Equal(t, "https://go.dev/blog/channels", doc.Caches[0].URL)
+	require.Equal(t, []string{"go", "concurrency"}, doc.Caches[0].Tags)
+	require.NotNil(t, doc.Caches[0].ArchivedAt)
+	require.Empty(t, doc.Caches[1].URL)
+	require.Equal(t, "milk\neggs", doc.Caches[1].Content)
+	require.Equal(t, []string{}, doc.Caches[1].Tags)
+
+	// the json export imports again, notes and reading state included
+	format, err := importer.Detect(data)
+	require.NoError(t, err)
+	require.Equal(t, importer.FormatJSON, format)
+
+	items, err := importer.Parse(format, data)
+	require.NoError(t, err)
+	require.Equal(t, []importer.Item{
+		{
+			URL:        "https://go.dev/blog/channels",
+			Title:      `Go & "channels"`,
+			Tags:       []string{"go", "concurrency"},
+			AddedAt:    savedAt,
+			Archived:   true,
+			IsPublic:   true,
+			Status:     "archived",
+			Progress:   100,
+			ArchivedAt: savedAt.Add(time.Hour),
+		},
+		{
+			Title:   "Shopping list",
+			Content: "milk\neggs",
+			AddedAt: savedAt.Add(time.Minute),
+			Status:  "unread",
+		},
+	}, items)
+}
+
+func TestEmptyJSONExport(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewWriter(FormatJSON, &buf)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	require.JSONEq(t, `{"version":1,"caches":[]}`, buf.String())
+}
+
+func TestCSVExport(t *testing.T) {
+	records, err := csv.NewReader(bytes.NewReader(export(t, FormatCSV))).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+	require.Equal(t, csvHeader, records[0])
+	require.Equal(t, []string{
+		"1", `Go & "channels"`, "https://go.dev/blog/channels", "https://go.dev/blog/channels", "go|concurrency",
+		"archived", "100", "true", "2024-03-01T09:30:00Z", "", "2024-03-01T10:30:00Z",
+	}, records[1])
+	require.Equal(t, "milk\neggs", records[2][2])
+	require.Empty(t, records[2][3])
+}
+
+func TestHTMLExport(t *testing.T) {
+	data := export(t, FormatHTML)
+	require.Contains(t, string(data), `TAGS="go,concurrency">Go &amp; &#34;channels&#34;</A>`)
+
+	// notes have no link and are left out, the file imports as a browser bookmark file
+	items, err := importer.Parse(importer.FormatNetscape, data)
+	require.NoError(t, err)
+	require.Equal(t, []importer.Item{{
+		URL:     "https://go.dev/blog/channels",
+		Title:   `Go & "channels"`,
+		Tags:    []string{"go", "concurrency"},
+		AddedAt: savedAt,
+	}}, items)
+}
+
+func TestMarkdownExport(t *testing.T) {
+	data := export(t, FormatMarkdown)
+
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+	require.Len(t, zr.File, 2)
+	require.Equal(t, "caches/1-go-channels.md", zr.File[0].Name)
+	require.Equal(t, "caches/2-shopping-list.md", zr.File[1].Name)
+
+	f, err := zr.File[0].Open()
+	require.NoError(t, err)
+	defer f.Close()
+	content, err := io.ReadAll(f)
+	require.NoError(t, err)
+
+	require.Equal(t, `---
+id: 1
+title: "Go & \"channels\""
+url: "https://go.dev/blog/channels"
+tags: ["go","concurrency"]
+status: archived
+progress: 100
+public: true
+created_at: 2024-03-01T09:30:00Z
+archived_at: 2024-03-01T10:30:00Z
+---
+
+# Go & "channels"
+
+<https://go.dev/blog/channels>
+`, string(content))
+}
+
+func TestNewWriterUnsupportedFormat(t *testing.T) {
+	_, err := NewWriter("pdf", io.Discard)
+	require.Error(t, err)
+}
@@ -0,0 +1,71 @@
+package exporter
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"strings"
+
+	db "github.com/imrishuroy/read-cache-api/db/sqlc"
+)
+
+const netscapeHeader = `<!DOCTYPE NETSCAPE-Bookmark-file-1>
+<!-- This is an automatically generated file.
+     It will be read and overwritten.
+     DO NOT EDIT! -->
+<META HTTP-EQUIV="Content-Type" CONTENT="text/html; charset=UTF-8">
+<TITLE>Bookmarks</TITLE>
+<H1>ReadCache</H1>
+<DL><p>
+`
+
+// htmlWriter writes a flat Netscape bookmark file with the tags in the TAGS
+// attribute the way Firefox does. Notes have no link to bookmark and are left out.
+type htmlWriter struct {
+	w      io.Writer
+	header bool
+}
+
+func newHTMLWriter(w io.Writer) *htmlWriter {
+	return &htmlWriter{w: w}
+}
+
+func (hw *htmlWriter) Write(cache db.ListCachesForExportRow) error {
+	link := linkURL(cache)
+	if link == "" {
+		return nil
+	}
+	if err := hw.writeHeader(); err != nil {
+		return err
+	}
+
+	title := cache.Title
+	if title == "" {
+		title = link
+	}
+
+	_, err := fmt.Fprintf(hw.w, "    <DT><A HREF=\"%s\" ADD_DATE=\"%d\" TAGS=\"%s\">%s</A>\n",
+		html.EscapeString(link),
+		cache.SavedAt.Unix(),
+		html.EscapeString(strings.Join(cache.Tags, ",")),
+		html.EscapeString(title),
+	)
+	return err
+}
+
+func (hw *htmlWriter) Close() error {
+	if err := hw.writeHeader(); err != nil {
+		return err
+	}
+	_, err := io.WriteString(hw.w, "</DL><p>\n")
+	return err
+}
+
+func (hw *htmlWriter) writeHeader() error {
+	if hw.header {
+		return nil
+	}
+	hw.header = true
+	_, err := io.WriteString(hw.w, netscapeHeader)
+	return err
+}
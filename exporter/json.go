@@ -0,0 +1,88 @@
+package exporter
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+
+	db "github.com/imrishuroy/read-cache-api/db/sqlc"
+)
+
+// jsonHeader opens the document, its version is bumped when the document
+// changes in a way importers notice
+const jsonHeader = `{"version":1,"caches":[`
+
+// jsonCache is a cache in the json export, importer.FormatJSON reads it back
+type jsonCache struct {
+	ID       int64            `json:"id"`
+	Title    string           `json:"title"`
+	Content  string           `json:"content"`
+	URL      string           `json:"url,omitempty"`
+	Tags     []string         `json:"tags"`
+	Status   db.ReadingStatus `json:"status"`
+	Progress int32            `json:"progress"`
+	IsPublic bool             `json:"is_public"`
+	// CreatedAt is when the link was saved, imported caches keep their original date
+	CreatedAt  time.Time  `json:"created_at"`
+	ReadAt     *time.Time `json:"read_at"`
+	ArchivedAt *time.Time `json:"archived_at"`
+}
+
+// jsonWriter writes {"version":1,"caches":[...]} one cache per line
+type jsonWriter struct {
+	w     io.Writer
+	count int
+}
+
+func newJSONWriter(w io.Writer) *jsonWriter {
+	return &jsonWriter{w: w}
+}
+
+func (jw *jsonWriter) Write(cache db.ListCachesForExportRow) error {
+	entry := jsonCache{
+		ID:        cache.ID,
+		Title:     cache.Title,
+		Content:   cache.Content,
+		URL:       linkURL(cache),
+		Tags:      cache.Tags,
+		Status:    cache.Status,
+		Progress:  cache.Progress,
+		IsPublic:  cache.IsPublic.Bool,
+		CreatedAt: cache.SavedAt,
+	}
+	if entry.Tags == nil {
+		entry.Tags = []string{}
+	}
+	if cache.ReadAt.Valid {
+		entry.ReadAt = &cache.ReadAt.Time
+	}
+	if cache.ArchivedAt.Valid {
+		entry.ArchivedAt = &cache.ArchivedAt.Time
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	separator := ",\n"
+	if jw.count == 0 {
+		separator = jsonHeader + "\n"
+	}
+	jw.count++
+
+	if _, err := io.WriteString(jw.w, separator); err != nil {
+		return err
+	}
+	_, err = jw.w.Write(data)
+	return err
+}
+
+func (jw *jsonWriter) Close() error {
+	if jw.count == 0 {
+		_, err := io.WriteString(jw.w, jsonHeader+"]}\n")
+		return err
+	}
+	_, err := io.WriteString(jw.w, "\n]}\n")
+	return err
+}
@@ -0,0 +1,116 @@
+package exporter
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode"
+
+	db "github.com/imrishuroy/read-cache-api/db/sqlc"
+)
+
+const maxSlugLength = 60
+
+// markdownWriter zips one markdown file per cache. Each file starts with yaml
+// front matter, strings are written as json which yaml reads as double quoted
+// scalars, so titles need no escaping rules of their own.
+type markdownWriter struct {
+	zw *zip.Writer
+}
+
+func newMarkdownWriter(w io.Writer) *markdownWriter {
+	return &markdownWriter{zw: zip.NewWriter(w)}
+}
+
+func (mw *markdownWriter) Write(cache db.ListCachesForExportRow) error {
+	f, err := mw.zw.CreateHeader(&zip.FileHeader{
+		Name:     markdownFilename(cache),
+		Method:   zip.Deflate,
+		Modified: cache.SavedAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	link := linkURL(cache)
+	tags := cache.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+
+	var b strings.Builder
+	b.WriteString("---\n")
+	fmt.Fprintf(&b, "id: %d\n", cache.ID)
+	fmt.Fprintf(&b, "title: %s\n", yamlValue(cache.Title))
+	if link != "" {
+		fmt.Fprintf(&b, "url: %s\n", yamlValue(link))
+	}
+	fmt.Fprintf(&b, "tags: %s\n", yamlValue(tags))
+	fmt.Fprintf(&b, "status: %s\n", cache.Status)
+	fmt.Fprintf(&b, "progress: %d\n", cache.Progress)
+	fmt.Fprintf(&b, "public: %t\n", cache.IsPublic.Bool)
+	fmt.Fprintf(&b, "created_at: %s\n", cache.SavedAt.UTC().Format(time.RFC3339))
+	if cache.ReadAt.Valid {
+		fmt.Fprintf(&b, "read_at: %s\n", cache.ReadAt.Time.UTC().Format(time.RFC3339))
+	}
+	if cache.ArchivedAt.Valid {
+		fmt.Fprintf(&b, "archived_at: %s\n", cache.ArchivedAt.Time.UTC().Format(time.RFC3339))
+	}
+	b.WriteString("---\n\n")
+
+	if cache.Title != "" {
+		fmt.Fprintf(&b, "# %s\n\n", strings.Join(strings.Fields(cache.Title), " "))
+	}
+	if link != "" {
+		fmt.Fprintf(&b, "<%s>\n", link)
+	} else {
+		b.WriteString(cache.Content)
+		b.WriteString("\n")
+	}
+
+	_, err = io.WriteString(f, b.String())
+	return err
+}
+
+func (mw *markdownWriter) Close() error {
+	return mw.zw.Close()
+}
+
+// markdownFilename is unique through the cache id, the slug only makes the
+// archive easier to browse
+func markdownFilename(cache db.ListCachesForExportRow) string {
+	var slug strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(cache.Title) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			slug.WriteRune(r)
+			dash = false
+			continue
+		}
+		if !dash && slug.Len() > 0 {
+			slug.WriteByte('-')
+			dash = true
+		}
+	}
+
+	name := strings.Trim(slug.String(), "-")
+	if len(name) > maxSlugLength {
+		name = strings.TrimRight(strings.ToValidUTF8(name[:maxSlugLength], ""), "-")
+	}
+	if name == "" {
+		return fmt.Sprintf("caches/%d.md", cache.ID)
+	}
+	return fmt.Sprintf("caches/%d-%s.md", cache.ID, name)
+}
+
+func yamlValue(v any) string {
+	var b strings.Builder
+	enc := json.NewEncoder(&b)
+	// yaml has no html to protect, keep & < > readable
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(v)
+	return strings.TrimSuffix(b.String(), "\n")
+}
@@ -0,0 +1,76 @@
+// Package feed renders lists of caches as RSS 2.0, Atom 1.0 and JSON Feed 1.1
+// documents for feed readers.
+package feed
+
+import (
+	"fmt"
+	"time"
+)
+
+// Format is the syndication format a feed is rendered as
+type Format string
+
+const (
+	// FormatRSS is RSS 2.0
+	FormatRSS Format = "rss"
+	// FormatAtom is Atom 1.0
+	FormatAtom Format = "atom"
+	// FormatJSON is JSON Feed 1.1
+	FormatJSON Format = "json"
+)
+
+// Feed is a format independent feed document
+type Feed struct {
+	Title       string
+	Description string
+	// Link is the page the feed describes, FeedURL is where the feed itself is served
+	Link    string
+	FeedURL string
+	// Updated is when the most recently changed item was last changed
+	Updated time.Time
+	Items   []Item
+}
+
+// Item is a single entry of a feed
+type Item struct {
+	// ID is a stable, globally unique identifier of the entry
+	ID    string
+	Title string
+	// Link is the saved link, empty for notes
+	Link    string
+	Summary string
+	// Content is the text of a note
+	Content   string
+	Image     string
+	Author    string
+	Published time.Time
+	// Updated is when the entry was last edited, zero when it never was
+	Updated time.Time
+	Tags    []string
+}
+
+// Render encodes f as a document of format
+func Render(format Format, f Feed) ([]byte, error) {
+	switch format {
+	case FormatRSS:
+		return renderRSS(f)
+	case FormatAtom:
+		return renderAtom(f)
+	case FormatJSON:
+		return renderJSON(f)
+	}
+	return nil, fmt.Errorf("unsupported feed format %q", format)
+}
+
+// ContentType is the media type of a feed of format
+func ContentType(format Format) string {
+	switch format {
+	case FormatRSS:
+		return "application/rss+xml; charset=utf-8"
+	case FormatAtom:
+		return "application/atom+xml; charset=utf-8"
+	case FormatJSON:
+		return "application/feed+json; charset=utf-8"
+	}
+	return "application/octet-stream"
+}
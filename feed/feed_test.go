@@ -0,0 +1,132 @@
+package feed
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	published = time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC)
+	testFeed  = Feed{
+		Title:       "#go on ReadCache",
+		Description: "Public caches tagged go",
+		Link:        "https://readcache.example/tags/go",
+		FeedURL:     "https://readcache.example/feeds/tags/go/atom",
+		Updated:     published.Add(time.Hour),
+		Items: []Item{
+			{
+				ID:        "https://readcache.example/feeds/caches/2",
+				Title:     "Go & channels",
+				Link:      "https://go.dev/blog/channels",
+				Summary:   "Share memory by communicating",
+				Image:     "https://go.dev/images/gopher.png",
+				Author:    "Ann",
+				Published: published.Add(time.Hour),
+				Tags:      []string{"go", "concurrency"},
+			},
+			{
+				ID:        "https://readcache.example/feeds/caches/1",
+				Title:     "Shopping list",
+				Content:   "milk <eggs>",
+				Author:    "Bob",
+				Published: published,
+				Updated:   published.Add(30 * time.Minute),
+			},
+		},
+	}
+)
+
+func TestRenderRSS(t *testing.T) {
+	data, err := Render(FormatRSS, testFeed)
+	require.NoError(t, err)
+	require.Contains(t, string(data), `<rss version="2.0" xmlns:atom="http://www.w3.org/2005/Atom"`)
+	require.Contains(t, string(data), `<atom:link href="https://readcache.example/feeds/tags/go/atom" rel="self" type="application/rss+xml"></atom:link>`)
+	require.Contains(t, string(data), `<dc:creator>Ann</dc:creator>`)
+	require.Contains(t, string(data), `milk &lt;eggs&gt;`)
+
+	var doc struct {
+		Channel struct {
+			LastBuildDate string `xml:"lastBuildDate"`
+			Items         []struct {
+				Title      string   `xml:"title"`
+				Link       string   `xml:"link"`
+				GUID       string   `xml:"guid"`
+				PubDate    string   `xml:"pubDate"`
+				Categories []string `xml:"category"`
+			} `xml:"item"`
+		} `xml:"channel"`
+	}
+	require.NoError(t, xml.Unmarshal(data, &doc))
+	require.Equal(t, "Fri, 01 Mar 2024 10:30:00 +0000", doc.Channel.LastBuildDate)
+	require.Len(t, doc.Channel.Items, 2)
+	require.Equal(t, "https://go.dev/blog/channels", doc.Channel.Items[0].Link)
+	require.Equal(t, "https://readcache.example/feeds/caches/2", doc.Channel.Items[0].GUID)
+	require.Equal(t, []string{"go", "concurrency"}, doc.Channel.Items[0].Categories)
+	require.Empty(t, doc.Channel.Items[1].Link)
+}
+
+func TestRenderAtom(t *testing.T) {
+	data, err := Render(FormatAtom, testFeed)
+	require.NoError(t, err)
+
+	var doc struct {
+		XMLName xml.Name `xml:"http://www.w3.org/2005/Atom feed"`
+		ID      string   `xml:"id"`
+		Updated string   `xml:"updated"`
+		Entries []struct {
+			ID      string `xml:"id"`
+			Updated string `xml:"updated"`
+			Author  string `xml:"author>name"`
+			Link    struct {
+				Href string `xml:"href,attr"`
+			} `xml:"link"`
+			Summary  string `xml:"summary"`
+			Content  string `xml:"content"`
+			Category []struct {
+				Term string `xml:"term,attr"`
+			} `xml:"category"`
+		} `xml:"entry"`
+	}
+	require.NoError(t, xml.Unmarshal(data, &doc))
+	require.Equal(t, testFeed.FeedURL, doc.ID)
+	require.Equal(t, "2024-03-01T10:30:00Z", doc.Updated)
+	require.Len(t, doc.Entries, 2)
+	require.Equal(t, "https://go.dev/blog/channels", doc.Entries[0].Link.Href)
+	require.Equal(t, "Share memory by communicating", doc.Entries[0].Summary)
+	require.Len(t, doc.Entries[0].Category, 2)
+	require.Equal(t, "Bob", doc.Entries[1].Author)
+	require.Equal(t, "milk <eggs>", doc.Entries[1].Content)
+	require.Equal(t, "2024-03-01T10:30:00Z", doc.Entries[0].Updated)
+	require.Equal(t, "2024-03-01T10:00:00Z", doc.Entries[1].Updated)
+}
+
+func TestRenderJSON(t *testing.T) {
+	data, err := Render(FormatJSON, testFeed)
+	require.NoError(t, err)
+
+	var doc jsonFeed
+	require.NoError(t, json.Unmarshal(data, &doc))
+	require.Equal(t, jsonFeedVersion, doc.Version)
+	require.Equal(t, testFeed.FeedURL, doc.FeedURL)
+	require.Len(t, doc.Items, 2)
+	require.Equal(t, "Share memory by communicating", doc.Items[0].ContentText)
+	require.Equal(t, "https://go.dev/images/gopher.png", doc.Items[0].Image)
+	require.Equal(t, []jsonAuthor{{Name: "Ann"}}, doc.Items[0].Authors)
+	require.Equal(t, "milk <eggs>", doc.Items[1].ContentText)
+	require.Empty(t, doc.Items[1].URL)
+}
+
+func TestRenderEmptyJSON(t *testing.T) {
+	data, err := Render(FormatJSON, Feed{Title: "empty"})
+	require.NoError(t, err)
+	require.JSONEq(t, `{"version":"https://jsonfeed.org/version/1.1","title":"empty","items":[]}`, string(data))
+}
+
+func TestRenderUnsupportedFormat(t *testing.T) {
+	_, err := Render("opml", testFeed)
+	require.Error(t, err)
+}
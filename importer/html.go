@@ -0,0 +1,122 @@
+package importer
+
+import (
+	"io"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// parseHTML reads a Netscape bookmark file. The format is tag soup that never
+// closes its <DT>s, so it is tokenized rather than parsed into a tree: every
+// <H3> names the folder whose <DL> follows it, and each <A> is a bookmark
+// filed under the folders currently open. Pocket's html export is the same
+// idea with <H1> sections for unread and archived items.
+func parseHTML(r io.Reader) ([]Item, error) {
+	z := html.NewTokenizer(r)
+
+	var (
+		items []Item
+		// folders is the stack of open <DL>s, unnamed lists push ""
+		folders []string
+		// folder names the <DL> that comes next
+		folder   string
+		archived bool
+
+		text    strings.Builder
+		heading atom.Atom
+		skip    bool
+		link    *Item
+	)
+
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			if z.Err() == io.EOF {
+				return items, nil
+			}
+			return nil, z.Err()
+
+		case html.TextToken:
+			if heading != 0 || link != nil {
+				text.Write(z.Text())
+			}
+
+		case html.StartTagToken, html.SelfClosingTagToken:
+			token := z.Token()
+			switch token.DataAtom {
+			case atom.H1, atom.H3:
+				heading = token.DataAtom
+				text.Reset()
+				// the toolbar and "other bookmarks" roots are not folders anyone tags by
+				skip = tokenAttr(token, "personal_toolbar_folder") == "true" ||
+					tokenAttr(token, "unfiled_bookmarks_folder") == "true"
+			case atom.Dl:
+				folders = append(folders, folder)
+				folder = ""
+			case atom.A:
+				href := strings.TrimSpace(tokenAttr(token, "href"))
+				if href == "" {
+					continue
+				}
+				link = &Item{
+					URL:      href,
+					Tags:     strings.Split(tokenAttr(token, "tags"), ","),
+					AddedAt:  parseUnix(firstAttr(token, "add_date", "time_added")),
+					Archived: archived,
+				}
+				text.Reset()
+			}
+
+		case html.EndTagToken:
+			token := z.Token()
+			switch token.DataAtom {
+			case atom.H1:
+				if heading == atom.H1 {
+					archived = strings.EqualFold(cleanText(text.String()), "read archive")
+				}
+				heading = 0
+			case atom.H3:
+				if heading == atom.H3 && !skip {
+					folder = cleanText(text.String())
+				}
+				heading = 0
+			case atom.Dl:
+				if len(folders) > 0 {
+					folders = folders[:len(folders)-1]
+				}
+			case atom.A:
+				if link == nil {
+					continue
+				}
+				link.Title = cleanText(text.String())
+				link.Tags = NormalizeTags(append(link.Tags, folders...))
+				items = append(items, *link)
+				link = nil
+			}
+		}
+	}
+}
+
+func tokenAttr(token html.Token, key string) string {
+	for _, a := range token.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+func firstAttr(token html.Token, keys ...string) string {
+	for _, key := range keys {
+		if val := tokenAttr(token, key); val != "" {
+			return val
+		}
+	}
+	return ""
+}
+
+func cleanText(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
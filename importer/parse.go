@@ -0,0 +1,296 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Format is the kind of export file an import reads
+type Format string
+
+const (
+	// FormatNetscape is the bookmark html every browser exports
+	FormatNetscape Format = "netscape"
+	// FormatPocketHTML is Pocket's html export, its unread and archive sections become the reading status
+	FormatPocketHTML Format = "pocket_html"
+	// FormatPocketCSV is Pocket's csv export: title,url,time_added,tags,status
+	FormatPocketCSV Format = "pocket_csv"
+	// FormatInstapaper is Instapaper's csv export: URL,Title,Selection,Folder,Timestamp
+	FormatInstapaper Format = "instapaper"
+	// FormatJSON is ReadCache's own json export
+	FormatJSON Format = "json"
+)
+
+// maxTagLength is the size of tags.tag_name, longer folder names are dropped
+const maxTagLength = 255
+
+var ErrUnknownFormat = errors.New("file is not a Netscape bookmark, Pocket, Instapaper or ReadCache export")
+
+// Item is one bookmark read from an export
+type Item struct {
+	URL   string
+	Title string
+	// Content is the text of a note, ReadCache exports hold notes without a URL
+	Content string
+	// Tags holds the bookmark's tags followed by the folders it was filed in
+	Tags    []string
+	AddedAt time.Time
+	// Archived is set for bookmarks the user had already read and archived
+	Archived bool
+	// IsPublic and the reading state below are only known for ReadCache
+	// exports, Status is empty for every other format
+	IsPublic   bool
+	Status     string
+	Progress   int32
+	ReadAt     time.Time
+	ArchivedAt time.Time
+}
+
+// Detect guesses the format of an export from its content
+func Detect(data []byte) (Format, error) {
+	data = trimBOM(data)
+	head := bytes.ToLower(data[:min(len(data), 1024)])
+
+	if bytes.HasPrefix(bytes.TrimSpace(head), []byte("{")) {
+		return FormatJSON, nil
+	}
+	if bytes.HasPrefix(bytes.TrimSpace(head), []byte("<")) {
+		if bytes.Contains(head, []byte("pocket export")) {
+			return FormatPocketHTML, nil
+		}
+		return FormatNetscape, nil
+	}
+
+	header, err := csv.NewReader(bytes.NewReader(data)).Read()
+	if err != nil {
+		return "", ErrUnknownFormat
+	}
+	columns := csvColumns(header)
+	switch {
+	case hasColumns(columns, "url", "folder", "timestamp"):
+		return FormatInstapaper, nil
+	case hasColumns(columns, "url", "time_added"):
+		return FormatPocketCSV, nil
+	}
+	return "", ErrUnknownFormat
+}
+
+// Parse reads every bookmark of an export in document order
+func Parse(format Format, data []byte) ([]Item, error) {
+	data = trimBOM(data)
+
+	switch format {
+	case FormatNetscape, FormatPocketHTML:
+		return parseHTML(bytes.NewReader(data))
+	case FormatPocketCSV:
+		return parseCSV(data, pocketRecord)
+	case FormatInstapaper:
+		return parseCSV(data, instapaperRecord)
+	case FormatJSON:
+		return parseJSON(data)
+	}
+	return nil, fmt.Errorf("unsupported import format %q", format)
+}
+
+// jsonExport is the part of an exporter.FormatJSON document an import reads
+type jsonExport struct {
+	Caches []struct {
+		Title      string     `json:"title"`
+		Content    string     `json:"content"`
+		URL        string     `json:"url"`
+		Tags       []string   `json:"tags"`
+		Status     string     `json:"status"`
+		Progress   int32      `json:"progress"`
+		IsPublic   bool       `json:"is_public"`
+		CreatedAt  time.Time  `json:"created_at"`
+		ReadAt     *time.Time `json:"read_at"`
+		ArchivedAt *time.Time `json:"archived_at"`
+	} `json:"caches"`
+}
+
+// readingStatuses are the statuses a ReadCache export may carry
+var readingStatuses = map[string]bool{
+	"unread":   true,
+	"reading":  true,
+	"read":     true,
+	"archived": true,
+}
+
+// parseJSON reads a ReadCache export, caches without a url are notes and keep
+// their content. An unknown status leaves the item unread.
+func parseJSON(data []byte) ([]Item, error) {
+	var export jsonExport
+	if err := json.Unmarshal(data, &export); err != nil {
+		return nil, err
+	}
+
+	var items []Item
+	for _, cache := range export.Caches {
+		item := Item{
+			URL:      cache.URL,
+			Title:    cache.Title,
+			Tags:     NormalizeTags(cache.Tags),
+			AddedAt:  cache.CreatedAt,
+			Archived: cache.Status == "archived",
+			IsPublic: cache.IsPublic,
+			Progress: min(max(cache.Progress, 0), 100),
+		}
+		if cache.URL == "" {
+			item.Content = cache.Content
+		}
+		if readingStatuses[cache.Status] {
+			item.Status = cache.Status
+		}
+		if cache.ReadAt != nil {
+			item.ReadAt = *cache.ReadAt
+		}
+		if cache.ArchivedAt != nil {
+			item.ArchivedAt = *cache.ArchivedAt
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// parseCSV reads a csv export with a header row, record turns a row keyed by
+// lower case column name into an item or reports it as empty
+func parseCSV(data []byte, record func(row map[string]string) (Item, bool)) ([]Item, error) {
+	r := csv.NewReader(bytes.NewReader(data))
+	r.FieldsPerRecord = -1
+	r.LazyQuotes = true
+
+	header, err := r.Read()
+	if err != nil {
+		return nil, ErrUnknownFormat
+	}
+	columns := csvColumns(header)
+
+	var items []Item
+	for {
+		fields, err := r.Read()
+		if err == io.EOF {
+			return items, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		row := make(map[string]string, len(columns))
+		for name, i := range columns {
+			if i < len(fields) {
+				row[name] = strings.TrimSpace(fields[i])
+			}
+		}
+		if item, ok := record(row); ok {
+			items = append(items, item)
+		}
+	}
+}
+
+func pocketRecord(row map[string]string) (Item, bool) {
+	if row["url"] == "" {
+		return Item{}, false
+	}
+	return Item{
+		URL:      row["url"],
+		Title:    row["title"],
+		Tags:     NormalizeTags(strings.Split(row["tags"], "|")),
+		AddedAt:  parseUnix(row["time_added"]),
+		Archived: strings.EqualFold(row["status"], "archive"),
+	}, true
+}
+
+// instapaperRecord maps Instapaper's built in folders onto the reading status
+// and starred flag, any other folder becomes a tag
+func instapaperRecord(row map[string]string) (Item, bool) {
+	if row["url"] == "" {
+		return Item{}, false
+	}
+
+	item := Item{
+		URL:     row["url"],
+		Title:   row["title"],
+		AddedAt: parseUnix(row["timestamp"]),
+	}
+
+	var tags []string
+	if raw := row["tags"]; raw != "" {
+		// newer exports list tags as a json array
+		tags = parseTagList(raw)
+	}
+
+	switch folder := row["folder"]; strings.ToLower(folder) {
+	case "", "unread":
+	case "archive":
+		item.Archived = true
+	case "starred":
+		tags = append(tags, "starred")
+	default:
+		tags = append(tags, folder)
+	}
+	item.Tags = NormalizeTags(tags)
+
+	return item, true
+}
+
+// parseTagList reads a json array of tags, falling back to a comma separated list
+func parseTagList(raw string) []string {
+	var tags []string
+	if err := json.Unmarshal([]byte(raw), &tags); err == nil {
+		return tags
+	}
+	return strings.Split(raw, ",")
+}
+
+func csvColumns(header []string) map[string]int {
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	return columns
+}
+
+func hasColumns(columns map[string]int, names ...string) bool {
+	for _, name := range names {
+		if _, ok := columns[name]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// parseUnix reads a unix timestamp in seconds, zero when missing or invalid
+func parseUnix(s string) time.Time {
+	seconds, err := strconv.ParseInt(strings.TrimSpace(s), 10, 64)
+	if err != nil || seconds <= 0 {
+		return time.Time{}
+	}
+	return time.Unix(seconds, 0).UTC()
+}
+
+// NormalizeTags lower cases tags and collapses their whitespace the way tag
+// names are stored, dropping empty, oversized and repeated ones
+func NormalizeTags(names []string) []string {
+	var tags []string
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		tag := strings.ToLower(strings.Join(strings.Fields(name), " "))
+		if tag == "" || len(tag) > maxTagLength || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		tags = append(tags, tag)
+	}
+	return tags
+}
+
+func trimBOM(data []byte) []byte {
+	return bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+}
@@ -0,0 +1,147 @@
+package importer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+const netscapeExport = `<!DOCTYPE NETSCAPE-Bookmark-file-1>
+<!-- This is an automatically generated file. -->
+<META HTTP-EQUIV="Content-Type" CONTENT="text/html; charset=UTF-8">
+<TITLE>Bookmarks</TITLE>
+<H1>Bookmarks</H1>
+<DL><p>
+    <DT><H3 ADD_DATE="1600000000" PERSONAL_TOOLBAR_FOLDER="true">Bookmarks bar</H3>
+    <DL><p>
+        <DT><A HREF="https://go.dev/blog/" ADD_DATE="1600000100">The Go   Blog</A>
+        <DT><H3 ADD_DATE="1600000000">Reading</H3>
+        <DL><p>
+            <DT><A HREF="https://example.com/a" ADD_DATE="1600000200" TAGS="Go,Concurrency">Article A</A>
+            <DT><H3>Later</H3>
+            <DL><p>
+                <DT><A HREF="https://example.com/b">Article B</A>
+            </DL><p>
+        </DL><p>
+    </DL><p>
+    <DT><A HREF="https://example.com/c" ADD_DATE="bogus">Article C</A>
+    <DT><A>no link</A>
+</DL><p>
+`
+
+const pocketHTMLExport = `<!DOCTYPE html>
+<html>
+<head><title>Pocket Export</title></head>
+<body>
+<h1>Unread</h1>
+<ul>
+  <li><a href="https://example.com/unread" time_added="1650000000" tags="go,reading">Unread article</a></li>
+</ul>
+<h1>Read Archive</h1>
+<ul>
+  <li><a href="https://example.com/read" time_added="1640000000" tags="">Read article</a></li>
+</ul>
+</body>
+</html>
+`
+
+const pocketCSVExport = "title,url,time_added,tags,status\n" +
+	"Unread article,https://example.com/unread,1650000000,go|Reading,unread\n" +
+	"\"Read, archived\",https://example.com/read,1640000000,,archive\n" +
+	"Missing,,1640000000,,unread\n"
+
+const instapaperExport = "\xef\xbb\xbfURL,Title,Selection,Folder,Timestamp,Tags\n" +
+	"https://example.com/a,Article A,,Unread,1650000000,\"[\"\"Go\"\"]\"\n" +
+	"https://example.com/b,Article B,,Archive,1640000000,[]\n" +
+	"https://example.com/c,Article C,,Starred,1630000000,\n" +
+	"https://example.com/d,Article D,a highlight,Recipes,1620000000,\n"
+
+func TestDetect(t *testing.T) {
+	testCases := []struct {
+		data   string
+		format Format
+	}{
+		{data: netscapeExport, format: FormatNetscape},
+		{data: pocketHTMLExport, format: FormatPocketHTML},
+		{data: pocketCSVExport, format: FormatPocketCSV},
+		{data: instapaperExport, format: FormatInstapaper},
+		{data: `{"version":1,"caches":[]}`, format: FormatJSON},
+	}
+
+	for _, tc := range testCases {
+		format, err := Detect([]byte(tc.data))
+		require.NoError(t, err)
+		require.Equal(t, tc.format, format)
+	}
+
+	_, err := Detect([]byte("id,name\n1,foo\n"))
+	require.ErrorIs(t, err, ErrUnknownFormat)
+}
+
+func TestParseNetscape(t *testing.T) {
+	items, err := Parse(FormatNetscape, []byte(netscapeExport))
+	require.NoError(t, err)
+
+	require.Equal(t, []Item{
+		{URL: "https://go.dev/blog/", Title: "The Go Blog", AddedAt: time.Unix(1600000100, 0).UTC()},
+		{URL: "https://example.com/a", Title: "Article A", Tags: []string{"go", "concurrency", "reading"}, AddedAt: time.Unix(1600000200, 0).UTC()},
+		{URL: "https://example.com/b", Title: "Article B", Tags: []string{"reading", "later"}},
+		{URL: "https://example.com/c", Title: "Article C"},
+	}, items)
+}
+
+func TestParsePocketHTML(t *testing.T) {
+	items, err := Parse(FormatPocketHTML, []byte(pocketHTMLExport))
+	require.NoError(t, err)
+
+	require.Equal(t, []Item{
+		{URL: "https://example.com/unread", Title: "Unread article", Tags: []string{"go", "reading"}, AddedAt: time.Unix(1650000000, 0).UTC()},
+		{URL: "https://example.com/read", Title: "Read article", AddedAt: time.Unix(1640000000, 0).UTC(), Archived: true},
+	}, items)
+}
+
+func TestParsePocketCSV(t *testing.T) {
+	items, err := Parse(FormatPocketCSV, []byte(pocketCSVExport))
+	require.NoError(t, err)
+
+	require.Equal(t, []Item{
+		{URL: "https://example.com/unread", Title: "Unread article", Tags: []string{"go", "reading"}, AddedAt: time.Unix(1650000000, 0).UTC()},
+		{URL: "https://example.com/read", Title: "Read, archived", AddedAt: time.Unix(1640000000, 0).UTC(), Archived: true},
+	}, items)
+}
+
+func TestParseInstapaper(t *testing.T) {
+	items, err := Parse(FormatInstapaper, []byte(instapaperExport))
+	require.NoError(t, err)
+
+	require.Equal(t, []Item{
+		{URL: "https://example.com/a", Title: "Article A", Tags: []string{"go"}, AddedAt: time.Unix(1650000000, 0).UTC()},
+		{URL: "https://example.com/b", Title: "Article B", AddedAt: time.Unix(1640000000, 0).UTC(), Archived: true},
+		{URL: "https://example.com/c", Title: "Article C", Tags: []string{"starred"}, AddedAt: time.Unix(1630000000, 0).UTC()},
+		{URL: "https://example.com/d", Title: "Article D", Tags: []string{"recipes"}, AddedAt: time.Unix(1620000000, 0).UTC()},
+	}, items)
+}
+
+func TestParseJSON(t *testing.T) {
+	data := `{"version":1,"caches":[
+		{"title":"Link","url":"https://example.com/a","content":"https://example.com/a","status":"read","progress":140,"is_public":true,"created_at":"2024-03-01T09:30:00Z","read_at":"2024-03-02T09:30:00Z"},
+		{"title":"Note","content":"text","status":"bogus","progress":-5,"created_at":"2024-03-01T09:30:00Z"},
+		{"title":"Empty"}
+	]}`
+	items, err := Parse(FormatJSON, []byte(data))
+	require.NoError(t, err)
+
+	addedAt := time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC)
+	require.Equal(t, []Item{
+		{URL: "https://example.com/a", Title: "Link", AddedAt: addedAt, IsPublic: true, Status: "read", Progress: 100, ReadAt: addedAt.Add(24 * time.Hour)},
+		{Title: "Note", Content: "text", AddedAt: addedAt},
+		// an empty entry is kept so the import reports it as failed
+		{Title: "Empty"},
+	}, items)
+}
+
+func TestNormalizeTags(t *testing.T) {
+	tags := NormalizeTags([]string{" Go ", "go", "", "Machine   Learning", string(make([]byte, maxTagLength+1))})
+	require.Equal(t, []string{"go", "machine learning"}, tags)
+}
@@ -0,0 +1,177 @@
+package importer
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	db "github.com/imrishuroy/read-cache-api/db/sqlc"
+	"github.com/imrishuroy/read-cache-api/jobs"
+	"github.com/imrishuroy/read-cache-api/unfurl"
+)
+
+const (
+	defaultPollInterval = 5 * time.Second
+	defaultBatchSize    = 2
+	defaultMaxAttempts  = 5
+	// itemBatchSize items are imported between two lease renewals
+	itemBatchSize = 100
+	jobLease      = 5 * time.Minute
+)
+
+var errUnsupportedLink = errors.New("only http and https links can be imported")
+
+// WorkerConfig tunes a Worker, zero values fall back to sane defaults
+type WorkerConfig struct {
+	jobs.Config
+	MaxAttempts int32
+}
+
+// Worker imports the bookmarks of queued import jobs. Jobs are claimed with a
+// lease like link previews are, and the lease is renewed after every batch of
+// items. Each item is finished in the transaction that saves it, so a job
+// picked up again after a crash carries on with the items still pending.
+type Worker struct {
+	store  db.Store
+	config WorkerConfig
+}
+
+// NewWorker creates a Worker
+func NewWorker(store db.Store, config WorkerConfig) *Worker {
+	config.Config = config.Config.WithDefaults(defaultPollInterval, defaultBatchSize)
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = defaultMaxAttempts
+	}
+
+	return &Worker{
+		store:  store,
+		config: config,
+	}
+}
+
+// Run polls for due import jobs until ctx is cancelled
+func (worker *Worker) Run(ctx context.Context) {
+	jobs.Loop(ctx, worker.config.PollInterval, "import jobs", worker.ProcessDue)
+}
+
+// ProcessDue claims one batch of due import jobs and runs them to completion,
+// it returns how many jobs were claimed
+func (worker *Worker) ProcessDue(ctx context.Context) (int, error) {
+	jobs, err := worker.store.ClaimDueImportJobs(ctx, db.ClaimDueImportJobsParams{
+		LeaseSeconds: int32(jobLease / time.Second),
+		BatchSize:    worker.config.BatchSize,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	for _, job := range jobs {
+		if err := worker.process(ctx, job); err != nil {
+			return len(jobs), err
+		}
+	}
+	return len(jobs), nil
+}
+
+func (worker *Worker) process(ctx context.Context, job db.ImportJob) error {
+	importErr := worker.importItems(ctx, job)
+	if importErr == nil {
+		return worker.store.CompleteImportJob(ctx, job.ID)
+	}
+
+	if ctx.Err() != nil {
+		// shutting down, the lease hands the job to the next run
+		return ctx.Err()
+	}
+
+	attempts := job.Attempts + 1
+	if attempts >= worker.config.MaxAttempts {
+		return worker.store.FailImportJob(ctx, db.FailImportJobParams{
+			ID:        job.ID,
+			LastError: importErr.Error(),
+		})
+	}
+
+	return worker.store.RetryImportJob(ctx, db.RetryImportJobParams{
+		ID:            job.ID,
+		LastError:     importErr.Error(),
+		NextAttemptAt: time.Now().Add(unfurl.RetryDelay(attempts)),
+	})
+}
+
+// importItems works through the pending items of a job batch by batch
+func (worker *Worker) importItems(ctx context.Context, job db.ImportJob) error {
+	for {
+		items, err := worker.store.ListPendingImportItems(ctx, db.ListPendingImportItemsParams{
+			JobID: job.ID,
+			Limit: itemBatchSize,
+		})
+		if err != nil {
+			return err
+		}
+		if len(items) == 0 {
+			return nil
+		}
+
+		for _, item := range items {
+			if err := worker.importItem(ctx, job, item); err != nil {
+				return err
+			}
+		}
+
+		err = worker.store.ExtendImportJobLease(ctx, db.ExtendImportJobLeaseParams{
+			LeaseSeconds: int32(jobLease / time.Second),
+			ID:           job.ID,
+		})
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// importItem saves one item, links we can't save are reported as failed items
+// while database errors fail the whole run so the job is retried
+func (worker *Worker) importItem(ctx context.Context, job db.ImportJob, item db.ImportItem) error {
+	arg := db.ImportCacheTxParams{
+		Owner: job.UserID,
+		Item:  item,
+	}
+
+	// notes of a ReadCache export have no link and are saved as they are
+	if item.Url != "" || item.Content == "" {
+		linkURL, ok := unfurl.LinkURL(item.Url)
+		if !ok {
+			return worker.failItem(ctx, item, errUnsupportedLink)
+		}
+		normalizedURL, err := unfurl.NormalizeURL(linkURL)
+		if err != nil {
+			return worker.failItem(ctx, item, err)
+		}
+		arg.LinkURL = linkURL
+		arg.NormalizedURL = normalizedURL
+	}
+
+	_, err := worker.store.ImportCacheTx(ctx, arg)
+	if db.ErrorCode(err) == db.UniqueViolation {
+		// the owner saved the link while we were importing it, the retry skips it
+		_, err = worker.store.ImportCacheTx(ctx, arg)
+	}
+	if errors.Is(err, db.ErrRecordNotFound) {
+		// another run finished the item first
+		return nil
+	}
+	return err
+}
+
+func (worker *Worker) failItem(ctx context.Context, item db.ImportItem, itemErr error) error {
+	_, err := worker.store.FinishImportItem(ctx, db.FinishImportItemParams{
+		Status:   db.ImportItemFailed,
+		Error:    itemErr.Error(),
+		JobID:    item.JobID,
+		Position: item.Position,
+	})
+	if errors.Is(err, db.ErrRecordNotFound) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,148 @@
+package importer
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/imrishuroy/read-cache-api/db/mock"
+	db "github.com/imrishuroy/read-cache-api/db/sqlc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkerProcessDue(t *testing.T) {
+	job := db.ImportJob{ID: 1, UserID: "user", Total: 4}
+	items := []db.ImportItem{
+		{JobID: job.ID, Position: 1, Url: "https://example.com/a?utm_source=x", Title: "A"},
+		{JobID: job.ID, Position: 2, Url: "javascript:alert(1)"},
+		{JobID: job.ID, Position: 3, Url: "https://example.com/b"},
+		{JobID: job.ID, Position: 4, Title: "Shopping list", Content: "milk\neggs"},
+	}
+
+	testCases := []struct {
+		name       string
+		attempts   int32
+		buildStubs func(store *mockdb.MockStore)
+	}{
+		{
+			name: "Complete",
+			buildStubs: func(store *mockdb.MockStore) {
+				gomock.InOrder(
+					store.EXPECT().
+						ListPendingImportItems(gomock.Any(), db.ListPendingImportItemsParams{JobID: job.ID, Limit: itemBatchSize}).
+						Times(1).
+						Return(items, nil),
+					store.EXPECT().
+						ListPendingImportItems(gomock.Any(), gomock.Any()).
+						Times(1).
+						Return([]db.ImportItem{}, nil),
+				)
+				store.EXPECT().
+					ImportCacheTx(gomock.Any(), db.ImportCacheTxParams{
+						Owner:         job.UserID,
+						Item:          items[0],
+						LinkURL:       items[0].Url,
+						NormalizedURL: "https://example.com/a",
+					}).
+					Times(1).
+					Return(db.ImportItem{}, nil)
+				// a note is saved without a link
+				store.EXPECT().
+					ImportCacheTx(gomock.Any(), db.ImportCacheTxParams{
+						Owner: job.UserID,
+						Item:  items[3],
+					}).
+					Times(1).
+					Return(db.ImportItem{}, nil)
+				store.EXPECT().
+					FinishImportItem(gomock.Any(), db.FinishImportItemParams{
+						Status:   db.ImportItemFailed,
+						Error:    errUnsupportedLink.Error(),
+						JobID:    job.ID,
+						Position: 2,
+					}).
+					Times(1).
+					Return(db.ImportItem{}, nil)
+				// saved concurrently, the second attempt skips it
+				gomock.InOrder(
+					store.EXPECT().
+						ImportCacheTx(gomock.Any(), gomock.Any()).
+						Times(1).
+						Return(db.ImportItem{}, db.ErrUniqueViolation),
+					store.EXPECT().
+						ImportCacheTx(gomock.Any(), gomock.Any()).
+						Times(1).
+						Return(db.ImportItem{}, nil),
+				)
+				store.EXPECT().
+					ExtendImportJobLease(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil)
+				store.EXPECT().
+					CompleteImportJob(gomock.Any(), job.ID).
+					Times(1).
+					Return(nil)
+			},
+		},
+		{
+			name: "Retry",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListPendingImportItems(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, sql.ErrConnDone)
+				store.EXPECT().
+					RetryImportJob(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.RetryImportJobParams) error {
+						require.Equal(t, job.ID, arg.ID)
+						require.Equal(t, sql.ErrConnDone.Error(), arg.LastError)
+						return nil
+					})
+			},
+		},
+		{
+			name:     "Fail",
+			attempts: defaultMaxAttempts - 1,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListPendingImportItems(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(items[:1], nil)
+				store.EXPECT().
+					ImportCacheTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.ImportItem{}, sql.ErrConnDone)
+				store.EXPECT().
+					FailImportJob(gomock.Any(), db.FailImportJobParams{ID: job.ID, LastError: sql.ErrConnDone.Error()}).
+					Times(1).
+					Return(nil)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			claimed := job
+			claimed.Attempts = tc.attempts
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().
+				ClaimDueImportJobs(gomock.Any(), gomock.Any()).
+				Times(1).
+				Return([]db.ImportJob{claimed}, nil)
+			tc.buildStubs(store)
+
+			worker := NewWorker(store, WorkerConfig{})
+			n, err := worker.ProcessDue(context.Background())
+			require.NoError(t, err)
+			require.Equal(t, 1, n)
+		})
+	}
+}
@@ -0,0 +1,54 @@
+// Package jobs runs the polling loop shared by the background workers. A
+// worker claims a batch of due rows under a lease and processes them, jobs
+// only decides when it does so.
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Config tunes the polling of a worker, it is embedded in every worker's
+// config and zero values fall back to the worker's defaults
+type Config struct {
+	PollInterval time.Duration
+	BatchSize    int32
+}
+
+// WithDefaults returns config with its zero values replaced by pollInterval
+// and batchSize
+func (config Config) WithDefaults(pollInterval time.Duration, batchSize int32) Config {
+	if config.PollInterval <= 0 {
+		config.PollInterval = pollInterval
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = batchSize
+	}
+	return config
+}
+
+// ProcessFunc claims one batch of due jobs and processes them, it returns how
+// many jobs were claimed
+type ProcessFunc func(ctx context.Context) (int, error)
+
+// Loop calls processDue right away and then every interval until ctx is
+// cancelled. Errors are logged as failures to process name and never stop the
+// loop, the leases hand unfinished jobs to a later poll.
+func Loop(ctx context.Context, interval time.Duration, name string, processDue ProcessFunc) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := processDue(ctx); err != nil && ctx.Err() == nil {
+			log.Error().Err(err).Msgf("cannot process %s", name)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
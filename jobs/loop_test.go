@@ -0,0 +1,44 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithDefaults(t *testing.T) {
+	config := Config{}.WithDefaults(time.Minute, 10)
+	require.Equal(t, Config{PollInterval: time.Minute, BatchSize: 10}, config)
+
+	config = Config{PollInterval: time.Second, BatchSize: 3}.WithDefaults(time.Minute, 10)
+	require.Equal(t, Config{PollInterval: time.Second, BatchSize: 3}, config)
+}
+
+func TestLoop(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	calls := 0
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		Loop(ctx, time.Millisecond, "test jobs", func(ctx context.Context) (int, error) {
+			calls++
+			if calls == 3 {
+				cancel()
+			}
+			// errors are logged and polling goes on
+			return 0, errors.New("claim failed")
+		})
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("loop did not stop after ctx was cancelled")
+	}
+	require.Equal(t, 3, calls)
+}
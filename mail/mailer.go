@@ -0,0 +1,165 @@
+package mail
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	netmail "net/mail"
+	"net/smtp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Message is a single email, Text is required and HTML is sent as an
+// alternative part when it is set
+type Message struct {
+	To      []string
+	Subject string
+	Text    string
+	HTML    string
+	// Headers are extra headers such as List-Unsubscribe
+	Headers map[string]string
+}
+
+// Mailer sends emails
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// SMTPConfig points an SMTPMailer at a relay. From may include a display name,
+// Username may be empty for local stand-ins such as MailHog or Mailpit that
+// accept unauthenticated mail.
+type SMTPConfig struct {
+	Addr     string
+	From     string
+	Username string
+	Password string
+}
+
+// SMTPMailer sends mail through an SMTP relay, upgrading to STARTTLS when the
+// server offers it
+type SMTPMailer struct {
+	config SMTPConfig
+}
+
+// NewSMTPMailer creates a Mailer for config
+func NewSMTPMailer(config SMTPConfig) *SMTPMailer {
+	return &SMTPMailer{config: config}
+}
+
+// Send delivers msg. net/smtp can't be cancelled, so ctx is only checked
+// before the connection is opened.
+func (mailer *SMTPMailer) Send(ctx context.Context, msg Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if len(msg.To) == 0 {
+		return fmt.Errorf("mail: message has no recipients")
+	}
+
+	var auth smtp.Auth
+	if mailer.config.Username != "" {
+		host := mailer.config.Addr
+		if i := strings.LastIndex(host, ":"); i >= 0 {
+			host = host[:i]
+		}
+		auth = smtp.PlainAuth("", mailer.config.Username, mailer.config.Password, host)
+	}
+
+	// From may carry a display name, the envelope only takes the address
+	sender, err := netmail.ParseAddress(mailer.config.From)
+	if err != nil {
+		return fmt.Errorf("mail: invalid sender: %w", err)
+	}
+
+	body, err := Compose(mailer.config.From, msg, time.Now())
+	if err != nil {
+		return err
+	}
+	return smtp.SendMail(mailer.config.Addr, auth, sender.Address, msg.To, body)
+}
+
+// Compose renders msg as an RFC 5322 message with quoted-printable bodies
+func Compose(from string, msg Message, date time.Time) ([]byte, error) {
+	for _, v := range append([]string{from, msg.Subject}, msg.To...) {
+		if strings.ContainsAny(v, "\r\n") {
+			return nil, fmt.Errorf("mail: header contains a line break")
+		}
+	}
+
+	var buf bytes.Buffer
+	header := func(key, value string) {
+		fmt.Fprintf(&buf, "%s: %s\r\n", key, value)
+	}
+	header("From", from)
+	header("To", strings.Join(msg.To, ", "))
+	header("Subject", mime.QEncoding.Encode("utf-8", msg.Subject))
+	header("Date", date.Format(time.RFC1123Z))
+	header("MIME-Version", "1.0")
+
+	keys := make([]string, 0, len(msg.Headers))
+	for key := range msg.Headers {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if strings.ContainsAny(key+msg.Headers[key], "\r\n") {
+			return nil, fmt.Errorf("mail: header contains a line break")
+		}
+		header(key, msg.Headers[key])
+	}
+
+	if msg.HTML == "" {
+		header("Content-Type", "text/plain; charset=utf-8")
+		header("Content-Transfer-Encoding", "quoted-printable")
+		buf.WriteString("\r\n")
+		if err := writeQuotedPrintable(&buf, msg.Text); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+
+	boundary, err := newBoundary()
+	if err != nil {
+		return nil, err
+	}
+	header("Content-Type", fmt.Sprintf("multipart/alternative; boundary=%q", boundary))
+	buf.WriteString("\r\n")
+
+	for _, part := range []struct{ contentType, body string }{
+		{"text/plain; charset=utf-8", msg.Text},
+		{"text/html; charset=utf-8", msg.HTML},
+	} {
+		fmt.Fprintf(&buf, "--%s\r\n", boundary)
+		header("Content-Type", part.contentType)
+		header("Content-Transfer-Encoding", "quoted-printable")
+		buf.WriteString("\r\n")
+		if err := writeQuotedPrintable(&buf, part.body); err != nil {
+			return nil, err
+		}
+		buf.WriteString("\r\n")
+	}
+	fmt.Fprintf(&buf, "--%s--\r\n", boundary)
+	return buf.Bytes(), nil
+}
+
+func writeQuotedPrintable(buf *bytes.Buffer, body string) error {
+	w := quotedprintable.NewWriter(buf)
+	if _, err := w.Write([]byte(body)); err != nil {
+		return err
+	}
+	return w.Close()
+}
+
+func newBoundary() (string, error) {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,157 @@
+package mail
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSMTP is a minimal SMTP server that records the envelope and data of
+// every message it accepts
+type fakeSMTP struct {
+	listener net.Listener
+	mu       sync.Mutex
+	messages []fakeMessage
+}
+
+type fakeMessage struct {
+	from string
+	to   []string
+	data string
+}
+
+func newFakeSMTP(t *testing.T) *fakeSMTP {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	server := &fakeSMTP{listener: listener}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go server.serve(conn)
+		}
+	}()
+	return server
+}
+
+func (server *fakeSMTP) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+
+	var msg fakeMessage
+	reply("220 localhost ESMTP")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		cmd := strings.ToUpper(strings.TrimSpace(line))
+		switch {
+		case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+			reply("250 localhost")
+		case strings.HasPrefix(cmd, "MAIL FROM:"):
+			msg = fakeMessage{from: strings.Trim(strings.TrimSpace(line)[10:], "<>")}
+			reply("250 OK")
+		case strings.HasPrefix(cmd, "RCPT TO:"):
+			msg.to = append(msg.to, strings.Trim(strings.TrimSpace(line)[8:], "<>"))
+			reply("250 OK")
+		case cmd == "DATA":
+			reply("354 go ahead")
+			var data strings.Builder
+			for {
+				l, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if l == ".\r\n" {
+					break
+				}
+				data.WriteString(l)
+			}
+			msg.data = data.String()
+			server.mu.Lock()
+			server.messages = append(server.messages, msg)
+			server.mu.Unlock()
+			reply("250 OK")
+		case cmd == "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("250 OK")
+		}
+	}
+}
+
+func (server *fakeSMTP) received() []fakeMessage {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	return append([]fakeMessage(nil), server.messages...)
+}
+
+func TestSMTPMailerSend(t *testing.T) {
+	server := newFakeSMTP(t)
+	mailer := NewSMTPMailer(SMTPConfig{
+		Addr: server.listener.Addr().String(),
+		From: "ReadCache <reminders@readcache.test>",
+	})
+
+	err := mailer.Send(context.Background(), Message{
+		To:      []string{"reader@example.com"},
+		Subject: "Time to read",
+		Text:    "Your reminder is due",
+	})
+	require.NoError(t, err)
+
+	messages := server.received()
+	require.Len(t, messages, 1)
+	require.Equal(t, "reminders@readcache.test", messages[0].from)
+	require.Equal(t, []string{"reader@example.com"}, messages[0].to)
+	require.Contains(t, messages[0].data, "From: ReadCache <reminders@readcache.test>\r\n")
+	require.Contains(t, messages[0].data, "Subject: Time to read\r\n")
+	require.Contains(t, messages[0].data, "Your reminder is due")
+}
+
+func TestSMTPMailerNoRecipients(t *testing.T) {
+	mailer := NewSMTPMailer(SMTPConfig{Addr: "127.0.0.1:1", From: "a@b.test"})
+	err := mailer.Send(context.Background(), Message{Subject: "x", Text: "y"})
+	require.Error(t, err)
+}
+
+func TestComposeAlternative(t *testing.T) {
+	data, err := Compose("a@b.test", Message{
+		To:      []string{"c@d.test"},
+		Subject: "Digest",
+		Text:    "plain",
+		HTML:    "<p>rich</p>",
+		Headers: map[string]string{"List-Unsubscribe": "<https://example.com/u>"},
+	}, time.Unix(0, 0))
+	require.NoError(t, err)
+
+	body := string(data)
+	require.Contains(t, body, "Content-Type: multipart/alternative; boundary=")
+	require.Contains(t, body, "List-Unsubscribe: <https://example.com/u>\r\n")
+	require.Contains(t, body, "Content-Type: text/plain; charset=utf-8")
+	require.Contains(t, body, "Content-Type: text/html; charset=utf-8")
+	require.Contains(t, body, "<p>rich</p>")
+}
+
+func TestComposeRejectsHeaderInjection(t *testing.T) {
+	_, err := Compose("a@b.test", Message{
+		To:      []string{"c@d.test"},
+		Subject: "hi\r\nBcc: victim@example.com",
+		Text:    "x",
+	}, time.Now())
+	require.Error(t, err)
+}
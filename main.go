@@ -2,8 +2,18 @@ package main
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"github.com/imrishuroy/read-cache-api/api"
+	"github.com/imrishuroy/read-cache-api/archive"
+	"github.com/imrishuroy/read-cache-api/digest"
+	"github.com/imrishuroy/read-cache-api/importer"
+	"github.com/imrishuroy/read-cache-api/jobs"
+	"github.com/imrishuroy/read-cache-api/mail"
+	"github.com/imrishuroy/read-cache-api/reminder"
+	"github.com/imrishuroy/read-cache-api/unfurl"
+	"github.com/imrishuroy/read-cache-api/webhook"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/rs/zerolog/log"
 
@@ -29,24 +39,75 @@ func main() {
 	}
 	defer connPool.Close() // close db connection
 
-	// creating a Firebase app instance
-	//opt := option.WithCredentialsFile("./service-account-key.json")
-	// app, err := firebase.NewApp(context.Background(), nil, opt)
-	// if err != nil {
-
-	// 	log.Fatal().Msg("Failed to create Firebase app")
-	// }
-
-	// fmt.Println("fb connection done ", app)
-
-	// auth, err := app.Auth(context.Background())
-	// if err != nil {
-	// 	log.Fatal().Err(err).Msg("Failed to create Firebase auth client")
-	// }
-
 	// store creation
 	store := db.NewStore(connPool)
 
+	// key the link caches saved before duplicate detection
+	go func() {
+		count, err := unfurl.BackfillNormalizedURLs(context.Background(), store)
+		if err != nil {
+			log.Error().Err(err).Msg("cannot backfill normalized urls:")
+			return
+		}
+		log.Info().Int("caches", count).Msg("backfilled normalized urls")
+	}()
+
+	// background link preview unfurling
+	fetcher := unfurl.NewFetcher(unfurl.Config{
+		Timeout:      config.UnfurlTimeout,
+		MaxBodyBytes: config.UnfurlMaxBodyBytes,
+	})
+	unfurler := unfurl.NewWorker(store, fetcher, unfurl.WorkerConfig{
+		Config: jobs.Config{PollInterval: config.UnfurlPollInterval},
+	})
+	go unfurler.Run(context.Background())
+
+	// background article snapshots
+	archiveFetcher := unfurl.NewFetcher(unfurl.Config{
+		Timeout:      config.UnfurlTimeout,
+		MaxBodyBytes: config.ArchiveMaxBodyBytes,
+	})
+	archiver := archive.NewWorker(store, archiveFetcher, archive.WorkerConfig{
+		Config: jobs.Config{PollInterval: config.ArchivePollInterval},
+	})
+	go archiver.Run(context.Background())
+
+	// background bookmark imports
+	importWorker := importer.NewWorker(store, importer.WorkerConfig{
+		Config: jobs.Config{PollInterval: config.ImportPollInterval},
+	})
+	go importWorker.Run(context.Background())
+
+	// background reminder delivery
+	notifier, err := newReminderNotifier(config)
+	if err != nil {
+		log.Fatal().Err(err).Msg("cannot create reminder notifier:")
+	}
+	scheduler := reminder.NewScheduler(store, notifier, reminder.SchedulerConfig{
+		Config: jobs.Config{PollInterval: config.ReminderPollInterval},
+	})
+	go scheduler.Run(context.Background())
+
+	// background email digests of subscribed tags, only with a secret to
+	// sign their unsubscribe links
+	if digest.ValidSecret([]byte(config.DigestSecret)) {
+		digestSender := digest.NewSender(store, newMailer(config), digest.SenderConfig{
+			Config:    jobs.Config{PollInterval: config.DigestPollInterval},
+			PublicURL: config.PublicURL,
+			Secret:    []byte(config.DigestSecret),
+		})
+		go digestSender.Run(context.Background())
+	} else {
+		log.Warn().Msgf("DIGEST_SECRET must be at least %d characters, email digests are disabled", digest.MinSecretSize)
+	}
+
+	// background webhook deliveries from the event outbox
+	webhookWorker := webhook.NewWorker(store, webhook.WorkerConfig{
+		Config:  jobs.Config{PollInterval: config.WebhookPollInterval},
+		Timeout: config.WebhookTimeout,
+	})
+	go webhookWorker.Run(context.Background())
+
 	// api server setup
 	server, err := api.NewServer(config, store)
 	if err != nil {
@@ -60,3 +121,28 @@ func main() {
 	}
 
 }
+
+func newReminderNotifier(config util.Config) (reminder.Notifier, error) {
+	switch config.ReminderNotifier {
+	case "", "log":
+		return reminder.LogNotifier{}, nil
+	case "webhook":
+		if config.ReminderWebhookURL == "" {
+			return nil, fmt.Errorf("REMINDER_WEBHOOK_URL is required for the webhook notifier")
+		}
+		return reminder.NewWebhookNotifier(config.ReminderWebhookURL, 10*time.Second), nil
+	case "email":
+		return reminder.NewEmailNotifier(newMailer(config)), nil
+	default:
+		return nil, fmt.Errorf("unknown reminder notifier %q", config.ReminderNotifier)
+	}
+}
+
+func newMailer(config util.Config) mail.Mailer {
+	return mail.NewSMTPMailer(mail.SMTPConfig{
+		Addr:     config.SMTPAddr,
+		From:     config.SMTPFrom,
+		Username: config.SMTPUsername,
+		Password: config.SMTPPassword,
+	})
+}
@@ -0,0 +1,125 @@
+package reminder
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/imrishuroy/read-cache-api/mail"
+	"github.com/rs/zerolog/log"
+)
+
+// Notification is a reminder that came due
+type Notification struct {
+	ReminderID   int64     `json:"reminder_id"`
+	UserID       string    `json:"user_id"`
+	Email        string    `json:"email"`
+	CacheID      int64     `json:"cache_id"`
+	CacheTitle   string    `json:"cache_title"`
+	CacheContent string    `json:"cache_content"`
+	Note         string    `json:"note"`
+	DueAt        time.Time `json:"due_at"`
+	// Late is set when the reminder fires well after it was due, typically
+	// because the server was down at the time
+	Late bool `json:"late"`
+}
+
+// Notifier delivers due reminders to their user
+type Notifier interface {
+	Notify(ctx context.Context, n Notification) error
+}
+
+// LogNotifier only logs reminders, it is the default for local development
+type LogNotifier struct{}
+
+func (LogNotifier) Notify(_ context.Context, n Notification) error {
+	log.Info().
+		Int64("reminder_id", n.ReminderID).
+		Str("user_id", n.UserID).
+		Int64("cache_id", n.CacheID).
+		Time("due_at", n.DueAt).
+		Bool("late", n.Late).
+		Msg("reminder due")
+	return nil
+}
+
+// WebhookNotifier posts reminders as JSON to a fixed url
+type WebhookNotifier struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhookNotifier creates a WebhookNotifier posting to url
+func NewWebhookNotifier(url string, timeout time.Duration) *WebhookNotifier {
+	return &WebhookNotifier{
+		url:    url,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+func (notifier *WebhookNotifier) Notify(ctx context.Context, n Notification) error {
+	body, err := json.Marshal(n)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, notifier.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := notifier.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("reminder webhook responded %s", resp.Status)
+	}
+	return nil
+}
+
+// EmailNotifier mails reminders to the user's address
+type EmailNotifier struct {
+	mailer mail.Mailer
+}
+
+// NewEmailNotifier creates an EmailNotifier sending through mailer
+func NewEmailNotifier(mailer mail.Mailer) *EmailNotifier {
+	return &EmailNotifier{mailer: mailer}
+}
+
+func (notifier *EmailNotifier) Notify(ctx context.Context, n Notification) error {
+	if n.Email == "" {
+		return fmt.Errorf("user %s has no email address", n.UserID)
+	}
+
+	title := n.CacheTitle
+	if title == "" {
+		title = "a saved cache"
+	}
+
+	var text strings.Builder
+	fmt.Fprintf(&text, "You asked to be reminded to read %s.\n\n", title)
+	if n.Note != "" {
+		fmt.Fprintf(&text, "%s\n\n", n.Note)
+	}
+	if n.CacheContent != "" {
+		fmt.Fprintf(&text, "%s\n\n", n.CacheContent)
+	}
+	if n.Late {
+		fmt.Fprintf(&text, "This reminder was due at %s and is being sent late.\n", n.DueAt.UTC().Format(time.RFC1123))
+	}
+
+	return notifier.mailer.Send(ctx, mail.Message{
+		To:      []string{n.Email},
+		Subject: "Reminder: " + title,
+		Text:    text.String(),
+	})
+}
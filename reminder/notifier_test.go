@@ -0,0 +1,80 @@
+package reminder
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/imrishuroy/read-cache-api/mail"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeMailer struct {
+	sent []mail.Message
+	err  error
+}
+
+func (mailer *fakeMailer) Send(_ context.Context, msg mail.Message) error {
+	mailer.sent = append(mailer.sent, msg)
+	return mailer.err
+}
+
+func testNotification() Notification {
+	return Notification{
+		ReminderID: 7,
+		UserID:     "user-1",
+		Email:      "reader@example.com",
+		CacheID:    3,
+		CacheTitle: "Go memory model",
+		Note:       "before the meetup",
+		DueAt:      time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestWebhookNotifier(t *testing.T) {
+	var got Notification
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		require.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	notifier := NewWebhookNotifier(server.URL, time.Second)
+	require.NoError(t, notifier.Notify(context.Background(), testNotification()))
+	require.Equal(t, testNotification(), got)
+}
+
+func TestWebhookNotifierErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	notifier := NewWebhookNotifier(server.URL, time.Second)
+	require.Error(t, notifier.Notify(context.Background(), testNotification()))
+}
+
+func TestEmailNotifier(t *testing.T) {
+	mailer := &fakeMailer{}
+	notifier := NewEmailNotifier(mailer)
+
+	n := testNotification()
+	n.Late = true
+	require.NoError(t, notifier.Notify(context.Background(), n))
+
+	require.Len(t, mailer.sent, 1)
+	msg := mailer.sent[0]
+	require.Equal(t, []string{"reader@example.com"}, msg.To)
+	require.Equal(t, "Reminder: Go memory model", msg.Subject)
+	require.Contains(t, msg.Text, "before the meetup")
+	require.Contains(t, msg.Text, "sent late")
+
+	n.Email = ""
+	require.Error(t, notifier.Notify(context.Background(), n))
+	require.Len(t, mailer.sent, 1)
+}
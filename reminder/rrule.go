@@ -0,0 +1,211 @@
+package reminder
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Frequency is how often a recurring reminder repeats
+type Frequency string
+
+const (
+	Daily   Frequency = "DAILY"
+	Weekly  Frequency = "WEEKLY"
+	Monthly Frequency = "MONTHLY"
+	Yearly  Frequency = "YEARLY"
+)
+
+// maxOccurrences bounds how far Next walks a series, a daily rule covers
+// a few hundred years before it gives up
+const maxOccurrences = 100000
+
+var ErrInvalidRule = errors.New("invalid recurrence rule")
+
+var weekdays = map[string]time.Weekday{
+	"MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday, "TH": time.Thursday,
+	"FR": time.Friday, "SA": time.Saturday, "SU": time.Sunday,
+}
+
+// Rule is the subset of RFC 5545 recurrence rules reminders support:
+// FREQ (DAILY, WEEKLY, MONTHLY or YEARLY), INTERVAL, COUNT, UNTIL and, for
+// weekly rules, BYDAY without ordinals, e.g. FREQ=WEEKLY;BYDAY=MO,WE;COUNT=10
+type Rule struct {
+	Freq     Frequency
+	Interval int
+	// Count limits the series to this many occurrences, 0 means unbounded
+	Count int
+	// Until is the last instant an occurrence may fall on, zero means unbounded
+	Until time.Time
+	ByDay []time.Weekday
+}
+
+// ParseRule parses a recurrence rule, with or without the RRULE: prefix
+func ParseRule(s string) (*Rule, error) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "RRULE:")
+	rule := &Rule{Interval: 1}
+
+	for _, part := range strings.Split(s, ";") {
+		key, value, ok := strings.Cut(part, "=")
+		if !ok || value == "" {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidRule, part)
+		}
+
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			switch freq := Frequency(strings.ToUpper(value)); freq {
+			case Daily, Weekly, Monthly, Yearly:
+				rule.Freq = freq
+			default:
+				return nil, fmt.Errorf("%w: unsupported FREQ %q", ErrInvalidRule, value)
+			}
+		case "INTERVAL":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 || n > 1000 {
+				return nil, fmt.Errorf("%w: INTERVAL must be between 1 and 1000", ErrInvalidRule)
+			}
+			rule.Interval = n
+		case "COUNT":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 || n > maxOccurrences {
+				return nil, fmt.Errorf("%w: COUNT must be between 1 and %d", ErrInvalidRule, maxOccurrences)
+			}
+			rule.Count = n
+		case "UNTIL":
+			until, err := parseUntil(value)
+			if err != nil {
+				return nil, fmt.Errorf("%w: UNTIL %q", ErrInvalidRule, value)
+			}
+			rule.Until = until
+		case "BYDAY":
+			for _, day := range strings.Split(strings.ToUpper(value), ",") {
+				weekday, ok := weekdays[day]
+				if !ok {
+					return nil, fmt.Errorf("%w: unsupported BYDAY %q", ErrInvalidRule, day)
+				}
+				rule.ByDay = append(rule.ByDay, weekday)
+			}
+		default:
+			return nil, fmt.Errorf("%w: unsupported part %q", ErrInvalidRule, key)
+		}
+	}
+
+	if rule.Freq == "" {
+		return nil, fmt.Errorf("%w: FREQ is required", ErrInvalidRule)
+	}
+	if rule.Count > 0 && !rule.Until.IsZero() {
+		return nil, fmt.Errorf("%w: COUNT and UNTIL can't be combined", ErrInvalidRule)
+	}
+	if len(rule.ByDay) > 0 && rule.Freq != Weekly {
+		return nil, fmt.Errorf("%w: BYDAY is only supported on weekly rules", ErrInvalidRule)
+	}
+
+	// walk the days of a week Monday first, the RFC 5545 default week start
+	sort.Slice(rule.ByDay, func(i, j int) bool {
+		return mondayOffset(rule.ByDay[i]) < mondayOffset(rule.ByDay[j])
+	})
+	return rule, nil
+}
+
+func parseUntil(value string) (time.Time, error) {
+	for _, layout := range []string{"20060102T150405Z", "20060102"} {
+		if until, err := time.Parse(layout, value); err == nil {
+			if layout == "20060102" {
+				// a date only UNTIL includes the whole day
+				until = until.Add(24*time.Hour - time.Second)
+			}
+			return until, nil
+		}
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+// Next returns the first occurrence strictly after after of the series that
+// starts at start, ok is false once the series has ended. Occurrences keep
+// start's wall clock time in start's location, so a daily 9am reminder stays
+// at 9am across daylight saving changes.
+func (rule *Rule) Next(start, after time.Time) (next time.Time, ok bool) {
+	n := 0
+	rule.each(start, func(t time.Time) bool {
+		n++
+		if rule.Count > 0 && n > rule.Count {
+			return false
+		}
+		if !rule.Until.IsZero() && t.After(rule.Until) {
+			return false
+		}
+		if t.After(after) {
+			next, ok = t, true
+			return false
+		}
+		return true
+	})
+	return next, ok
+}
+
+// each yields the occurrences of the series in order until yield returns false
+func (rule *Rule) each(start time.Time, yield func(time.Time) bool) {
+	year, month, day := start.Date()
+	hour, min, sec := start.Clock()
+	at := func(y int, m time.Month, d int) time.Time {
+		return time.Date(y, m, d, hour, min, sec, start.Nanosecond(), start.Location())
+	}
+
+	for i, n := 0, 0; n < maxOccurrences; i++ {
+		step := i * rule.Interval
+		switch rule.Freq {
+		case Daily:
+			n++
+			if !yield(at(year, month, day+step)) {
+				return
+			}
+		case Weekly:
+			if len(rule.ByDay) == 0 {
+				n++
+				if !yield(at(year, month, day+7*step)) {
+					return
+				}
+				continue
+			}
+			monday := day - mondayOffset(start.Weekday()) + 7*step
+			for _, weekday := range rule.ByDay {
+				t := at(year, month, monday+mondayOffset(weekday))
+				if t.Before(start) {
+					continue
+				}
+				n++
+				if !yield(t) {
+					return
+				}
+			}
+		case Monthly:
+			n++
+			// months without the start day are skipped, as RFC 5545 does
+			t := at(year, month+time.Month(step), day)
+			if t.Day() != day {
+				continue
+			}
+			if !yield(t) {
+				return
+			}
+		case Yearly:
+			n++
+			t := at(year+step, month, day)
+			if t.Day() != day {
+				continue
+			}
+			if !yield(t) {
+				return
+			}
+		default:
+			return
+		}
+	}
+}
+
+func mondayOffset(weekday time.Weekday) int {
+	return (int(weekday) + 6) % 7
+}
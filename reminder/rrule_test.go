@@ -0,0 +1,167 @@
+package reminder
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func mustParseRule(t *testing.T, s string) *Rule {
+	rule, err := ParseRule(s)
+	require.NoError(t, err)
+	return rule
+}
+
+func TestParseRule(t *testing.T) {
+	rule := mustParseRule(t, "RRULE:FREQ=WEEKLY;INTERVAL=2;BYDAY=FR,MO;COUNT=4")
+	require.Equal(t, Weekly, rule.Freq)
+	require.Equal(t, 2, rule.Interval)
+	require.Equal(t, 4, rule.Count)
+	require.Equal(t, []time.Weekday{time.Monday, time.Friday}, rule.ByDay)
+
+	rule = mustParseRule(t, "FREQ=DAILY;UNTIL=20240105")
+	require.Equal(t, time.Date(2024, 1, 5, 23, 59, 59, 0, time.UTC), rule.Until)
+
+	for _, s := range []string{
+		"",
+		"INTERVAL=2",
+		"FREQ=HOURLY",
+		"FREQ=DAILY;INTERVAL=0",
+		"FREQ=DAILY;COUNT=2;UNTIL=20240101",
+		"FREQ=MONTHLY;BYDAY=MO",
+		"FREQ=WEEKLY;BYDAY=1MO",
+		"FREQ=DAILY;BYHOUR=9",
+		"FREQ",
+	} {
+		_, err := ParseRule(s)
+		require.ErrorIs(t, err, ErrInvalidRule, s)
+	}
+}
+
+func TestRuleNext(t *testing.T) {
+	start := time.Date(2024, 1, 31, 9, 0, 0, 0, time.UTC) // a Wednesday
+
+	testCases := []struct {
+		name  string
+		rule  string
+		start time.Time
+		after time.Time
+		next  time.Time
+		ok    bool
+	}{
+		{
+			name:  "FirstOccurrenceIsStart",
+			rule:  "FREQ=DAILY",
+			after: start.Add(-time.Second),
+			next:  start,
+			ok:    true,
+		},
+		{
+			name:  "DailyInterval",
+			rule:  "FREQ=DAILY;INTERVAL=3",
+			after: start,
+			next:  start.AddDate(0, 0, 3),
+			ok:    true,
+		},
+		{
+			name:  "SkipsMissedOccurrences",
+			rule:  "FREQ=DAILY",
+			after: time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC),
+			next:  time.Date(2024, 3, 11, 9, 0, 0, 0, time.UTC),
+			ok:    true,
+		},
+		{
+			name:  "WeeklyByDay",
+			rule:  "FREQ=WEEKLY;BYDAY=MO,FR",
+			after: start,
+			next:  time.Date(2024, 2, 2, 9, 0, 0, 0, time.UTC),
+			ok:    true,
+		},
+		{
+			name:  "WeeklyByDayEveryOtherWeek",
+			rule:  "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO",
+			after: start,
+			next:  time.Date(2024, 2, 12, 9, 0, 0, 0, time.UTC),
+			ok:    true,
+		},
+		{
+			name:  "MonthlySkipsShortMonths",
+			rule:  "FREQ=MONTHLY",
+			after: start,
+			next:  time.Date(2024, 3, 31, 9, 0, 0, 0, time.UTC),
+			ok:    true,
+		},
+		{
+			name:  "YearlyLeapDay",
+			rule:  "FREQ=YEARLY",
+			start: time.Date(2024, 2, 29, 9, 0, 0, 0, time.UTC),
+			after: time.Date(2024, 2, 29, 10, 0, 0, 0, time.UTC),
+			next:  time.Date(2028, 2, 29, 9, 0, 0, 0, time.UTC),
+			ok:    true,
+		},
+		{
+			name:  "CountExhausted",
+			rule:  "FREQ=DAILY;COUNT=3",
+			after: start.AddDate(0, 0, 2),
+			ok:    false,
+		},
+		{
+			name:  "CountRemaining",
+			rule:  "FREQ=DAILY;COUNT=3",
+			after: start.AddDate(0, 0, 1),
+			next:  start.AddDate(0, 0, 2),
+			ok:    true,
+		},
+		{
+			name:  "UntilPassed",
+			rule:  "FREQ=WEEKLY;UNTIL=20240210T000000Z",
+			after: start.AddDate(0, 0, 7),
+			ok:    false,
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			seriesStart := start
+			if !tc.start.IsZero() {
+				seriesStart = tc.start
+			}
+
+			next, ok := mustParseRule(t, tc.rule).Next(seriesStart, tc.after)
+			require.Equal(t, tc.ok, ok)
+			if tc.ok {
+				require.Equal(t, tc.next, next)
+			}
+		})
+	}
+}
+
+func TestScheduleKeepsWallClockAcrossDST(t *testing.T) {
+	// clocks in Berlin go forward on 31 March 2024
+	schedule, err := NewSchedule(time.Date(2024, 3, 30, 8, 0, 0, 0, time.UTC), "FREQ=DAILY", "Europe/Berlin")
+	require.NoError(t, err)
+
+	next, ok := schedule.Next(time.Date(2024, 3, 30, 12, 0, 0, 0, time.UTC))
+	require.True(t, ok)
+	require.Equal(t, time.Date(2024, 3, 31, 7, 0, 0, 0, time.UTC), next.UTC())
+	require.Equal(t, 9, next.Hour())
+}
+
+func TestScheduleOneOff(t *testing.T) {
+	remindAt := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	schedule, err := NewSchedule(remindAt, "", "")
+	require.NoError(t, err)
+
+	next, ok := schedule.Next(remindAt.Add(-time.Minute))
+	require.True(t, ok)
+	require.True(t, remindAt.Equal(next))
+
+	_, ok = schedule.Next(remindAt)
+	require.False(t, ok)
+
+	_, err = NewSchedule(remindAt, "", "Mars/Olympus")
+	require.Error(t, err)
+}
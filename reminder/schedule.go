@@ -0,0 +1,48 @@
+package reminder
+
+import (
+	"fmt"
+	"time"
+
+	// reminders carry IANA time zones, embed the database so they resolve on
+	// hosts without zoneinfo such as slim containers
+	_ "time/tzdata"
+)
+
+// Schedule is when a reminder fires: once at RemindAt, or on every
+// occurrence of Rule starting at RemindAt when Rule is set
+type Schedule struct {
+	RemindAt time.Time
+	Rule     *Rule
+	Location *time.Location
+}
+
+// NewSchedule parses a reminder's stored rule and time zone, rrule may be
+// empty for one-off reminders and timeZone defaults to UTC
+func NewSchedule(remindAt time.Time, rrule, timeZone string) (Schedule, error) {
+	if timeZone == "" {
+		timeZone = "UTC"
+	}
+	loc, err := time.LoadLocation(timeZone)
+	if err != nil {
+		return Schedule{}, fmt.Errorf("unknown time zone %q", timeZone)
+	}
+
+	schedule := Schedule{RemindAt: remindAt, Location: loc}
+	if rrule != "" {
+		if schedule.Rule, err = ParseRule(rrule); err != nil {
+			return Schedule{}, err
+		}
+	}
+	return schedule, nil
+}
+
+// Next returns the first time the reminder fires strictly after after, ok is
+// false once a one-off reminder has passed or a recurring one has ended
+func (schedule Schedule) Next(after time.Time) (next time.Time, ok bool) {
+	start := schedule.RemindAt.In(schedule.Location)
+	if schedule.Rule == nil {
+		return start, start.After(after)
+	}
+	return schedule.Rule.Next(start, after)
+}
@@ -0,0 +1,172 @@
+package reminder
+
+import (
+	"context"
+	"time"
+
+	db "github.com/imrishuroy/read-cache-api/db/sqlc"
+	"github.com/imrishuroy/read-cache-api/jobs"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	defaultPollInterval  = 30 * time.Second
+	defaultBatchSize     = 20
+	defaultMaxAttempts   = 5
+	defaultLateAfter     = 5 * time.Minute
+	defaultNotifyTimeout = 10 * time.Second
+	baseRetryDelay       = 30 * time.Second
+	maxRetryDelay        = 30 * time.Minute
+	leaseMargin          = time.Minute
+)
+
+// SchedulerConfig tunes a Scheduler, zero values fall back to sane defaults
+type SchedulerConfig struct {
+	jobs.Config
+	// MaxAttempts is how often delivering one occurrence is tried before it is skipped
+	MaxAttempts int32
+	// LateAfter is how far past its due time a reminder is flagged as late
+	LateAfter time.Duration
+	// NotifyTimeout bounds a single notification
+	NotifyTimeout time.Duration
+}
+
+// Scheduler fires due reminders through a Notifier. Reminders are claimed
+// with FOR UPDATE SKIP LOCKED and leased like link previews, so several
+// server processes can run a Scheduler and a reminder claimed by a process
+// that crashes fires again once its lease runs out.
+//
+// Nothing is kept in memory: a reminder whose time passed while no server was
+// running is still due in the database and fires, flagged as late, on the
+// next poll. A recurring reminder that missed several occurrences fires once
+// and then moves on to its next future occurrence instead of replaying every
+// missed one.
+type Scheduler struct {
+	store    db.Store
+	notifier Notifier
+	config   SchedulerConfig
+	lease    time.Duration
+}
+
+// NewScheduler creates a Scheduler delivering through notifier
+func NewScheduler(store db.Store, notifier Notifier, config SchedulerConfig) *Scheduler {
+	config.Config = config.Config.WithDefaults(defaultPollInterval, defaultBatchSize)
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = defaultMaxAttempts
+	}
+	if config.LateAfter <= 0 {
+		config.LateAfter = defaultLateAfter
+	}
+	if config.NotifyTimeout <= 0 {
+		config.NotifyTimeout = defaultNotifyTimeout
+	}
+
+	return &Scheduler{
+		store:    store,
+		notifier: notifier,
+		config:   config,
+		lease:    time.Duration(config.BatchSize)*config.NotifyTimeout + leaseMargin,
+	}
+}
+
+// Run polls for due reminders until ctx is cancelled
+func (scheduler *Scheduler) Run(ctx context.Context) {
+	jobs.Loop(ctx, scheduler.config.PollInterval, "reminders", scheduler.ProcessDue)
+}
+
+// ProcessDue claims one batch of due reminders and fires them, it returns how
+// many reminders were claimed
+func (scheduler *Scheduler) ProcessDue(ctx context.Context) (int, error) {
+	reminders, err := scheduler.store.ClaimDueReminders(ctx, db.ClaimDueRemindersParams{
+		BatchSize:    scheduler.config.BatchSize,
+		LeaseSeconds: int32(scheduler.lease / time.Second),
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	for _, reminder := range reminders {
+		if err := scheduler.fire(ctx, reminder); err != nil {
+			return len(reminders), err
+		}
+	}
+	return len(reminders), nil
+}
+
+func (scheduler *Scheduler) fire(ctx context.Context, reminder db.ClaimDueRemindersRow) error {
+	now := time.Now()
+	notification := Notification{
+		ReminderID:   reminder.ID,
+		UserID:       reminder.UserID,
+		Email:        reminder.UserEmail,
+		CacheID:      reminder.CacheID,
+		CacheTitle:   reminder.CacheTitle,
+		CacheContent: reminder.CacheContent,
+		Note:         reminder.Note,
+		DueAt:        reminder.DueAt.Time,
+		Late:         now.Sub(reminder.DueAt.Time) > scheduler.config.LateAfter,
+	}
+
+	notifyCtx, cancel := context.WithTimeout(ctx, scheduler.config.NotifyTimeout)
+	notifyErr := scheduler.notifier.Notify(notifyCtx, notification)
+	cancel()
+
+	if notifyErr == nil {
+		return scheduler.store.CompleteReminderFire(ctx, db.CompleteReminderFireParams{
+			ID:         reminder.ID,
+			NextFireAt: scheduler.nextFire(reminder, now),
+		})
+	}
+
+	if ctx.Err() != nil {
+		// shutting down, the lease hands the reminder to the next run
+		return ctx.Err()
+	}
+
+	attempts := reminder.Attempts + 1
+	if attempts >= scheduler.config.MaxAttempts {
+		log.Error().Err(notifyErr).Int64("reminder_id", reminder.ID).Msg("giving up on reminder occurrence")
+		return scheduler.store.SkipReminderFire(ctx, db.SkipReminderFireParams{
+			ID:         reminder.ID,
+			NextFireAt: scheduler.nextFire(reminder, now),
+			LastError:  notifyErr.Error(),
+		})
+	}
+
+	return scheduler.store.RetryReminder(ctx, db.RetryReminderParams{
+		ID:         reminder.ID,
+		NextFireAt: pgtype.Timestamptz{Time: now.Add(RetryDelay(attempts)), Valid: true},
+		LastError:  notifyErr.Error(),
+	})
+}
+
+// nextFire is the reminder's first occurrence after now, or NULL once it is
+// done. Occurrences missed while the server was down are skipped.
+func (scheduler *Scheduler) nextFire(reminder db.ClaimDueRemindersRow, now time.Time) pgtype.Timestamptz {
+	schedule, err := NewSchedule(reminder.RemindAt, reminder.Rrule, reminder.TimeZone)
+	if err != nil {
+		// rules are validated when reminders are created, a bad one ends the series
+		log.Error().Err(err).Int64("reminder_id", reminder.ID).Msg("invalid reminder schedule")
+		return pgtype.Timestamptz{}
+	}
+
+	next, ok := schedule.Next(now)
+	if !ok {
+		return pgtype.Timestamptz{}
+	}
+	return pgtype.Timestamptz{Time: next, Valid: true}
+}
+
+// RetryDelay is how long to wait before delivering a reminder again after
+// attempts failures, it backs off exponentially from 30 seconds to 30 minutes
+func RetryDelay(attempts int32) time.Duration {
+	delay := baseRetryDelay
+	for i := int32(1); i < attempts; i++ {
+		delay *= 2
+		if delay >= maxRetryDelay {
+			return maxRetryDelay
+		}
+	}
+	return delay
+}
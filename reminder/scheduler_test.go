@@ -0,0 +1,157 @@
+package reminder
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/imrishuroy/read-cache-api/db/mock"
+	db "github.com/imrishuroy/read-cache-api/db/sqlc"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingNotifier struct {
+	notified []Notification
+	err      error
+}
+
+func (notifier *recordingNotifier) Notify(_ context.Context, n Notification) error {
+	notifier.notified = append(notifier.notified, n)
+	return notifier.err
+}
+
+func dueReminder(remindAt, dueAt time.Time, rrule string) db.ClaimDueRemindersRow {
+	return db.ClaimDueRemindersRow{
+		ID:         1,
+		CacheID:    2,
+		UserID:     "user-1",
+		RemindAt:   remindAt,
+		Rrule:      rrule,
+		TimeZone:   "UTC",
+		DueAt:      pgtype.Timestamptz{Time: dueAt, Valid: true},
+		CacheTitle: "title",
+		UserEmail:  "reader@example.com",
+	}
+}
+
+func TestSchedulerProcessDue(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+
+	testCases := []struct {
+		name       string
+		reminder   db.ClaimDueRemindersRow
+		notifyErr  error
+		buildStubs func(store *mockdb.MockStore)
+		checkSent  func(t *testing.T, notified []Notification)
+	}{
+		{
+			name:     "OneOffCompletes",
+			reminder: dueReminder(now.Add(-time.Second), now.Add(-time.Second), ""),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CompleteReminderFire(gomock.Any(), gomock.Eq(db.CompleteReminderFireParams{ID: 1})).
+					Times(1).
+					Return(nil)
+			},
+			checkSent: func(t *testing.T, notified []Notification) {
+				require.Len(t, notified, 1)
+				require.False(t, notified[0].Late)
+				require.Equal(t, "reader@example.com", notified[0].Email)
+			},
+		},
+		{
+			name: "MissedRecurringFiresOnceAndSkipsAhead",
+			// a daily reminder whose last three occurrences passed while the server was down
+			reminder: dueReminder(now.Add(-10*24*time.Hour), now.Add(-3*24*time.Hour), "FREQ=DAILY"),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CompleteReminderFire(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CompleteReminderFireParams) error {
+						require.True(t, arg.NextFireAt.Valid)
+						require.True(t, arg.NextFireAt.Time.After(now))
+						require.WithinDuration(t, now.Add(24*time.Hour), arg.NextFireAt.Time, 0)
+						return nil
+					})
+			},
+			checkSent: func(t *testing.T, notified []Notification) {
+				require.Len(t, notified, 1)
+				require.True(t, notified[0].Late)
+			},
+		},
+		{
+			name:      "Retry",
+			reminder:  dueReminder(now, now, ""),
+			notifyErr: errors.New("smtp unavailable"),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					RetryReminder(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.RetryReminderParams) error {
+						require.Equal(t, "smtp unavailable", arg.LastError)
+						require.WithinDuration(t, time.Now().Add(baseRetryDelay), arg.NextFireAt.Time, time.Second)
+						return nil
+					})
+				store.EXPECT().CompleteReminderFire(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkSent: func(t *testing.T, notified []Notification) {
+				require.Len(t, notified, 1)
+			},
+		},
+		{
+			name: "OutOfAttemptsSkipsOccurrence",
+			reminder: func() db.ClaimDueRemindersRow {
+				r := dueReminder(now.Add(-time.Hour), now, "FREQ=WEEKLY")
+				r.Attempts = defaultMaxAttempts - 1
+				return r
+			}(),
+			notifyErr: errors.New("smtp unavailable"),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					SkipReminderFire(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.SkipReminderFireParams) error {
+						require.Equal(t, "smtp unavailable", arg.LastError)
+						require.WithinDuration(t, now.Add(-time.Hour).AddDate(0, 0, 7), arg.NextFireAt.Time, 0)
+						return nil
+					})
+				store.EXPECT().RetryReminder(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkSent: func(t *testing.T, notified []Notification) {
+				require.Len(t, notified, 1)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().
+				ClaimDueReminders(gomock.Any(), gomock.Any()).
+				Times(1).
+				Return([]db.ClaimDueRemindersRow{tc.reminder}, nil)
+			tc.buildStubs(store)
+
+			notifier := &recordingNotifier{err: tc.notifyErr}
+			scheduler := NewScheduler(store, notifier, SchedulerConfig{})
+			n, err := scheduler.ProcessDue(context.Background())
+			require.NoError(t, err)
+			require.Equal(t, 1, n)
+			tc.checkSent(t, notifier.notified)
+		})
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	require.Equal(t, baseRetryDelay, RetryDelay(1))
+	require.Equal(t, 4*baseRetryDelay, RetryDelay(3))
+	require.Equal(t, maxRetryDelay, RetryDelay(30))
+}
@@ -0,0 +1,67 @@
+package unfurl
+
+import (
+	"context"
+	"errors"
+
+	db "github.com/imrishuroy/read-cache-api/db/sqlc"
+)
+
+const (
+	backfillBatchSize = 100
+	// normalizedURLBackfill names the backfill in the backfills table
+	normalizedURLBackfill = "normalized_urls"
+)
+
+// BackfillNormalizedURLs keys the link caches saved before duplicate
+// detection existed, so saving one of those links again is caught as a
+// duplicate. When an owner saved a link more than once only the oldest cache
+// gets the key. It returns how many caches it looked at, once a backfill
+// finished later calls return right away.
+func BackfillNormalizedURLs(ctx context.Context, store db.Store) (int, error) {
+	_, err := store.GetBackfill(ctx, normalizedURLBackfill)
+	if err == nil {
+		return 0, nil
+	}
+	if !errors.Is(err, db.ErrRecordNotFound) {
+		return 0, err
+	}
+
+	var afterID int64
+	count := 0
+	for {
+		caches, err := store.ListCachesWithoutNormalizedURL(ctx, db.ListCachesWithoutNormalizedURLParams{
+			AfterID:   afterID,
+			PageLimit: backfillBatchSize,
+		})
+		if err != nil {
+			return count, err
+		}
+
+		for _, cache := range caches {
+			afterID = cache.ID
+			count++
+
+			linkURL, ok := LinkURL(cache.Content)
+			if !ok {
+				continue
+			}
+			normalized, err := NormalizeURL(linkURL)
+			if err != nil {
+				continue
+			}
+
+			err = store.UpdateCacheNormalizedURL(ctx, db.UpdateCacheNormalizedURLParams{
+				ID:            cache.ID,
+				NormalizedUrl: normalized,
+			})
+			if err != nil {
+				return count, err
+			}
+		}
+
+		if len(caches) < backfillBatchSize {
+			return count, store.FinishBackfill(ctx, normalizedURLBackfill)
+		}
+	}
+}
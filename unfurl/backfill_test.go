@@ -0,0 +1,94 @@
+package unfurl
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/imrishuroy/read-cache-api/db/mock"
+	db "github.com/imrishuroy/read-cache-api/db/sqlc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackfillNormalizedURLs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := mockdb.NewMockStore(ctrl)
+
+	// a full first page is followed by another one
+	firstPage := make([]db.ListCachesWithoutNormalizedURLRow, backfillBatchSize)
+	for i := range firstPage {
+		firstPage[i] = db.ListCachesWithoutNormalizedURLRow{
+			ID:      int64(i + 1),
+			Content: fmt.Sprintf("http://Example.com/%d/?utm_source=x", i+1),
+		}
+	}
+	lastPage := []db.ListCachesWithoutNormalizedURLRow{
+		{ID: backfillBatchSize + 1, Content: "https://example.com/last"},
+		// matches the query but isn't a link we can key
+		{ID: backfillBatchSize + 2, Content: "https://"},
+	}
+
+	gomock.InOrder(
+		store.EXPECT().
+			GetBackfill(gomock.Any(), gomock.Eq(normalizedURLBackfill)).
+			Times(1).
+			Return(db.Backfill{}, db.ErrRecordNotFound),
+		store.EXPECT().
+			ListCachesWithoutNormalizedURL(gomock.Any(), gomock.Eq(db.ListCachesWithoutNormalizedURLParams{
+				AfterID:   0,
+				PageLimit: backfillBatchSize,
+			})).
+			Times(1).
+			Return(firstPage, nil),
+		store.EXPECT().
+			ListCachesWithoutNormalizedURL(gomock.Any(), gomock.Eq(db.ListCachesWithoutNormalizedURLParams{
+				AfterID:   backfillBatchSize,
+				PageLimit: backfillBatchSize,
+			})).
+			Times(1).
+			Return(lastPage, nil),
+		store.EXPECT().
+			FinishBackfill(gomock.Any(), gomock.Eq(normalizedURLBackfill)).
+			Times(1).
+			Return(nil),
+	)
+
+	store.EXPECT().
+		UpdateCacheNormalizedURL(gomock.Any(), gomock.Any()).
+		Times(backfillBatchSize + 1).
+		DoAndReturn(func(_ context.Context, arg db.UpdateCacheNormalizedURLParams) error {
+			want := fmt.Sprintf("https://example.com/%d", arg.ID)
+			if arg.ID == backfillBatchSize+1 {
+				want = "https://example.com/last"
+			}
+			require.Equal(t, want, arg.NormalizedUrl)
+			return nil
+		})
+
+	count, err := BackfillNormalizedURLs(context.Background(), store)
+	require.NoError(t, err)
+	require.Equal(t, backfillBatchSize+2, count)
+}
+
+func TestBackfillNormalizedURLsFinished(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := mockdb.NewMockStore(ctrl)
+
+	// a finished backfill doesn't scan the caches again
+	store.EXPECT().
+		GetBackfill(gomock.Any(), gomock.Eq(normalizedURLBackfill)).
+		Times(1).
+		Return(db.Backfill{Name: normalizedURLBackfill, FinishedAt: time.Now()}, nil)
+	store.EXPECT().
+		ListCachesWithoutNormalizedURL(gomock.Any(), gomock.Any()).
+		Times(0)
+	store.EXPECT().
+		FinishBackfill(gomock.Any(), gomock.Any()).
+		Times(0)
+
+	count, err := BackfillNormalizedURLs(context.Background(), store)
+	require.NoError(t, err)
+	require.Zero(t, count)
+}
@@ -0,0 +1,234 @@
+package unfurl
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const (
+	defaultTimeout      = 10 * time.Second
+	defaultMaxBodyBytes = 1 << 20
+	defaultUserAgent    = "ReadCacheBot/1.0 (+link preview)"
+	maxRedirects        = 5
+)
+
+var (
+	ErrUnsupportedURL     = errors.New("only http and https links can be unfurled")
+	ErrBlockedAddress     = errors.New("link resolves to a private or reserved address")
+	ErrUnsupportedContent = errors.New("link does not point to an html page")
+	ErrTooManyRedirects   = errors.New("link redirects too many times")
+)
+
+// StatusError is returned when the page answers with a non 2xx status
+type StatusError struct {
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("unexpected status %d", e.StatusCode)
+}
+
+// IsPermanent reports whether fetching the same link again can not succeed
+func IsPermanent(err error) bool {
+	if errors.Is(err, ErrUnsupportedURL) || errors.Is(err, ErrBlockedAddress) ||
+		errors.Is(err, ErrUnsupportedContent) || errors.Is(err, ErrTooManyRedirects) {
+		return true
+	}
+
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		code := statusErr.StatusCode
+		return code >= 400 && code < 500 &&
+			code != http.StatusRequestTimeout && code != http.StatusTooManyRequests
+	}
+	return false
+}
+
+// Config tunes a Fetcher, zero values fall back to sane defaults
+type Config struct {
+	Timeout      time.Duration
+	MaxBodyBytes int64
+	UserAgent    string
+	// AllowPrivateNetworks disables the SSRF guard, only meant for tests
+	AllowPrivateNetworks bool
+}
+
+// Fetcher downloads pages and extracts their link metadata
+type Fetcher struct {
+	client       *http.Client
+	maxBodyBytes int64
+	userAgent    string
+}
+
+// NewFetcher creates a Fetcher. Unless private networks are allowed every
+// connection, including the ones made for redirects, is checked against the
+// resolved IP so a public hostname can not point the fetcher at internal hosts.
+func NewFetcher(config Config) *Fetcher {
+	if config.Timeout <= 0 {
+		config.Timeout = defaultTimeout
+	}
+	if config.MaxBodyBytes <= 0 {
+		config.MaxBodyBytes = defaultMaxBodyBytes
+	}
+	if config.UserAgent == "" {
+		config.UserAgent = defaultUserAgent
+	}
+
+	dialer := &net.Dialer{Timeout: config.Timeout}
+	if !config.AllowPrivateNetworks {
+		dialer.Control = GuardDial
+	}
+
+	transport := &http.Transport{
+		Proxy:                 nil,
+		DialContext:           dialer.DialContext,
+		TLSHandshakeTimeout:   config.Timeout,
+		ResponseHeaderTimeout: config.Timeout,
+		MaxIdleConns:          10,
+		IdleConnTimeout:       30 * time.Second,
+	}
+
+	client := &http.Client{
+		Transport: transport,
+		Timeout:   config.Timeout,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= maxRedirects {
+				return ErrTooManyRedirects
+			}
+			if !isHTTPURL(req.URL) {
+				return ErrUnsupportedURL
+			}
+			return nil
+		},
+	}
+
+	return &Fetcher{
+		client:       client,
+		maxBodyBytes: config.MaxBodyBytes,
+		userAgent:    config.UserAgent,
+	}
+}
+
+// Page is a downloaded html document
+type Page struct {
+	// URL is where the page was served from after following redirects
+	URL  *url.URL
+	Body []byte
+}
+
+// Timeout is the time limit of a single fetch
+func (f *Fetcher) Timeout() time.Duration {
+	return f.client.Timeout
+}
+
+// FetchPage downloads the html at rawURL, pages larger than the size cap are cut off
+func (f *Fetcher) FetchPage(ctx context.Context, rawURL string) (Page, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || !isHTTPURL(u) {
+		return Page{}, ErrUnsupportedURL
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return Page{}, err
+	}
+	req.Header.Set("User-Agent", f.userAgent)
+	req.Header.Set("Accept", "text/html,application/xhtml+xml;q=0.9")
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return Page{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return Page{}, &StatusError{StatusCode: resp.StatusCode}
+	}
+
+	if !isHTMLContent(resp.Header.Get("Content-Type")) {
+		return Page{}, ErrUnsupportedContent
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, f.maxBodyBytes))
+	if err != nil {
+		return Page{}, err
+	}
+
+	return Page{URL: resp.Request.URL, Body: body}, nil
+}
+
+// Fetch downloads rawURL and returns the metadata found in its html
+func (f *Fetcher) Fetch(ctx context.Context, rawURL string) (Metadata, error) {
+	page, err := f.FetchPage(ctx, rawURL)
+	if err != nil {
+		return Metadata{}, err
+	}
+	// a truncated page still has its metadata, it lives in <head>
+	return ParseMetadata(bytes.NewReader(page.Body), page.URL)
+}
+
+// LinkURL returns the url when content is a single http(s) link
+func LinkURL(content string) (string, bool) {
+	content = strings.TrimSpace(content)
+	if content == "" || strings.ContainsAny(content, " \t\r\n") {
+		return "", false
+	}
+
+	u, err := url.Parse(content)
+	if err != nil || !isHTTPURL(u) {
+		return "", false
+	}
+	return u.String(), true
+}
+
+func isHTTPURL(u *url.URL) bool {
+	return (u.Scheme == "http" || u.Scheme == "https") && u.Hostname() != ""
+}
+
+func isHTMLContent(contentType string) bool {
+	// servers that omit the header are given the benefit of the doubt
+	if contentType == "" {
+		return true
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == "text/html" || mediaType == "application/xhtml+xml"
+}
+
+// GuardDial is a net.Dialer Control function that refuses connections to
+// private and reserved addresses. It runs after DNS resolution, so it also
+// catches public hostnames pointing at internal hosts.
+func GuardDial(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil || isBlockedIP(ip) {
+		return ErrBlockedAddress
+	}
+	return nil
+}
+
+// carrier grade NAT space is not covered by net.IP.IsPrivate
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+func isBlockedIP(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() ||
+		sharedAddressSpace.Contains(ip)
+}
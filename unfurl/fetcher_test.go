@@ -0,0 +1,177 @@
+package unfurl
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testPage = `<html><head>
+<title>Test page</title>
+<meta property="og:description" content="A page served by the test server">
+</head><body>hello</body></html>`
+
+func newTestFetcher(config Config) *Fetcher {
+	config.AllowPrivateNetworks = true
+	return NewFetcher(config)
+}
+
+func TestFetch(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/page", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprint(w, testPage)
+	})
+	mux.HandleFunc("/redirect", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/page", http.StatusFound)
+	})
+	mux.HandleFunc("/loop", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/loop", http.StatusFound)
+	})
+	mux.HandleFunc("/image", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		w.Write([]byte{0x89, 'P', 'N', 'G'})
+	})
+	mux.HandleFunc("/missing", func(w http.ResponseWriter, r *http.Request) {
+		http.NotFound(w, r)
+	})
+	mux.HandleFunc("/unavailable", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	mux.HandleFunc("/slow", func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		fmt.Fprint(w, testPage)
+	})
+	mux.HandleFunc("/huge", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, "<html><head>")
+		fmt.Fprint(w, strings.Repeat("<!-- padding -->", 1000))
+		fmt.Fprint(w, "<title>Too far</title></head></html>")
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	testCases := []struct {
+		name   string
+		path   string
+		config Config
+		check  func(t *testing.T, md Metadata, err error)
+	}{
+		{
+			name: "OK",
+			path: "/page",
+			check: func(t *testing.T, md Metadata, err error) {
+				require.NoError(t, err)
+				require.Equal(t, "Test page", md.Title)
+				require.Equal(t, "A page served by the test server", md.Description)
+				require.Equal(t, server.URL+"/page", md.CanonicalURL)
+			},
+		},
+		{
+			name: "FollowsRedirect",
+			path: "/redirect",
+			check: func(t *testing.T, md Metadata, err error) {
+				require.NoError(t, err)
+				require.Equal(t, server.URL+"/page", md.CanonicalURL)
+			},
+		},
+		{
+			name: "RedirectLoop",
+			path: "/loop",
+			check: func(t *testing.T, md Metadata, err error) {
+				require.ErrorIs(t, err, ErrTooManyRedirects)
+				require.True(t, IsPermanent(err))
+			},
+		},
+		{
+			name: "NotHTML",
+			path: "/image",
+			check: func(t *testing.T, md Metadata, err error) {
+				require.ErrorIs(t, err, ErrUnsupportedContent)
+				require.True(t, IsPermanent(err))
+			},
+		},
+		{
+			name: "NotFound",
+			path: "/missing",
+			check: func(t *testing.T, md Metadata, err error) {
+				require.Error(t, err)
+				require.True(t, IsPermanent(err))
+			},
+		},
+		{
+			name: "ServerError",
+			path: "/unavailable",
+			check: func(t *testing.T, md Metadata, err error) {
+				require.Error(t, err)
+				require.False(t, IsPermanent(err))
+			},
+		},
+		{
+			name:   "Timeout",
+			path:   "/slow",
+			config: Config{Timeout: 50 * time.Millisecond},
+			check: func(t *testing.T, md Metadata, err error) {
+				require.Error(t, err)
+				require.False(t, IsPermanent(err))
+			},
+		},
+		{
+			name:   "SizeCap",
+			path:   "/huge",
+			config: Config{MaxBodyBytes: 1024},
+			check: func(t *testing.T, md Metadata, err error) {
+				require.NoError(t, err)
+				require.Empty(t, md.Title)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			fetcher := newTestFetcher(tc.config)
+			md, err := fetcher.Fetch(context.Background(), server.URL+tc.path)
+			tc.check(t, md, err)
+		})
+	}
+}
+
+func TestFetchBlocksPrivateAddresses(t *testing.T) {
+	requested := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = true
+		fmt.Fprint(w, testPage)
+	}))
+	defer server.Close()
+
+	fetcher := NewFetcher(Config{})
+
+	_, err := fetcher.Fetch(context.Background(), server.URL)
+	require.ErrorIs(t, err, ErrBlockedAddress)
+	require.True(t, IsPermanent(err))
+	require.False(t, requested)
+
+	_, err = fetcher.Fetch(context.Background(), "file:///etc/passwd")
+	require.ErrorIs(t, err, ErrUnsupportedURL)
+}
+
+func TestIsBlockedIP(t *testing.T) {
+	blocked := []string{"127.0.0.1", "10.1.2.3", "172.16.0.1", "192.168.1.1", "169.254.169.254", "100.64.0.1", "0.0.0.0", "::1", "fd00::1", "fe80::1"}
+	for _, ip := range blocked {
+		require.True(t, isBlockedIP(net.ParseIP(ip)), ip)
+	}
+
+	allowed := []string{"93.184.216.34", "8.8.8.8", "2606:4700:4700::1111"}
+	for _, ip := range allowed {
+		require.False(t, isBlockedIP(net.ParseIP(ip)), ip)
+	}
+}
@@ -0,0 +1,147 @@
+package unfurl
+
+import (
+	"io"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// column limits keep a hostile page from bloating link_previews
+const (
+	maxTitleLength       = 300
+	maxDescriptionLength = 1000
+	maxURLLength         = 2048
+)
+
+// Metadata is what a page says about itself through OpenGraph, Twitter
+// cards and plain html tags
+type Metadata struct {
+	Title        string `json:"title"`
+	Description  string `json:"description"`
+	SiteName     string `json:"site_name"`
+	ImageURL     string `json:"image_url"`
+	CanonicalURL string `json:"canonical_url"`
+}
+
+// ParseMetadata reads an html document, base is used to resolve relative
+// image and canonical links. OpenGraph wins over Twitter cards, which win
+// over the generic <title>, <meta name="description"> and <link rel="canonical">.
+func ParseMetadata(r io.Reader, base *url.URL) (Metadata, error) {
+	doc, err := html.Parse(r)
+	if err != nil {
+		return Metadata{}, err
+	}
+
+	var (
+		meta      = map[string]string{}
+		title     string
+		canonical string
+	)
+
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			switch n.DataAtom {
+			case atom.Title:
+				if title == "" && n.FirstChild != nil {
+					title = n.FirstChild.Data
+				}
+			case atom.Meta:
+				key := strings.ToLower(attr(n, "property"))
+				if key == "" {
+					key = strings.ToLower(attr(n, "name"))
+				}
+				if _, seen := meta[key]; key != "" && !seen {
+					meta[key] = attr(n, "content")
+				}
+			case atom.Link:
+				if canonical == "" && hasToken(attr(n, "rel"), "canonical") {
+					canonical = attr(n, "href")
+				}
+			case atom.Body:
+				// metadata outside <head> is not trustworthy, skip the page body
+				return
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(doc)
+
+	md := Metadata{
+		Title:       clean(firstNonEmpty(meta["og:title"], meta["twitter:title"], title), maxTitleLength),
+		Description: clean(firstNonEmpty(meta["og:description"], meta["twitter:description"], meta["description"]), maxDescriptionLength),
+		SiteName:    clean(meta["og:site_name"], maxTitleLength),
+		ImageURL: resolve(base, firstNonEmpty(
+			meta["og:image:secure_url"], meta["og:image"], meta["og:image:url"],
+			meta["twitter:image"], meta["twitter:image:src"],
+		)),
+		CanonicalURL: resolve(base, firstNonEmpty(canonical, meta["og:url"])),
+	}
+	if md.CanonicalURL == "" && base != nil {
+		md.CanonicalURL = base.String()
+	}
+
+	return md, nil
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if strings.EqualFold(a.Key, key) {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+func hasToken(list, token string) bool {
+	for _, field := range strings.Fields(list) {
+		if strings.EqualFold(field, token) {
+			return true
+		}
+	}
+	return false
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if strings.TrimSpace(v) != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// clean collapses whitespace and truncates on a rune boundary
+func clean(s string, max int) string {
+	s = strings.Join(strings.Fields(s), " ")
+	runes := []rune(s)
+	if len(runes) > max {
+		return string(runes[:max])
+	}
+	return s
+}
+
+// resolve makes ref absolute, anything that is not an http(s) url is dropped
+func resolve(base *url.URL, ref string) string {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return ""
+	}
+
+	u, err := url.Parse(ref)
+	if err != nil {
+		return ""
+	}
+	if base != nil {
+		u = base.ResolveReference(u)
+	}
+	if !isHTTPURL(u) || len(u.String()) > maxURLLength {
+		return ""
+	}
+	return u.String()
+}
@@ -0,0 +1,117 @@
+package unfurl
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMetadata(t *testing.T) {
+	base, err := url.Parse("https://example.com/articles/go")
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name     string
+		page     string
+		expected Metadata
+	}{
+		{
+			name: "OpenGraph",
+			page: `<html><head>
+				<title>Plain title</title>
+				<meta property="og:title" content="  OG   title ">
+				<meta property="og:description" content="OG description">
+				<meta property="og:site_name" content="Example">
+				<meta property="og:image" content="/images/cover.png">
+				<meta name="twitter:title" content="Twitter title">
+				<link rel="canonical" href="https://example.com/go">
+			</head><body></body></html>`,
+			expected: Metadata{
+				Title:        "OG title",
+				Description:  "OG description",
+				SiteName:     "Example",
+				ImageURL:     "https://example.com/images/cover.png",
+				CanonicalURL: "https://example.com/go",
+			},
+		},
+		{
+			name: "TwitterCard",
+			page: `<html><head>
+				<meta name="twitter:title" content="Twitter title">
+				<meta name="twitter:description" content="Twitter description">
+				<meta name="twitter:image" content="https://cdn.example.com/card.jpg">
+				<meta property="og:url" content="https://example.com/canonical">
+			</head></html>`,
+			expected: Metadata{
+				Title:        "Twitter title",
+				Description:  "Twitter description",
+				ImageURL:     "https://cdn.example.com/card.jpg",
+				CanonicalURL: "https://example.com/canonical",
+			},
+		},
+		{
+			name: "PlainHTML",
+			page: `<html><head>
+				<title>Plain
+				title</title>
+				<meta name="description" content="Plain description">
+				<meta property="og:image" content="javascript:alert(1)">
+			</head></html>`,
+			expected: Metadata{
+				Title:        "Plain title",
+				Description:  "Plain description",
+				CanonicalURL: "https://example.com/articles/go",
+			},
+		},
+		{
+			name: "IgnoresBody",
+			page: `<html><head><title>Head</title></head>
+				<body><meta property="og:title" content="Injected"></body></html>`,
+			expected: Metadata{
+				Title:        "Head",
+				CanonicalURL: "https://example.com/articles/go",
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			md, err := ParseMetadata(strings.NewReader(tc.page), base)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, md)
+		})
+	}
+}
+
+func TestParseMetadataTruncates(t *testing.T) {
+	page := `<title>` + strings.Repeat("é", maxTitleLength+10) + `</title>`
+
+	md, err := ParseMetadata(strings.NewReader(page), nil)
+	require.NoError(t, err)
+	require.Len(t, []rune(md.Title), maxTitleLength)
+}
+
+func TestLinkURL(t *testing.T) {
+	testCases := []struct {
+		content string
+		link    string
+		ok      bool
+	}{
+		{content: " https://example.com/a?b=c ", link: "https://example.com/a?b=c", ok: true},
+		{content: "http://example.com", link: "http://example.com", ok: true},
+		{content: "ftp://example.com/file", ok: false},
+		{content: "read https://example.com later", ok: false},
+		{content: "just a note", ok: false},
+		{content: "https://", ok: false},
+	}
+
+	for _, tc := range testCases {
+		link, ok := LinkURL(tc.content)
+		require.Equal(t, tc.ok, ok, tc.content)
+		require.Equal(t, tc.link, link, tc.content)
+	}
+}
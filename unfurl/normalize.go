@@ -0,0 +1,98 @@
+package unfurl
+
+import (
+	"net"
+	"net/url"
+	"strings"
+)
+
+// trackingParams are query parameters that only identify where a click came
+// from, every parameter starting with utm_ is dropped as well
+var trackingParams = map[string]bool{
+	"fbclid": true, "gclid": true, "gclsrc": true, "dclid": true, "msclkid": true,
+	"yclid": true, "igshid": true, "mc_cid": true, "mc_eid": true, "mkt_tok": true,
+	"_ga": true, "_gl": true, "_hsenc": true, "_hsmi": true, "ref_src": true,
+}
+
+// NormalizeURL turns a link into the key used to detect duplicate saves. Two
+// links that differ only in scheme, host case, default port, trailing slash,
+// fragment, tracking parameters or query order normalize to the same key.
+// The result is not meant to be fetched, http links are keyed as https.
+func NormalizeURL(rawURL string) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return "", err
+	}
+
+	u.Scheme = strings.ToLower(u.Scheme)
+	if !isHTTPURL(u) {
+		return "", ErrUnsupportedURL
+	}
+
+	host := strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+	port := u.Port()
+	if port != "" && !(u.Scheme == "http" && port == "80") && !(u.Scheme == "https" && port == "443") {
+		host = net.JoinHostPort(host, port)
+	} else if strings.Contains(host, ":") {
+		// bare IPv6 hosts need their brackets back
+		host = "[" + host + "]"
+	}
+
+	path := u.EscapedPath()
+	if len(path) > 1 {
+		path = strings.TrimRight(path, "/")
+	}
+	if path == "" {
+		path = "/"
+	}
+
+	normalized := &url.URL{
+		Scheme:   "https",
+		Host:     host,
+		RawPath:  path,
+		RawQuery: normalizeQuery(u.RawQuery),
+	}
+	normalized.Path, err = url.PathUnescape(path)
+	if err != nil {
+		return "", err
+	}
+
+	return normalized.String(), nil
+}
+
+// normalizeQuery drops tracking parameters and sorts the rest by key
+func normalizeQuery(rawQuery string) string {
+	if rawQuery == "" {
+		return ""
+	}
+
+	values, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		// leave queries we can't parse, such as ones using ';', as they are
+		return rawQuery
+	}
+
+	for key := range values {
+		lower := strings.ToLower(key)
+		if strings.HasPrefix(lower, "utm_") || trackingParams[lower] {
+			values.Del(key)
+		}
+	}
+	return values.Encode()
+}
+
+// SameSite reports whether two urls point at the same host, ignoring a leading www.
+func SameSite(a, b string) bool {
+	ua, err := url.Parse(a)
+	if err != nil {
+		return false
+	}
+	ub, err := url.Parse(b)
+	if err != nil {
+		return false
+	}
+
+	hostA := strings.TrimPrefix(strings.ToLower(ua.Hostname()), "www.")
+	hostB := strings.TrimPrefix(strings.ToLower(ub.Hostname()), "www.")
+	return hostA != "" && hostA == hostB
+}
@@ -0,0 +1,147 @@
+package unfurl
+
+import (
+	"context"
+	"time"
+
+	db "github.com/imrishuroy/read-cache-api/db/sqlc"
+	"github.com/imrishuroy/read-cache-api/jobs"
+)
+
+const (
+	defaultPollInterval = 5 * time.Second
+	defaultBatchSize    = 10
+	defaultMaxAttempts  = 5
+	baseRetryDelay      = time.Minute
+	maxRetryDelay       = 6 * time.Hour
+)
+
+// WorkerConfig tunes a Worker, zero values fall back to sane defaults
+type WorkerConfig struct {
+	jobs.Config
+	MaxAttempts int32
+}
+
+// Worker unfurls the link previews queued by cache creation. Due previews are
+// claimed with a lease instead of a long running transaction: the claim
+// pushes next_attempt_at past the fetch timeout, so another worker only picks
+// a preview up again when this one crashed while fetching it.
+type Worker struct {
+	store   db.Store
+	fetcher *Fetcher
+	config  WorkerConfig
+	lease   time.Duration
+}
+
+// NewWorker creates a Worker that fetches pages with fetcher
+func NewWorker(store db.Store, fetcher *Fetcher, config WorkerConfig) *Worker {
+	config.Config = config.Config.WithDefaults(defaultPollInterval, defaultBatchSize)
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = defaultMaxAttempts
+	}
+
+	return &Worker{
+		store:   store,
+		fetcher: fetcher,
+		config:  config,
+		lease:   2*fetcher.Timeout() + time.Minute,
+	}
+}
+
+// Run polls for due previews until ctx is cancelled
+func (worker *Worker) Run(ctx context.Context) {
+	jobs.Loop(ctx, worker.config.PollInterval, "link previews", worker.ProcessDue)
+}
+
+// ProcessDue claims one batch of due previews and unfurls them, it returns
+// how many previews were claimed
+func (worker *Worker) ProcessDue(ctx context.Context) (int, error) {
+	previews, err := worker.store.ClaimDueLinkPreviews(ctx, db.ClaimDueLinkPreviewsParams{
+		LeaseSeconds: int32(worker.lease / time.Second),
+		BatchSize:    worker.config.BatchSize,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	for _, preview := range previews {
+		if err := worker.process(ctx, preview); err != nil {
+			return len(previews), err
+		}
+	}
+	return len(previews), nil
+}
+
+func (worker *Worker) process(ctx context.Context, preview db.LinkPreview) error {
+	md, fetchErr := worker.fetcher.Fetch(ctx, preview.Url)
+	if fetchErr == nil {
+		err := worker.store.CompleteLinkPreview(ctx, db.CompleteLinkPreviewParams{
+			CacheID:      preview.CacheID,
+			Title:        md.Title,
+			Description:  md.Description,
+			SiteName:     md.SiteName,
+			ImageUrl:     md.ImageURL,
+			CanonicalUrl: md.CanonicalURL,
+		})
+		if err != nil {
+			return err
+		}
+		return worker.followCanonical(ctx, preview, md.CanonicalURL)
+	}
+
+	if ctx.Err() != nil {
+		// shutting down, the lease hands the preview to the next run
+		return ctx.Err()
+	}
+
+	attempts := preview.Attempts + 1
+	if IsPermanent(fetchErr) || attempts >= worker.config.MaxAttempts {
+		return worker.store.FailLinkPreview(ctx, db.FailLinkPreviewParams{
+			CacheID:   preview.CacheID,
+			LastError: fetchErr.Error(),
+		})
+	}
+
+	return worker.store.RetryLinkPreview(ctx, db.RetryLinkPreviewParams{
+		CacheID:       preview.CacheID,
+		LastError:     fetchErr.Error(),
+		NextAttemptAt: time.Now().Add(RetryDelay(attempts)),
+	})
+}
+
+// followCanonical re-keys the cache by the page's canonical url, so later
+// saves of any variant of the link are caught as duplicates. Only canonical
+// links on the same site are trusted, a page can't claim to be another site.
+func (worker *Worker) followCanonical(ctx context.Context, preview db.LinkPreview, canonicalURL string) error {
+	if canonicalURL == "" || !SameSite(preview.Url, canonicalURL) {
+		return nil
+	}
+
+	normalized, err := NormalizeURL(canonicalURL)
+	if err != nil {
+		return nil
+	}
+
+	err = worker.store.UpdateCacheNormalizedURL(ctx, db.UpdateCacheNormalizedURLParams{
+		ID:            preview.CacheID,
+		NormalizedUrl: normalized,
+	})
+	if db.ErrorCode(err) == db.UniqueViolation {
+		// the owner saved the canonical link concurrently, keep the cache as it is
+		return nil
+	}
+	return err
+}
+
+// RetryDelay is how long to wait before the next fetch of a link that failed
+// attempts times, it backs off exponentially from a minute up to six hours
+func RetryDelay(attempts int32) time.Duration {
+	delay := baseRetryDelay
+	for i := int32(1); i < attempts; i++ {
+		delay *= 2
+		if delay >= maxRetryDelay {
+			return maxRetryDelay
+		}
+	}
+	return delay
+}
@@ -0,0 +1,127 @@
+package unfurl
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/imrishuroy/read-cache-api/db/mock"
+	db "github.com/imrishuroy/read-cache-api/db/sqlc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkerProcessDue(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/page":
+			fmt.Fprint(w, testPage)
+		case "/gone":
+			w.WriteHeader(http.StatusGone)
+		default:
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer server.Close()
+
+	testCases := []struct {
+		name       string
+		preview    db.LinkPreview
+		buildStubs func(store *mockdb.MockStore, preview db.LinkPreview)
+	}{
+		{
+			name:    "Complete",
+			preview: db.LinkPreview{CacheID: 1, Url: server.URL + "/page"},
+			buildStubs: func(store *mockdb.MockStore, preview db.LinkPreview) {
+				arg := db.CompleteLinkPreviewParams{
+					CacheID:      preview.CacheID,
+					Title:        "Test page",
+					Description:  "A page served by the test server",
+					CanonicalUrl: server.URL + "/page",
+				}
+				store.EXPECT().
+					CompleteLinkPreview(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(nil)
+
+				normalized, err := NormalizeURL(server.URL + "/page")
+				require.NoError(t, err)
+				store.EXPECT().
+					UpdateCacheNormalizedURL(gomock.Any(), gomock.Eq(db.UpdateCacheNormalizedURLParams{
+						ID:            preview.CacheID,
+						NormalizedUrl: normalized,
+					})).
+					Times(1).
+					Return(nil)
+			},
+		},
+		{
+			name:    "Retry",
+			preview: db.LinkPreview{CacheID: 2, Url: server.URL + "/flaky", Attempts: 1},
+			buildStubs: func(store *mockdb.MockStore, preview db.LinkPreview) {
+				store.EXPECT().
+					RetryLinkPreview(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.RetryLinkPreviewParams) error {
+						require.Equal(t, preview.CacheID, arg.CacheID)
+						require.NotEmpty(t, arg.LastError)
+						require.WithinDuration(t, time.Now().Add(2*baseRetryDelay), arg.NextAttemptAt, time.Second)
+						return nil
+					})
+			},
+		},
+		{
+			name:    "PermanentFailure",
+			preview: db.LinkPreview{CacheID: 3, Url: server.URL + "/gone"},
+			buildStubs: func(store *mockdb.MockStore, preview db.LinkPreview) {
+				store.EXPECT().
+					FailLinkPreview(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil)
+			},
+		},
+		{
+			name:    "OutOfAttempts",
+			preview: db.LinkPreview{CacheID: 4, Url: server.URL + "/flaky", Attempts: defaultMaxAttempts - 1},
+			buildStubs: func(store *mockdb.MockStore, preview db.LinkPreview) {
+				store.EXPECT().
+					FailLinkPreview(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil)
+				store.EXPECT().
+					RetryLinkPreview(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().
+				ClaimDueLinkPreviews(gomock.Any(), gomock.Any()).
+				Times(1).
+				Return([]db.LinkPreview{tc.preview}, nil)
+			tc.buildStubs(store, tc.preview)
+
+			worker := NewWorker(store, newTestFetcher(Config{}), WorkerConfig{})
+			n, err := worker.ProcessDue(context.Background())
+			require.NoError(t, err)
+			require.Equal(t, 1, n)
+		})
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	require.Equal(t, baseRetryDelay, RetryDelay(1))
+	require.Equal(t, 4*baseRetryDelay, RetryDelay(3))
+	require.Equal(t, maxRetryDelay, RetryDelay(30))
+}
@@ -1,6 +1,10 @@
 package util
 
-import "github.com/spf13/viper"
+import (
+	"time"
+
+	"github.com/spf13/viper"
+)
 
 // Config stores all configuration of the application
 // The values are read by viper from a config file or environemnt variables.
@@ -9,6 +13,47 @@ type Config struct {
 	DBDriver      string `mapstructure:"DB_DRIVER"`
 	DBSource      string `mapstructure:"DB_SOURCE"`
 	ServerAddress string `mapstructure:"SERVER_ADDRESS"`
+	// PublicURL is the externally visible base URL, used for links in feeds
+	PublicURL string `mapstructure:"PUBLIC_URL"`
+	// MaxPageSize caps page_size on cursor paginated list endpoints
+	MaxPageSize int32 `mapstructure:"MAX_PAGE_SIZE"`
+
+	// AuthProvider selects the token verifier: "firebase" or "jwt"
+	AuthProvider            string `mapstructure:"AUTH_PROVIDER"`
+	FirebaseCredentialsFile string `mapstructure:"FIREBASE_CREDENTIALS_FILE"`
+	// JWTAlgorithm is either HS256 (uses JWTSecretKey) or RS256 (uses the key files)
+	JWTAlgorithm      string `mapstructure:"JWT_ALGORITHM"`
+	JWTSecretKey      string `mapstructure:"JWT_SECRET_KEY"`
+	JWTPublicKeyFile  string `mapstructure:"JWT_PUBLIC_KEY_FILE"`
+	JWTPrivateKeyFile string `mapstructure:"JWT_PRIVATE_KEY_FILE"`
+	JWTIssuer         string `mapstructure:"JWT_ISSUER"`
+
+	// UnfurlTimeout bounds a single link preview fetch, UnfurlMaxBodyBytes caps the page size read
+	UnfurlTimeout      time.Duration `mapstructure:"UNFURL_TIMEOUT"`
+	UnfurlMaxBodyBytes int64         `mapstructure:"UNFURL_MAX_BODY_BYTES"`
+	UnfurlPollInterval time.Duration `mapstructure:"UNFURL_POLL_INTERVAL"`
+	// ArchiveMaxBodyBytes caps the page size read for article snapshots, which need the whole page
+	ArchiveMaxBodyBytes int64         `mapstructure:"ARCHIVE_MAX_BODY_BYTES"`
+	ArchivePollInterval time.Duration `mapstructure:"ARCHIVE_POLL_INTERVAL"`
+	ImportPollInterval  time.Duration `mapstructure:"IMPORT_POLL_INTERVAL"`
+
+	// ReminderNotifier selects how due reminders are delivered: "log", "webhook" or "email"
+	ReminderNotifier     string        `mapstructure:"REMINDER_NOTIFIER"`
+	ReminderWebhookURL   string        `mapstructure:"REMINDER_WEBHOOK_URL"`
+	ReminderPollInterval time.Duration `mapstructure:"REMINDER_POLL_INTERVAL"`
+	// SMTPAddr is the relay used for email, e.g. a local MailHog at localhost:1025
+	SMTPAddr     string `mapstructure:"SMTP_ADDR"`
+	SMTPFrom     string `mapstructure:"SMTP_FROM"`
+	SMTPUsername string `mapstructure:"SMTP_USERNAME"`
+	SMTPPassword string `mapstructure:"SMTP_PASSWORD"`
+
+	// DigestSecret signs the unsubscribe links in digest emails
+	DigestSecret       string        `mapstructure:"DIGEST_SECRET"`
+	DigestPollInterval time.Duration `mapstructure:"DIGEST_POLL_INTERVAL"`
+
+	// WebhookTimeout bounds a single outbound webhook delivery
+	WebhookTimeout      time.Duration `mapstructure:"WEBHOOK_TIMEOUT"`
+	WebhookPollInterval time.Duration `mapstructure:"WEBHOOK_POLL_INTERVAL"`
 }
 
 // LoadConfig reads configuration from file or environemnt variables
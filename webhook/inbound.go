@@ -0,0 +1,70 @@
+package webhook
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// InboundTokenPrefix marks the secret path segment of an inbound hook URL
+const InboundTokenPrefix = "rc_hook_"
+
+const inboundTokenBytes = 32
+
+// SignatureTolerance is how far the timestamp of a signed inbound request may
+// be from the server clock, older requests are treated as replays
+const SignatureTolerance = 5 * time.Minute
+
+var (
+	ErrMissingSignature = errors.New("request is not signed")
+	ErrInvalidSignature = errors.New("request signature is invalid")
+	ErrStaleSignature   = errors.New("request timestamp is too old or too far in the future")
+)
+
+// NewInboundToken generates the secret path segment of an inbound hook and
+// returns it with its hash. Only the hash should be persisted.
+func NewInboundToken() (token string, hash string, err error) {
+	buf := make([]byte, inboundTokenBytes)
+	if _, err = rand.Read(buf); err != nil {
+		return "", "", fmt.Errorf("cannot generate inbound hook token: %w", err)
+	}
+
+	token = InboundTokenPrefix + base64.RawURLEncoding.EncodeToString(buf)
+	return token, HashInboundToken(token), nil
+}
+
+// HashInboundToken returns the hex encoded SHA-256 hash of an inbound hook token
+func HashInboundToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// VerifyRequest checks the timestamp and signature headers of an inbound
+// request. They are built the same way as on outgoing deliveries, see Sign.
+func VerifyRequest(secret string, header http.Header, body []byte, now time.Time) error {
+	signature := header.Get(SignatureHeader)
+	if signature == "" {
+		return ErrMissingSignature
+	}
+
+	timestamp, err := strconv.ParseInt(header.Get(TimestampHeader), 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+
+	sentAt := time.Unix(timestamp, 0)
+	if sentAt.Before(now.Add(-SignatureTolerance)) || sentAt.After(now.Add(SignatureTolerance)) {
+		return ErrStaleSignature
+	}
+
+	if !Verify(secret, timestamp, body, signature) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package webhook
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewInboundToken(t *testing.T) {
+	token, hash, err := NewInboundToken()
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(token, InboundTokenPrefix))
+	require.Equal(t, HashInboundToken(token), hash)
+	require.NotContains(t, hash, token)
+}
+
+func TestVerifyRequest(t *testing.T) {
+	body := []byte(`{"url":"https://example.com"}`)
+	now := time.Now()
+
+	signed := func(sentAt time.Time, secret string) http.Header {
+		header := http.Header{}
+		header.Set(TimestampHeader, strconv.FormatInt(sentAt.Unix(), 10))
+		header.Set(SignatureHeader, Sign(secret, sentAt.Unix(), body))
+		return header
+	}
+
+	require.NoError(t, VerifyRequest("secret", signed(now, "secret"), body, now))
+	require.NoError(t, VerifyRequest("secret", signed(now.Add(-time.Minute), "secret"), body, now))
+
+	require.ErrorIs(t, VerifyRequest("secret", http.Header{}, body, now), ErrMissingSignature)
+	require.ErrorIs(t, VerifyRequest("secret", signed(now, "other"), body, now), ErrInvalidSignature)
+	require.ErrorIs(t, VerifyRequest("secret", signed(now.Add(-time.Hour), "secret"), body, now), ErrStaleSignature)
+	require.ErrorIs(t, VerifyRequest("secret", signed(now, "secret"), []byte(`{}`), now), ErrInvalidSignature)
+
+	header := signed(now, "secret")
+	header.Set(TimestampHeader, "yesterday")
+	require.ErrorIs(t, VerifyRequest("secret", header, body, now), ErrInvalidSignature)
+}
@@ -0,0 +1,44 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+)
+
+// Headers sent with every delivery
+const (
+	EventHeader     = "X-ReadCache-Event"
+	DeliveryHeader  = "X-ReadCache-Delivery"
+	TimestampHeader = "X-ReadCache-Timestamp"
+	SignatureHeader = "X-ReadCache-Signature"
+)
+
+// Sign returns the signature header value of a delivery sent at timestamp
+// (unix seconds): sha256= followed by the hex HMAC-SHA256 of
+// "<timestamp>.<body>" keyed with the endpoint secret. Covering the timestamp
+// lets receivers reject old deliveries replayed at them.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify reports whether signature is valid for body sent at timestamp, it is
+// what a receiver written in Go would run
+func Verify(secret string, timestamp int64, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, timestamp, body)), []byte(signature))
+}
+
+// NewSecret generates the signing secret of a new endpoint
+func NewSecret() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
@@ -0,0 +1,31 @@
+package webhook
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignature(t *testing.T) {
+	body := []byte(`{"id":1}`)
+
+	signature := Sign("secret", 1700000000, body)
+	require.True(t, strings.HasPrefix(signature, "sha256="))
+	require.True(t, Verify("secret", 1700000000, body, signature))
+
+	require.False(t, Verify("other", 1700000000, body, signature))
+	require.False(t, Verify("secret", 1700000001, body, signature))
+	require.False(t, Verify("secret", 1700000000, []byte(`{"id":2}`), signature))
+}
+
+func TestNewSecret(t *testing.T) {
+	a, err := NewSecret()
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(a, "whsec_"))
+	require.Len(t, a, len("whsec_")+48)
+
+	b, err := NewSecret()
+	require.NoError(t, err)
+	require.NotEqual(t, a, b)
+}
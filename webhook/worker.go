@@ -0,0 +1,209 @@
+// Package webhook delivers the events in the webhook outbox to the endpoints
+// users registered for them, and verifies the requests sent to inbound hooks.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	db "github.com/imrishuroy/read-cache-api/db/sqlc"
+	"github.com/imrishuroy/read-cache-api/jobs"
+	"github.com/imrishuroy/read-cache-api/unfurl"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	defaultPollInterval = 5 * time.Second
+	defaultBatchSize    = 20
+	defaultMaxAttempts  = 10
+	defaultTimeout      = 10 * time.Second
+	userAgent           = "ReadCache-Webhooks/1.0"
+	// maxResponseBytes is how much of a response is read before the connection is dropped
+	maxResponseBytes = 4 << 10
+)
+
+// errRedirect is returned for endpoints that answer with a redirect, which
+// would send a signed payload somewhere the user didn't register
+var errRedirect = errors.New("webhook endpoint responded with a redirect")
+
+// WorkerConfig tunes a Worker, zero values fall back to sane defaults
+type WorkerConfig struct {
+	jobs.Config
+	// MaxAttempts is how often a delivery is tried before it is marked failed
+	MaxAttempts int32
+	// Timeout bounds a single delivery
+	Timeout time.Duration
+	// AllowPrivateNetworks disables the SSRF guard, only meant for tests
+	AllowPrivateNetworks bool
+}
+
+// Event is the JSON body of a delivery. ID is the outbox event, redeliveries
+// of an event carry the same ID so receivers can drop duplicates.
+type Event struct {
+	ID        int64           `json:"id"`
+	Type      string          `json:"type"`
+	CreatedAt time.Time       `json:"created_at"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// Worker moves events from the outbox to their endpoints. Each poll first
+// fans undispatched events out into one delivery per subscribed endpoint,
+// then claims due deliveries under a lease and POSTs them. Failed deliveries
+// back off exponentially and are marked failed after MaxAttempts.
+type Worker struct {
+	store  db.Store
+	client *http.Client
+	config WorkerConfig
+	lease  time.Duration
+}
+
+// NewWorker creates a Worker
+func NewWorker(store db.Store, config WorkerConfig) *Worker {
+	config.Config = config.Config.WithDefaults(defaultPollInterval, defaultBatchSize)
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = defaultMaxAttempts
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = defaultTimeout
+	}
+
+	dialer := &net.Dialer{Timeout: config.Timeout}
+	if !config.AllowPrivateNetworks {
+		dialer.Control = unfurl.GuardDial
+	}
+
+	client := &http.Client{
+		Transport: &http.Transport{
+			Proxy:                 nil,
+			DialContext:           dialer.DialContext,
+			TLSHandshakeTimeout:   config.Timeout,
+			ResponseHeaderTimeout: config.Timeout,
+			MaxIdleConns:          10,
+			IdleConnTimeout:       30 * time.Second,
+		},
+		Timeout: config.Timeout,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return errRedirect
+		},
+	}
+
+	return &Worker{
+		store:  store,
+		client: client,
+		config: config,
+		lease:  time.Duration(config.BatchSize)*config.Timeout + time.Minute,
+	}
+}
+
+// Run polls the outbox until ctx is cancelled
+func (worker *Worker) Run(ctx context.Context) {
+	jobs.Loop(ctx, worker.config.PollInterval, "webhooks", worker.ProcessDue)
+}
+
+// ProcessDue dispatches one batch of outbox events and sends one batch of
+// due deliveries, it returns how many deliveries were claimed
+func (worker *Worker) ProcessDue(ctx context.Context) (int, error) {
+	if _, err := worker.store.DispatchWebhookEvents(ctx, worker.config.BatchSize); err != nil {
+		return 0, err
+	}
+
+	deliveries, err := worker.store.ClaimDueWebhookDeliveries(ctx, db.ClaimDueWebhookDeliveriesParams{
+		BatchSize:    worker.config.BatchSize,
+		LeaseSeconds: int32(worker.lease / time.Second),
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	for _, delivery := range deliveries {
+		if err := worker.process(ctx, delivery); err != nil {
+			return len(deliveries), err
+		}
+	}
+	return len(deliveries), nil
+}
+
+func (worker *Worker) process(ctx context.Context, delivery db.ClaimDueWebhookDeliveriesRow) error {
+	statusCode, sendErr := worker.send(ctx, delivery)
+
+	var responseStatus pgtype.Int4
+	if statusCode != 0 {
+		responseStatus = pgtype.Int4{Int32: int32(statusCode), Valid: true}
+	}
+
+	if sendErr == nil {
+		return worker.store.CompleteWebhookDelivery(ctx, db.CompleteWebhookDeliveryParams{
+			ID:             delivery.ID,
+			ResponseStatus: responseStatus,
+		})
+	}
+
+	if ctx.Err() != nil {
+		// shutting down, the lease hands the delivery to the next run
+		return ctx.Err()
+	}
+
+	attempts := delivery.Attempts + 1
+	if attempts >= worker.config.MaxAttempts || errors.Is(sendErr, unfurl.ErrBlockedAddress) {
+		log.Warn().Err(sendErr).Int64("delivery_id", delivery.ID).Msg("giving up on webhook delivery")
+		return worker.store.FailWebhookDelivery(ctx, db.FailWebhookDeliveryParams{
+			ID:             delivery.ID,
+			ResponseStatus: responseStatus,
+			LastError:      sendErr.Error(),
+		})
+	}
+
+	return worker.store.RetryWebhookDelivery(ctx, db.RetryWebhookDeliveryParams{
+		ID:             delivery.ID,
+		ResponseStatus: responseStatus,
+		LastError:      sendErr.Error(),
+		NextAttemptAt:  time.Now().Add(unfurl.RetryDelay(attempts)),
+	})
+}
+
+// send POSTs a delivery and returns the status code the endpoint answered with
+func (worker *Worker) send(ctx context.Context, delivery db.ClaimDueWebhookDeliveriesRow) (int, error) {
+	body, err := json.Marshal(Event{
+		ID:        delivery.EventID,
+		Type:      delivery.EventType,
+		CreatedAt: delivery.EventCreatedAt,
+		Data:      delivery.Payload,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.Url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set(EventHeader, delivery.EventType)
+	req.Header.Set(DeliveryHeader, strconv.FormatInt(delivery.ID, 10))
+	req.Header.Set(TimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(SignatureHeader, Sign(delivery.Secret, timestamp, body))
+
+	resp, err := worker.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, maxResponseBytes))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("webhook endpoint responded %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,170 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/imrishuroy/read-cache-api/db/mock"
+	db "github.com/imrishuroy/read-cache-api/db/sqlc"
+	"github.com/imrishuroy/read-cache-api/unfurl"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkerProcessDue(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ok":
+			body, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+
+			timestamp, err := strconv.ParseInt(r.Header.Get(TimestampHeader), 10, 64)
+			require.NoError(t, err)
+			require.True(t, Verify("whsec_test", timestamp, body, r.Header.Get(SignatureHeader)))
+			require.Equal(t, db.EventCacheCreated, r.Header.Get(EventHeader))
+			require.NotEmpty(t, r.Header.Get(DeliveryHeader))
+
+			var event Event
+			require.NoError(t, json.Unmarshal(body, &event))
+			require.Equal(t, int64(7), event.ID)
+			require.Equal(t, db.EventCacheCreated, event.Type)
+			require.JSONEq(t, `{"cache":{"id":1}}`, string(event.Data))
+
+			w.WriteHeader(http.StatusNoContent)
+		case "/redirect":
+			http.Redirect(w, r, "/ok", http.StatusFound)
+		default:
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer server.Close()
+
+	testCases := []struct {
+		name       string
+		delivery   db.ClaimDueWebhookDeliveriesRow
+		buildStubs func(store *mockdb.MockStore, delivery db.ClaimDueWebhookDeliveriesRow)
+	}{
+		{
+			name:     "Complete",
+			delivery: db.ClaimDueWebhookDeliveriesRow{ID: 1, Url: server.URL + "/ok"},
+			buildStubs: func(store *mockdb.MockStore, delivery db.ClaimDueWebhookDeliveriesRow) {
+				arg := db.CompleteWebhookDeliveryParams{
+					ID:             delivery.ID,
+					ResponseStatus: pgtype.Int4{Int32: http.StatusNoContent, Valid: true},
+				}
+				store.EXPECT().
+					CompleteWebhookDelivery(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(nil)
+			},
+		},
+		{
+			name:     "Retry",
+			delivery: db.ClaimDueWebhookDeliveriesRow{ID: 2, Url: server.URL + "/flaky", Attempts: 1},
+			buildStubs: func(store *mockdb.MockStore, delivery db.ClaimDueWebhookDeliveriesRow) {
+				store.EXPECT().
+					RetryWebhookDelivery(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.RetryWebhookDeliveryParams) error {
+						require.Equal(t, delivery.ID, arg.ID)
+						require.Equal(t, int32(http.StatusBadGateway), arg.ResponseStatus.Int32)
+						require.NotEmpty(t, arg.LastError)
+						require.WithinDuration(t, time.Now().Add(unfurl.RetryDelay(2)), arg.NextAttemptAt, time.Second)
+						return nil
+					})
+			},
+		},
+		{
+			name:     "RedirectIsNotFollowed",
+			delivery: db.ClaimDueWebhookDeliveriesRow{ID: 3, Url: server.URL + "/redirect"},
+			buildStubs: func(store *mockdb.MockStore, delivery db.ClaimDueWebhookDeliveriesRow) {
+				store.EXPECT().
+					RetryWebhookDelivery(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil)
+				store.EXPECT().
+					CompleteWebhookDelivery(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+		},
+		{
+			name:     "OutOfAttempts",
+			delivery: db.ClaimDueWebhookDeliveriesRow{ID: 4, Url: server.URL + "/flaky", Attempts: defaultMaxAttempts - 1},
+			buildStubs: func(store *mockdb.MockStore, delivery db.ClaimDueWebhookDeliveriesRow) {
+				store.EXPECT().
+					FailWebhookDelivery(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil)
+				store.EXPECT().
+					RetryWebhookDelivery(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			tc.delivery.EventID = 7
+			tc.delivery.EventType = db.EventCacheCreated
+			tc.delivery.Secret = "whsec_test"
+			tc.delivery.Payload = []byte(`{"cache":{"id":1}}`)
+			tc.delivery.EventCreatedAt = time.Now()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().
+				DispatchWebhookEvents(gomock.Any(), gomock.Any()).
+				Times(1).
+				Return(int64(0), nil)
+			store.EXPECT().
+				ClaimDueWebhookDeliveries(gomock.Any(), gomock.Any()).
+				Times(1).
+				Return([]db.ClaimDueWebhookDeliveriesRow{tc.delivery}, nil)
+			tc.buildStubs(store, tc.delivery)
+
+			worker := NewWorker(store, WorkerConfig{AllowPrivateNetworks: true})
+			n, err := worker.ProcessDue(context.Background())
+			require.NoError(t, err)
+			require.Equal(t, 1, n)
+		})
+	}
+}
+
+func TestWorkerBlocksPrivateNetworks(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("delivery reached a loopback address")
+	}))
+	defer server.Close()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().
+		DispatchWebhookEvents(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(int64(1), nil)
+	store.EXPECT().
+		ClaimDueWebhookDeliveries(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return([]db.ClaimDueWebhookDeliveriesRow{{ID: 1, Url: server.URL, Payload: []byte(`{}`)}}, nil)
+	store.EXPECT().
+		FailWebhookDelivery(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(nil)
+
+	worker := NewWorker(store, WorkerConfig{})
+	_, err := worker.ProcessDue(context.Background())
+	require.NoError(t, err)
+}